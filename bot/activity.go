@@ -0,0 +1,94 @@
+package bot
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/DrDelphi/ElrondDSSC/data"
+	"github.com/DrDelphi/ElrondDSSC/network"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+)
+
+// activityListSize - number of transactions listed in the activity feed
+const activityListSize = 15
+
+// activityAll - callback parameter for the feed of all the categories
+const activityAll = "all"
+
+var activityTitles = map[string]string{
+	activityAll:                "Contract Activity",
+	network.ActivityDelegation: "Delegation Activity",
+	network.ActivityNodes:      "Nodes Activity",
+	network.ActivityAdmin:      "Admin Activity",
+}
+
+// sendActivity - sends the newest transactions sent to the contract, from the local index
+func (b *Bot) sendActivity(user *data.User, category string) {
+	title, ok := activityTitles[category]
+	if !ok {
+		return
+	}
+
+	if b.contracts.ContractAddress() == "" {
+		b.sendMessage(user.TgID, "⭕️ Contract Address not found")
+		return
+	}
+
+	filter := category
+	if category == activityAll {
+		filter = ""
+	}
+	activities, err := b.activity.GetActivity(filter, activityListSize)
+	if err != nil {
+		b.sendMessage(user.TgID, "⭕️ Can not read the contract activity")
+		return
+	}
+
+	text := fmt.Sprintf("`%s`\n\r", title)
+	if len(activities) == 0 {
+		text += "\n\r`No transactions indexed yet`"
+	}
+	for _, activity := range activities {
+		text += "\n\r" + activityLine(activity)
+	}
+
+	msg := tgbotapi.NewMessage(user.TgID, text)
+	msg.ParseMode = tgbotapi.ModeMarkdown
+	msg.DisableWebPagePreview = true
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("All", ":Activity_"+activityAll),
+			tgbotapi.NewInlineKeyboardButtonData("Delegations", ":Activity_"+network.ActivityDelegation),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("Nodes", ":Activity_"+network.ActivityNodes),
+			tgbotapi.NewInlineKeyboardButtonData("Admin", ":Activity_"+network.ActivityAdmin),
+		),
+	)
+	b.tgBot.Send(msg)
+}
+
+func activityLine(activity *data.ContractActivity) string {
+	status := "✅"
+	if activity.Status != network.TxStatusSuccess {
+		status = "❌"
+	}
+
+	line := fmt.Sprintf("%s `%s` %s", status, time.Unix(activity.Timestamp, 0).UTC().Format("01-02 15:04"),
+		activity.Function)
+	if !activity.Amount.IsZero() {
+		line += fmt.Sprintf(" %s eGLD", activity.Amount.Format(2))
+	}
+	line += " by " + shortAddress(activity.Sender)
+
+	return line
+}
+
+// shortAddress - returns the start and the end of a bech32 address
+func shortAddress(address string) string {
+	if len(address) <= 16 {
+		return address
+	}
+
+	return address[:10] + "…" + address[len(address)-6:]
+}
@@ -3,51 +3,94 @@ package bot
 import (
 	"encoding/hex"
 	"fmt"
-	"math/big"
 	"strings"
 	"time"
 
 	"github.com/DrDelphi/ElrondDSSC/data"
 	"github.com/DrDelphi/ElrondDSSC/db"
 	"github.com/DrDelphi/ElrondDSSC/network"
-	"github.com/DrDelphi/ElrondDSSC/utils"
 	logger "github.com/ElrondNetwork/elrond-go-logger"
-	"github.com/ElrondNetwork/elrond-sdk/erdgo"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
 )
 
 var log = logger.GetOrCreate("bot")
 
+// nodeKeyPrefixLength - number of hex characters of a BLS key sent in callbacks, as the
+// callback data can not exceed 64 bytes
+const nodeKeyPrefixLength = 32
+
+// txHashLength - size in bytes of a transaction hash
+const txHashLength = 32
+
 // Bot - holds the required fields of the bot application
 type Bot struct {
-	tgBot          *tgbotapi.BotAPI
-	owner          int64
-	walletHook     string
-	database       *db.Database
-	networkManager *network.NetworkManager
+	tgBot        TelegramClient
+	owner        int64
+	hooks        *network.HookLinkBuilder
+	explorerURL  string
+	database     db.Store
+	contract     network.DelegationContract
+	accounts     network.AccountReader
+	transactions network.TransactionSender
+	simulator    network.TransactionSimulator
+	txTracker    TxTracker
+	contracts    ContractRegistry
+	clock        NetworkClock
+	nodes        NodeLister
+	providers    ProviderLister
+	apr          APREstimator
+	history      RewardsHistory
+	activity     ActivityIndexer
+	snapshots    StakeSnapshotter
+	vault        KeyVault
 }
 
 // NewBot - creates a new Bot object
-func NewBot(cfg *data.AppConfig, database *db.Database, networkManager *network.NetworkManager) (*Bot, error) {
-	tgBot, err := tgbotapi.NewBotAPI(cfg.BotToken)
-	if err != nil {
-		log.Error("can not create telegram bot", "error", err)
-		return nil, err
+func NewBot(cfg *data.AppConfig, deps *Dependencies) *Bot {
+	callbackURL := ""
+	if deps.Callbacks != nil && cfg.HookCallback != nil {
+		callbackURL = cfg.HookCallback.PublicURL
 	}
 
 	telegramBot := &Bot{
-		tgBot:          tgBot,
-		owner:          cfg.BotOwner,
-		walletHook:     cfg.WalletHook,
-		database:       database,
-		networkManager: networkManager,
+		tgBot:        deps.Telegram,
+		owner:        cfg.BotOwner,
+		hooks:        network.NewHookLinkBuilder(cfg.WalletHook, callbackURL, deps.Database),
+		explorerURL:  strings.TrimSuffix(cfg.ExplorerURL, "/"),
+		database:     deps.Database,
+		contract:     deps.Contract,
+		accounts:     deps.Accounts,
+		transactions: deps.Transactions,
+		simulator:    deps.Simulator,
+		txTracker:    deps.TxTracker,
+		contracts:    deps.Contracts,
+		clock:        deps.Clock,
+		nodes:        deps.Nodes,
+		providers:    deps.Providers,
+		apr:          deps.APR,
+		history:      deps.History,
+		activity:     deps.Activity,
+		snapshots:    deps.Snapshots,
+		vault:        deps.Vault,
+	}
+	telegramBot.txTracker.OnOutcome(telegramBot.txOutcome)
+	telegramBot.contracts.OnChange(telegramBot.contractChanged)
+	if deps.Callbacks != nil {
+		deps.Callbacks.OnCallback(telegramBot.hookCallback)
 	}
 
-	return telegramBot, nil
+	return telegramBot
 }
 
 // StartTasks - starts bot's tasks
 func (b *Bot) StartTasks() {
+	b.clock.Start()
+	b.txTracker.Start()
+	b.contracts.Start()
+	b.providers.Start()
+	b.activity.Start()
+	b.snapshots.Start()
+
 	go func() {
 		u := tgbotapi.NewUpdate(0)
 		u.Timeout = 60
@@ -63,14 +106,8 @@ func (b *Bot) StartTasks() {
 						b.privateCommandReceived(update.Message)
 						continue
 					}
-					if update.Message.ReplyToMessage != nil {
-						b.privateReplyReceived(update.Message)
-						continue
-					}
-					if update.Message.Document != nil {
-						_, _ = b.downloadFile(update.Message)
-						continue
-					}
+					b.privateMessageReceived(update.Message)
+					continue
 				}
 			}
 			if update.CallbackQuery != nil {
@@ -78,41 +115,6 @@ func (b *Bot) StartTasks() {
 			}
 		}
 	}()
-
-	// read the DSSC address
-	go func() {
-		oldAddress := b.database.GetOwnerAddress()
-		for {
-			time.Sleep(time.Second * 10)
-			if oldAddress != b.database.GetOwnerAddress() || utils.ContractAddress == "" {
-				address := b.database.GetOwnerAddress()
-				utils.ContractAddress = ""
-				txs, err := b.networkManager.GetLastTxs("erd1qqqqqqqqqqqqqqqpqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqylllslmq6y6", 3000, "receiver")
-				if err != nil {
-					continue
-				}
-
-				for _, tx := range txs {
-					if tx.Sender == address && tx.Status == "success" {
-						for _, scr := range tx.SmartContractResults {
-							if len(scr.Data) != 70 || !strings.HasPrefix(string(scr.Data), "@6f6b@") { // @ok@
-								continue
-							}
-							hexAddress := strings.TrimPrefix(string(scr.Data), "@6f6b@")
-							hexBytes, _ := hex.DecodeString(hexAddress)
-							utils.ContractAddress, err = erdgo.PubkeyToBech32(hexBytes)
-							if err == nil {
-								break
-							}
-						}
-						break
-					}
-				}
-
-				oldAddress = b.database.GetOwnerAddress()
-			}
-		}
-	}()
 }
 
 func (b *Bot) reportError(text string) {
@@ -126,6 +128,111 @@ func (b *Bot) sendMessage(userID int64, text string) {
 	b.tgBot.Send(msg)
 }
 
+// contractChanged - lets the owner know the contract address the bot works with
+func (b *Bot) contractChanged(address string) {
+	if address == "" {
+		b.sendMessage(b.owner, "ℹ️ No delegation contract found for the owner address")
+		return
+	}
+
+	b.sendMessage(b.owner, "ℹ️ Contract address set to "+address)
+
+	info, err := b.contract.GetContractInfo(address)
+	if err == nil {
+		b.checkContractOwner(info, b.database.GetOwnerAddress())
+	}
+}
+
+// checkContractOwner - warns the owner if the contract is owned by another address than the configured one
+func (b *Bot) checkContractOwner(info *data.ContractInfo, ownerAddress string) {
+	if info.OwnerAddress == "" || info.OwnerAddress == ownerAddress {
+		return
+	}
+
+	b.sendMessage(b.owner, fmt.Sprintf("⚠️ The contract is owned by %s, not by the configured owner address %s",
+		info.OwnerAddress, ownerAddress))
+}
+
+// txOutcome - lets the user who initiated a tracked transaction know how it ended
+func (b *Bot) txOutcome(outcome *network.TxOutcome) {
+	tx := outcome.Tx
+	if outcome.Succeeded() {
+		b.sendMessage(tx.TgID, fmt.Sprintf("✅ %s transaction succeeded. Hash: `%s`", tx.Description, tx.Hash)+
+			b.explorerLink(tx.Hash))
+		return
+	}
+
+	text := fmt.Sprintf("⭕️ %s transaction %s. Hash: `%s`", tx.Description, outcomeText(outcome.Status), tx.Hash)
+	if outcome.Reason != "" {
+		text += "\nReason: " + outcome.Reason
+	}
+	b.sendMessage(tx.TgID, text+b.explorerLink(tx.Hash))
+}
+
+// explorerLink - returns a link to the transaction on the network's explorer, if the profile has one
+func (b *Bot) explorerLink(hash string) string {
+	if b.explorerURL == "" {
+		return ""
+	}
+
+	return fmt.Sprintf("\n[View in explorer](%s/transactions/%s)", b.explorerURL, hash)
+}
+
+func outcomeText(status string) string {
+	switch status {
+	case network.TxStatusInvalid:
+		return "is invalid"
+	case network.TxStatusTimeout:
+		return "did not complete in time"
+	default:
+		return "failed"
+	}
+}
+
+// hookURL - returns the web wallet link which prepares the requested transaction for signing. The wallet
+// redirects back to the bot, if configured, so the user gets the outcome in chat
+func (b *Bot) hookURL(tgID int64, description string, req *network.TxRequest) string {
+	return b.hooks.Link(&network.TxIntent{
+		Request:     req,
+		TgID:        tgID,
+		Description: description,
+	})
+}
+
+// ownerHookURL - returns the web wallet link of a transaction only the owner's address may send
+func (b *Bot) ownerHookURL(tgID int64, description string, req *network.TxRequest) string {
+	return b.hooks.Link(&network.TxIntent{
+		Request:     req,
+		TgID:        tgID,
+		Description: description,
+		Sender:      b.database.GetOwnerAddress(),
+	})
+}
+
+// hookCallback - lets the user know the wallet sent or cancelled the transaction, and tracks the sent ones
+// which were found on the network and match the link
+func (b *Bot) hookCallback(callback *data.HookCallback) {
+	intent := callback.Intent
+	if callback.Status == network.HookStatusUnverified {
+		b.sendMessage(intent.TgID, fmt.Sprintf("⭕️ %s transaction reported by the wallet was not found or does "+
+			"not match the link, so it is not followed", intent.Description))
+		return
+	}
+
+	hash, err := hex.DecodeString(callback.TxHash)
+	if callback.Status != network.HookStatusSuccess || err != nil || len(hash) != txHashLength {
+		b.sendMessage(intent.TgID, fmt.Sprintf("⭕️ %s transaction was not sent from the wallet", intent.Description))
+		return
+	}
+
+	b.sendMessage(intent.TgID, fmt.Sprintf("📨 %s transaction signed and sent from %s. Hash: `%s`", intent.Description,
+		shortAddress(callback.Sender), callback.TxHash)+b.explorerLink(callback.TxHash))
+	err = b.txTracker.Track(callback.TxHash, intent.TgID, intent.Description)
+	if err != nil {
+		b.reportError(fmt.Sprintf("Can not follow the %s transaction %s. %s", intent.Description, callback.TxHash, err))
+	}
+}
+
 func (b *Bot) sendBalances(user *data.User) {
 	b.sendMessage(user.TgID, "`Balances`")
 
@@ -141,12 +248,12 @@ func (b *Bot) sendBalances(user *data.User) {
 	}
 
 	for i, w := range user.Wallets {
-		account, err := b.networkManager.Proxy.GetAccount(w.Address)
-		text := fmt.Sprintf("`Wallet %v/%v`", i+1, len(user.Wallets))
+		account, err := b.accounts.GetAccount(w.Address)
+		text := fmt.Sprintf("`Wallet %v/%v` %s\n\r%s", i+1, len(user.Wallets), walletStatus(w), w.Address)
 		if err == nil {
-			balance, err := account.GetBalance(18)
+			balance, err := data.ParseAttoAmount(account.Balance)
 			if err == nil {
-				text += fmt.Sprintf("\n\r`Balance:` %.4f eGLD", balance)
+				text += fmt.Sprintf("\n\r`Balance:` %s eGLD", balance.Format(4))
 			} else {
 				text += "\n\r❌ Balance error"
 			}
@@ -156,56 +263,19 @@ func (b *Bot) sendBalances(user *data.User) {
 
 		keyboard := tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow())
 
-		activeStake, err := b.networkManager.GetUserActiveStake(w.Address)
-		if err == nil && activeStake != nil {
-			fActiveStake, _ := activeStake.Float64()
-			if fActiveStake > 0 {
-				text += fmt.Sprintf("\n\r`Delegated:` %.4f eGLD", fActiveStake)
-			}
-		}
-
-		unstaked, err := b.networkManager.GetUserUnStakedValue(w.Address)
-		if err == nil && unstaked != nil {
-			fUnstaked, _ := unstaked.Float64()
-			if fUnstaked > 0 {
-				text += fmt.Sprintf("\n\r`Undelegated:` %.4f eGLD", fUnstaked)
-				list, err := b.networkManager.GetUserUnDelegatedList(w.Address)
-				if err == nil {
-					for i := 0; i < len(list); i += 2 {
-						iAmount := big.NewInt(0).SetBytes(list[i])
-						fAmount := big.NewFloat(0).SetInt(iAmount)
-						fAmount.Quo(fAmount, b.networkManager.GetDenominator())
-						amount, _ := fAmount.Float64()
-
-						iRounds := big.NewInt(0).SetBytes(list[i+1])
-						seconds := iRounds.Uint64() * 6 // TODO: get the round duration from network config
-						text += fmt.Sprintf("\n\r    - %.4f eGLD (ETA: %v:%02v:%02v)", amount, seconds/3600, seconds/60%60, seconds%60)
-						if i == 18 {
-							text += "\n\r    ..."
-							break
-						}
-					}
-				}
-			}
-		}
-
-		unbondable, err := b.networkManager.GetUserUnBondable(w.Address)
-		if err == nil && unbondable != nil {
-			fUnbondable, _ := unbondable.Float64()
-			if fUnbondable > 0 {
-				text += fmt.Sprintf("\n\r`Can withdraw:` %.4f eGLD", fUnbondable)
-			}
-		}
-
-		claimable, err := b.networkManager.GetClaimableRewards(w.Address)
-		if err == nil && claimable != nil {
-			fClaimable, _ := claimable.Float64()
-			if fClaimable > 0 {
-				text += fmt.Sprintf("\n\r`Claimable rewards:` %.4f eGLD", fClaimable)
-			}
+		if w.Verified {
+			text += b.delegationText(w.Address)
+			text += b.recentRewardsText(w.Address)
+			keyboard.InlineKeyboard[0] = append(keyboard.InlineKeyboard[0],
+				tgbotapi.NewInlineKeyboardButtonData("📆 Stake History", fmt.Sprintf(":StakeHistory_%v", w.ID)))
+		} else {
+			text += "\n\rℹ️ Verify the wallet to see its delegations and rewards"
+			keyboard.InlineKeyboard[0] = append(keyboard.InlineKeyboard[0],
+				tgbotapi.NewInlineKeyboardButtonData("🔐 Verify", fmt.Sprintf(":VerifyWallet_%v", w.ID)))
 		}
 
 		keyboard.InlineKeyboard[0] = append(keyboard.InlineKeyboard[0],
+			tgbotapi.NewInlineKeyboardButtonData("📷 Deposit QR", fmt.Sprintf(":WalletQR_%v", w.ID)),
 			tgbotapi.NewInlineKeyboardButtonData("🗑 Remove", fmt.Sprintf(":RemoveWallet_%v", w.ID)))
 
 		msg := tgbotapi.NewMessage(user.TgID, text)
@@ -215,6 +285,70 @@ func (b *Bot) sendBalances(user *data.User) {
 	}
 }
 
+// delegationText - returns the delegation details of a wallet, shown only to the wallet's verified owners
+func (b *Bot) delegationText(address string) string {
+	text := ""
+
+	activeStake, err := b.contract.GetUserActiveStake(address)
+	if err == nil && activeStake.Sign() > 0 {
+		text += fmt.Sprintf("\n\r`Delegated:` %s eGLD", activeStake.Format(4))
+	}
+
+	unstaked, err := b.contract.GetUserUnStakedValue(address)
+	if err == nil && unstaked.Sign() > 0 {
+		text += fmt.Sprintf("\n\r`Undelegated:` %s eGLD", unstaked.Format(4))
+		list, err := b.contract.GetUserUnDelegatedList(address)
+		if err == nil {
+			for i, fund := range list {
+				text += fmt.Sprintf("\n\r    - %s eGLD (%s)", fund.Amount.Format(4), b.withdrawableText(fund.RemainingRounds))
+				if i == 9 {
+					text += "\n\r    ..."
+					break
+				}
+			}
+		}
+	}
+
+	unbondable, err := b.contract.GetUserUnBondable(address)
+	if err == nil && unbondable.Sign() > 0 {
+		text += fmt.Sprintf("\n\r`Can withdraw:` %s eGLD", unbondable.Format(4))
+	}
+
+	claimable, err := b.contract.GetClaimableRewards(address)
+	if err == nil && claimable.Sign() > 0 {
+		text += fmt.Sprintf("\n\r`Claimable rewards:` %s eGLD", claimable.Format(4))
+	}
+
+	return text
+}
+
+// withdrawableText - returns when funds still unbonding for the given number of rounds can be withdrawn
+func (b *Bot) withdrawableText(rounds uint64) string {
+	if rounds == 0 {
+		return "withdrawable now"
+	}
+
+	text := "withdrawable on " + b.clock.TimeAfterRounds(rounds).UTC().Format("2006-01-02 15:04 UTC")
+	if b.clock.Synced() {
+		text += fmt.Sprintf(" (epoch %v)", b.clock.EpochAfterRounds(rounds))
+	}
+
+	return text
+}
+
+// formatDuration - formats a duration as days, hours and minutes
+func formatDuration(d time.Duration) string {
+	minutes := int64(d.Round(time.Minute) / time.Minute)
+	days := minutes / (24 * 60)
+	hours := minutes / 60 % 24
+	minutes %= 60
+	if days > 0 {
+		return fmt.Sprintf("%vd %vh %02vm", days, hours, minutes)
+	}
+
+	return fmt.Sprintf("%vh %02vm", hours, minutes)
+}
+
 func (b *Bot) sendContractInfo(user *data.User) {
 	b.sendMessage(user.TgID, "`Contract Info`")
 
@@ -224,129 +358,164 @@ func (b *Bot) sendContractInfo(user *data.User) {
 		return
 	}
 
-	if utils.ContractAddress == "" {
+	contractAddress := b.contracts.ContractAddress()
+	if contractAddress == "" {
 		b.sendMessage(user.TgID, "⭕️ Contract Address not found")
 		return
 	}
 
-	text := fmt.Sprintf("`Contract address`: %s", utils.ContractAddress)
+	text := fmt.Sprintf("`Contract address`: %s", contractAddress)
 
-	info, err := b.networkManager.GetContractInfo(utils.ContractAddress)
+	info, err := b.contract.GetContractInfo(contractAddress)
 	if err == nil {
 		text += fmt.Sprintf("\n\r`Service fee:` %.2f%%", info.ServiceFee)
 		if info.ChangeableServiceFee {
 			text += " (changeable)"
 		}
 		if info.WithDelegationCap {
-			text += fmt.Sprintf("\n\r`Max delegation cap:` %v eGLD", uint64(info.MaxDelegationCap))
+			text += fmt.Sprintf("\n\r`Max delegation cap:` %s eGLD", info.MaxDelegationCap)
+		}
+		text += fmt.Sprintf("\n\r`Initial owner funds:` %s eGLD", info.InitialOwnerFunds)
+		text += fmt.Sprintf("\n\r`Unbond period:` %s", formatDuration(b.clock.RoundsDuration(info.UnBondPeriod)))
+		if roundsPerEpoch := b.clock.RoundsPerEpoch(); roundsPerEpoch > 0 {
+			text += fmt.Sprintf(" (~%v epochs)", (info.UnBondPeriod+roundsPerEpoch-1)/roundsPerEpoch)
 		}
-		text += fmt.Sprintf("\n\r`Initial owner funds:` %v eGLD", uint64(info.InitialOwnerFunds))
-		unbondSeconds := info.UnBondPeriod * 6 // TODO: replace 6 with round duration from network config
-		text += fmt.Sprintf("\n\r`Unbond period:` %v:%02v:%02v", unbondSeconds/3600, unbondSeconds/60%60, unbondSeconds%60)
 		text += fmt.Sprintf("\n\r`Automatic activation:` %v", info.AutomaticActivation)
 		text += fmt.Sprintf("\n\r`Created at nonce:` %v", info.CreatedNonce)
+		if user.TgID == b.owner {
+			b.checkContractOwner(info, ownerAddress)
+		}
+	}
+
+	estimate, err := b.apr.Estimate()
+	if err == nil && estimate.StakedNodes > 0 {
+		text += fmt.Sprintf("\n\r`Estimated APR:` %.2f%% (%.2f%% before the service fee)", estimate.NetAPR, estimate.GrossAPR)
+	}
+
+	metadata, err := b.contract.GetContractMetadata(contractAddress)
+	if err == nil && metadata.Name != "" {
+		text += fmt.Sprintf("\n\r`Provider:` %s", metadata.Name)
+		if metadata.Website != "" {
+			text += fmt.Sprintf("\n\r`Website:` %s", metadata.Website)
+		}
+		if metadata.Identifier != "" {
+			text += fmt.Sprintf("\n\r`Keybase:` https://keybase.io/%s", metadata.Identifier)
+		}
 	}
 
 	if user.TgID != b.owner {
 		b.sendMessage(user.TgID, text)
+		b.sendAddressQRCode(user.TgID, "Contract address", contractAddress)
 		return
 	}
 
 	text += "\n\r"
 
-	numNodes, err := b.networkManager.GetNumNodes()
+	numNodes, err := b.contract.GetNumNodes()
 	if err == nil {
 		text += fmt.Sprintf("\n\r`Nodes:` %v", numNodes)
 	}
 
-	numUsers, err := b.networkManager.GetNumUsers()
+	numUsers, err := b.contract.GetNumUsers()
 	if err == nil {
 		text += fmt.Sprintf("\n\r`Delegators:` %v", numUsers)
 	}
 
-	totalActiveStake, err := b.networkManager.GetTotalActiveStake()
+	totalActiveStake, err := b.contract.GetTotalActiveStake()
 	if err == nil {
-		fTotalActiveStake, _ := totalActiveStake.Float64()
-		text += fmt.Sprintf("\n\r`Total active stake:` %.4f eGLD", fTotalActiveStake)
+		text += fmt.Sprintf("\n\r`Total active stake:` %s eGLD", totalActiveStake.Format(4))
 	}
 
-	totalCumulatedRewards, err := b.networkManager.GetTotalCumulatedRewards()
+	totalCumulatedRewards, err := b.contract.GetTotalCumulatedRewards()
 	if err == nil {
-		fTotalCumulatedRewards, _ := totalCumulatedRewards.Float64()
-		text += fmt.Sprintf("\n\r`Total cumulated rewards:` %.4f eGLD", fTotalCumulatedRewards)
+		text += fmt.Sprintf("\n\r`Total cumulated rewards:` %s eGLD", totalCumulatedRewards.Format(4))
 	}
 
-	totalUnStaked, err := b.networkManager.GetTotalUnStaked()
+	totalUnStaked, err := b.contract.GetTotalUnStaked()
 	if err == nil {
-		fTotalUnStaked, _ := totalUnStaked.Float64()
-		text += fmt.Sprintf("\n\r`Total unstaked:` %.4f eGLD", fTotalUnStaked)
+		text += fmt.Sprintf("\n\r`Total unstaked:` %s eGLD", totalUnStaked.Format(4))
 	}
 
-	// totalUnStakedFromNodes, err := b.networkManager.GetTotalUnStakedFromNodes()
+	// totalUnStakedFromNodes, err := b.contract.GetTotalUnStakedFromNodes()
 	// if err == nil {
-	// 	fTotalUnStakedFromNodes, _ := totalUnStakedFromNodes.Float64()
-	// 	text += fmt.Sprintf("\n\r`Total unstaked from nodes:` %.4f eGLD", fTotalUnStakedFromNodes)
+	// 	text += fmt.Sprintf("\n\r`Total unstaked from nodes:` %s eGLD", totalUnStakedFromNodes.Format(4))
 	// }
 
-	// totalUnBondedFromNodes, err := b.networkManager.GetTotalUnBondedFromNodes()
+	// totalUnBondedFromNodes, err := b.contract.GetTotalUnBondedFromNodes()
 	// if err == nil {
-	// 	fTotalUnBondedFromNodes, _ := totalUnBondedFromNodes.Float64()
-	// 	text += fmt.Sprintf("\n\r`Total unbonded from nodes:` %.4f eGLD", fTotalUnBondedFromNodes)
+	// 	text += fmt.Sprintf("\n\r`Total unbonded from nodes:` %s eGLD", totalUnBondedFromNodes.Format(4))
 	// }
 
 	b.sendMessage(user.TgID, text)
+	b.sendAddressQRCode(user.TgID, "Contract address", contractAddress)
 }
 
 func (b *Bot) sendNodes(user *data.User) {
-	if utils.ContractAddress == "" {
+	contractAddress := b.contracts.ContractAddress()
+	if contractAddress == "" {
 		b.sendMessage(user.TgID, "⭕️ Contract Address not found")
 		return
 	}
 
-	list, err := b.networkManager.GetAllNodeStates()
+	nodes, err := b.nodes.GetNodes()
 	if err != nil {
 		b.sendMessage(user.TgID, "⭕️ Can not get all nodes states")
 		return
 	}
 
-	state := "unknown"
-	n := 0
-	for i := 0; i < len(list); i++ {
-		if !utils.IsValidNodeKey(hex.EncodeToString(list[i])) {
-			state = string(list[i])
-			continue
+	if len(nodes) == 0 {
+		b.sendMessage(user.TgID, "ℹ️ The contract has no nodes")
+		return
+	}
+
+	for i, node := range nodes {
+		key := node.HexKey()
+		title := fmt.Sprintf("Node %v", i+1)
+		if node.Name != "" {
+			title += " - " + node.Name
+		}
+		text := fmt.Sprintf("`%s`\n\r`Key:` %s\n\r`State:` %s", title, key, node.DSSCState)
+		if node.SCStatus != "" {
+			text += fmt.Sprintf("\n\r`Validator status:` %s", node.SCStatus)
+		}
+		if node.Jailed() {
+			text += "\n\r⚠️ `Jailed`"
+		}
+		if stats := node.Statistics; stats != nil {
+			text += fmt.Sprintf("\n\r`Rating:` %.2f\n\r`Shard:` %s", stats.Rating, shardName(stats.ShardID))
+			text += fmt.Sprintf("\n\r`Leader:` %v success / %v failure", stats.NumLeaderSuccess, stats.NumLeaderFailure)
+			text += fmt.Sprintf("\n\r`Validator:` %v success / %v failure", stats.NumValidatorSuccess, stats.NumValidatorFailure)
 		}
-		n++
-		key := hex.EncodeToString(list[i])
-		text := fmt.Sprintf("`Node %v`\n\r`Key:` %s\n\r`State:` %s", n, key, state)
-
-		stakeURL := fmt.Sprintf("%s/hook/transaction?receiver=%s&value=0&gasLimit=12000000&data=stakeNodes@%s&callbackUrl=none",
-			b.walletHook, utils.ContractAddress, key)
-		unStakeURL := fmt.Sprintf("%s/hook/transaction?receiver=%s&value=0&gasLimit=12000000&data=unStakeNodes@%s&callbackUrl=none",
-			b.walletHook, utils.ContractAddress, key)
-		unBondURL := fmt.Sprintf("%s/hook/transaction?receiver=%s&value=0&gasLimit=12000000&data=unBondNodes@%s&callbackUrl=none",
-			b.walletHook, utils.ContractAddress, key)
-		reStakeUnStakedURL := fmt.Sprintf("%s/hook/transaction?receiver=%s&value=0&gasLimit=120000000&data=reStakeUnStakedNodes@%s&callbackUrl=none",
-			b.walletHook, utils.ContractAddress, key)
-		unJailURL := fmt.Sprintf("%s/hook/transaction?receiver=%s&value=2500000000000000000&gasLimit=12000000&data=unJailNodes@%s&callbackUrl=none",
-			b.walletHook, utils.ContractAddress, key)
-		removeURL := fmt.Sprintf("%s/hook/transaction?receiver=%s&value=0&gasLimit=12000000&data=removeNodes@%s&callbackUrl=none",
-			b.walletHook, utils.ContractAddress, key)
 
-		msg := tgbotapi.NewMessage(user.TgID, text)
-		msg.ParseMode = tgbotapi.ModeMarkdown
-		msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
-			tgbotapi.NewInlineKeyboardRow(
-				tgbotapi.NewInlineKeyboardButtonURL("Stake", stakeURL),
-				tgbotapi.NewInlineKeyboardButtonURL("Unstake", unStakeURL),
-				tgbotapi.NewInlineKeyboardButtonURL("Unbond", unBondURL),
-			),
+		keyboard := tgbotapi.NewInlineKeyboardMarkup()
+		for j, action := range nodeActions {
+			if j%3 == 0 {
+				keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, tgbotapi.NewInlineKeyboardRow())
+			}
+			url := b.ownerHookURL(user.TgID, action.Description, action.Tx(contractAddress, node.BlsKey))
+			row := len(keyboard.InlineKeyboard) - 1
+			keyboard.InlineKeyboard[row] = append(keyboard.InlineKeyboard[row],
+				tgbotapi.NewInlineKeyboardButtonURL(action.Button, url))
+		}
+		keyboard.InlineKeyboard = append(keyboard.InlineKeyboard,
 			tgbotapi.NewInlineKeyboardRow(
-				tgbotapi.NewInlineKeyboardButtonURL("Restake", reStakeUnStakedURL),
-				tgbotapi.NewInlineKeyboardButtonURL("Unjail", unJailURL),
-				tgbotapi.NewInlineKeyboardButtonURL("Remove", removeURL),
+				tgbotapi.NewInlineKeyboardButtonData("✏️ Rename", ":RenameNode_"+key[:nodeKeyPrefixLength]),
+				tgbotapi.NewInlineKeyboardButtonData("📷 QR Codes", ":NodeQR_"+key[:nodeKeyPrefixLength]),
 			),
 		)
+
+		msg := tgbotapi.NewMessage(user.TgID, text)
+		msg.ParseMode = tgbotapi.ModeMarkdown
+		msg.ReplyMarkup = keyboard
 		b.tgBot.Send(msg)
 	}
 }
+
+// shardName - returns the printable name of a shard
+func shardName(shard uint32) string {
+	if shard == network.MetachainShardID {
+		return "metachain"
+	}
+
+	return fmt.Sprintf("%v", shard)
+}
@@ -0,0 +1,616 @@
+package bot
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/DrDelphi/ElrondDSSC/data"
+	"github.com/DrDelphi/ElrondDSSC/db"
+	"github.com/DrDelphi/ElrondDSSC/network"
+	"github.com/DrDelphi/ElrondDSSC/network/networktest"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+)
+
+const (
+	testOwnerTgID    = 1
+	testUserTgID     = 2
+	testOwnerAddress = "erd1qyu5wthldzr8wx5c9ucg8kjagg0jfs53s8nr3zpz3hypefsdd8ssycr6th"
+	testContract     = "erd1qqqqqqqqqqqqqqqpqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqhllllsajxzat"
+	testWalletA      = "erd1spyavw0956vq68xj8y4tenjpq2wd5a9p2c6j8gsz7ztyrnpxrruqzu66jx"
+	testWalletB      = "erd1k2s324ww2g0yj38qn2ch2jwctdy8mnfxep94q9arncc6xecg3xaq6mjse8"
+)
+
+// sentMessage - a text message or a photo sent by the bot
+type sentMessage struct {
+	chatID   int64
+	text     string // the caption of a photo
+	photo    bool
+	keyboard *tgbotapi.InlineKeyboardMarkup
+}
+
+// buttons - returns the texts of the message's inline buttons
+func (m *sentMessage) buttons() []string {
+	texts := make([]string, 0)
+	if m.keyboard == nil {
+		return texts
+	}
+	for _, row := range m.keyboard.InlineKeyboard {
+		for _, button := range row {
+			texts = append(texts, button.Text)
+		}
+	}
+
+	return texts
+}
+
+// fakeTelegram - records the messages sent by the bot instead of calling the Telegram Bot API
+type fakeTelegram struct {
+	mut  sync.Mutex
+	sent []*sentMessage
+}
+
+func (ft *fakeTelegram) Send(c tgbotapi.Chattable) (tgbotapi.Message, error) {
+	ft.mut.Lock()
+	defer ft.mut.Unlock()
+
+	switch msg := c.(type) {
+	case tgbotapi.MessageConfig:
+		m := &sentMessage{chatID: msg.ChatID, text: msg.Text}
+		if keyboard, ok := msg.ReplyMarkup.(tgbotapi.InlineKeyboardMarkup); ok {
+			m.keyboard = &keyboard
+		}
+		ft.sent = append(ft.sent, m)
+	case tgbotapi.PhotoConfig:
+		m := &sentMessage{chatID: msg.ChatID, text: msg.Caption, photo: true}
+		if keyboard, ok := msg.ReplyMarkup.(*tgbotapi.InlineKeyboardMarkup); ok {
+			m.keyboard = keyboard
+		}
+		ft.sent = append(ft.sent, m)
+	default:
+		return tgbotapi.Message{}, errors.New("unexpected message type")
+	}
+
+	return tgbotapi.Message{MessageID: len(ft.sent)}, nil
+}
+
+func (ft *fakeTelegram) AnswerCallbackQuery(_ tgbotapi.CallbackConfig) (tgbotapi.APIResponse, error) {
+	return tgbotapi.APIResponse{Ok: true}, nil
+}
+
+func (ft *fakeTelegram) DeleteMessage(_ tgbotapi.DeleteMessageConfig) (tgbotapi.APIResponse, error) {
+	return tgbotapi.APIResponse{Ok: true}, nil
+}
+
+func (ft *fakeTelegram) GetUpdatesChan(_ tgbotapi.UpdateConfig) (tgbotapi.UpdatesChannel, error) {
+	return make(chan tgbotapi.Update), nil
+}
+
+func (ft *fakeTelegram) GetFileDirectURL(_ string) (string, error) {
+	return "", errors.New("no files in tests")
+}
+
+// messages - returns the messages sent so far and forgets them
+func (ft *fakeTelegram) messages() []*sentMessage {
+	ft.mut.Lock()
+	defer ft.mut.Unlock()
+
+	sent := ft.sent
+	ft.sent = nil
+
+	return sent
+}
+
+// fakeContracts - a contract registry with a fixed address
+type fakeContracts struct {
+	address string
+}
+
+func (fc *fakeContracts) ContractAddress() string {
+	return fc.address
+}
+
+func (fc *fakeContracts) Start() {
+}
+
+func (fc *fakeContracts) Resolve() (string, error) {
+	return fc.address, nil
+}
+
+func (fc *fakeContracts) SetContractAddress(address string) error {
+	fc.address = address
+	return nil
+}
+
+func (fc *fakeContracts) OnChange(_ func(address string)) {
+}
+
+// fakeAPR - returns a fixed APR estimate
+type fakeAPR struct {
+	estimate *data.APREstimate
+	err      error
+}
+
+func (fa *fakeAPR) Estimate() (*data.APREstimate, error) {
+	return fa.estimate, fa.err
+}
+
+// fakeSnapshots - returns fixed recent rewards and no history
+type fakeSnapshots struct {
+	rewards []*data.RewardsEarned
+}
+
+func (fs *fakeSnapshots) Start() {
+}
+
+func (fs *fakeSnapshots) RecentRewards(_ string) ([]*data.RewardsEarned, error) {
+	return fs.rewards, nil
+}
+
+func (fs *fakeSnapshots) History(_ string, _ int) ([]*data.StakeSnapshot, error) {
+	return make([]*data.StakeSnapshot, 0), nil
+}
+
+// fakeVault - keeps the owner's private key in memory
+type fakeVault struct {
+	key []byte
+}
+
+func (fv *fakeVault) HasKey() bool {
+	return len(fv.key) > 0
+}
+
+func (fv *fakeVault) Store(privateKey []byte) error {
+	fv.key = append([]byte(nil), privateKey...)
+	return nil
+}
+
+func (fv *fakeVault) Unlock() ([]byte, error) {
+	return append([]byte(nil), fv.key...), nil
+}
+
+func (fv *fakeVault) Wipe() error {
+	fv.key = nil
+	return nil
+}
+
+// failingTracker - a transaction tracker which can not save the transactions to follow
+type failingTracker struct {
+	TxTracker
+}
+
+func (ft failingTracker) Track(_ string, _ int64, _ string) error {
+	return errors.New("database is locked")
+}
+
+// testBot - a bot working with in-memory fakes of Telegram and of the network, and with an SQLite database
+type testBot struct {
+	*Bot
+	telegram  *fakeTelegram
+	network   *networktest.Fake
+	database  db.Store
+	contracts *fakeContracts
+	apr       *fakeAPR
+	snapshots *fakeSnapshots
+	vault     *fakeVault
+}
+
+func newTestBot(t *testing.T) *testBot {
+	database, err := db.NewDatabase(db.DriverSQLite, filepath.Join(t.TempDir(), "bot.sqlite"))
+	if err != nil {
+		t.Fatalf("can not open database: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = database.Close()
+	})
+
+	return newTestBotOn(database)
+}
+
+// newTestBotOn - creates a test bot working with an existing database, like a restarted bot would
+func newTestBotOn(database db.Store) *testBot {
+	tb := &testBot{
+		telegram:  &fakeTelegram{},
+		network:   networktest.NewFake(),
+		database:  database,
+		contracts: &fakeContracts{address: testContract},
+		apr:       &fakeAPR{err: errors.New("no economics")},
+		snapshots: &fakeSnapshots{},
+		vault:     &fakeVault{},
+	}
+	clock := network.NewNetworkClock(tb.network, &data.NetworkConfig{}, network.MetachainShardID)
+
+	cfg := &data.AppConfig{
+		BotOwner:    testOwnerTgID,
+		WalletHook:  "https://wallet.elrond.com",
+		ExplorerURL: "https://explorer.elrond.com",
+	}
+	tb.Bot = NewBot(cfg, &Dependencies{
+		Telegram:     tb.telegram,
+		Database:     database,
+		Contract:     tb.network,
+		Accounts:     tb.network,
+		Transactions: tb.network,
+		Simulator:    tb.network,
+		TxTracker:    network.NewTxTracker(tb.network, database),
+		Contracts:    tb.contracts,
+		Clock:        clock,
+		Nodes:        network.NewNodesProvider(tb.network, tb.network, tb.contracts, database),
+		APR:          tb.apr,
+		Snapshots:    tb.snapshots,
+		Vault:        tb.vault,
+	})
+
+	return tb
+}
+
+// addUser - registers a Telegram user with the given wallets, the verified ones being marked as such
+func (tb *testBot) addUser(t *testing.T, tgID int, wallets map[string]bool, order ...string) *data.User {
+	err := tb.database.AddUser(&tgbotapi.User{ID: tgID, UserName: "user"})
+	if err != nil {
+		t.Fatalf("AddUser: %v", err)
+	}
+	user := tb.database.GetUserByTgID(int64(tgID))
+	for _, address := range order {
+		err = tb.database.AddUserWallet(user, address)
+		if err != nil {
+			t.Fatalf("AddUserWallet: %v", err)
+		}
+		wallet := user.Wallets[len(user.Wallets)-1]
+		if wallets[address] {
+			err = tb.database.SetWalletVerified(wallet)
+			if err != nil {
+				t.Fatalf("SetWalletVerified: %v", err)
+			}
+		}
+	}
+
+	return tb.database.GetUserByTgID(int64(tgID))
+}
+
+func (tb *testBot) setOwner(t *testing.T) {
+	err := tb.database.SetOwnerAddress(testOwnerAddress)
+	if err != nil {
+		t.Fatalf("SetOwnerAddress: %v", err)
+	}
+}
+
+func requireTexts(t *testing.T, messages []*sentMessage, want ...string) {
+	t.Helper()
+
+	if len(messages) != len(want) {
+		texts := make([]string, 0, len(messages))
+		for _, m := range messages {
+			texts = append(texts, m.text)
+		}
+		t.Fatalf("expected %v messages, got %v: %q", len(want), len(messages), texts)
+	}
+	for i, m := range messages {
+		if !strings.Contains(m.text, want[i]) {
+			t.Errorf("message %v: expected %q in %q", i, want[i], m.text)
+		}
+	}
+}
+
+func requireContains(t *testing.T, text string, parts ...string) {
+	t.Helper()
+
+	for _, part := range parts {
+		if !strings.Contains(text, part) {
+			t.Errorf("expected %q in %q", part, text)
+		}
+	}
+}
+
+func requireMissing(t *testing.T, text string, parts ...string) {
+	t.Helper()
+
+	for _, part := range parts {
+		if strings.Contains(text, part) {
+			t.Errorf("unexpected %q in %q", part, text)
+		}
+	}
+}
+
+func TestSendBalancesWithoutWallets(t *testing.T) {
+	tb := newTestBot(t)
+	tb.setOwner(t)
+	user := tb.addUser(t, testUserTgID, nil)
+
+	tb.sendBalances(user)
+	requireTexts(t, tb.telegram.messages(), "Balances", "No wallets added")
+}
+
+func TestSendBalancesWithoutOwner(t *testing.T) {
+	tb := newTestBot(t)
+	user := tb.addUser(t, testUserTgID, nil, testWalletA)
+
+	tb.sendBalances(user)
+	requireTexts(t, tb.telegram.messages(), "Balances", "The owner didn't set up the DSSC yet")
+}
+
+func TestSendBalances(t *testing.T) {
+	tb := newTestBot(t)
+	tb.setOwner(t)
+	user := tb.addUser(t, testUserTgID, map[string]bool{testWalletA: true}, testWalletA, testWalletB)
+
+	tb.network.SetAccount(testWalletA, data.MustParseAmount("12.5"), 3)
+	tb.network.SetAccount(testWalletB, data.MustParseAmount("7"), 1)
+	stake := &networktest.UserState{
+		ActiveStake:      data.MustParseAmount("1000"),
+		UnStakedValue:    data.NewAmount(nil),
+		UnBondable:       data.MustParseAmount("10"),
+		ClaimableRewards: data.MustParseAmount("1.25"),
+	}
+	tb.network.SetUser(testWalletA, stake)
+	tb.network.SetUser(testWalletB, stake)
+
+	tb.sendBalances(user)
+	messages := tb.telegram.messages()
+	requireTexts(t, messages, "Balances", "Wallet 1/2", "Wallet 2/2")
+
+	verified := messages[1]
+	requireContains(t, verified.text, testWalletA, "🔐 verified", "`Balance:` 12.5000 eGLD", "`Delegated:` 1000.0000 eGLD",
+		"`Can withdraw:` 10.0000 eGLD", "`Claimable rewards:` 1.2500 eGLD")
+	if got := strings.Join(verified.buttons(), ","); got != "📆 Stake History,📷 Deposit QR,🗑 Remove" {
+		t.Errorf("unexpected buttons of the verified wallet: %v", got)
+	}
+
+	// the delegation details of unverified wallets are not shown
+	unverified := messages[2]
+	requireContains(t, unverified.text, testWalletB, "⚠️ not verified", "`Balance:` 7.0000 eGLD", "Verify the wallet")
+	requireMissing(t, unverified.text, "Delegated", "Claimable")
+	if got := strings.Join(unverified.buttons(), ","); got != "🔐 Verify,📷 Deposit QR,🗑 Remove" {
+		t.Errorf("unexpected buttons of the unverified wallet: %v", got)
+	}
+}
+
+func TestSendBalancesAccountError(t *testing.T) {
+	tb := newTestBot(t)
+	tb.setOwner(t)
+	user := tb.addUser(t, testUserTgID, nil, testWalletA)
+	tb.network.SetError("GetAccount", errors.New("proxy down"))
+
+	tb.sendBalances(user)
+	messages := tb.telegram.messages()
+	requireTexts(t, messages, "Balances", "Wallet 1/1")
+	requireContains(t, messages[1].text, "❌ Balance error")
+}
+
+func TestSendBalancesRecentRewards(t *testing.T) {
+	tb := newTestBot(t)
+	tb.setOwner(t)
+	user := tb.addUser(t, testUserTgID, map[string]bool{testWalletA: true}, testWalletA)
+	tb.network.SetAccount(testWalletA, data.MustParseAmount("1"), 0)
+	tb.snapshots.rewards = []*data.RewardsEarned{
+		{Period: network.RewardsPeriods[0], Covered: network.RewardsPeriods[0], Earned: data.MustParseAmount("0.5")},
+		{Period: network.RewardsPeriods[1], Covered: network.RewardsPeriods[1], Earned: data.MustParseAmount("3.5")},
+		{Period: network.RewardsPeriods[2], Covered: network.RewardsPeriods[2], Earned: data.MustParseAmount("15")},
+	}
+
+	tb.sendBalances(user)
+	messages := tb.telegram.messages()
+	requireTexts(t, messages, "Balances", "Wallet 1/1")
+	requireContains(t, messages[1].text, "`Rewards 24h / 7d / 30d:` 0.5000 / 3.5000 / 15.0000 eGLD", "`Daily rewards:` ~0.5000 eGLD")
+}
+
+func testContractInfo() *data.ContractInfo {
+	return &data.ContractInfo{
+		OwnerAddress:         testOwnerAddress,
+		ServiceFee:           7.5,
+		MaxDelegationCap:     data.MustParseAmount("50000"),
+		InitialOwnerFunds:    data.MustParseAmount("1250"),
+		WithDelegationCap:    true,
+		ChangeableServiceFee: true,
+		UnBondPeriod:         14400 * 10,
+		CreatedNonce:         42,
+	}
+}
+
+func TestSendContractInfoWithoutContract(t *testing.T) {
+	tb := newTestBot(t)
+	tb.setOwner(t)
+	tb.contracts.address = ""
+	user := tb.addUser(t, testUserTgID, nil)
+
+	tb.sendContractInfo(user)
+	requireTexts(t, tb.telegram.messages(), "Contract Info", "Contract Address not found")
+}
+
+func TestSendContractInfo(t *testing.T) {
+	tb := newTestBot(t)
+	tb.setOwner(t)
+	tb.network.SetContractInfo(testContract, testContractInfo())
+	tb.network.SetContractMetadata(testContract, &data.ContractMetadata{Name: "Staking Provider",
+		Website: "https://provider.example", Identifier: "provider"})
+	tb.network.SetTotals(networktest.Totals{NumUsers: 120, NumNodes: 4,
+		TotalActiveStake: data.MustParseAmount("10000")})
+	tb.apr.estimate, tb.apr.err = &data.APREstimate{GrossAPR: 16, NetAPR: 14.8, StakedNodes: 4}, nil
+	user := tb.addUser(t, testUserTgID, nil)
+
+	tb.sendContractInfo(user)
+	messages := tb.telegram.messages()
+	requireTexts(t, messages, "Contract Info", testContract, "Contract address")
+
+	info := messages[1].text
+	requireContains(t, info, "`Service fee:` 7.50% (changeable)", "`Max delegation cap:` 50000 eGLD",
+		"`Initial owner funds:` 1250 eGLD", "`Unbond period:` 10d 0h 00m", "`Created at nonce:` 42",
+		"`Estimated APR:` 14.80% (16.00% before the service fee)", "`Provider:` Staking Provider",
+		"`Website:` https://provider.example", "`Keybase:` https://keybase.io/provider")
+	// the contract totals are only shown to the owner
+	requireMissing(t, info, "Delegators", "Total active stake")
+	if !messages[2].photo {
+		t.Errorf("expected the contract address QR code")
+	}
+}
+
+func TestSendContractInfoToOwner(t *testing.T) {
+	tb := newTestBot(t)
+	tb.setOwner(t)
+	info := testContractInfo()
+	info.OwnerAddress = testWalletA
+	tb.network.SetContractInfo(testContract, info)
+	tb.network.SetTotals(networktest.Totals{NumUsers: 120, NumNodes: 4,
+		TotalActiveStake:      data.MustParseAmount("10000"),
+		TotalCumulatedRewards: data.MustParseAmount("321.5"),
+		TotalUnStaked:         data.MustParseAmount("20")})
+	owner := tb.addUser(t, testOwnerTgID, nil)
+
+	tb.sendContractInfo(owner)
+	messages := tb.telegram.messages()
+	requireTexts(t, messages, "Contract Info", "is owned by "+testWalletA, testContract, "Contract address")
+
+	text := messages[2].text
+	requireContains(t, text, "`Nodes:` 4", "`Delegators:` 120", "`Total active stake:` 10000.0000 eGLD",
+		"`Total cumulated rewards:` 321.5000 eGLD", "`Total unstaked:` 20.0000 eGLD")
+	requireMissing(t, text, "Estimated APR")
+}
+
+func TestSendNodes(t *testing.T) {
+	tb := newTestBot(t)
+	tb.setOwner(t)
+	keyA := []byte(strings.Repeat("a", 96))
+	keyB := []byte(strings.Repeat("b", 96))
+	tb.network.AddNode("staked", keyA)
+	tb.network.AddNode("notStaked", keyB)
+	tb.network.SetBlsKeyStatus(keyA, "jailed")
+	tb.network.SetValidatorStatistics(keyA, &data.ValidatorStatistics{Rating: 87.5, ShardID: network.MetachainShardID,
+		NumLeaderSuccess: 3, NumValidatorSuccess: 120, NumValidatorFailure: 2})
+	nodeA := &data.NodeInfo{BlsKey: keyA}
+	err := tb.database.SetNodeName(nodeA.HexKey(), "Main")
+	if err != nil {
+		t.Fatalf("SetNodeName: %v", err)
+	}
+	owner := tb.addUser(t, testOwnerTgID, nil)
+
+	tb.sendNodes(owner)
+	messages := tb.telegram.messages()
+	requireTexts(t, messages, "Node 1 - Main", "Node 2")
+
+	requireContains(t, messages[0].text, nodeA.HexKey(), "`State:` staked", "`Validator status:` jailed",
+		"⚠️ `Jailed`", "`Rating:` 87.50", "`Shard:` metachain", "`Leader:` 3 success / 0 failure",
+		"`Validator:` 120 success / 2 failure")
+	requireMissing(t, messages[1].text, "Jailed", "Rating")
+
+	want := "Stake,Unstake,Unbond,Restake,Unjail,Remove,✏️ Rename,📷 QR Codes"
+	for i, m := range messages {
+		if got := strings.Join(m.buttons(), ","); got != want {
+			t.Errorf("node %v: unexpected buttons %v", i+1, got)
+		}
+		link := m.keyboard.InlineKeyboard[0][0].URL
+		if link == nil || !strings.HasPrefix(*link, "https://wallet.elrond.com/hook/transaction?") ||
+			!strings.Contains(*link, "receiver="+testContract) {
+			t.Errorf("node %v: unexpected hook link %v", i+1, *link)
+		}
+	}
+}
+
+func TestSendNodesErrors(t *testing.T) {
+	tb := newTestBot(t)
+	owner := tb.addUser(t, testOwnerTgID, nil)
+
+	tb.sendNodes(owner)
+	requireTexts(t, tb.telegram.messages(), "The contract has no nodes")
+
+	tb.network.SetError("GetAllNodeStates", errors.New("vm query failed"))
+	tb.sendNodes(owner)
+	requireTexts(t, tb.telegram.messages(), "Can not get all nodes states")
+
+	tb.contracts.address = ""
+	tb.sendNodes(owner)
+	requireTexts(t, tb.telegram.messages(), "Contract Address not found")
+}
+
+func TestSendSimulatedTxSkipsUnverifiedWallets(t *testing.T) {
+	tb := newTestBot(t)
+	user := tb.addUser(t, testUserTgID, map[string]bool{testWalletA: false}, testWalletA)
+	tb.network.SetSimulation("delegate", &data.TxSimulation{Error: "insufficient funds"})
+
+	req := &network.TxRequest{Receiver: testContract, Value: data.MustParseAmount("10"), Function: "delegate"}
+	tb.sendSimulatedTx(user, "Delegate", req, "10 eGLD")
+	messages := tb.telegram.messages()
+	requireTexts(t, messages, "Delegate", "Delegate")
+
+	requireContains(t, messages[0].text, shortAddress(testWalletA)+": verify the wallet from Balances")
+	requireMissing(t, messages[0].text, "will fail", "insufficient funds", "estimated fee", "no link is provided")
+	if got := strings.Join(messages[0].buttons(), ","); got != "10 eGLD" {
+		t.Errorf("unexpected buttons %v", got)
+	}
+}
+
+func TestSendSimulatedTxVerifiedWallets(t *testing.T) {
+	tb := newTestBot(t)
+	user := tb.addUser(t, testUserTgID, map[string]bool{testWalletA: true}, testWalletA, testWalletB)
+	tb.network.SetSimulation("delegate", &data.TxSimulation{Error: "insufficient funds"})
+
+	req := &network.TxRequest{Receiver: testContract, Value: data.MustParseAmount("10"), Function: "delegate"}
+	tb.sendSimulatedTx(user, "Delegate", req, "10 eGLD")
+	messages := tb.telegram.messages()
+	requireTexts(t, messages, "Delegate")
+
+	requireContains(t, messages[0].text, shortAddress(testWalletA)+": will fail - insufficient funds",
+		shortAddress(testWalletB)+": verify the wallet from Balances", "no link is provided")
+	requireMissing(t, messages[0].text, shortAddress(testWalletB)+": will fail")
+	if len(messages[0].buttons()) != 0 {
+		t.Errorf("unexpected buttons %v", messages[0].buttons())
+	}
+}
+
+func TestHookCallback(t *testing.T) {
+	tb := newTestBot(t)
+	hash := strings.Repeat("ab", txHashLength)
+	intent := &data.HookIntent{TgID: testUserTgID, Description: "Delegate", Receiver: testContract}
+
+	tb.hookCallback(&data.HookCallback{Intent: intent, Status: network.HookStatusUnverified, TxHash: hash})
+	requireTexts(t, tb.telegram.messages(), "does not match the link")
+	if txs, _ := tb.database.GetPendingTxs(); len(txs) != 0 {
+		t.Fatalf("unverified transaction followed: %v", txs)
+	}
+
+	tb.hookCallback(&data.HookCallback{Intent: intent, Status: network.HookStatusCancelled, TxHash: hash})
+	requireTexts(t, tb.telegram.messages(), "was not sent from the wallet")
+
+	tb.hookCallback(&data.HookCallback{Intent: intent, Status: network.HookStatusSuccess, TxHash: hash,
+		Sender: testWalletA})
+	messages := tb.telegram.messages()
+	requireTexts(t, messages, "Delegate transaction signed and sent from "+shortAddress(testWalletA))
+	requireContains(t, messages[0].text, hash)
+	if txs, _ := tb.database.GetPendingTxs(); len(txs) != 1 || txs[0].Hash != hash {
+		t.Errorf("expected the transaction followed, got %v", txs)
+	}
+
+	// a transaction which can not be followed is reported to the owner
+	_ = tb.database.Close()
+	tb.hookCallback(&data.HookCallback{Intent: intent, Status: network.HookStatusSuccess, TxHash: hash,
+		Sender: testWalletA})
+	messages = tb.telegram.messages()
+	requireTexts(t, messages, "Delegate transaction signed and sent", "Can not follow the Delegate transaction "+hash)
+	if messages[1].chatID != testOwnerTgID {
+		t.Errorf("the tracking error was sent to %v, want the owner", messages[1].chatID)
+	}
+}
+
+func TestCreateDSSCCallback(t *testing.T) {
+	tb := newTestBot(t)
+	tb.addUser(t, testOwnerTgID, nil)
+	tb.contracts.address = ""
+	err := tb.vault.Store(bytes.Repeat([]byte{7}, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	callback := &tgbotapi.CallbackQuery{ID: "1", From: &tgbotapi.User{ID: testOwnerTgID}, Data: "CreateDSSC"}
+
+	tb.callbackQueryReceived(callback)
+	requireTexts(t, tb.telegram.messages(), "Create DSSC transaction sent")
+	if txs, _ := tb.database.GetPendingTxs(); len(txs) != 1 || txs[0].Description != "Create DSSC" {
+		t.Errorf("expected the transaction followed, got %v", txs)
+	}
+
+	// a transaction which can not be followed is reported
+	tb.txTracker = failingTracker{tb.txTracker}
+	tb.callbackQueryReceived(callback)
+	requireTexts(t, tb.telegram.messages(), "Create DSSC transaction sent", "Can not follow the Create DSSC transaction")
+}
@@ -1,10 +1,13 @@
 package bot
 
 import (
+	"fmt"
 	"strconv"
 	"strings"
 
+	"github.com/DrDelphi/ElrondDSSC/network"
 	"github.com/DrDelphi/ElrondDSSC/utils"
+	"github.com/DrDelphi/ElrondDSSC/vault"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
 )
 
@@ -37,18 +40,17 @@ func (b *Bot) callbackQueryReceived(cb *tgbotapi.CallbackQuery) {
 	}
 
 	if cb.Data == "AddWallet" {
-		msg := tgbotapi.NewMessage(int64(cb.From.ID), utils.AddWalletMessage)
-		msg.ReplyMarkup = tgbotapi.ForceReply{
-			ForceReply: true,
-			Selective:  false,
-		}
-		b.tgBot.Send(msg)
+		b.askFor(user, stateWalletAddress, "", utils.AddWalletMessage)
 	}
 
 	if cb.Data == "Balances" {
 		b.sendBalances(user)
 	}
 
+	if cb.Data == "RewardsHistory" {
+		b.sendRewardsHistory(user)
+	}
+
 	if cb.Data == "AdminMenu" && user.TgID == b.owner {
 		b.adminMenu(user)
 	}
@@ -62,35 +64,56 @@ func (b *Bot) callbackQueryReceived(cb *tgbotapi.CallbackQuery) {
 		if ownerAddress != "" {
 			b.sendMessage(user.TgID, "Old address: "+ownerAddress)
 		}
-		msg := tgbotapi.NewMessage(int64(cb.From.ID), utils.SetOwnerAddressMessage)
-		msg.ReplyMarkup = tgbotapi.ForceReply{
-			ForceReply: true,
-			Selective:  false,
+		b.askFor(user, stateOwnerAddress, "", utils.SetOwnerAddressMessage)
+	}
+
+	if cb.Data == "SetContractAddress" && user.TgID == b.owner {
+		contractAddress := b.contracts.ContractAddress()
+		if contractAddress != "" {
+			b.sendMessage(user.TgID, "Current address: "+contractAddress)
 		}
-		b.tgBot.Send(msg)
+		b.askFor(user, stateContractAddress, "", utils.SetContractAddressMessage)
 	}
 
 	if cb.Data == "CreateDSSC" && user.TgID == b.owner {
-		if utils.ContractAddress != "" {
+		if b.contracts.ContractAddress() != "" {
 			b.sendMessage(user.TgID, "⭕️ Contract already created")
 			return
 		}
 
-		privateKey := b.database.GetOwnerPrivateKey()
-		if privateKey == "" {
+		if !b.vault.HasKey() {
 			b.sendMessage(user.TgID, "⭕️ Owner private key not set. You have to create the contract manually")
 			return
 		}
 
-		txHash, err := b.networkManager.CreateDSSC(privateKey)
+		privateKey, err := b.vault.Unlock()
+		if err != nil {
+			b.sendMessage(user.TgID, "⭕️ Can not unlock the owner private key: "+err.Error())
+			return
+		}
+
+		txHash, err := b.transactions.CreateDSSC(privateKey)
+		vault.Zero(privateKey)
 		if err == nil {
 			b.sendMessage(user.TgID, "✅ Create DSSC transaction sent. Hash: "+txHash)
+			err = b.txTracker.Track(txHash, user.TgID, "Create DSSC")
+			if err != nil {
+				b.reportError(fmt.Sprintf("Can not follow the Create DSSC transaction %s. %s", txHash, err))
+			}
 		} else {
 			b.sendMessage(user.TgID, "⭕️ Failed to send create DSSC transaction: "+err.Error())
 			return
 		}
 	}
 
+	if cb.Data == "WipeOwnerKey" && user.TgID == b.owner {
+		b.confirmWipeOwnerKey(user)
+	}
+
+	if cb.Data == "WipeOwnerKeyConfirm" && user.TgID == b.owner {
+		b.wipeOwnerKey(user)
+	}
+
 	if cb.Data == "ContractInfo" {
 		b.sendContractInfo(user)
 	}
@@ -100,23 +123,19 @@ func (b *Bot) callbackQueryReceived(cb *tgbotapi.CallbackQuery) {
 	}
 
 	if cb.Data == "Delegate" {
-		text := utils.DelegateAmountMessage
-		msg := tgbotapi.NewMessage(user.TgID, text)
-		msg.ReplyMarkup = tgbotapi.ForceReply{
-			ForceReply: true,
-			Selective:  false,
-		}
-		b.tgBot.Send(msg)
+		b.askFor(user, stateDelegateAmount, "", utils.DelegateAmountMessage)
 	}
 
 	if cb.Data == "Undelegate" {
-		text := utils.UndelegateAmountMessage
-		msg := tgbotapi.NewMessage(user.TgID, text)
-		msg.ReplyMarkup = tgbotapi.ForceReply{
-			ForceReply: true,
-			Selective:  false,
-		}
-		b.tgBot.Send(msg)
+		b.askFor(user, stateUndelegateAmount, "", utils.UndelegateAmountMessage)
+	}
+
+	if cb.Data == "Activity" {
+		b.sendActivity(user, activityAll)
+	}
+
+	if cb.Data == "Providers" && user.TgID == b.owner {
+		b.sendProviders(user, network.ProviderSortFee)
 	}
 
 	if strings.HasPrefix(cb.Data, ":") {
@@ -145,6 +164,58 @@ func (b *Bot) callbackQueryReceived(cb *tgbotapi.CallbackQuery) {
 
 			b.sendMessage(user.TgID, "⭕️ Wallet not found")
 		}
+
+		if params[0] == "VerifyWallet" && len(params) == 2 {
+			id, _ := strconv.ParseUint(params[1], 10, 32)
+			b.sendWalletChallenge(user, id)
+		}
+
+		if params[0] == "StakeHistory" && len(params) == 2 {
+			id, _ := strconv.ParseUint(params[1], 10, 32)
+			b.sendStakeHistory(user, id)
+		}
+
+		if params[0] == "WalletQR" && len(params) == 2 {
+			id, _ := strconv.ParseUint(params[1], 10, 32)
+			b.sendWalletQRCode(user, id)
+		}
+
+		if params[0] == "TxQR" && len(params) == 2 {
+			b.sendDelegatorQRCode(user, params[1])
+		}
+
+		if params[0] == "NodeQR" && len(params) == 2 && user.TgID == b.owner {
+			b.sendNodeQRCodes(user, params[1])
+		}
+
+		if params[0] == "Activity" && len(params) == 2 {
+			b.sendActivity(user, params[1])
+		}
+
+		if params[0] == "Providers" && len(params) == 2 && user.TgID == b.owner {
+			b.sendProviders(user, params[1])
+		}
+
+		if params[0] == "RenameNode" && len(params) == 2 && user.TgID == b.owner {
+			nodes, err := b.nodes.GetNodes()
+			if err != nil {
+				b.sendMessage(user.TgID, "⭕️ Can not get all nodes states")
+				return
+			}
+
+			for _, node := range nodes {
+				key := node.HexKey()
+				if !strings.HasPrefix(key, params[1]) {
+					continue
+				}
+
+				b.askFor(user, stateNodeName, key, utils.NodeNameMessage+" "+key)
+
+				return
+			}
+
+			b.sendMessage(user.TgID, "⭕️ Node not found")
+		}
 	}
 
 	if cb.Data == "MyNodes" && user.TgID == b.owner {
@@ -152,37 +223,35 @@ func (b *Bot) callbackQueryReceived(cb *tgbotapi.CallbackQuery) {
 	}
 
 	if cb.Data == "AddNode" && user.TgID == b.owner {
-		if utils.ContractAddress == "" {
+		if b.contracts.ContractAddress() == "" {
 			b.sendMessage(user.TgID, "⭕️ Contract Address not found")
 			return
 		}
 
-		text := utils.AddNodeMessage
-		msg := tgbotapi.NewMessage(user.TgID, text)
-		msg.ReplyMarkup = tgbotapi.ForceReply{
-			ForceReply: true,
-			Selective:  false,
-		}
-		b.tgBot.Send(msg)
+		b.askFor(user, stateNodePEM, "", utils.AddNodeMessage)
 	}
 
 	if cb.Data == "ChangeServiceFee" && user.TgID == b.owner {
-		text := utils.ChangeServiceFeeMessage
-		msg := tgbotapi.NewMessage(user.TgID, text)
-		msg.ReplyMarkup = tgbotapi.ForceReply{
-			ForceReply: true,
-			Selective:  false,
+		b.askFor(user, stateServiceFee, "", utils.ChangeServiceFeeMessage)
+	}
+
+	if cb.Data == "SetMetadata" && user.TgID == b.owner {
+		contractAddress := b.contracts.ContractAddress()
+		if contractAddress == "" {
+			b.sendMessage(user.TgID, "⭕️ Contract Address not found")
+			return
 		}
-		b.tgBot.Send(msg)
+
+		metadata, err := b.contract.GetContractMetadata(contractAddress)
+		if err == nil && metadata.Name != "" {
+			current := fmt.Sprintf("Current metadata:\n%s\n%s\n%s", metadata.Name, metadata.Website, metadata.Identifier)
+			b.tgBot.Send(tgbotapi.NewMessage(user.TgID, current))
+		}
+
+		b.askFor(user, stateMetadata, "", utils.SetMetadataMessage)
 	}
 
 	if cb.Data == "ModifyDelegationCap" && user.TgID == b.owner {
-		text := utils.ModifyDelegationCapMessage
-		msg := tgbotapi.NewMessage(user.TgID, text)
-		msg.ReplyMarkup = tgbotapi.ForceReply{
-			ForceReply: true,
-			Selective:  false,
-		}
-		b.tgBot.Send(msg)
+		b.askFor(user, stateDelegationCap, "", utils.ModifyDelegationCapMessage)
 	}
 }
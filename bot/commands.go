@@ -2,7 +2,11 @@ package bot
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 
+	"github.com/DrDelphi/ElrondDSSC/data"
+	"github.com/DrDelphi/ElrondDSSC/network"
 	"github.com/DrDelphi/ElrondDSSC/utils"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
 )
@@ -32,4 +36,85 @@ func (b *Bot) privateCommandReceived(message *tgbotapi.Message) {
 	if cmd == "start" {
 		b.mainMenu(user)
 	}
+
+	if cmd == "cancel" {
+		b.cancelConversation(user)
+	}
+
+	if cmd == "calc" {
+		b.sendRewardsProjection(user, args)
+	}
+
+	if cmd == "wipekey" && user.TgID == b.owner {
+		b.confirmWipeOwnerKey(user)
+	}
+}
+
+// sendRewardsProjection - handles /calc <amount> <days>
+func (b *Bot) sendRewardsProjection(user *data.User, args string) {
+	fields := strings.Fields(args)
+	if len(fields) != 2 {
+		b.sendMessage(user.TgID, utils.CalcUsageMessage)
+		return
+	}
+
+	amount, err := data.ParseAmount(fields[0])
+	if err != nil || amount.IsZero() {
+		b.sendMessage(user.TgID, "⭕️ Invalid amount")
+		return
+	}
+
+	days, err := strconv.ParseUint(fields[1], 10, 32)
+	if err != nil || days == 0 {
+		b.sendMessage(user.TgID, "⭕️ Invalid number of days")
+		return
+	}
+
+	estimate, err := b.apr.Estimate()
+	if err != nil {
+		b.sendMessage(user.TgID, "⭕️ Can not estimate the APR right now")
+		return
+	}
+	if estimate.StakedNodes == 0 {
+		b.sendMessage(user.TgID, "ℹ️ The contract has no staked nodes, so it does not earn rewards yet")
+		return
+	}
+
+	simple := network.ProjectRewards(amount, estimate.NetAPR, days, false)
+	compounded := network.ProjectRewards(amount, estimate.NetAPR, days, true)
+	text := fmt.Sprintf("`Rewards for %s eGLD in %v days`", amount, days)
+	text += fmt.Sprintf("\n\r`Estimated APR:` %.2f%%", estimate.NetAPR)
+	text += fmt.Sprintf("\n\r`Claiming:` %s eGLD", simple.Format(4))
+	text += fmt.Sprintf("\n\r`Redelegating daily:` %s eGLD", compounded.Format(4))
+	text += "\n\r\n\r_The estimation assumes the current network economics, stake and service fee do not change_"
+	b.sendMessage(user.TgID, text)
+}
+
+// confirmWipeOwnerKey - asks the owner to confirm removing the stored private key
+func (b *Bot) confirmWipeOwnerKey(user *data.User) {
+	if !b.vault.HasKey() {
+		b.sendMessage(user.TgID, "ℹ️ No owner private key is stored")
+		return
+	}
+
+	msg := tgbotapi.NewMessage(user.TgID, "⚠️ Remove the owner private key from the database? Creating the "+
+		"contract from the bot will need the key to be set again")
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🧨 Wipe", "WipeOwnerKeyConfirm"),
+		),
+	)
+	b.tgBot.Send(msg)
+}
+
+// wipeOwnerKey - removes the stored owner private key
+func (b *Bot) wipeOwnerKey(user *data.User) {
+	err := b.vault.Wipe()
+	if err != nil {
+		b.sendMessage(user.TgID, "⭕️ Error removing the owner private key: "+err.Error())
+		return
+	}
+
+	log.Info("owner private key wiped")
+	b.sendMessage(user.TgID, "✅ Owner private key removed")
 }
@@ -0,0 +1,198 @@
+package bot
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/DrDelphi/ElrondDSSC/data"
+	"github.com/DrDelphi/ElrondDSSC/utils"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+)
+
+// conversation states, saved in the database while the bot waits for a user's answer
+const (
+	stateOwnerAddress     = "OwnerAddress"
+	stateContractAddress  = "ContractAddress"
+	stateWalletAddress    = "WalletAddress"
+	stateWalletSignature  = "WalletSignature" // the param is the wallet's address
+	stateDelegateAmount   = "DelegateAmount"
+	stateUndelegateAmount = "UndelegateAmount"
+	stateServiceFee       = "ServiceFee"
+	stateDelegationCap    = "DelegationCap"
+	stateMetadata         = "Metadata"
+	stateNodeName         = "NodeName" // the param is the node's BLS key
+	stateNodePEM          = "NodePEM"
+)
+
+// conversationTimeout - how long the bot waits for an answer by default
+const conversationTimeout = 10 * time.Minute
+
+// conversationStep - handles the answer expected in a conversation state. The handler returns true
+// once the answer was accepted, otherwise the user can send it again
+type conversationStep struct {
+	ownerOnly bool
+	withFile  bool // a document is accepted as the answer
+	timeout   time.Duration
+	handle    func(b *Bot, message *tgbotapi.Message, user *data.User, param string, fileName string) bool
+}
+
+var conversationSteps = map[string]*conversationStep{
+	stateOwnerAddress: {ownerOnly: true, withFile: true,
+		handle: func(b *Bot, message *tgbotapi.Message, user *data.User, _ string, fileName string) bool {
+			return b.setOwnerAddress(message, user, fileName)
+		}},
+	stateContractAddress: {ownerOnly: true,
+		handle: func(b *Bot, message *tgbotapi.Message, user *data.User, _ string, _ string) bool {
+			return b.setContractAddress(message, user)
+		}},
+	stateWalletAddress: {
+		handle: func(b *Bot, message *tgbotapi.Message, user *data.User, _ string, _ string) bool {
+			return b.addWallet(message, user)
+		}},
+	stateWalletSignature: {timeout: walletChallengeMaxAge,
+		handle: func(b *Bot, message *tgbotapi.Message, user *data.User, address string, _ string) bool {
+			return b.verifyWallet(message, user, address)
+		}},
+	stateDelegateAmount: {
+		handle: func(b *Bot, message *tgbotapi.Message, user *data.User, _ string, _ string) bool {
+			return b.delegate(message, user)
+		}},
+	stateUndelegateAmount: {
+		handle: func(b *Bot, message *tgbotapi.Message, user *data.User, _ string, _ string) bool {
+			return b.unDelegate(message, user)
+		}},
+	stateServiceFee: {ownerOnly: true,
+		handle: func(b *Bot, message *tgbotapi.Message, user *data.User, _ string, _ string) bool {
+			return b.changeServiceFee(message, user)
+		}},
+	stateDelegationCap: {ownerOnly: true,
+		handle: func(b *Bot, message *tgbotapi.Message, user *data.User, _ string, _ string) bool {
+			return b.modifyDelegationCap(message, user)
+		}},
+	stateMetadata: {ownerOnly: true,
+		handle: func(b *Bot, message *tgbotapi.Message, user *data.User, _ string, _ string) bool {
+			return b.setMetadata(message, user)
+		}},
+	stateNodeName: {ownerOnly: true,
+		handle: func(b *Bot, message *tgbotapi.Message, user *data.User, key string, _ string) bool {
+			return b.setNodeName(message, user, key)
+		}},
+	stateNodePEM: {ownerOnly: true, withFile: true,
+		handle: func(b *Bot, message *tgbotapi.Message, user *data.User, _ string, fileName string) bool {
+			return b.addNode(message, user, fileName)
+		}},
+}
+
+// askFor - starts a conversation in the given state and sends its prompt. The answer can be a reply to
+// the prompt or any message sent before the conversation expires
+func (b *Bot) askFor(user *data.User, state string, param string, prompt string) {
+	timeout := conversationTimeout
+	if step, ok := conversationSteps[state]; ok && step.timeout > 0 {
+		timeout = step.timeout
+	}
+
+	err := b.database.SetConversation(&data.Conversation{
+		TgID:      user.TgID,
+		State:     state,
+		Param:     param,
+		ExpiresAt: time.Now().Add(timeout).Unix(),
+	})
+	if err != nil {
+		b.sendMessage(user.TgID, "⭕️ Error saving the conversation in database")
+		return
+	}
+
+	msg := tgbotapi.NewMessage(user.TgID, prompt)
+	msg.ReplyMarkup = tgbotapi.ForceReply{
+		ForceReply: true,
+		Selective:  false,
+	}
+	b.tgBot.Send(msg)
+}
+
+// cancelConversation - handles /cancel
+func (b *Bot) cancelConversation(user *data.User) {
+	conversation, err := b.database.GetConversation(user.TgID)
+	if err != nil {
+		b.sendMessage(user.TgID, "⭕️ Error reading the conversation from database")
+		return
+	}
+	if conversation == nil {
+		b.sendMessage(user.TgID, "ℹ️ Nothing to cancel")
+		return
+	}
+
+	err = b.database.RemoveConversation(user.TgID)
+	if err != nil {
+		b.sendMessage(user.TgID, "⭕️ Error removing the conversation from database")
+		return
+	}
+
+	b.sendMessage(user.TgID, "❎ Cancelled")
+}
+
+// privateMessageReceived - handles a text or a document sent in a private chat, as the answer to the
+// conversation the bot has with the user
+func (b *Bot) privateMessageReceived(message *tgbotapi.Message) {
+	user := b.database.GetUserByTgID(int64(message.From.ID))
+	name := utils.FormatTgUser(message.From)
+	if user == nil {
+		log.Warn("message received from unknown user", "user", name)
+		return
+	}
+
+	conversation, err := b.database.GetConversation(user.TgID)
+	if err != nil {
+		b.sendMessage(user.TgID, "⭕️ Error reading the conversation from database")
+		return
+	}
+	if conversation == nil {
+		b.sendMessage(user.TgID, "ℹ️ Pick an action from the menu first, /start shows it")
+		return
+	}
+
+	log.Info("answer received", "state", conversation.State, "user", name)
+
+	step, ok := conversationSteps[conversation.State]
+	if !ok || (step.ownerOnly && user.TgID != b.owner) {
+		log.Warn("unknown conversation state", "state", conversation.State, "user", name)
+		_ = b.database.RemoveConversation(user.TgID)
+		return
+	}
+	if time.Now().Unix() > conversation.ExpiresAt {
+		_ = b.database.RemoveConversation(user.TgID)
+		b.sendMessage(user.TgID, "⌛️ The request expired, start it again from the menu")
+		return
+	}
+
+	fileName := ""
+	if message.Document != nil {
+		if !step.withFile {
+			b.sendMessage(user.TgID, "⭕️ Send the answer as a text message, or /cancel")
+			return
+		}
+
+		fileName, err = b.downloadFile(message)
+		if err != nil {
+			log.Error("error downloading file", "file", message.Document.FileName, "user", name, "error", err)
+			b.reportError("error downloading file: " + err.Error())
+			b.sendMessage(user.TgID, "⭕️ Can not download the file")
+			return
+		}
+
+		// the key files must not outlive the request on disk
+		defer func() {
+			log.LogIfError(os.Remove(fileName))
+		}()
+	}
+
+	if !step.handle(b, message, user, conversation.Param, fileName) {
+		return
+	}
+
+	err = b.database.RemoveConversation(user.TgID)
+	if err != nil {
+		b.reportError(fmt.Sprintf("Can not end the conversation with '%s'. %s", name, err))
+	}
+}
@@ -0,0 +1,240 @@
+package bot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DrDelphi/ElrondDSSC/data"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+)
+
+// textMessage - a private text message, not replying to any prompt
+func textMessage(tgID int, text string) *tgbotapi.Message {
+	return &tgbotapi.Message{
+		From: &tgbotapi.User{ID: tgID, UserName: "user"},
+		Chat: &tgbotapi.Chat{ID: int64(tgID), Type: "private"},
+		Text: text,
+	}
+}
+
+// commandMessage - a private command message, such as /cancel
+func commandMessage(tgID int, command string) *tgbotapi.Message {
+	message := textMessage(tgID, "/"+command)
+	message.Entities = &[]tgbotapi.MessageEntity{{Type: "bot_command", Offset: 0, Length: len(command) + 1}}
+
+	return message
+}
+
+// documentMessage - a private message with a file attached
+func documentMessage(tgID int, fileName string) *tgbotapi.Message {
+	message := textMessage(tgID, "")
+	message.Document = &tgbotapi.Document{FileID: "file", FileName: fileName}
+
+	return message
+}
+
+// conversation - returns the conversation saved for the Telegram user, or nil
+func (tb *testBot) conversation(t *testing.T, tgID int64) *data.Conversation {
+	t.Helper()
+
+	conversation, err := tb.database.GetConversation(tgID)
+	if err != nil {
+		t.Fatalf("GetConversation: %v", err)
+	}
+
+	return conversation
+}
+
+// startConversation - asks the user for an answer, discarding the prompt
+func (tb *testBot) startConversation(t *testing.T, user *data.User, state string, param string) {
+	t.Helper()
+
+	tb.askFor(user, state, param, "answer please")
+	requireTexts(t, tb.telegram.messages(), "answer please")
+	if tb.conversation(t, user.TgID) == nil {
+		t.Fatalf("conversation %s not saved", state)
+	}
+}
+
+func TestConversationFreeTextAnswer(t *testing.T) {
+	tb := newTestBot(t)
+	user := tb.addUser(t, testUserTgID, nil)
+	tb.startConversation(t, user, stateWalletAddress, "")
+
+	tb.privateMessageReceived(textMessage(testUserTgID, " "+testWalletA+" "))
+
+	requireTexts(t, tb.telegram.messages(), "Wallet added")
+	if tb.conversation(t, user.TgID) != nil {
+		t.Fatalf("the conversation did not end")
+	}
+	user = tb.database.GetUserByTgID(testUserTgID)
+	if len(user.Wallets) != 1 || user.Wallets[0].Address != testWalletA {
+		t.Fatalf("wallets %+v, want %s", user.Wallets, testWalletA)
+	}
+}
+
+func TestConversationRejectedAnswerKeepsState(t *testing.T) {
+	tb := newTestBot(t)
+	user := tb.addUser(t, testUserTgID, nil)
+	tb.startConversation(t, user, stateWalletAddress, "")
+
+	tb.privateMessageReceived(textMessage(testUserTgID, "not an address"))
+	requireTexts(t, tb.telegram.messages(), "Invalid address")
+	if conversation := tb.conversation(t, user.TgID); conversation == nil || conversation.State != stateWalletAddress {
+		t.Fatalf("conversation %+v after a rejected answer, want %s", conversation, stateWalletAddress)
+	}
+
+	tb.privateMessageReceived(textMessage(testUserTgID, testWalletB))
+	requireTexts(t, tb.telegram.messages(), "Wallet added")
+	if tb.conversation(t, user.TgID) != nil {
+		t.Fatalf("the conversation did not end")
+	}
+}
+
+func TestConversationExpired(t *testing.T) {
+	tb := newTestBot(t)
+	user := tb.addUser(t, testUserTgID, nil)
+	err := tb.database.SetConversation(&data.Conversation{
+		TgID:      user.TgID,
+		State:     stateWalletAddress,
+		ExpiresAt: time.Now().Add(-time.Second).Unix(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tb.privateMessageReceived(textMessage(testUserTgID, testWalletA))
+
+	requireTexts(t, tb.telegram.messages(), "expired")
+	if tb.conversation(t, user.TgID) != nil {
+		t.Fatalf("the expired conversation was kept")
+	}
+	if len(tb.database.GetUserByTgID(testUserTgID).Wallets) != 0 {
+		t.Fatalf("the answer to an expired conversation was handled")
+	}
+}
+
+func TestConversationTimeouts(t *testing.T) {
+	tb := newTestBot(t)
+	user := tb.addUser(t, testUserTgID, nil)
+
+	tb.startConversation(t, user, stateWalletAddress, "")
+	expiresIn := time.Until(time.Unix(tb.conversation(t, user.TgID).ExpiresAt, 0))
+	if expiresIn < conversationTimeout-time.Minute || expiresIn > conversationTimeout {
+		t.Fatalf("conversation expires in %v, want %v", expiresIn, conversationTimeout)
+	}
+
+	tb.startConversation(t, user, stateWalletSignature, testWalletA)
+	conversation := tb.conversation(t, user.TgID)
+	expiresIn = time.Until(time.Unix(conversation.ExpiresAt, 0))
+	if expiresIn < walletChallengeMaxAge-time.Minute || expiresIn > walletChallengeMaxAge {
+		t.Fatalf("signature conversation expires in %v, want %v", expiresIn, walletChallengeMaxAge)
+	}
+	if conversation.State != stateWalletSignature || conversation.Param != testWalletA {
+		t.Fatalf("conversation %+v replaced with the wrong state", conversation)
+	}
+}
+
+func TestConversationCancel(t *testing.T) {
+	tb := newTestBot(t)
+	user := tb.addUser(t, testUserTgID, nil)
+
+	tb.privateCommandReceived(commandMessage(testUserTgID, "cancel"))
+	requireTexts(t, tb.telegram.messages(), "Nothing to cancel")
+
+	tb.startConversation(t, user, stateWalletAddress, "")
+	tb.privateCommandReceived(commandMessage(testUserTgID, "cancel"))
+	requireTexts(t, tb.telegram.messages(), "Cancelled")
+	if tb.conversation(t, user.TgID) != nil {
+		t.Fatalf("the conversation was not cancelled")
+	}
+
+	tb.privateMessageReceived(textMessage(testUserTgID, testWalletA))
+	requireTexts(t, tb.telegram.messages(), "Pick an action from the menu")
+	if len(tb.database.GetUserByTgID(testUserTgID).Wallets) != 0 {
+		t.Fatalf("the answer to a cancelled conversation was handled")
+	}
+}
+
+func TestConversationDocumentWithoutFileStep(t *testing.T) {
+	tb := newTestBot(t)
+	user := tb.addUser(t, testUserTgID, nil)
+	tb.startConversation(t, user, stateWalletAddress, "")
+
+	tb.privateMessageReceived(documentMessage(testUserTgID, "wallet.pem"))
+
+	requireTexts(t, tb.telegram.messages(), "Send the answer as a text message")
+	if conversation := tb.conversation(t, user.TgID); conversation == nil || conversation.State != stateWalletAddress {
+		t.Fatalf("conversation %+v after a document, want %s", conversation, stateWalletAddress)
+	}
+}
+
+func TestConversationDocumentDownloadError(t *testing.T) {
+	tb := newTestBot(t)
+	owner := tb.addUser(t, testOwnerTgID, nil)
+	tb.startConversation(t, owner, stateNodePEM, "")
+
+	tb.privateMessageReceived(documentMessage(testOwnerTgID, "node.pem"))
+
+	requireTexts(t, tb.telegram.messages(), "error downloading file", "Can not download the file")
+	if tb.conversation(t, owner.TgID) == nil {
+		t.Fatalf("the conversation ended without a file")
+	}
+}
+
+func TestConversationOwnerOnly(t *testing.T) {
+	tb := newTestBot(t)
+	user := tb.addUser(t, testUserTgID, nil)
+	owner := tb.addUser(t, testOwnerTgID, nil)
+	for _, u := range []*data.User{user, owner} {
+		err := tb.database.SetConversation(&data.Conversation{
+			TgID:      u.TgID,
+			State:     stateContractAddress,
+			ExpiresAt: time.Now().Add(time.Minute).Unix(),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	newContract := testWalletB
+
+	tb.privateMessageReceived(textMessage(testUserTgID, newContract))
+	requireTexts(t, tb.telegram.messages())
+	if tb.conversation(t, user.TgID) != nil {
+		t.Fatalf("the owner only conversation was kept for a user")
+	}
+	if tb.contracts.address != testContract {
+		t.Fatalf("a user changed the contract address to %s", tb.contracts.address)
+	}
+
+	tb.privateMessageReceived(textMessage(testOwnerTgID, newContract))
+	requireTexts(t, tb.telegram.messages(), "Contract address updated")
+	if tb.contracts.address != newContract {
+		t.Fatalf("contract address %s, want %s", tb.contracts.address, newContract)
+	}
+}
+
+func TestConversationUnknownUser(t *testing.T) {
+	tb := newTestBot(t)
+
+	tb.privateMessageReceived(textMessage(testUserTgID, testWalletA))
+
+	requireTexts(t, tb.telegram.messages())
+}
+
+func TestConversationSurvivesRestart(t *testing.T) {
+	tb := newTestBot(t)
+	user := tb.addUser(t, testUserTgID, nil)
+	tb.startConversation(t, user, stateWalletAddress, "")
+
+	restarted := newTestBotOn(tb.database)
+	restarted.privateMessageReceived(textMessage(testUserTgID, testWalletA))
+
+	requireTexts(t, restarted.telegram.messages(), "Wallet added")
+	if restarted.conversation(t, user.TgID) != nil {
+		t.Fatalf("the conversation did not end")
+	}
+	if len(restarted.database.GetUserByTgID(testUserTgID).Wallets) != 1 {
+		t.Fatalf("the answer was not handled after the restart")
+	}
+}
@@ -18,13 +18,12 @@ func (b *Bot) downloadFile(message *tgbotapi.Message) (string, error) {
 		return "", errors.New("nil document object")
 	}
 
-	file, err := b.tgBot.GetFile(tgbotapi.FileConfig{FileID: doc.FileID})
+	url, err := b.tgBot.GetFileDirectURL(doc.FileID)
 	if err != nil {
 		return "", err
 	}
 
 	fileName := fmt.Sprintf("%v-%v-%s", message.From.ID, time.Now().Unix(), doc.FileName)
-	url := file.Link(b.tgBot.Token)
 
 	req, err := http.NewRequest(http.MethodGet, url, nil)
 	if err != nil {
@@ -0,0 +1,140 @@
+package bot
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/DrDelphi/ElrondDSSC/data"
+)
+
+// sendRewardsHistory - syncs and sends the lifetime totals of each of the user's wallets
+func (b *Bot) sendRewardsHistory(user *data.User) {
+	b.sendMessage(user.TgID, "`Rewards History`")
+
+	if len(user.Wallets) == 0 {
+		b.sendMessage(user.TgID, "⭕️ No wallets added")
+		return
+	}
+
+	if b.contracts.ContractAddress() == "" {
+		b.sendMessage(user.TgID, "⭕️ Contract Address not found")
+		return
+	}
+
+	for i, w := range user.Wallets {
+		text := fmt.Sprintf("`Wallet %v/%v` %s\n\r%s", i+1, len(user.Wallets), walletStatus(w), w.Address)
+		if !w.Verified {
+			text += "\n\rℹ️ Verify the wallet from Balances to see its history"
+			b.sendMessage(user.TgID, text)
+			continue
+		}
+
+		_, err := b.history.Sync(w.Address)
+		if err != nil {
+			log.Warn("can not sync wallet history", "address", w.Address, "error", err)
+			text += "\n\r⚠️ Can not read the latest transactions, the history may be incomplete"
+		}
+
+		history, err := b.history.GetHistory(w.Address)
+		if err != nil {
+			text += "\n\r❌ History error"
+			b.sendMessage(user.TgID, text)
+			continue
+		}
+
+		if history.Operations == 0 {
+			text += "\n\r`No delegation transactions found`"
+			b.sendMessage(user.TgID, text)
+			continue
+		}
+
+		text += fmt.Sprintf("\n\r`Delegated:` %s eGLD", history.Delegated.Format(4))
+		text += fmt.Sprintf("\n\r`Undelegated:` %s eGLD", history.UnDelegated.Format(4))
+		text += fmt.Sprintf("\n\r`Withdrawn:` %s eGLD", history.Withdrawn.Format(4))
+		text += fmt.Sprintf("\n\r`Net deposits:` %s eGLD", history.NetDeposits().Format(4))
+		text += fmt.Sprintf("\n\r`Rewards claimed:` %s eGLD", history.Claimed.Format(4))
+		text += fmt.Sprintf("\n\r`Rewards compounded:` %s eGLD", history.Compounded.Format(4))
+		text += fmt.Sprintf("\n\r`Total rewards:` %s eGLD", history.TotalRewards().Format(4))
+		text += fmt.Sprintf("\n\r`Transactions:` %v since %s", history.Operations,
+			time.Unix(history.FirstOperation, 0).UTC().Format("2006-01-02"))
+		text += b.recentRewardsText(w.Address)
+		b.sendMessage(user.TgID, text)
+	}
+}
+
+// stakeHistoryLength - number of snapshots shown in a wallet's stake history
+const stakeHistoryLength = 10
+
+// periodName - returns a short name of a rewards period, such as 24h or 7d
+func periodName(period time.Duration) string {
+	if period < 48*time.Hour {
+		return fmt.Sprintf("%vh", int64(period/time.Hour))
+	}
+
+	return fmt.Sprintf("%vd", int64(period/(24*time.Hour)))
+}
+
+// recentRewardsText - returns the rewards a wallet earned over the last periods, from its stake snapshots
+func (b *Bot) recentRewardsText(address string) string {
+	rewards, err := b.snapshots.RecentRewards(address)
+	if err != nil || len(rewards) == 0 || rewards[len(rewards)-1].Covered == 0 {
+		return ""
+	}
+
+	names := make([]string, 0, len(rewards))
+	amounts := make([]string, 0, len(rewards))
+	for _, r := range rewards {
+		names = append(names, periodName(r.Period))
+		amounts = append(amounts, r.Earned.Format(4))
+	}
+	text := fmt.Sprintf("\n\r`Rewards %s:` %s eGLD", strings.Join(names, " / "), strings.Join(amounts, " / "))
+
+	rate := rewards[len(rewards)-1].DailyRate()
+	if rate != nil {
+		text += fmt.Sprintf("\n\r`Daily rewards:` ~%s eGLD", rate.Format(4))
+	}
+
+	return text
+}
+
+// sendStakeHistory - sends the newest stake snapshots of one of the user's wallets
+func (b *Bot) sendStakeHistory(user *data.User, id uint64) {
+	wallet := findWallet(user, id)
+	if wallet == nil {
+		b.sendMessage(user.TgID, "⭕️ Wallet not found")
+		return
+	}
+	if !wallet.Verified {
+		b.sendMessage(user.TgID, "ℹ️ Verify the wallet from Balances to see its history")
+		return
+	}
+
+	if b.contracts.ContractAddress() == "" {
+		b.sendMessage(user.TgID, "⭕️ Contract Address not found")
+		return
+	}
+
+	snapshots, err := b.snapshots.History(wallet.Address, stakeHistoryLength)
+	if err != nil {
+		b.sendMessage(user.TgID, "❌ History error")
+		return
+	}
+
+	text := fmt.Sprintf("`Stake History`\n\r%s", wallet.Address)
+	if len(snapshots) == 0 {
+		text += "\n\r`No snapshots taken yet`"
+		b.sendMessage(user.TgID, text)
+		return
+	}
+
+	for _, s := range snapshots {
+		text += fmt.Sprintf("\n\r\n\r`%s` (epoch %v)", time.Unix(s.Timestamp, 0).UTC().Format("2006-01-02 15:04"), s.Epoch)
+		text += fmt.Sprintf("\n\r    Delegated: %s eGLD", s.ActiveStake.Format(4))
+		if s.UnStaked.Sign() > 0 || s.UnBondable.Sign() > 0 {
+			text += fmt.Sprintf("\n\r    Undelegated: %s eGLD", s.UnStaked.Add(s.UnBondable).Format(4))
+		}
+		text += fmt.Sprintf("\n\r    Claimable: %s eGLD (+%s)", s.ClaimableRewards.Format(4), s.Earned.Format(4))
+	}
+	b.sendMessage(user.TgID, text)
+}
@@ -0,0 +1,135 @@
+package bot
+
+import (
+	"time"
+
+	"github.com/DrDelphi/ElrondDSSC/data"
+	"github.com/DrDelphi/ElrondDSSC/db"
+	"github.com/DrDelphi/ElrondDSSC/network"
+	"github.com/DrDelphi/ElrondDSSC/vault"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+)
+
+// Sender - defines the Telegram calls which send, answer or delete messages
+type Sender interface {
+	Send(c tgbotapi.Chattable) (tgbotapi.Message, error)
+	AnswerCallbackQuery(config tgbotapi.CallbackConfig) (tgbotapi.APIResponse, error)
+	DeleteMessage(config tgbotapi.DeleteMessageConfig) (tgbotapi.APIResponse, error)
+}
+
+// TelegramClient - defines the Telegram Bot API calls the bot makes. It is implemented by *tgbotapi.BotAPI
+type TelegramClient interface {
+	Sender
+	GetUpdatesChan(config tgbotapi.UpdateConfig) (tgbotapi.UpdatesChannel, error)
+	GetFileDirectURL(fileID string) (string, error)
+}
+
+// TxTracker - defines the following of the sent transactions until they complete
+type TxTracker interface {
+	Start()
+	Track(hash string, tgID int64, description string) error
+	OnOutcome(handler func(outcome *network.TxOutcome))
+}
+
+// ContractRegistry - defines the source of the delegation contract address the bot works with
+type ContractRegistry interface {
+	network.ContractAddressProvider
+	Start()
+	Resolve() (string, error)
+	SetContractAddress(address string) error
+	OnChange(handler func(address string))
+}
+
+// NetworkClock - defines the conversions between rounds, epochs and time
+type NetworkClock interface {
+	Start()
+	Synced() bool
+	RoundsDuration(rounds uint64) time.Duration
+	RoundsPerEpoch() uint64
+	TimeAfterRounds(rounds uint64) time.Time
+	EpochAfterRounds(rounds uint64) uint32
+}
+
+// NodeLister - defines the listing of the contract's nodes
+type NodeLister interface {
+	GetNodes() ([]*data.NodeInfo, error)
+}
+
+// ProviderLister - defines the directory of all the staking providers
+type ProviderLister interface {
+	Start()
+	Providers() []*data.Provider
+	UpdatedAt() time.Time
+}
+
+// APREstimator - defines the estimation of the contract's APR
+type APREstimator interface {
+	Estimate() (*data.APREstimate, error)
+}
+
+// RewardsHistory - defines the history of a wallet's operations with the contract
+type RewardsHistory interface {
+	Sync(address string) (int, error)
+	GetHistory(address string) (*data.WalletHistory, error)
+}
+
+// ActivityIndexer - defines the feed of the contract's recent activity
+type ActivityIndexer interface {
+	Start()
+	GetActivity(category string, limit int) ([]*data.ContractActivity, error)
+}
+
+// StakeSnapshotter - defines the recorded history of the wallets' balances with the contract
+type StakeSnapshotter interface {
+	Start()
+	RecentRewards(address string) ([]*data.RewardsEarned, error)
+	History(address string, limit int) ([]*data.StakeSnapshot, error)
+}
+
+// HookCallbacks - defines the notification of the web wallet's redirects back to the bot
+type HookCallbacks interface {
+	OnCallback(handler func(callback *data.HookCallback))
+}
+
+// KeyVault - defines the storage of the owner's private key
+type KeyVault interface {
+	HasKey() bool
+	Store(privateKey []byte) error
+	Unlock() ([]byte, error)
+	Wipe() error
+}
+
+// Dependencies - holds the components the bot works with. Callbacks is nil when the wallet does not
+// redirect back to the bot
+type Dependencies struct {
+	Telegram     TelegramClient
+	Database     db.Store
+	Contract     network.DelegationContract
+	Accounts     network.AccountReader
+	Transactions network.TransactionSender
+	Simulator    network.TransactionSimulator
+	TxTracker    TxTracker
+	Contracts    ContractRegistry
+	Clock        NetworkClock
+	Nodes        NodeLister
+	Providers    ProviderLister
+	APR          APREstimator
+	History      RewardsHistory
+	Activity     ActivityIndexer
+	Snapshots    StakeSnapshotter
+	Callbacks    HookCallbacks
+	Vault        KeyVault
+}
+
+var _ TelegramClient = (*tgbotapi.BotAPI)(nil)
+var _ TxTracker = (*network.TxTracker)(nil)
+var _ ContractRegistry = (*network.ContractRegistry)(nil)
+var _ NetworkClock = (*network.NetworkClock)(nil)
+var _ NodeLister = (*network.NodesProvider)(nil)
+var _ ProviderLister = (*network.ProviderDirectory)(nil)
+var _ APREstimator = (*network.APREstimator)(nil)
+var _ RewardsHistory = (*network.RewardsHistory)(nil)
+var _ ActivityIndexer = (*network.ActivityIndexer)(nil)
+var _ StakeSnapshotter = (*network.StakeSnapshotter)(nil)
+var _ HookCallbacks = (*network.HookCallbackServer)(nil)
+var _ KeyVault = (*vault.KeyVault)(nil)
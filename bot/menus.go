@@ -1,10 +1,8 @@
 package bot
 
 import (
-	"fmt"
-
 	"github.com/DrDelphi/ElrondDSSC/data"
-	"github.com/DrDelphi/ElrondDSSC/utils"
+	"github.com/DrDelphi/ElrondDSSC/network"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
 )
 
@@ -16,12 +14,10 @@ func (b *Bot) mainMenu(user *data.User) {
 		})
 	}
 
-	withdrawURL := fmt.Sprintf("%s/hook/transaction?receiver=%s&value=0&gasLimit=12000000&data=withdraw&callbackUrl=none",
-		b.walletHook, utils.ContractAddress)
-	claimURL := fmt.Sprintf("%s/hook/transaction?receiver=%s&value=0&gasLimit=6000000&data=claimRewards&callbackUrl=none",
-		b.walletHook, utils.ContractAddress)
-	compoundURL := fmt.Sprintf("%s/hook/transaction?receiver=%s&value=0&gasLimit=12000000&data=reDelegateRewards&callbackUrl=none",
-		b.walletHook, utils.ContractAddress)
+	contractAddress := b.contracts.ContractAddress()
+	withdrawURL := b.hookURL(user.TgID, "Withdraw", network.WithdrawTx(contractAddress))
+	claimURL := b.hookURL(user.TgID, "Claim rewards", network.ClaimRewardsTx(contractAddress))
+	compoundURL := b.hookURL(user.TgID, "Compound", network.ReDelegateRewardsTx(contractAddress))
 
 	msg := tgbotapi.NewMessage(user.TgID, "`Main Menu`")
 	keyboard := tgbotapi.NewInlineKeyboardMarkup(
@@ -37,8 +33,14 @@ func (b *Bot) mainMenu(user *data.User) {
 			tgbotapi.NewInlineKeyboardButtonURL("😋 Claim Rewards", claimURL),
 			tgbotapi.NewInlineKeyboardButtonURL("🍽 Withdraw", withdrawURL),
 		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("📷 Claim QR", ":TxQR_Claim"),
+			tgbotapi.NewInlineKeyboardButtonData("📷 Compound QR", ":TxQR_Compound"),
+			tgbotapi.NewInlineKeyboardButtonData("📷 Withdraw QR", ":TxQR_Withdraw"),
+		),
 		tgbotapi.NewInlineKeyboardRow(
 			tgbotapi.NewInlineKeyboardButtonData("ℹ️ Contract Info", "ContractInfo"),
+			tgbotapi.NewInlineKeyboardButtonData("🗞 Activity", "Activity"),
 		),
 		tgbotapi.NewInlineKeyboardRow(
 			tgbotapi.NewInlineKeyboardButtonData("📜 Help", "MainHelp"),
@@ -58,7 +60,7 @@ func (b *Bot) mainMenu(user *data.User) {
 	msg.ReplyMarkup = keyboard
 	msg.ParseMode = tgbotapi.ModeMarkdown
 	resp, _ := b.tgBot.Send(msg)
-	user.LastMenuID = resp.MessageID
+	_ = b.database.SetLastMenuID(user, resp.MessageID)
 }
 
 func (b *Bot) walletsMenu(user *data.User) {
@@ -74,6 +76,9 @@ func (b *Bot) walletsMenu(user *data.User) {
 			tgbotapi.NewInlineKeyboardButtonData("➕ Add", "AddWallet"),
 			tgbotapi.NewInlineKeyboardButtonData("💰 Balances", "Balances"),
 		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("📈 Rewards History", "RewardsHistory"),
+		),
 		tgbotapi.NewInlineKeyboardRow(
 			tgbotapi.NewInlineKeyboardButtonData("📜 Help", "MyWalletsHelp"),
 			tgbotapi.NewInlineKeyboardButtonData("🚪 Back", "Back"),
@@ -82,7 +87,7 @@ func (b *Bot) walletsMenu(user *data.User) {
 	msg.ReplyMarkup = keyboard
 	msg.ParseMode = tgbotapi.ModeMarkdown
 	resp, _ := b.tgBot.Send(msg)
-	user.LastMenuID = resp.MessageID
+	_ = b.database.SetLastMenuID(user, resp.MessageID)
 }
 
 func (b *Bot) adminMenu(user *data.User) {
@@ -92,10 +97,11 @@ func (b *Bot) adminMenu(user *data.User) {
 			MessageID: user.LastMenuID,
 		})
 	}
-	enableAutoActivateURL := fmt.Sprintf("%s/hook/transaction?receiver=%s&value=0&gasLimit=6000000&data=setAutomaticActivation@796573&callbackUrl=none",
-		b.walletHook, utils.ContractAddress)
-	disableAutoActivateURL := fmt.Sprintf("%s/hook/transaction?receiver=%s&value=0&gasLimit=6000000&data=setAutomaticActivation@6e6f&callbackUrl=none",
-		b.walletHook, utils.ContractAddress)
+	contractAddress := b.contracts.ContractAddress()
+	enableAutoActivateURL := b.ownerHookURL(user.TgID, "Enable automatic activation",
+		network.SetAutomaticActivationTx(contractAddress, true))
+	disableAutoActivateURL := b.ownerHookURL(user.TgID, "Disable automatic activation",
+		network.SetAutomaticActivationTx(contractAddress, false))
 	msg := tgbotapi.NewMessage(user.TgID, "`Admin Control Panel`")
 	keyboard := tgbotapi.NewInlineKeyboardMarkup(
 		tgbotapi.NewInlineKeyboardRow(
@@ -104,12 +110,24 @@ func (b *Bot) adminMenu(user *data.User) {
 		tgbotapi.NewInlineKeyboardRow(
 			tgbotapi.NewInlineKeyboardButtonData("Create DSSC", "CreateDSSC"),
 		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🧨 Wipe owner private key", "WipeOwnerKey"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("Set contract address", "SetContractAddress"),
+		),
 		tgbotapi.NewInlineKeyboardRow(
 			tgbotapi.NewInlineKeyboardButtonData("Change Service Fee", "ChangeServiceFee"),
 		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("Set Provider Metadata", "SetMetadata"),
+		),
 		tgbotapi.NewInlineKeyboardRow(
 			tgbotapi.NewInlineKeyboardButtonData("Modify Delegation Cap", "ModifyDelegationCap"),
 		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("📊 Providers", "Providers"),
+		),
 		tgbotapi.NewInlineKeyboardRow(
 			tgbotapi.NewInlineKeyboardButtonURL("Enable Automatic Activation", enableAutoActivateURL),
 		),
@@ -123,7 +141,7 @@ func (b *Bot) adminMenu(user *data.User) {
 	msg.ReplyMarkup = keyboard
 	msg.ParseMode = tgbotapi.ModeMarkdown
 	resp, _ := b.tgBot.Send(msg)
-	user.LastMenuID = resp.MessageID
+	_ = b.database.SetLastMenuID(user, resp.MessageID)
 }
 
 func (b *Bot) nodesMenu(user *data.User) {
@@ -146,5 +164,5 @@ func (b *Bot) nodesMenu(user *data.User) {
 	msg.ReplyMarkup = keyboard
 	msg.ParseMode = tgbotapi.ModeMarkdown
 	resp, _ := b.tgBot.Send(msg)
-	user.LastMenuID = resp.MessageID
+	_ = b.database.SetLastMenuID(user, resp.MessageID)
 }
@@ -0,0 +1,123 @@
+package bot
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/DrDelphi/ElrondDSSC/data"
+	"github.com/DrDelphi/ElrondDSSC/network"
+	"github.com/DrDelphi/ElrondDSSC/utils"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+)
+
+// providersListSize - number of providers listed in the directory view
+const providersListSize = 10
+
+var providerSortTitles = map[string]string{
+	network.ProviderSortFee:          "service fee",
+	network.ProviderSortStake:        "stake",
+	network.ProviderSortDelegators:   "delegators",
+	network.ProviderSortRemainingCap: "remaining cap",
+}
+
+// sendProviders - sends the top providers ranked by the given criterion, with our contract highlighted
+func (b *Bot) sendProviders(user *data.User, by string) {
+	title, ok := providerSortTitles[by]
+	if !ok {
+		return
+	}
+
+	providers := b.providers.Providers()
+	if len(providers) == 0 {
+		b.sendMessage(user.TgID, "ℹ️ The provider directory is not available yet, try again in a few minutes")
+		return
+	}
+
+	contractAddress := b.contracts.ContractAddress()
+	ranked := network.RankProviders(providers, by)
+	text := fmt.Sprintf("`Providers by %s` (%v contracts, updated %s UTC)\n\r", title, len(ranked),
+		b.providers.UpdatedAt().UTC().Format("2006-01-02 15:04"))
+	position := 0
+	for i, provider := range ranked {
+		if provider.Address == contractAddress {
+			position = i + 1
+		}
+		if i < providersListSize {
+			text += "\n\r" + providerLine(i+1, provider, provider.Address == contractAddress)
+		}
+	}
+
+	if position > providersListSize {
+		text += "\n\r...\n\r" + providerLine(position, ranked[position-1], true)
+	}
+	if position > 0 {
+		text += fmt.Sprintf("\n\r\n\r`Our position:` %v of %v", position, len(ranked))
+	}
+
+	lower, median := feeStats(ranked, contractAddress)
+	text += fmt.Sprintf("\n\r`Median service fee:` %.2f%%", median)
+	if position > 0 {
+		text += fmt.Sprintf("\n\r`Providers with a lower fee:` %v", lower)
+	}
+
+	msg := tgbotapi.NewMessage(user.TgID, text)
+	msg.ParseMode = tgbotapi.ModeMarkdown
+	msg.DisableWebPagePreview = true
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("Fee", ":Providers_"+network.ProviderSortFee),
+			tgbotapi.NewInlineKeyboardButtonData("Stake", ":Providers_"+network.ProviderSortStake),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("Delegators", ":Providers_"+network.ProviderSortDelegators),
+			tgbotapi.NewInlineKeyboardButtonData("Remaining cap", ":Providers_"+network.ProviderSortRemainingCap),
+		),
+	)
+	b.tgBot.Send(msg)
+}
+
+func providerLine(position int, provider *data.Provider, ours bool) string {
+	capText := "uncapped"
+	if provider.Capped() {
+		capText = provider.RemainingCap().Format(0) + " eGLD left"
+	}
+
+	line := fmt.Sprintf("%v. *%s* - %.2f%% fee, %s eGLD, %v delegators, %s", position,
+		utils.EscapeMarkdown(provider.DisplayName()), provider.ServiceFee, provider.TotalActiveStake.Format(0),
+		provider.NumUsers, capText)
+	if ours {
+		line = "➡️ " + line
+	}
+
+	return line
+}
+
+// feeStats - returns the number of providers with a lower fee than our contract and the median fee
+func feeStats(providers []*data.Provider, contractAddress string) (int, float64) {
+	fees := make([]float64, 0, len(providers))
+	ourFee := -1.0
+	for _, provider := range providers {
+		fees = append(fees, provider.ServiceFee)
+		if provider.Address == contractAddress {
+			ourFee = provider.ServiceFee
+		}
+	}
+	if len(fees) == 0 {
+		return 0, 0
+	}
+
+	sort.Float64s(fees)
+	median := fees[len(fees)/2]
+	if len(fees)%2 == 0 {
+		median = (fees[len(fees)/2-1] + fees[len(fees)/2]) / 2
+	}
+
+	lower := 0
+	for _, fee := range fees {
+		if fee < ourFee {
+			lower++
+		}
+	}
+
+	return lower, median
+}
@@ -0,0 +1,129 @@
+package bot
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/DrDelphi/ElrondDSSC/data"
+	"github.com/DrDelphi/ElrondDSSC/network"
+	"github.com/DrDelphi/ElrondDSSC/qrcode"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+)
+
+// qrCodeScale - the size in pixels of a QR code module. Hook links need versions up to ~15, so 8 pixels
+// per module keeps the images under 700x700
+const qrCodeScale = 8
+
+// nodeAction - a transaction the owner can send for a single node
+type nodeAction struct {
+	Button      string
+	Description string
+	Tx          func(contract string, blsKeys ...[]byte) *network.TxRequest
+}
+
+// nodeActions - the per node transactions, in the order of the node's buttons
+var nodeActions = []nodeAction{
+	{"Stake", "Stake node", network.StakeNodesTx},
+	{"Unstake", "Unstake node", network.UnStakeNodesTx},
+	{"Unbond", "Unbond node", network.UnBondNodesTx},
+	{"Restake", "Restake node", network.ReStakeUnStakedNodesTx},
+	{"Unjail", "Unjail node", network.UnJailNodesTx},
+	{"Remove", "Remove node", network.RemoveNodesTx},
+}
+
+// sendQRCode - sends the content encoded in a QR code, with an optional inline keyboard under it
+func (b *Bot) sendQRCode(tgID int64, content string, caption string, keyboard *tgbotapi.InlineKeyboardMarkup) {
+	png, err := qrcode.EncodePNG(content, qrCodeScale)
+	if err != nil {
+		log.Error("error encoding QR code", "content", content, "error", err)
+		return
+	}
+
+	msg := tgbotapi.NewPhotoUpload(tgID, tgbotapi.FileBytes{Name: "qrcode.png", Bytes: png})
+	msg.Caption = caption
+	if keyboard != nil {
+		msg.ReplyMarkup = keyboard
+	}
+	b.tgBot.Send(msg)
+}
+
+// sendTxQRCode - sends the wallet link of a prepared transaction as a QR code, for signing on another device
+func (b *Bot) sendTxQRCode(tgID int64, description string, url string) {
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonURL("Send transaction", url),
+		),
+	)
+	b.sendQRCode(tgID, url, fmt.Sprintf("📷 %s - scan to open the transaction in the web wallet", description),
+		&keyboard)
+}
+
+// sendAddressQRCode - sends an address as a QR code
+func (b *Bot) sendAddressQRCode(tgID int64, title string, address string) {
+	b.sendQRCode(tgID, address, fmt.Sprintf("📷 %s\n%s", title, address), nil)
+}
+
+// sendDelegatorQRCode - sends the claim, compound or withdraw transaction as a QR code
+func (b *Bot) sendDelegatorQRCode(user *data.User, action string) {
+	contractAddress := b.contracts.ContractAddress()
+	if contractAddress == "" {
+		b.sendMessage(user.TgID, "⭕️ Contract Address not found")
+		return
+	}
+
+	var description string
+	var req *network.TxRequest
+	switch action {
+	case "Claim":
+		description, req = "Claim rewards", network.ClaimRewardsTx(contractAddress)
+	case "Compound":
+		description, req = "Compound", network.ReDelegateRewardsTx(contractAddress)
+	case "Withdraw":
+		description, req = "Withdraw", network.WithdrawTx(contractAddress)
+	default:
+		return
+	}
+
+	b.sendTxQRCode(user.TgID, description, b.hookURL(user.TgID, description, req))
+}
+
+// sendNodeQRCodes - sends the transactions of the node whose hex key starts with the given prefix as QR codes
+func (b *Bot) sendNodeQRCodes(user *data.User, keyPrefix string) {
+	contractAddress := b.contracts.ContractAddress()
+	if contractAddress == "" {
+		b.sendMessage(user.TgID, "⭕️ Contract Address not found")
+		return
+	}
+
+	nodes, err := b.nodes.GetNodes()
+	if err != nil {
+		b.sendMessage(user.TgID, "⭕️ Can not get all nodes states")
+		return
+	}
+
+	for _, node := range nodes {
+		if !strings.HasPrefix(node.HexKey(), keyPrefix) {
+			continue
+		}
+
+		for _, action := range nodeActions {
+			url := b.ownerHookURL(user.TgID, action.Description, action.Tx(contractAddress, node.BlsKey))
+			b.sendTxQRCode(user.TgID, action.Description, url)
+		}
+
+		return
+	}
+
+	b.sendMessage(user.TgID, "⭕️ Node not found")
+}
+
+// sendWalletQRCode - sends the deposit address of one of the user's wallets as a QR code
+func (b *Bot) sendWalletQRCode(user *data.User, id uint64) {
+	wallet := findWallet(user, id)
+	if wallet == nil {
+		b.sendMessage(user.TgID, "⭕️ Wallet not found")
+		return
+	}
+
+	b.sendAddressQRCode(user.TgID, "Deposit address", wallet.Address)
+}
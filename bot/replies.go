@@ -1,196 +1,155 @@
 package bot
 
 import (
-	"encoding/hex"
 	"fmt"
-	"math/big"
+	"math"
+	"regexp"
 	"strconv"
 	"strings"
 
 	"github.com/DrDelphi/ElrondDSSC/data"
+	"github.com/DrDelphi/ElrondDSSC/network"
 	"github.com/DrDelphi/ElrondDSSC/utils"
+	"github.com/DrDelphi/ElrondDSSC/vault"
 	"github.com/ElrondNetwork/elrond-sdk/erdgo"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
 )
 
-func (b *Bot) privateReplyReceived(message *tgbotapi.Message) {
-	user := b.database.GetUserByTgID(int64(message.From.ID))
-	name := utils.FormatTgUser(message.From)
-	log.Info("reply received", "reply to message", message.ReplyToMessage.Text, "message", message.Text, "user", name)
+// minDelegationAmount - the minimum amount accepted by the DSSC for delegate and undelegate
+var minDelegationAmount = data.MustParseAmount("10")
 
-	if user == nil {
-		log.Warn("reply received from unknown user", "reply", message.Text, "user", name)
-		return
-	}
+// keybaseIdentityRegex - keybase usernames are letters, digits and underscores
+var keybaseIdentityRegex = regexp.MustCompile(`^[a-zA-Z0-9_]*$`)
 
-	var err error
-	fileName := ""
-	if message.Document != nil {
-		fileName, err = b.downloadFile(message)
-		if err != nil {
-			log.Error("error downloading file", "file", message.Document.FileName, "user", name, "error", err)
-			b.reportError("error downloading file: " + err.Error())
-		}
+// addWallet - adds the address sent by the user to the user's wallets
+func (b *Bot) addWallet(message *tgbotapi.Message, user *data.User) bool {
+	address := strings.TrimSpace(message.Text)
+	if !erdgo.IsValidBech32Address(address) {
+		b.sendMessage(user.TgID, "⭕️ Invalid address")
+		return false
 	}
 
-	if message.ReplyToMessage.Text == utils.SetOwnerAddressMessage && user.TgID == b.owner {
-		b.setOwnerAddress(message, user, fileName)
+	err := b.database.AddUserWallet(user, address)
+	if err != nil {
+		b.sendMessage(user.TgID, "⭕️ Error adding wallet in database")
+		return false
 	}
 
-	if message.ReplyToMessage.Text == utils.AddWalletMessage {
-		if !erdgo.IsValidBech32Address(message.Text) {
-			b.sendMessage(user.TgID, "⭕️ Invalid address")
-			return
-		}
+	b.sendMessage(user.TgID, "✅ Wallet added. Verify it from Balances to see its delegations and rewards")
 
-		err = b.database.AddUserWallet(user, message.Text)
-		if err == nil {
-			b.sendMessage(user.TgID, "✅ Wallet added")
-		} else {
-			b.sendMessage(user.TgID, "⭕️ Error adding wallet in database")
-		}
-	}
-
-	if message.ReplyToMessage.Text == utils.DelegateAmountMessage {
-		amount, err := strconv.ParseFloat(message.Text, 32)
-		if err != nil {
-			b.sendMessage(user.TgID, "⭕️ Invalid amount")
-			return
-		}
+	return true
+}
 
-		if amount < 10 {
-			b.sendMessage(user.TgID, "⭕️ Minimum amount is 10 eGLD")
-			return
-		}
+// parseDelegationAmount - parses an amount to delegate or undelegate, letting the user know if it is not valid
+func (b *Bot) parseDelegationAmount(message *tgbotapi.Message, user *data.User) *data.Amount {
+	amount, err := data.ParseAmount(strings.TrimSpace(message.Text))
+	if err != nil {
+		b.sendMessage(user.TgID, "⭕️ Invalid amount")
+		return nil
+	}
 
-		fAmount := big.NewFloat(amount)
-		fAmount.Mul(fAmount, b.networkManager.GetDenominator())
-		iAmount, _ := fAmount.Int(nil)
+	if amount.Cmp(minDelegationAmount) < 0 {
+		b.sendMessage(user.TgID, fmt.Sprintf("⭕️ Minimum amount is %s eGLD", minDelegationAmount))
+		return nil
+	}
 
-		url := fmt.Sprintf("%s/hook/transaction?receiver=%s&value=%v&gasLimit=12000000&data=delegate&callbackUrl=none",
-			b.walletHook, utils.ContractAddress, iAmount)
-		text := fmt.Sprintf("%.4f eGLD", amount)
+	return amount
+}
 
-		msg := tgbotapi.NewMessage(user.TgID, "Delegate")
-		msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
-			tgbotapi.NewInlineKeyboardRow(
-				tgbotapi.NewInlineKeyboardButtonURL(text, url),
-			),
-		)
-		b.tgBot.Send(msg)
+func (b *Bot) delegate(message *tgbotapi.Message, user *data.User) bool {
+	amount := b.parseDelegationAmount(message, user)
+	if amount == nil {
+		return false
 	}
 
-	if message.ReplyToMessage.Text == utils.UndelegateAmountMessage {
-		amount, err := strconv.ParseFloat(message.Text, 32)
-		if err != nil {
-			b.sendMessage(user.TgID, "⭕️ Invalid amount")
-			return
-		}
+	req := network.DelegateTx(b.contracts.ContractAddress(), amount)
+	b.sendSimulatedTx(user, "Delegate", req, fmt.Sprintf("%s eGLD", amount))
 
-		if amount < 10 {
-			b.sendMessage(user.TgID, "⭕️ Minimum amount is 10 eGLD")
-			return
-		}
+	return true
+}
 
-		fAmount := big.NewFloat(amount)
-		fAmount.Mul(fAmount, b.networkManager.GetDenominator())
-		iAmount, _ := fAmount.Int(nil)
-		bytesAmount := iAmount.Bytes()
-		strBytesAmount := hex.EncodeToString(bytesAmount)
-
-		url := fmt.Sprintf("%s/hook/transaction?receiver=%s&value=0&gasLimit=12000000&data=unDelegate@%s&callbackUrl=none",
-			b.walletHook, utils.ContractAddress, strBytesAmount)
-		text := fmt.Sprintf("%.4f eGLD", amount)
-
-		msg := tgbotapi.NewMessage(user.TgID, "Undelegate")
-		msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
-			tgbotapi.NewInlineKeyboardRow(
-				tgbotapi.NewInlineKeyboardButtonURL(text, url),
-			),
-		)
-		b.tgBot.Send(msg)
-	}
-
-	if message.ReplyToMessage.Text == utils.ChangeServiceFeeMessage && user.TgID == b.owner {
-		fee, err := strconv.ParseFloat(message.Text, 32)
-		if err != nil || fee < 0 || fee > 100 {
-			b.sendMessage(user.TgID, "⭕️ Invalid fee")
-			return
-		}
+func (b *Bot) unDelegate(message *tgbotapi.Message, user *data.User) bool {
+	amount := b.parseDelegationAmount(message, user)
+	if amount == nil {
+		return false
+	}
 
-		iFee := uint64(fee * 100)
-		strFee := hex.EncodeToString([]byte{byte(iFee / 256), byte(iFee % 256)})
+	req := network.UnDelegateTx(b.contracts.ContractAddress(), amount)
+	b.sendSimulatedTx(user, "Undelegate", req, fmt.Sprintf("%s eGLD", amount))
 
-		url := fmt.Sprintf("%s/hook/transaction?receiver=%s&value=0&gasLimit=6000000&data=changeServiceFee@%s&callbackUrl=none",
-			b.walletHook, utils.ContractAddress, strFee)
-		text := fmt.Sprintf("%.2f%%", fee)
+	return true
+}
 
-		msg := tgbotapi.NewMessage(user.TgID, "Change service fee")
-		msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
-			tgbotapi.NewInlineKeyboardRow(
-				tgbotapi.NewInlineKeyboardButtonURL(text, url),
-			),
-		)
-		b.tgBot.Send(msg)
+func (b *Bot) changeServiceFee(message *tgbotapi.Message, user *data.User) bool {
+	fee, err := strconv.ParseFloat(strings.TrimSpace(message.Text), 32)
+	if err != nil || fee < 0 || fee > 100 {
+		b.sendMessage(user.TgID, "⭕️ Invalid fee")
+		return false
 	}
 
-	if message.ReplyToMessage.Text == utils.ModifyDelegationCapMessage && user.TgID == b.owner {
-		cap, err := strconv.ParseFloat(message.Text, 32)
-		if err != nil {
-			b.sendMessage(user.TgID, "⭕️ Invalid fee")
-			return
-		}
-
-		fCap := big.NewFloat(cap)
-		fCap.Mul(fCap, b.networkManager.GetDenominator())
+	req := network.ChangeServiceFeeTx(b.contracts.ContractAddress(), uint64(math.Round(fee*100)))
+	url := b.ownerHookURL(user.TgID, "Change service fee", req)
+	text := fmt.Sprintf("%.2f%%", fee)
 
-		iCap, _ := fCap.Int(nil)
-		strCap := hex.EncodeToString(iCap.Bytes())
+	msg := tgbotapi.NewMessage(user.TgID, "Change service fee")
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonURL(text, url),
+		),
+	)
+	b.tgBot.Send(msg)
 
-		url := fmt.Sprintf("%s/hook/transaction?receiver=%s&value=0&gasLimit=6000000&data=modifyTotalDelegationCap@%s&callbackUrl=none",
-			b.walletHook, utils.ContractAddress, strCap)
-		text := fmt.Sprintf("%.2f eGLD", cap)
+	return true
+}
 
-		msg := tgbotapi.NewMessage(user.TgID, "Modify delegation cap")
-		msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
-			tgbotapi.NewInlineKeyboardRow(
-				tgbotapi.NewInlineKeyboardButtonURL(text, url),
-			),
-		)
-		b.tgBot.Send(msg)
+func (b *Bot) modifyDelegationCap(message *tgbotapi.Message, user *data.User) bool {
+	cap, err := data.ParseAmount(strings.TrimSpace(message.Text))
+	if err != nil {
+		b.sendMessage(user.TgID, "⭕️ Invalid delegation cap")
+		return false
 	}
 
-	if message.ReplyToMessage.Text == utils.AddNodeMessage && user.TgID == b.owner {
-		b.addNode(message, user, fileName)
-	}
+	req := network.ModifyTotalDelegationCapTx(b.contracts.ContractAddress(), cap)
+	url := b.ownerHookURL(user.TgID, "Modify delegation cap", req)
+	text := fmt.Sprintf("%s eGLD", cap)
+
+	msg := tgbotapi.NewMessage(user.TgID, "Modify delegation cap")
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonURL(text, url),
+		),
+	)
+	b.tgBot.Send(msg)
+
+	return true
 }
 
-func (b *Bot) addNode(message *tgbotapi.Message, user *data.User, fileName string) {
+func (b *Bot) addNode(message *tgbotapi.Message, user *data.User, fileName string) bool {
 	if fileName == "" {
 		b.sendMessage(user.TgID, "⭕️ No pem file received")
-		return
+		return false
 	}
 
 	privateKey, err := erdgo.LoadPrivateKeyFromPemFile(fileName)
 	if err != nil {
 		b.sendMessage(user.TgID, "⭕️ Invalid pem file")
-		return
+		return false
 	}
 
 	publicKey, err := utils.GetValidatorKeyFromPrivateKey(privateKey)
 	if err != nil {
 		b.sendMessage(user.TgID, "⭕️ Can not derive public key from private key")
-		return
+		return false
 	}
 
-	sig, err := utils.GetStakeSig(utils.ContractAddress, fileName)
+	contractAddress := b.contracts.ContractAddress()
+	sig, err := utils.GetStakeSig(contractAddress, fileName)
 	if err != nil {
 		b.sendMessage(user.TgID, "⭕️ Error signing with BLS key")
-		return
+		return false
 	}
 
-	url := fmt.Sprintf("%s/hook/transaction?receiver=%s&value=0&gasLimit=6000000&data=addNodes@%s@%s&callbackUrl=none",
-		b.walletHook, utils.ContractAddress, hex.EncodeToString(publicKey), hex.EncodeToString(sig))
+	url := b.ownerHookURL(user.TgID, "Add node", network.AddNodesTx(contractAddress, publicKey, sig))
 	msg := tgbotapi.NewMessage(user.TgID, "Add node")
 	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
 		tgbotapi.NewInlineKeyboardRow(
@@ -198,12 +157,89 @@ func (b *Bot) addNode(message *tgbotapi.Message, user *data.User, fileName strin
 		),
 	)
 	b.tgBot.Send(msg)
+	b.sendTxQRCode(user.TgID, "Add node", url)
+
+	return true
+}
+
+func (b *Bot) setMetadata(message *tgbotapi.Message, user *data.User) bool {
+	lines := strings.Split(strings.TrimSpace(message.Text), "\n")
+	if len(lines) != 3 {
+		b.sendMessage(user.TgID, "⭕️ Send exactly 3 lines: name, website and keybase identity")
+		return false
+	}
+
+	metadata := &data.ContractMetadata{
+		Name:       strings.TrimSpace(lines[0]),
+		Website:    strings.TrimSpace(lines[1]),
+		Identifier: strings.TrimPrefix(strings.TrimSpace(lines[2]), "@"),
+	}
+	if metadata.Name == "" {
+		b.sendMessage(user.TgID, "⭕️ Invalid name")
+		return false
+	}
+	if metadata.Website != "" && !strings.HasPrefix(metadata.Website, "http://") && !strings.HasPrefix(metadata.Website, "https://") {
+		metadata.Website = "https://" + metadata.Website
+	}
+	if !keybaseIdentityRegex.MatchString(metadata.Identifier) {
+		b.sendMessage(user.TgID, "⭕️ Invalid keybase identity")
+		return false
+	}
+
+	req := network.SetMetaDataTx(b.contracts.ContractAddress(), metadata)
+	preview := fmt.Sprintf("Set provider metadata\n\nName: %s\nWebsite: %s\nKeybase: %s\n\nData: %s\nGas limit: %v",
+		metadata.Name, metadata.Website, metadata.Identifier, req.Data(), req.GasLimit)
+
+	msg := tgbotapi.NewMessage(user.TgID, preview)
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonURL("Send transaction", b.ownerHookURL(user.TgID, "Set provider metadata", req)),
+		),
+	)
+	b.tgBot.Send(msg)
+
+	return true
+}
+
+func (b *Bot) setContractAddress(message *tgbotapi.Message, user *data.User) bool {
+	text := strings.TrimSpace(message.Text)
+	if strings.ToLower(text) == "auto" {
+		address, err := b.contracts.Resolve()
+		if err != nil {
+			b.sendMessage(user.TgID, "⭕️ Error looking up the contract: "+err.Error())
+			return false
+		}
+		if address == "" {
+			b.sendMessage(user.TgID, "⭕️ No contract found for the owner address")
+			return false
+		}
+		b.sendMessage(user.TgID, "✅ Contract address found: "+address)
+		return true
+	}
+
+	if !erdgo.IsValidBech32Address(text) {
+		b.sendMessage(user.TgID, "⭕️ Invalid address")
+		return false
+	}
+
+	err := b.contracts.SetContractAddress(text)
+	if err != nil {
+		b.sendMessage(user.TgID, "⭕️ Error setting contract address: "+err.Error())
+		return false
+	}
+
+	b.sendMessage(user.TgID, "✅ Contract address updated")
+
+	return true
 }
 
-func (b *Bot) setOwnerAddress(message *tgbotapi.Message, user *data.User, fileName string) {
+func (b *Bot) setOwnerAddress(message *tgbotapi.Message, user *data.User, fileName string) bool {
 	var err error
-	address := message.Text
+	address := strings.TrimSpace(message.Text)
 	privateKey := make([]byte, 0)
+	defer func() {
+		vault.Zero(privateKey)
+	}()
 
 	if fileName != "" {
 		if strings.Contains(strings.ToLower(fileName), ".pem") {
@@ -213,13 +249,13 @@ func (b *Bot) setOwnerAddress(message *tgbotapi.Message, user *data.User, fileNa
 				privateKey, err = erdgo.LoadPrivateKeyFromJsonFile(fileName, message.Caption)
 			} else {
 				b.sendMessage(user.TgID, "⭕️ Unknown file type")
-				return
+				return false
 			}
 		}
 		if err != nil {
 			log.Error("error loading wallet file", "error", err, "file", fileName)
 			b.sendMessage(user.TgID, "⭕️ Invalid file: "+err.Error())
-			return
+			return false
 		}
 	}
 
@@ -227,29 +263,52 @@ func (b *Bot) setOwnerAddress(message *tgbotapi.Message, user *data.User, fileNa
 		address, err = erdgo.GetAddressFromPrivateKey(privateKey)
 		if err != nil {
 			b.sendMessage(user.TgID, "⭕️ Invalid file")
-			return
+			return false
 		}
 	}
 
 	if !erdgo.IsValidBech32Address(address) {
 		b.sendMessage(user.TgID, "⭕️ Invalid address")
-		return
+		return false
 	}
 
 	err = b.database.SetOwnerAddress(address)
-	if err == nil {
-		b.sendMessage(user.TgID, "✅ Owner address updated")
-	} else {
+	if err != nil {
 		b.sendMessage(user.TgID, "⭕️ Error setting owner address: "+err.Error())
+		return false
 	}
+	b.sendMessage(user.TgID, "✅ Owner address updated")
 
 	if len(privateKey) > 0 {
-		privateKeyStr := hex.EncodeToString(privateKey)
-		err = b.database.SetOwnerPrivateKey(privateKeyStr)
+		err = b.vault.Store(privateKey)
 		if err == nil {
-			b.sendMessage(user.TgID, "✅ Owner private key updated")
+			b.sendMessage(user.TgID, "✅ Owner private key encrypted and saved")
 		} else {
 			b.sendMessage(user.TgID, "⭕️ Error setting owner private key: "+err.Error())
 		}
 	}
+
+	return true
+}
+
+func (b *Bot) setNodeName(message *tgbotapi.Message, user *data.User, key string) bool {
+	name := strings.TrimSpace(message.Text)
+	if name == "-" {
+		name = ""
+	}
+
+	err := b.database.SetNodeName(key, name)
+	if err != nil {
+		b.sendMessage(user.TgID, "⭕️ Error saving node name in database")
+		return false
+	}
+
+	if name == "" {
+		b.sendMessage(user.TgID, "✅ Node name cleared")
+		return true
+	}
+
+	b.sendMessage(user.TgID, "✅ Node renamed to "+name)
+
+	return true
 }
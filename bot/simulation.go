@@ -0,0 +1,68 @@
+package bot
+
+import (
+	"fmt"
+
+	"github.com/DrDelphi/ElrondDSSC/data"
+	"github.com/DrDelphi/ElrondDSSC/network"
+	"github.com/DrDelphi/ElrondDSSC/utils"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+)
+
+// sendSimulatedTx - simulates the transaction from each of the user's verified wallets and sends the
+// estimated fees and the predicted errors, which reveal the wallets' balances. The wallet hook link is
+// sent only if the transaction can succeed from at least one verified wallet, if it could not be
+// simulated, or if the user has no verified wallets
+func (b *Bot) sendSimulatedTx(user *data.User, title string, req *network.TxRequest, buttonText string) {
+	text := fmt.Sprintf("`%s`", title)
+	if len(user.Wallets) == 0 {
+		text += "\n\rℹ️ Add your wallets in My Wallets to have the transaction checked before sending it"
+	}
+
+	verified := 0
+	canSucceed := false
+	for _, w := range user.Wallets {
+		if !w.Verified {
+			text += fmt.Sprintf("\n\rℹ️ %s: verify the wallet from Balances to have the transaction checked",
+				shortAddress(w.Address))
+			continue
+		}
+		verified++
+
+		simulation, err := b.simulator.SimulateTransaction(w.Address, req)
+		if err != nil {
+			text += fmt.Sprintf("\n\r⚠️ %s: can not be checked right now", shortAddress(w.Address))
+			canSucceed = true
+			continue
+		}
+
+		if !simulation.Succeeded() {
+			text += fmt.Sprintf("\n\r⭕️ %s: will fail - %s", shortAddress(w.Address),
+				utils.EscapeMarkdown(simulation.Error))
+			continue
+		}
+
+		text += fmt.Sprintf("\n\r✅ %s: estimated fee %s eGLD", shortAddress(w.Address), simulation.Fee.Format(6))
+		canSucceed = true
+	}
+	if verified == 0 {
+		canSucceed = true
+	}
+
+	msg := tgbotapi.NewMessage(user.TgID, text)
+	msg.ParseMode = tgbotapi.ModeMarkdown
+	if !canSucceed {
+		msg.Text += "\n\r\n\rThe transaction would fail from all your verified wallets, so no link is provided"
+		b.tgBot.Send(msg)
+		return
+	}
+
+	url := b.hookURL(user.TgID, title, req)
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonURL(buttonText, url),
+		),
+	)
+	b.tgBot.Send(msg)
+	b.sendTxQRCode(user.TgID, title, url)
+}
@@ -0,0 +1,139 @@
+package bot
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/DrDelphi/ElrondDSSC/data"
+	"github.com/DrDelphi/ElrondDSSC/utils"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+)
+
+// walletChallengeMaxAge - how long a wallet verification challenge can be signed for
+const walletChallengeMaxAge = time.Hour
+
+// walletChallengeNonceLength - size in bytes of the random part of a challenge
+const walletChallengeNonceLength = 16
+
+// sendWalletChallenge - issues a new challenge for one of the user's wallets and asks for its signature
+func (b *Bot) sendWalletChallenge(user *data.User, id uint64) {
+	wallet := findWallet(user, id)
+	if wallet == nil {
+		b.sendMessage(user.TgID, "⭕️ Wallet not found")
+		return
+	}
+	if wallet.Verified {
+		b.sendMessage(user.TgID, "✅ Wallet already verified")
+		return
+	}
+
+	nonce := make([]byte, walletChallengeNonceLength)
+	_, err := rand.Read(nonce)
+	if err != nil {
+		log.Error("can not generate wallet challenge", "error", err)
+		b.sendMessage(user.TgID, "⭕️ Can not generate a challenge right now")
+		return
+	}
+
+	challenge := fmt.Sprintf("Verify %s for Telegram user %v: %s", wallet.Address, user.TgID, hex.EncodeToString(nonce))
+	err = b.database.SetWalletChallenge(wallet.ID, challenge)
+	if err != nil {
+		b.sendMessage(user.TgID, "⭕️ Error saving the challenge in database")
+		return
+	}
+
+	b.sendMessage(user.TgID, fmt.Sprintf("🔐 Sign this message with the wallet %s (Web Wallet > Utils > Sign "+
+		"message), within an hour:\n\r\n\r`%s`", wallet.Address, challenge))
+
+	b.askFor(user, stateWalletSignature, wallet.Address, utils.VerifyWalletMessage+" "+wallet.Address)
+}
+
+// verifyWallet - checks the signature sent by the user against the challenge of the wallet with the address.
+// Returns false if the user can send the signature again
+func (b *Bot) verifyWallet(message *tgbotapi.Message, user *data.User, address string) bool {
+	var wallet *data.UserWallet
+	for _, w := range user.Wallets {
+		if w.Address == address {
+			wallet = w
+			break
+		}
+	}
+	if wallet == nil {
+		b.sendMessage(user.TgID, "⭕️ Wallet not found")
+		return true
+	}
+
+	challenge, createdAt, err := b.database.GetWalletChallenge(wallet.ID)
+	if err != nil {
+		b.sendMessage(user.TgID, "⭕️ Error reading the challenge from database")
+		return false
+	}
+	if challenge == "" || time.Since(time.Unix(createdAt, 0)) > walletChallengeMaxAge {
+		b.sendMessage(user.TgID, "⭕️ The challenge expired. Request a new one from Balances")
+		return true
+	}
+
+	signature, err := parseSignature(message.Text)
+	if err != nil {
+		b.sendMessage(user.TgID, "⭕️ Invalid signature format")
+		return false
+	}
+
+	err = utils.VerifyMessageSignature(wallet.Address, challenge, signature)
+	if err != nil {
+		log.Info("wallet verification failed", "address", wallet.Address, "user", user.TgID, "error", err)
+		b.sendMessage(user.TgID, "⭕️ The signature does not match the challenge and the wallet")
+		return false
+	}
+
+	err = b.database.SetWalletVerified(wallet)
+	if err != nil {
+		b.sendMessage(user.TgID, "⭕️ Error saving the verification in database")
+		return false
+	}
+
+	b.sendMessage(user.TgID, "✅ Wallet verified")
+
+	return true
+}
+
+// parseSignature - reads a hex signature, as is or from the JSON the web wallet outputs
+func parseSignature(text string) ([]byte, error) {
+	text = strings.TrimSpace(text)
+	if strings.HasPrefix(text, "{") {
+		signed := struct {
+			Signature string `json:"signature"`
+		}{}
+		err := json.Unmarshal([]byte(text), &signed)
+		if err != nil {
+			return nil, err
+		}
+		text = signed.Signature
+	}
+
+	return hex.DecodeString(strings.TrimPrefix(text, "0x"))
+}
+
+// findWallet - returns the user's wallet with the given ID, or nil
+func findWallet(user *data.User, id uint64) *data.UserWallet {
+	for _, w := range user.Wallets {
+		if w.ID == id {
+			return w
+		}
+	}
+
+	return nil
+}
+
+// walletStatus - returns the text showing whether a wallet is verified
+func walletStatus(wallet *data.UserWallet) string {
+	if wallet.Verified {
+		return "🔐 verified"
+	}
+
+	return "⚠️ not verified"
+}
@@ -0,0 +1,41 @@
+package bot
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestParseSignature(t *testing.T) {
+	signature := bytes.Repeat([]byte{0xab}, 64)
+	signatureHex := strings.Repeat("ab", 64)
+
+	tests := []struct {
+		name    string
+		text    string
+		want    []byte
+		wantErr bool
+	}{
+		{"bare hex", signatureHex, signature, false},
+		{"hex with 0x", "0x" + signatureHex, signature, false},
+		{"surrounding spaces", "  " + signatureHex + "\n", signature, false},
+		{"web wallet json", `{"address":"erd1","message":"0x6869","signature":"0x` + signatureHex + `","version":1,"signer":"ErdJS"}`, signature, false},
+		{"web wallet json without 0x", `{"signature":"` + signatureHex + `"}`, signature, false},
+		{"invalid json", `{"signature":`, nil, true},
+		{"json without signature", `{"address":"erd1"}`, []byte{}, false},
+		{"not hex", "not a signature", nil, true},
+		{"odd length hex", "0xabc", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseSignature(tt.text)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseSignature() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !bytes.Equal(got, tt.want) {
+				t.Fatalf("parseSignature() = %x, want %x", got, tt.want)
+			}
+		})
+	}
+}
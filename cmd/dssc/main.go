@@ -2,19 +2,26 @@ package main
 
 import (
 	"fmt"
+	"io/ioutil"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"syscall"
 	"time"
 
 	"github.com/DrDelphi/ElrondDSSC/bot"
 	"github.com/DrDelphi/ElrondDSSC/config"
+	"github.com/DrDelphi/ElrondDSSC/data"
 	"github.com/DrDelphi/ElrondDSSC/db"
 	"github.com/DrDelphi/ElrondDSSC/network"
+	"github.com/DrDelphi/ElrondDSSC/network/proxytest"
+	"github.com/DrDelphi/ElrondDSSC/network/simulator"
 	"github.com/DrDelphi/ElrondDSSC/utils"
+	"github.com/DrDelphi/ElrondDSSC/vault"
 	logger "github.com/ElrondNetwork/elrond-go-logger"
 	"github.com/ElrondNetwork/elrond-go/cmd/node/factory"
 	"github.com/ElrondNetwork/elrond-go/core/logging"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
 	"github.com/urfave/cli"
 )
 
@@ -28,11 +35,14 @@ var (
 	dsscHelpTemplate = `NAME:
    {{.Name}} - {{.Usage}}
 USAGE:
-   {{.HelpName}} {{if .VisibleFlags}}[global options]{{end}}
+   {{.HelpName}} {{if .VisibleFlags}}[global options]{{end}}{{if .Commands}} [command]{{end}}
    {{if len .Authors}}
 AUTHOR:
    {{range .Authors}}{{ . }}{{end}}
    {{end}}{{if .Commands}}
+COMMANDS:
+   {{range .VisibleCommands}}{{join .Names ", "}}{{"\t"}}{{.Usage}}
+   {{end}}
 GLOBAL OPTIONS:
    {{range .VisibleFlags}}{{.}}
    {{end}}
@@ -60,6 +70,32 @@ VERSION:
 		Name:  "log-save",
 		Usage: "Boolean option for enabling log saving. If set, it will automatically save all the logs into a file.",
 	}
+	// proxyStandIn defines a flag for replacing the Elrond proxy and API with a local stand-in
+	proxyStandIn = cli.StringFlag{
+		Name: "proxy-stand-in",
+		Usage: "Serve the Elrond proxy, meta observer and API from a local stand-in. `mode` can be: fake (empty " +
+			"in-memory network), record (forward to the configured URLs and save the responses) or replay " +
+			"(serve the saved responses offline). The fake mode uses a temporary database instead of the configured one.",
+	}
+	// sandbox defines a flag for running against the in-memory delegation contract simulator
+	sandbox = cli.BoolFlag{
+		Name: "sandbox",
+		Usage: "Boolean option for running against a simulated network and delegation contract instead of the " +
+			"configured Elrond network. Nothing is sent to the real network in this mode, and a temporary database " +
+			"is used instead of the configured one.",
+	}
+	// networkFlag defines the network profile the application runs on
+	networkFlag = cli.StringFlag{
+		Name: "network",
+		Usage: "The `name` of the network profile to run on: mainnet, testnet, devnet, localnet or one defined " +
+			"in the configuration file. Overrides the network set in the configuration file.",
+	}
+	// cassettesPath defines the directory holding the stand-in's recorded responses
+	cassettesPath = cli.StringFlag{
+		Name:  "cassettes-path",
+		Usage: "The proxy stand-in will record to and replay from the cassette files in this directory",
+		Value: "./cassettes",
+	}
 )
 
 var log = logger.GetOrCreate("main")
@@ -71,8 +107,12 @@ func main() {
 	app.Usage = "This app is a Telegram Bot for interacting with the Elrond DSSC"
 	app.Flags = []cli.Flag{
 		configPathFlag,
+		networkFlag,
 		logLevel,
 		logSaveFile,
+		proxyStandIn,
+		cassettesPath,
+		sandbox,
 	}
 	app.Version = "v0.0.1"
 	app.Authors = []cli.Author{
@@ -85,6 +125,24 @@ func main() {
 	app.Action = func(c *cli.Context) error {
 		return startApp(c)
 	}
+	app.Commands = []cli.Command{
+		{
+			Name:  "db",
+			Usage: "Manages the application's database schema",
+			Subcommands: []cli.Command{
+				{
+					Name:   "migrate",
+					Usage:  "Creates the database if needed and applies the pending schema migrations",
+					Action: migrateDatabase,
+				},
+				{
+					Name:   "status",
+					Usage:  "Lists the database schema migrations and whether they are applied",
+					Action: databaseStatus,
+				},
+			},
+		},
+	}
 
 	err := app.Run(os.Args)
 	if err != nil {
@@ -130,12 +188,96 @@ func startApp(ctx *cli.Context) error {
 		return err
 	}
 
+	err = config.SelectNetwork(appConfig, ctx.GlobalString(networkFlag.Name))
+	if err != nil {
+		return err
+	}
+	if appConfig.Network != "" {
+		log.Info("network profile selected", "network", appConfig.Network, "chain ID", appConfig.ChainID)
+	}
+
+	standInMode := ctx.GlobalString(proxyStandIn.Name)
+	if ctx.GlobalBool(sandbox.Name) && standInMode != "" {
+		return fmt.Errorf("the --%s and --%s flags can not be used together", sandbox.Name, proxyStandIn.Name)
+	}
+	sandboxMode := ctx.GlobalBool(sandbox.Name)
+	fakeNetwork := sandboxMode
+	if sandboxMode {
+		log.Info("starting sandbox network...")
+
+		simConfig := simulator.DefaultConfig()
+		sim := simulator.NewSimulator(simConfig)
+		sim.Start()
+		defer sim.Close()
+
+		sandboxServer := proxytest.NewFakeServer(sim)
+		defer func() {
+			log.LogIfError(sandboxServer.Close())
+		}()
+
+		appConfig.MetaObserver = sandboxServer.URL()
+		appConfig.NetworkProxy = sandboxServer.URL()
+		appConfig.NetworkAPI = sandboxServer.URL()
+		appConfig.NetworkProxies = nil
+		appConfig.MetaObservers = nil
+		appConfig.ChainID = simConfig.ChainID
+	}
+	if standInMode != "" {
+		log.Info("starting proxy stand-in...", "mode", standInMode)
+
+		standIns, err := startProxyStandIns(appConfig, standInMode, ctx.GlobalString(cassettesPath.Name))
+		if err != nil {
+			return err
+		}
+		defer func() {
+			for _, s := range standIns {
+				log.LogIfError(s.Close())
+			}
+		}()
+		mode, _ := proxytest.ParseMode(standInMode)
+		fakeNetwork = mode == proxytest.ModeFake
+	}
+
+	// the simulated and the fake networks start over at each run, so they get a new database, leaving the
+	// configured one and its chain ID untouched
+	if fakeNetwork {
+		scratchDir, err := ioutil.TempDir("", "dssc-scratch")
+		if err != nil {
+			return err
+		}
+		defer func() {
+			log.LogIfError(os.RemoveAll(scratchDir))
+		}()
+		appConfig.Database = &data.DatabaseConfig{
+			Driver:     db.DriverSQLite,
+			DataSource: filepath.Join(scratchDir, "scratch.sqlite"),
+		}
+	}
+
 	log.Info("opening database...")
 
-	database, err := db.NewDatabase(appConfig.DatabasePath)
+	database, err := db.NewDatabase(appConfig.DatabaseSource())
+	if err != nil {
+		return err
+	}
+	defer func() {
+		log.LogIfError(database.Close())
+	}()
+
+	log.Info("unlocking key vault...")
+
+	passphrase, err := vault.ReadPassphrase(appConfig.KeyVault)
+	if err != nil {
+		return err
+	}
+	keyVault, err := vault.NewKeyVault(database, passphrase)
+	vault.Zero(passphrase)
 	if err != nil {
 		return err
 	}
+	if keyVault.Locked() {
+		log.Info("key vault locked: the owner private key can not be saved or used")
+	}
 
 	log.Info("initializing network manager...")
 
@@ -144,13 +286,66 @@ func startApp(ctx *cli.Context) error {
 		return err
 	}
 
+	if !fakeNetwork {
+		err = checkDatabaseChainID(database, networkManager.ChainID())
+		if err != nil {
+			return err
+		}
+	}
+
+	contracts := network.NewContractRegistry(networkManager, database)
+	networkManager.SetContractAddressProvider(contracts)
+	txTracker := network.NewTxTracker(networkManager, database)
+	txTracker.OnOutcome(networkManager.HandleTxOutcome)
+	clock := network.NewNetworkClock(networkManager, networkManager.GetNetworkConfig(), network.MetachainShardID)
+	nodes := network.NewNodesProvider(networkManager, networkManager, contracts, database)
+	providers := network.NewProviderDirectory(networkManager, database)
+	apr := network.NewAPREstimator(networkManager, networkManager, contracts, clock)
+	history := network.NewRewardsHistory(networkManager, database, contracts)
+	activity := network.NewActivityIndexer(networkManager, database, contracts)
+	snapshots := network.NewStakeSnapshotter(networkManager, database, database, contracts, clock,
+		appConfig.SnapshotInterval())
+
+	deps := &bot.Dependencies{
+		Database:     database,
+		Contract:     networkManager,
+		Accounts:     networkManager,
+		Transactions: networkManager,
+		Simulator:    networkManager,
+		TxTracker:    txTracker,
+		Contracts:    contracts,
+		Clock:        clock,
+		Nodes:        nodes,
+		Providers:    providers,
+		APR:          apr,
+		History:      history,
+		Activity:     activity,
+		Snapshots:    snapshots,
+		Vault:        keyVault,
+	}
+
+	if appConfig.HookCallback != nil && appConfig.HookCallback.ListenAddress != "" {
+		log.Info("starting wallet callback server...")
+
+		callbacks := network.NewHookCallbackServer(appConfig.HookCallback.ListenAddress, database, networkManager)
+		err = callbacks.Start()
+		if err != nil {
+			return err
+		}
+		defer func() {
+			log.LogIfError(callbacks.Close())
+		}()
+		deps.Callbacks = callbacks
+	}
+
 	log.Info("creating Telegram bot instance...")
 
-	tgBot, err := bot.NewBot(appConfig, database, networkManager)
+	deps.Telegram, err = tgbotapi.NewBotAPI(appConfig.BotToken)
 	if err != nil {
+		log.Error("can not create telegram bot", "error", err)
 		return err
 	}
-
+	tgBot := bot.NewBot(appConfig, deps)
 	tgBot.StartTasks()
 
 	log.Info("application is now running...")
@@ -166,6 +361,126 @@ func startApp(ctx *cli.Context) error {
 	return nil
 }
 
+// startProxyStandIns - starts the meta observer, proxy and API stand-ins and points the configuration to them
+func startProxyStandIns(cfg *data.AppConfig, mode string, dir string) ([]*proxytest.Server, error) {
+	standInMode, err := proxytest.ParseMode(mode)
+	if err != nil {
+		return nil, err
+	}
+
+	if standInMode == proxytest.ModeRecord {
+		err = os.MkdirAll(dir, os.ModePerm)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if standInMode == proxytest.ModeFake {
+		cfg.ChainID = proxytest.DefaultChainID
+	}
+	cfg.NetworkProxies = nil
+	cfg.MetaObservers = nil
+
+	backend := proxytest.NewStaticBackend()
+	urls := []*string{&cfg.MetaObserver, &cfg.NetworkProxy, &cfg.NetworkAPI}
+	names := []string{"meta", "proxy", "api"}
+	servers := make([]*proxytest.Server, 0, len(urls))
+	for i, url := range urls {
+		cassette := filepath.Join(dir, names[i]+".json")
+
+		var server *proxytest.Server
+		switch standInMode {
+		case proxytest.ModeFake:
+			server = proxytest.NewFakeServer(backend)
+		case proxytest.ModeRecord:
+			server = proxytest.NewRecordingServer(*url, cassette)
+		case proxytest.ModeReplay:
+			server, err = proxytest.NewReplayServer(cassette, nil)
+			if err != nil {
+				for _, s := range servers {
+					_ = s.Close()
+				}
+				return nil, err
+			}
+		}
+
+		log.Info("proxy stand-in started", "name", names[i], "url", server.URL(), "replaces", *url)
+		*url = server.URL()
+		servers = append(servers, server)
+	}
+
+	return servers, nil
+}
+
+// checkDatabaseChainID - binds the database to the network it is first used with and refuses any other network
+func checkDatabaseChainID(database db.Store, chainID string) error {
+	savedChainID, err := database.GetChainID()
+	if err != nil {
+		return err
+	}
+	if savedChainID == "" {
+		return database.SetChainID(chainID)
+	}
+	if savedChainID != chainID {
+		return fmt.Errorf("%w: the database was used with chain %q, the network is %q",
+			network.ErrChainIDMismatch, savedChainID, chainID)
+	}
+
+	return nil
+}
+
+// migrateDatabase - applies the pending schema migrations to the configured database
+func migrateDatabase(ctx *cli.Context) error {
+	appConfig, err := config.NewConfig(ctx.GlobalString(configPathFlag.Name))
+	if err != nil {
+		return err
+	}
+
+	applied, err := db.Migrate(appConfig.DatabaseSource())
+	if err != nil {
+		return err
+	}
+
+	if len(applied) == 0 {
+		fmt.Println("the database schema is up to date")
+		return nil
+	}
+	for _, m := range applied {
+		fmt.Printf("applied %v: %s\n", m.Version, m.Description)
+	}
+
+	return nil
+}
+
+// databaseStatus - prints the schema migrations of the configured database
+func databaseStatus(ctx *cli.Context) error {
+	appConfig, err := config.NewConfig(ctx.GlobalString(configPathFlag.Name))
+	if err != nil {
+		return err
+	}
+
+	driver, dataSource := appConfig.DatabaseSource()
+	status, err := db.SchemaStatus(driver, dataSource)
+	if err != nil {
+		return err
+	}
+
+	if driver == db.DriverSQLite {
+		fmt.Println("database:", dataSource)
+	} else {
+		fmt.Println("database:", driver)
+	}
+	for _, m := range status {
+		state := "pending"
+		if m.AppliedAt > 0 {
+			state = "applied " + time.Unix(m.AppliedAt, 0).UTC().Format("2006-01-02 15:04:05 UTC")
+		}
+		fmt.Printf("%3v  %-40s %s\n", m.Version, m.Description, state)
+	}
+
+	return nil
+}
+
 func getWorkingDir(log logger.Logger) string {
 	workingDir, err := os.Getwd()
 	if err != nil {
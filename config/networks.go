@@ -0,0 +1,152 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/DrDelphi/ElrondDSSC/data"
+)
+
+// ErrNoWalletHook - the transaction links can not be built without the web wallet's URL
+var ErrNoWalletHook = errors.New("no web wallet set: add walletHook to the config or to the network profile")
+
+// DefaultNetworks - returns the built-in network profiles
+func DefaultNetworks() map[string]*data.NetworkProfile {
+	return map[string]*data.NetworkProfile{
+		"mainnet": {
+			ChainID:      "1",
+			NetworkAPI:   "https://api.elrond.com",
+			NetworkProxy: "https://gateway.elrond.com",
+			ExplorerURL:  "https://explorer.elrond.com",
+			WalletHook:   "https://wallet.elrond.com",
+		},
+		"testnet": {
+			ChainID:      "T",
+			NetworkAPI:   "https://testnet-api.elrond.com",
+			NetworkProxy: "https://testnet-gateway.elrond.com",
+			ExplorerURL:  "https://testnet-explorer.elrond.com",
+			WalletHook:   "https://testnet-wallet.elrond.com",
+		},
+		"devnet": {
+			ChainID:      "D",
+			NetworkAPI:   "https://devnet-api.elrond.com",
+			NetworkProxy: "https://devnet-gateway.elrond.com",
+			ExplorerURL:  "https://devnet-explorer.elrond.com",
+			WalletHook:   "https://devnet-wallet.elrond.com",
+		},
+		"localnet": {
+			ChainID:      "localnet",
+			NetworkAPI:   "http://127.0.0.1:7950",
+			NetworkProxy: "http://127.0.0.1:7950",
+			WalletHook:   "http://127.0.0.1:3000", // a web wallet run locally against the localnet proxy
+		},
+	}
+}
+
+// SelectNetwork - applies the named network profile (or, if name is empty, the one set in the config
+// file) to the configuration. The file's profiles are merged over the built-in ones. The explorer and web
+// wallet URLs set at the top level of the file are kept, unless the file's profile overrides them. Without
+// any network name the top level endpoints of the config file are used as they are
+func SelectNetwork(cfg *data.AppConfig, name string) error {
+	if name == "" {
+		name = cfg.Network
+	}
+	cfg.SystemSCs = data.DefaultSystemSCAddresses().Merge(cfg.SystemSCs)
+	if name == "" {
+		return checkWalletHook(cfg)
+	}
+
+	profiles := DefaultNetworks()
+	for profileName, profile := range cfg.Networks {
+		profiles[profileName] = mergeProfiles(profiles[profileName], profile)
+	}
+
+	profile, ok := profiles[name]
+	if !ok {
+		return fmt.Errorf("unknown network %q, available networks: %s", name, strings.Join(profileNames(profiles), ", "))
+	}
+	if profile.ChainID == "" {
+		return fmt.Errorf("the %s network profile has no chain ID", name)
+	}
+
+	override := cfg.Networks[name]
+	if override == nil {
+		override = &data.NetworkProfile{}
+	}
+
+	cfg.Network = name
+	cfg.ChainID = profile.ChainID
+	cfg.NetworkAPI = profile.NetworkAPI
+	cfg.NetworkProxy = profile.NetworkProxy
+	cfg.MetaObserver = profile.MetaObserver
+	cfg.NetworkProxies = profile.NetworkProxies
+	cfg.MetaObservers = profile.MetaObservers
+	if cfg.ExplorerURL == "" || override.ExplorerURL != "" {
+		cfg.ExplorerURL = profile.ExplorerURL
+	}
+	if cfg.WalletHook == "" || override.WalletHook != "" {
+		cfg.WalletHook = profile.WalletHook
+	}
+	cfg.SystemSCs = cfg.SystemSCs.Merge(profile.SystemSCs)
+
+	return checkWalletHook(cfg)
+}
+
+// checkWalletHook - returns an error if the web wallet the transaction links open is not set
+func checkWalletHook(cfg *data.AppConfig) error {
+	if strings.TrimSpace(cfg.WalletHook) == "" {
+		return ErrNoWalletHook
+	}
+
+	return nil
+}
+
+// mergeProfiles - returns base with the non empty fields of override
+func mergeProfiles(base *data.NetworkProfile, override *data.NetworkProfile) *data.NetworkProfile {
+	if base == nil {
+		return override
+	}
+
+	merged := *base
+	if override.ChainID != "" {
+		merged.ChainID = override.ChainID
+	}
+	if override.NetworkAPI != "" {
+		merged.NetworkAPI = override.NetworkAPI
+	}
+	if override.NetworkProxy != "" {
+		merged.NetworkProxy = override.NetworkProxy
+	}
+	if override.MetaObserver != "" {
+		merged.MetaObserver = override.MetaObserver
+	}
+	if len(override.NetworkProxies) > 0 {
+		merged.NetworkProxies = override.NetworkProxies
+	}
+	if len(override.MetaObservers) > 0 {
+		merged.MetaObservers = override.MetaObservers
+	}
+	if override.ExplorerURL != "" {
+		merged.ExplorerURL = override.ExplorerURL
+	}
+	if override.WalletHook != "" {
+		merged.WalletHook = override.WalletHook
+	}
+	if override.SystemSCs != nil {
+		merged.SystemSCs = override.SystemSCs
+	}
+
+	return &merged
+}
+
+func profileNames(profiles map[string]*data.NetworkProfile) []string {
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,98 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/DrDelphi/ElrondDSSC/data"
+)
+
+func TestSelectNetworkURLs(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfg         *data.AppConfig
+		network     string
+		walletHook  string
+		explorerURL string
+	}{
+		{
+			name:        "profile values",
+			cfg:         &data.AppConfig{},
+			network:     "testnet",
+			walletHook:  "https://testnet-wallet.elrond.com",
+			explorerURL: "https://testnet-explorer.elrond.com",
+		},
+		{
+			name:        "top level values kept",
+			cfg:         &data.AppConfig{WalletHook: "https://my-wallet", ExplorerURL: "https://my-explorer"},
+			network:     "mainnet",
+			walletHook:  "https://my-wallet",
+			explorerURL: "https://my-explorer",
+		},
+		{
+			name: "file profile over top level values",
+			cfg: &data.AppConfig{WalletHook: "https://my-wallet", ExplorerURL: "https://my-explorer",
+				Networks: map[string]*data.NetworkProfile{"devnet": {WalletHook: "https://devnet-wallet"}}},
+			network:     "devnet",
+			walletHook:  "https://devnet-wallet",
+			explorerURL: "https://my-explorer",
+		},
+		{
+			name:       "localnet",
+			cfg:        &data.AppConfig{},
+			network:    "localnet",
+			walletHook: "http://127.0.0.1:3000",
+		},
+		{
+			name:       "no network",
+			cfg:        &data.AppConfig{WalletHook: "https://my-wallet"},
+			walletHook: "https://my-wallet",
+		},
+	}
+
+	for _, tt := range tests {
+		err := SelectNetwork(tt.cfg, tt.network)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if tt.cfg.WalletHook != tt.walletHook || tt.cfg.ExplorerURL != tt.explorerURL {
+			t.Errorf("%s: got wallet %q and explorer %q, want %q and %q", tt.name, tt.cfg.WalletHook,
+				tt.cfg.ExplorerURL, tt.walletHook, tt.explorerURL)
+		}
+	}
+}
+
+func TestSelectNetworkWithoutWalletHook(t *testing.T) {
+	err := SelectNetwork(&data.AppConfig{}, "")
+	if err != ErrNoWalletHook {
+		t.Errorf("no network: expected ErrNoWalletHook, got %v", err)
+	}
+
+	cfg := &data.AppConfig{Networks: map[string]*data.NetworkProfile{"private": {ChainID: "P"}}}
+	err = SelectNetwork(cfg, "private")
+	if err != ErrNoWalletHook {
+		t.Errorf("profile without wallet: expected ErrNoWalletHook, got %v", err)
+	}
+}
+
+func TestSelectNetworkSystemSCs(t *testing.T) {
+	cfg := &data.AppConfig{
+		SystemSCs: &data.SystemSCAddresses{Validator: "erd1validator"},
+		Networks: map[string]*data.NetworkProfile{
+			"testnet": {SystemSCs: &data.SystemSCAddresses{EndOfEpoch: "erd1endofepoch"}},
+		},
+	}
+	err := SelectNetwork(cfg, "testnet")
+	if err != nil {
+		t.Fatalf("SelectNetwork: %v", err)
+	}
+
+	want := data.SystemSCAddresses{
+		DelegationManager: data.DelegationManagerAddress,
+		Validator:         "erd1validator",
+		EndOfEpoch:        "erd1endofepoch",
+	}
+	if *cfg.SystemSCs != want {
+		t.Errorf("got %+v, want %+v", *cfg.SystemSCs, want)
+	}
+}
@@ -0,0 +1,253 @@
+package data
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// Denomination - number of decimals of the eGLD token
+const Denomination = 18
+
+var (
+	errInvalidAmount  = errors.New("invalid amount")
+	errNegativeAmount = errors.New("negative amount")
+	errTooManyDigits  = fmt.Errorf("too many decimals (maximum is %v)", Denomination)
+
+	denominator = big.NewInt(0).Exp(big.NewInt(10), big.NewInt(Denomination), nil)
+)
+
+// Amount - holds an exact eGLD amount expressed in atto-eGLD (10^-18 eGLD).
+// A nil *Amount is treated as zero by all read-only methods
+type Amount struct {
+	value *big.Int
+}
+
+// NewAmount - creates a new Amount from an atto-eGLD value
+func NewAmount(value *big.Int) *Amount {
+	if value == nil {
+		return &Amount{value: big.NewInt(0)}
+	}
+
+	return &Amount{value: big.NewInt(0).Set(value)}
+}
+
+// NewAmountFromBytes - creates a new Amount from a big endian atto-eGLD value,
+// as returned by the smart contract queries
+func NewAmountFromBytes(b []byte) *Amount {
+	return &Amount{value: big.NewInt(0).SetBytes(b)}
+}
+
+// ParseAttoAmount - creates a new Amount from a base 10 atto-eGLD string, as used
+// by the proxy for balances and transaction values
+func ParseAttoAmount(s string) (*Amount, error) {
+	value, ok := big.NewInt(0).SetString(strings.TrimSpace(s), 10)
+	if !ok {
+		return nil, errInvalidAmount
+	}
+	if value.Sign() < 0 {
+		return nil, errNegativeAmount
+	}
+
+	return &Amount{value: value}, nil
+}
+
+// ParseAmount - parses a human readable eGLD amount like "12.5" or "10.123456789012345678"
+// without any loss of precision. Both '.' and ',' are accepted as decimal separator
+func ParseAmount(s string) (*Amount, error) {
+	s = strings.TrimSpace(s)
+	s = strings.Replace(s, ",", ".", 1)
+	if s == "" || s == "." {
+		return nil, errInvalidAmount
+	}
+	if strings.HasPrefix(s, "-") {
+		return nil, errNegativeAmount
+	}
+
+	intPart, fracPart := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		intPart, fracPart = s[:i], s[i+1:]
+	}
+	if !isDigits(intPart) || !isDigits(fracPart) {
+		return nil, errInvalidAmount
+	}
+
+	fracPart = strings.TrimRight(fracPart, "0")
+	if len(fracPart) > Denomination {
+		return nil, errTooManyDigits
+	}
+	fracPart += strings.Repeat("0", Denomination-len(fracPart))
+
+	value, ok := big.NewInt(0).SetString(intPart+fracPart, 10)
+	if !ok {
+		return nil, errInvalidAmount
+	}
+
+	return &Amount{value: value}, nil
+}
+
+// MustParseAmount - same as ParseAmount, but panics on invalid input.
+// It is meant for package level constants
+func MustParseAmount(s string) *Amount {
+	a, err := ParseAmount(s)
+	if err != nil {
+		panic(err)
+	}
+
+	return a
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Int - returns a copy of the atto-eGLD value
+func (a *Amount) Int() *big.Int {
+	if a == nil || a.value == nil {
+		return big.NewInt(0)
+	}
+
+	return big.NewInt(0).Set(a.value)
+}
+
+// Bytes - returns the big endian atto-eGLD value, as expected by the smart contract arguments
+func (a *Amount) Bytes() []byte {
+	return a.Int().Bytes()
+}
+
+// Sign - returns -1, 0 or +1 depending on the sign of the amount
+func (a *Amount) Sign() int {
+	return a.Int().Sign()
+}
+
+// IsZero - returns true if the amount is zero
+func (a *Amount) IsZero() bool {
+	return a.Sign() == 0
+}
+
+// Cmp - compares two amounts and returns -1, 0 or +1
+func (a *Amount) Cmp(b *Amount) int {
+	return a.Int().Cmp(b.Int())
+}
+
+// Add - returns a new Amount holding a + b
+func (a *Amount) Add(b *Amount) *Amount {
+	return &Amount{value: a.Int().Add(a.Int(), b.Int())}
+}
+
+// Sub - returns a new Amount holding a - b
+func (a *Amount) Sub(b *Amount) *Amount {
+	return &Amount{value: a.Int().Sub(a.Int(), b.Int())}
+}
+
+// Float64 - returns the approximate eGLD value, for estimations where the loss of precision is acceptable
+func (a *Amount) Float64() float64 {
+	f, _ := big.NewFloat(0).Quo(new(big.Float).SetInt(a.Int()), new(big.Float).SetInt(denominator)).Float64()
+
+	return f
+}
+
+// AmountFromFloat64 - creates a new Amount from an approximate eGLD value. Negative values are turned into zero
+func AmountFromFloat64(f float64) *Amount {
+	if f <= 0 {
+		return NewAmount(nil)
+	}
+
+	value, _ := big.NewFloat(0).Mul(big.NewFloat(f), new(big.Float).SetInt(denominator)).Int(nil)
+
+	return &Amount{value: value}
+}
+
+// Format - returns the eGLD amount with exactly precision decimals.
+// Extra decimals are truncated, never rounded up
+func (a *Amount) Format(precision int) string {
+	if precision < 0 {
+		precision = 0
+	}
+	if precision > Denomination {
+		precision = Denomination
+	}
+
+	value := a.Int()
+	sign := ""
+	if value.Sign() < 0 {
+		sign = "-"
+		value.Neg(value)
+	}
+
+	intPart, fracPart := big.NewInt(0).QuoRem(value, denominator, big.NewInt(0))
+	if precision == 0 {
+		return sign + intPart.String()
+	}
+
+	frac := fmt.Sprintf("%0*s", Denomination, fracPart.String())
+
+	return sign + intPart.String() + "." + frac[:precision]
+}
+
+// String - returns the exact eGLD amount without trailing zeros
+func (a *Amount) String() string {
+	s := a.Format(Denomination)
+	s = strings.TrimRight(s, "0")
+
+	return strings.TrimSuffix(s, ".")
+}
+
+// AttoString - returns the base 10 atto-eGLD value, as expected by the transaction value field
+func (a *Amount) AttoString() string {
+	return a.Int().String()
+}
+
+// MarshalJSON - encodes the amount as a quoted atto-eGLD string, like the Elrond API does
+func (a *Amount) MarshalJSON() ([]byte, error) {
+	return json.Marshal(a.AttoString())
+}
+
+// UnmarshalJSON - decodes an atto-eGLD amount given either as a string or as a number
+func (a *Amount) UnmarshalJSON(b []byte) error {
+	s := strings.Trim(string(b), `"`)
+	if s == "" || s == "null" {
+		a.value = big.NewInt(0)
+		return nil
+	}
+
+	parsed, err := ParseAttoAmount(s)
+	if err != nil {
+		return err
+	}
+	a.value = parsed.value
+
+	return nil
+}
+
+// Value - implements driver.Valuer. Amounts are stored as atto-eGLD strings
+func (a *Amount) Value() (driver.Value, error) {
+	return a.AttoString(), nil
+}
+
+// Scan - implements sql.Scanner
+func (a *Amount) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		a.value = big.NewInt(0)
+		return nil
+	case int64:
+		a.value = big.NewInt(v)
+		return nil
+	case []byte:
+		return a.UnmarshalJSON(v)
+	case string:
+		return a.UnmarshalJSON([]byte(v))
+	default:
+		return fmt.Errorf("can not scan %T into Amount", src)
+	}
+}
@@ -0,0 +1,186 @@
+package data_test
+
+import (
+	"database/sql"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/DrDelphi/ElrondDSSC/data"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		input   string
+		atto    string
+		wantErr bool
+	}{
+		{"12.5", "12500000000000000000", false},
+		{"0", "0", false},
+		{"1", "1000000000000000000", false},
+		{" 7 ", "7000000000000000000", false},
+		{"12,5", "12500000000000000000", false},
+		{".5", "500000000000000000", false},
+		{",25", "250000000000000000", false},
+		{"5.", "5000000000000000000", false},
+		{"10.123456789012345678", "10123456789012345678", false},
+		{"0.000000000000000001", "1", false},
+		{"1.1000000000000000000", "1100000000000000000", false},
+		{"0.0000000000000000001", "", true},
+		{"1e18", "", true},
+		{"1.5E3", "", true},
+		{"-1", "", true},
+		{"", "", true},
+		{".", "", true},
+		{"1.2.3", "", true},
+		{"1,2,3", "", true},
+		{"1 000", "", true},
+		{"abc", "", true},
+	}
+	for _, tt := range tests {
+		amount, err := data.ParseAmount(tt.input)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseAmount(%q) = %v, expected an error", tt.input, amount.AttoString())
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseAmount(%q): %v", tt.input, err)
+			continue
+		}
+		if amount.AttoString() != tt.atto {
+			t.Errorf("ParseAmount(%q) = %v, want %v", tt.input, amount.AttoString(), tt.atto)
+		}
+	}
+}
+
+func TestAmountFormat(t *testing.T) {
+	amount := data.MustParseAmount("1234.567891234567891234")
+	tests := []struct {
+		precision int
+		want      string
+	}{
+		{0, "1234"},
+		{2, "1234.56"},
+		{4, "1234.5678"},
+		{18, "1234.567891234567891234"},
+		{30, "1234.567891234567891234"},
+		{-1, "1234"},
+	}
+	for _, tt := range tests {
+		if got := amount.Format(tt.precision); got != tt.want {
+			t.Errorf("Format(%v) = %v, want %v", tt.precision, got, tt.want)
+		}
+	}
+
+	if got := data.MustParseAmount("12.5").Format(4); got != "12.5000" {
+		t.Errorf("Format(4) = %v, want 12.5000", got)
+	}
+	if got := data.MustParseAmount("1").Sub(data.MustParseAmount("2.5")).Format(2); got != "-1.50" {
+		t.Errorf("negative Format(2) = %v, want -1.50", got)
+	}
+	if got := data.MustParseAmount("10.500").String(); got != "10.5" {
+		t.Errorf("String() = %v, want 10.5", got)
+	}
+	var zero *data.Amount
+	if got := zero.String(); got != "0" {
+		t.Errorf("nil String() = %v, want 0", got)
+	}
+}
+
+func TestAmountJSON(t *testing.T) {
+	type holder struct {
+		Value *data.Amount `json:"value"`
+	}
+
+	original := holder{Value: data.MustParseAmount("10.123456789012345678")}
+	encoded, err := json.Marshal(original)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if string(encoded) != `{"value":"10123456789012345678"}` {
+		t.Errorf("Marshal = %s", encoded)
+	}
+
+	decoded := holder{}
+	err = json.Unmarshal(encoded, &decoded)
+	if err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if decoded.Value.Cmp(original.Value) != 0 {
+		t.Errorf("round trip = %v, want %v", decoded.Value, original.Value)
+	}
+
+	tests := []struct {
+		input   string
+		atto    string
+		wantErr bool
+	}{
+		{`{"value":12345}`, "12345", false},
+		{`{"value":""}`, "0", false},
+		{`{"value":"-5"}`, "", true},
+		{`{"value":"1.5"}`, "", true},
+		{`{"value":1e18}`, "", true},
+	}
+	for _, tt := range tests {
+		decoded := holder{}
+		err = json.Unmarshal([]byte(tt.input), &decoded)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("Unmarshal(%s) = %v, expected an error", tt.input, decoded.Value.AttoString())
+			}
+			continue
+		}
+		if err != nil || decoded.Value.AttoString() != tt.atto {
+			t.Errorf("Unmarshal(%s) = %v, %v, want %v", tt.input, decoded.Value.AttoString(), err, tt.atto)
+		}
+	}
+}
+
+func TestAmountSQL(t *testing.T) {
+	sqldb, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "amount.sqlite"))
+	if err != nil {
+		t.Fatalf("can not open database: %v", err)
+	}
+	defer sqldb.Close()
+
+	_, err = sqldb.Exec("create table Amounts(ID INTEGER PRIMARY KEY, Amount TEXT, Number INTEGER)")
+	if err != nil {
+		t.Fatalf("can not create table: %v", err)
+	}
+
+	original := data.MustParseAmount("123456789.123456789012345678")
+	_, err = sqldb.Exec("insert into Amounts(ID, Amount, Number) values(1, ?, 42)", original)
+	if err == nil {
+		_, err = sqldb.Exec("insert into Amounts(ID) values(2)")
+	}
+	if err != nil {
+		t.Fatalf("can not insert amounts: %v", err)
+	}
+
+	scanned := &data.Amount{}
+	number := &data.Amount{}
+	err = sqldb.QueryRow("select Amount, Number from Amounts where ID = 1").Scan(scanned, number)
+	if err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+	if scanned.Cmp(original) != 0 {
+		t.Errorf("round trip = %v, want %v", scanned, original)
+	}
+	if number.AttoString() != "42" {
+		t.Errorf("integer column scanned as %v, want 42", number.AttoString())
+	}
+
+	null := data.MustParseAmount("1")
+	err = sqldb.QueryRow("select Amount from Amounts where ID = 2").Scan(null)
+	if err != nil || !null.IsZero() {
+		t.Errorf("NULL scanned as %v, %v, want 0", null, err)
+	}
+
+	err = (&data.Amount{}).Scan(1.5)
+	if err == nil {
+		t.Error("expected an error when scanning a float")
+	}
+}
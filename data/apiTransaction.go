@@ -0,0 +1,32 @@
+package data
+
+// APITransaction - holds a transaction as listed by the API's /transactions endpoint
+type APITransaction struct {
+	Hash      string         `json:"txHash"`
+	Nonce     uint64         `json:"nonce"`
+	Round     uint64         `json:"round"`
+	Value     string         `json:"value"`
+	Receiver  string         `json:"receiver"`
+	Sender    string         `json:"sender"`
+	GasPrice  uint64         `json:"gasPrice"`
+	GasLimit  uint64         `json:"gasLimit"`
+	GasUsed   uint64         `json:"gasUsed"`
+	Data      []byte         `json:"data"` // base64 encoded by the API
+	Signature string         `json:"signature"`
+	Timestamp int64          `json:"timestamp"` // unix seconds
+	Status    string         `json:"status"`
+	Results   []*APIScResult `json:"results,omitempty"`
+}
+
+// APIScResult - holds a smart contract result of an API transaction
+type APIScResult struct {
+	Hash           string `json:"hash"`
+	Nonce          uint64 `json:"nonce"`
+	Value          string `json:"value"`
+	Sender         string `json:"sender"`
+	Receiver       string `json:"receiver"`
+	Data           []byte `json:"data"` // base64 encoded by the API
+	PrevTxHash     string `json:"prevTxHash"`
+	OriginalTxHash string `json:"originalTxHash"`
+	ReturnMessage  string `json:"returnMessage,omitempty"`
+}
@@ -1,12 +1,170 @@
 package data
 
+import "time"
+
 // AppConfig holds the application configuration read from config.json
 type AppConfig struct {
 	BotToken     string `json:"botToken"`
 	BotOwner     int64  `json:"botOwner"`
-	DatabasePath string `json:"databasePath"`
+	DatabasePath string `json:"databasePath"` // the SQLite file, used when no other database is configured
 	NetworkAPI   string `json:"networkAPI"`
 	NetworkProxy string `json:"networkProxy"`
 	MetaObserver string `json:"metaObserver"`
 	WalletHook   string `json:"walletHook"`
+
+	// optional fallback endpoints, used in round-robin order together with the ones above
+	NetworkProxies []string `json:"networkProxies,omitempty"`
+	MetaObservers  []string `json:"metaObservers,omitempty"`
+
+	HTTPClient *HTTPClientConfig `json:"httpClient,omitempty"`
+
+	// optional endpoint receiving the web wallet's redirect after signing a transaction
+	HookCallback *HookCallbackConfig `json:"hookCallback,omitempty"`
+
+	// optional database server used instead of the SQLite file
+	Database *DatabaseConfig `json:"database,omitempty"`
+
+	// optional schedule of the wallets' stake snapshots, taken at each epoch change by default
+	Snapshots *SnapshotsConfig `json:"snapshots,omitempty"`
+
+	// optional source of the passphrase encrypting the owner's private key
+	KeyVault *KeyVaultConfig `json:"keyVault,omitempty"`
+
+	// the network profile selected by default, overridden by the --network flag, and the
+	// profiles defined in the config file on top of the built-in ones
+	Network  string                     `json:"network,omitempty"`
+	Networks map[string]*NetworkProfile `json:"networks,omitempty"`
+
+	// set from the selected network profile
+	ChainID     string             `json:"chainID,omitempty"`
+	ExplorerURL string             `json:"explorerURL,omitempty"`
+	SystemSCs   *SystemSCAddresses `json:"systemSCs,omitempty"`
+}
+
+// NetworkProfile holds the settings of an Elrond network. Empty fields keep the built-in values
+type NetworkProfile struct {
+	ChainID        string             `json:"chainID"`
+	NetworkAPI     string             `json:"networkAPI"`
+	NetworkProxy   string             `json:"networkProxy"`
+	MetaObserver   string             `json:"metaObserver"`
+	NetworkProxies []string           `json:"networkProxies,omitempty"`
+	MetaObservers  []string           `json:"metaObservers,omitempty"`
+	ExplorerURL    string             `json:"explorerURL"`
+	WalletHook     string             `json:"walletHook"`
+	SystemSCs      *SystemSCAddresses `json:"systemSCs,omitempty"`
+}
+
+// SystemSCAddresses holds the addresses of the system smart contracts used by the application
+type SystemSCAddresses struct {
+	DelegationManager string `json:"delegationManager"`
+	Validator         string `json:"validator"`
+	EndOfEpoch        string `json:"endOfEpoch"` // the caller allowed to query the cumulated rewards
+}
+
+// the system smart contract addresses, the same on all the Elrond networks
+const (
+	DelegationManagerAddress = "erd1qqqqqqqqqqqqqqqpqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqylllslmq6y6"
+	ValidatorSCAddress       = "erd1qqqqqqqqqqqqqqqpqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqplllst77y4l"
+	EndOfEpochAddress        = "erd1qqqqqqqqqqqqqqqpqqqqqqqqlllllllllllllllllllllllllllsr9gav8"
+)
+
+// DefaultSystemSCAddresses - returns the system smart contract addresses of the Elrond networks
+func DefaultSystemSCAddresses() *SystemSCAddresses {
+	return &SystemSCAddresses{
+		DelegationManager: DelegationManagerAddress,
+		Validator:         ValidatorSCAddress,
+		EndOfEpoch:        EndOfEpochAddress,
+	}
+}
+
+// Merge - returns the addresses with the non empty fields of override replacing them
+func (s *SystemSCAddresses) Merge(override *SystemSCAddresses) *SystemSCAddresses {
+	merged := *s
+	if override == nil {
+		return &merged
+	}
+	if override.DelegationManager != "" {
+		merged.DelegationManager = override.DelegationManager
+	}
+	if override.Validator != "" {
+		merged.Validator = override.Validator
+	}
+	if override.EndOfEpoch != "" {
+		merged.EndOfEpoch = override.EndOfEpoch
+	}
+
+	return &merged
+}
+
+// HTTPClientConfig holds the optional timeout, retry and circuit breaker settings of the network requests
+type HTTPClientConfig struct {
+	TimeoutSeconds         int `json:"timeoutSeconds"`
+	MaxRetries             int `json:"maxRetries"`
+	BreakerThreshold       int `json:"breakerThreshold"`
+	BreakerCooldownSeconds int `json:"breakerCooldownSeconds"`
+}
+
+// HookCallbackConfig holds the address the wallet callback endpoint listens on and the public URL it is reachable at
+type HookCallbackConfig struct {
+	ListenAddress string `json:"listenAddress"`
+	PublicURL     string `json:"publicURL"`
+}
+
+// DatabaseConfig holds the driver ("sqlite3" or "postgres") and the data source name of the database
+type DatabaseConfig struct {
+	Driver     string `json:"driver"`
+	DataSource string `json:"dataSource"`
+}
+
+// SnapshotsConfig holds the interval between the stake snapshots. Zero takes them at each epoch change
+type SnapshotsConfig struct {
+	IntervalMinutes int `json:"intervalMinutes"`
+}
+
+// KeyVaultConfig holds where the key vault passphrase is read from. The environment variable is tried first
+type KeyVaultConfig struct {
+	PassphraseEnv  string `json:"passphraseEnv"`
+	PassphraseFile string `json:"passphraseFile"`
+}
+
+// DatabaseSource - returns the database driver and data source name, the SQLite file if none is configured
+func (c *AppConfig) DatabaseSource() (string, string) {
+	if c.Database == nil || c.Database.Driver == "" {
+		return "sqlite3", c.DatabasePath
+	}
+
+	return c.Database.Driver, c.Database.DataSource
+}
+
+// SnapshotInterval - returns the interval between the stake snapshots, zero for each epoch change
+func (c *AppConfig) SnapshotInterval() time.Duration {
+	if c.Snapshots == nil || c.Snapshots.IntervalMinutes <= 0 {
+		return 0
+	}
+
+	return time.Duration(c.Snapshots.IntervalMinutes) * time.Minute
+}
+
+// NetworkProxyURLs - returns the network proxy followed by the fallback proxies
+func (c *AppConfig) NetworkProxyURLs() []string {
+	return joinURLs(c.NetworkProxy, c.NetworkProxies)
+}
+
+// MetaObserverURLs - returns the meta observer followed by the fallback observers
+func (c *AppConfig) MetaObserverURLs() []string {
+	return joinURLs(c.MetaObserver, c.MetaObservers)
+}
+
+func joinURLs(first string, others []string) []string {
+	urls := make([]string, 0, len(others)+1)
+	if first != "" {
+		urls = append(urls, first)
+	}
+	for _, url := range others {
+		if url != "" && url != first {
+			urls = append(urls, url)
+		}
+	}
+
+	return urls
 }
@@ -0,0 +1,11 @@
+package data
+
+// APREstimate - holds the estimated yearly rewards of a delegation contract, in percents
+type APREstimate struct {
+	GrossAPR         float64 // before the service fee
+	NetAPR           float64 // received by the delegators
+	ServiceFee       float64
+	StakedNodes      uint64
+	TotalActiveStake *Amount
+	Epoch            uint32 // the epoch of the economics data used
+}
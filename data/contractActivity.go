@@ -0,0 +1,28 @@
+package data
+
+// ContractActivity - holds a transaction sent to a delegation contract, decoded
+type ContractActivity struct {
+	Hash      string
+	Contract  string
+	Sender    string
+	Function  string
+	Category  string
+	Args      []string // hex encoded, as found in the transaction's data
+	Value     *Amount
+	Amount    *Amount // the amount moved by the call, see network.DecodeContractActivity
+	Timestamp int64
+	Status    string
+}
+
+// ActivityCursor - holds an indexer's progress through a contract's transactions. Transactions
+// up to Synced are indexed. While a walk is in progress, the transactions newer than WalkBefore,
+// plus WalkSkip of those at exactly WalkBefore, are indexed down to Synced, and the walk ends
+// by setting Synced to WalkTop
+type ActivityCursor struct {
+	Contract   string
+	Synced     int64
+	Walking    bool
+	WalkTop    int64
+	WalkBefore int64
+	WalkSkip   int
+}
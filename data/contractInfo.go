@@ -1,9 +1,10 @@
 package data
 
 type ContractInfo struct {
+	OwnerAddress         string
 	ServiceFee           float64
-	MaxDelegationCap     float64
-	InitialOwnerFunds    float64
+	MaxDelegationCap     *Amount
+	InitialOwnerFunds    *Amount
 	AutomaticActivation  bool
 	WithDelegationCap    bool
 	ChangeableServiceFee bool
@@ -0,0 +1,8 @@
+package data
+
+// ContractMetadata - holds the staking provider identity set by the contract owner
+type ContractMetadata struct {
+	Name       string
+	Website    string
+	Identifier string // keybase identity
+}
@@ -0,0 +1,9 @@
+package data
+
+// Conversation - holds the answer the bot is waiting for from a user, and what it is about
+type Conversation struct {
+	TgID      int64
+	State     string
+	Param     string // the wallet address, node key... the answer is for, if any
+	ExpiresAt int64
+}
@@ -0,0 +1,23 @@
+package data
+
+// HookIntent - holds the user, the purpose and the transaction of a link sent to the web wallet, looked up
+// by the token found in the link's callback URL
+type HookIntent struct {
+	Token       string
+	TgID        int64
+	Description string
+	Sender      string // the expected sender, empty if any wallet can send the transaction
+	Receiver    string
+	Value       string // in atto-eGLD
+	Data        string
+	CreatedAt   int64
+}
+
+// HookCallback - holds the web wallet's redirect received after a transaction was signed or cancelled.
+// Sender is set once the transaction was found on the network and matched the intent
+type HookCallback struct {
+	Intent *HookIntent
+	Status string
+	TxHash string
+	Sender string
+}
@@ -7,6 +7,7 @@ type NetworkConfig struct {
 			ErdChainID                  string `json:"erd_chain_id"`
 			ErdDenomination             int    `json:"erd_denomination"`
 			ErdGasPerDataByte           uint64 `json:"erd_gas_per_data_byte"`
+			ErdGasPriceModifier         string `json:"erd_gas_price_modifier"`
 			ErdLatestTagSoftwareVersion string `json:"erd_latest_tag_software_version"`
 			ErdMetaConsensusGroupSize   uint64 `json:"erd_meta_consensus_group_size"`
 			ErdMinGasLimit              uint64 `json:"erd_min_gas_limit"`
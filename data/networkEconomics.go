@@ -0,0 +1,20 @@
+package data
+
+// EconomicsMetrics - holds the network wide staking and inflation values, in atto-eGLD
+type EconomicsMetrics struct {
+	TotalSupply           *Amount `json:"erd_total_supply"`
+	TotalStakedValue      *Amount `json:"erd_total_staked_value"`
+	TotalTopUpValue       *Amount `json:"erd_total_top_up_value"`
+	Inflation             *Amount `json:"erd_inflation"` // minted in the epoch the economics data refers to
+	DevRewards            *Amount `json:"erd_dev_rewards"`
+	EpochForEconomicsData uint32  `json:"erd_epoch_for_economics_data"`
+}
+
+// NetworkEconomics - holds the response of the proxy's /network/economics endpoint
+type NetworkEconomics struct {
+	Data struct {
+		Metrics EconomicsMetrics `json:"metrics"`
+	} `json:"data"`
+	Error string `json:"error"`
+	Code  string `json:"code"`
+}
@@ -0,0 +1,20 @@
+package data
+
+// NetworkStatus - holds a shard's status as received from the proxy's /network/status/{shard} endpoint
+type NetworkStatus struct {
+	Data struct {
+		Status struct {
+			ErdCurrentRound               uint64 `json:"erd_current_round"`
+			ErdEpochNumber                uint32 `json:"erd_epoch_number"`
+			ErdHighestFinalNonce          uint64 `json:"erd_highest_final_nonce"`
+			ErdNonce                      uint64 `json:"erd_nonce"`
+			ErdNonceAtEpochStart          uint64 `json:"erd_nonce_at_epoch_start"`
+			ErdNoncesPassedInCurrentEpoch uint64 `json:"erd_nonces_passed_in_current_epoch"`
+			ErdRoundAtEpochStart          uint64 `json:"erd_round_at_epoch_start"`
+			ErdRoundsPassedInCurrentEpoch uint64 `json:"erd_rounds_passed_in_current_epoch"`
+			ErdRoundsPerEpoch             uint64 `json:"erd_rounds_per_epoch"`
+		} `json:"status"`
+	} `json:"data"`
+	Error string `json:"error"`
+	Code  string `json:"code"`
+}
@@ -0,0 +1,54 @@
+package data
+
+import "encoding/hex"
+
+// ValidatorStatistics - holds a validator's statistics as received from the proxy's /validator/statistics endpoint
+type ValidatorStatistics struct {
+	TempRating                         float32 `json:"tempRating"`
+	Rating                             float32 `json:"rating"`
+	RatingModifier                     float32 `json:"ratingModifier"`
+	NumLeaderSuccess                   uint32  `json:"numLeaderSuccess"`
+	NumLeaderFailure                   uint32  `json:"numLeaderFailure"`
+	NumValidatorSuccess                uint32  `json:"numValidatorSuccess"`
+	NumValidatorFailure                uint32  `json:"numValidatorFailure"`
+	NumValidatorIgnoredSignatures      uint32  `json:"numValidatorIgnoredSignatures"`
+	TotalNumLeaderSuccess              uint32  `json:"totalNumLeaderSuccess"`
+	TotalNumLeaderFailure              uint32  `json:"totalNumLeaderFailure"`
+	TotalNumValidatorSuccess           uint32  `json:"totalNumValidatorSuccess"`
+	TotalNumValidatorFailure           uint32  `json:"totalNumValidatorFailure"`
+	TotalNumValidatorIgnoredSignatures uint32  `json:"totalNumValidatorIgnoredSignatures"`
+	ShardID                            uint32  `json:"shardId"`
+	ValidatorStatus                    string  `json:"validatorStatus"`
+}
+
+// ValidatorStatisticsResponse - holds the proxy's response for the validators statistics, indexed by hex BLS key
+type ValidatorStatisticsResponse struct {
+	Data struct {
+		Statistics map[string]*ValidatorStatistics `json:"statistics"`
+	} `json:"data"`
+	Error string `json:"error"`
+	Code  string `json:"code"`
+}
+
+// NodeInfo - holds everything known about one of the contract's nodes
+type NodeInfo struct {
+	BlsKey     []byte
+	Name       string               // set by the owner, empty if not set
+	DSSCState  string               // the state reported by getAllNodeStates (staked, notStaked, unStaked, ...)
+	SCStatus   string               // the status reported by the validator system SC's getBlsKeysStatus
+	Statistics *ValidatorStatistics // nil if the node has no statistics yet
+}
+
+// HexKey - returns the hex encoded BLS key
+func (n *NodeInfo) HexKey() string {
+	return hex.EncodeToString(n.BlsKey)
+}
+
+// Jailed - returns true if either the validator system SC or the statistics report the node as jailed
+func (n *NodeInfo) Jailed() bool {
+	if n.SCStatus == "jailed" {
+		return true
+	}
+
+	return n.Statistics != nil && n.Statistics.ValidatorStatus == "jailed"
+}
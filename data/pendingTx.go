@@ -0,0 +1,9 @@
+package data
+
+// PendingTx - holds a broadcasted transaction whose outcome has not been reported yet
+type PendingTx struct {
+	Hash        string
+	TgID        int64
+	Description string
+	CreatedAt   int64
+}
@@ -0,0 +1,47 @@
+package data
+
+// Provider - holds the public details of a delegation contract, as listed in the provider directory
+type Provider struct {
+	Address          string
+	OwnerAddress     string
+	Name             string
+	Website          string
+	Identifier       string // keybase identity
+	ServiceFee       float64
+	MaxDelegationCap *Amount // zero for contracts without a delegation cap
+	TotalActiveStake *Amount
+	NumUsers         uint64
+	UpdatedAt        int64
+}
+
+// Capped - returns true if the contract has a maximum delegation cap
+func (p *Provider) Capped() bool {
+	return !p.MaxDelegationCap.IsZero()
+}
+
+// RemainingCap - returns the amount which can still be delegated to a capped contract, or nil if
+// the contract has no delegation cap
+func (p *Provider) RemainingCap() *Amount {
+	if !p.Capped() {
+		return nil
+	}
+
+	remaining := p.MaxDelegationCap.Sub(p.TotalActiveStake)
+	if remaining.Sign() < 0 {
+		return NewAmount(nil)
+	}
+
+	return remaining
+}
+
+// DisplayName - returns the provider's name or, if not set, its shortened contract address
+func (p *Provider) DisplayName() string {
+	if p.Name != "" {
+		return p.Name
+	}
+	if len(p.Address) > 16 {
+		return p.Address[:10] + "..." + p.Address[len(p.Address)-6:]
+	}
+
+	return p.Address
+}
@@ -0,0 +1,8 @@
+package data
+
+// SchemaMigration - holds the state of a database schema migration
+type SchemaMigration struct {
+	Version     int
+	Description string
+	AppliedAt   int64 // unix time, 0 if the migration is pending
+}
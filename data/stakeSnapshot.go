@@ -0,0 +1,34 @@
+package data
+
+import "time"
+
+// StakeSnapshot - holds a wallet's delegation balances with a contract at a moment in time
+type StakeSnapshot struct {
+	Address          string
+	Contract         string
+	Epoch            uint32
+	Timestamp        int64
+	ActiveStake      *Amount
+	UnStaked         *Amount
+	UnBondable       *Amount
+	ClaimableRewards *Amount
+	CumulatedRewards *Amount // all the rewards earned with the contract, claimed or not. Nil if not recorded
+	Earned           *Amount // the rewards earned since the wallet's previous snapshot
+}
+
+// RewardsEarned - holds the rewards a wallet earned during a period, summed up from its snapshots
+type RewardsEarned struct {
+	Period  time.Duration
+	Covered time.Duration // the part of the period covered by snapshots, shorter for recently added wallets
+	Earned  *Amount
+}
+
+// DailyRate - returns the rewards earned per day over the covered part of the period, or nil if
+// the snapshots cover less than a day
+func (re *RewardsEarned) DailyRate() *Amount {
+	if re.Covered < 24*time.Hour {
+		return nil
+	}
+
+	return AmountFromFloat64(re.Earned.Float64() * float64(24*time.Hour) / float64(re.Covered))
+}
@@ -0,0 +1,44 @@
+package data
+
+// TransactionOnNetwork - holds a transaction's details as returned by the proxy, including its smart contract results
+type TransactionOnNetwork struct {
+	Hash                 string                 `json:"hash"`
+	Nonce                uint64                 `json:"nonce"`
+	Value                string                 `json:"value"`
+	Receiver             string                 `json:"receiver"`
+	Sender               string                 `json:"sender"`
+	GasPrice             uint64                 `json:"gasPrice"`
+	GasLimit             uint64                 `json:"gasLimit"`
+	Data                 []byte                 `json:"data"`
+	Status               string                 `json:"status"`
+	SmartContractResults []*SmartContractResult `json:"smartContractResults,omitempty"`
+}
+
+// SmartContractResult - holds the fields of a smart contract result as returned by the proxy
+type SmartContractResult struct {
+	Hash          string `json:"hash"`
+	Nonce         uint64 `json:"nonce"`
+	Value         string `json:"value"`
+	Receiver      string `json:"receiver"`
+	Sender        string `json:"sender"`
+	Data          string `json:"data"`
+	ReturnMessage string `json:"returnMessage,omitempty"`
+}
+
+// TransactionResponse - holds the proxy's response for a transaction's details
+type TransactionResponse struct {
+	Data struct {
+		Transaction *TransactionOnNetwork `json:"transaction"`
+	} `json:"data"`
+	Error string `json:"error"`
+	Code  string `json:"code"`
+}
+
+// TransactionStatusResponse - holds the proxy's response for a transaction's status
+type TransactionStatusResponse struct {
+	Data struct {
+		Status string `json:"status"`
+	} `json:"data"`
+	Error string `json:"error"`
+	Code  string `json:"code"`
+}
@@ -0,0 +1,28 @@
+package data
+
+// TransactionCost - holds the gas units a transaction would use, or the reason it would fail
+type TransactionCost struct {
+	TxGasUnits    uint64 `json:"txGasUnits"`
+	ReturnMessage string `json:"returnMessage"`
+}
+
+// TransactionCostResponse - holds the response of the proxy's /transaction/cost endpoint
+type TransactionCostResponse struct {
+	Data  TransactionCost `json:"data"`
+	Error string          `json:"error"`
+	Code  string          `json:"code"`
+}
+
+// TxSimulation - holds the outcome of a transaction simulated from a sender before being sent
+type TxSimulation struct {
+	Sender   string
+	GasLimit uint64
+	GasUnits uint64
+	Fee      *Amount // the fee for the gas units used, after the refund of the unused gas
+	Error    string  // the reason the transaction would fail, empty if it would succeed
+}
+
+// Succeeded - returns true if the simulation predicts the transaction will succeed
+func (ts *TxSimulation) Succeeded() bool {
+	return ts.Error == ""
+}
@@ -0,0 +1,7 @@
+package data
+
+// UnDelegatedFund - holds an undelegated amount and the number of rounds left until it can be withdrawn
+type UnDelegatedFund struct {
+	Amount          *Amount
+	RemainingRounds uint64
+}
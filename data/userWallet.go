@@ -5,4 +5,7 @@ type UserWallet struct {
 	ID      uint64
 	UserID  uint64
 	Address string
+
+	// Verified - true once the user proved they own the address by signing a challenge
+	Verified bool
 }
@@ -0,0 +1,35 @@
+package data
+
+// WalletOperation - holds a delegation transaction sent by a wallet to a contract and the amount it moved
+type WalletOperation struct {
+	Hash      string
+	Address   string
+	Contract  string
+	Function  string
+	Amount    *Amount
+	Timestamp int64
+	Status    string
+}
+
+// WalletHistory - holds the lifetime totals of a wallet's successful operations with a contract
+type WalletHistory struct {
+	Address        string
+	Delegated      *Amount
+	UnDelegated    *Amount
+	Withdrawn      *Amount
+	Claimed        *Amount // rewards sent to the wallet
+	Compounded     *Amount // rewards redelegated
+	Operations     int
+	FirstOperation int64
+	LastOperation  int64
+}
+
+// NetDeposits - returns the amount delegated minus the amount withdrawn
+func (wh *WalletHistory) NetDeposits() *Amount {
+	return wh.Delegated.Sub(wh.Withdrawn)
+}
+
+// TotalRewards - returns the claimed plus the compounded rewards
+func (wh *WalletHistory) TotalRewards() *Amount {
+	return wh.Claimed.Add(wh.Compounded)
+}
@@ -0,0 +1,110 @@
+package db
+
+import (
+	"database/sql"
+	"strings"
+
+	"github.com/DrDelphi/ElrondDSSC/data"
+)
+
+// GetActivityCursor - returns the activity indexer's cursor for a contract, a new one if not saved yet
+func (d *Database) GetActivityCursor(contract string) (*data.ActivityCursor, error) {
+	cursor := &data.ActivityCursor{Contract: contract}
+	query := "select Synced, Walking, WalkTop, WalkBefore, WalkSkip from ActivityCursors where Contract = ?"
+	err := d.sqldb.QueryRow(query, contract).Scan(&cursor.Synced, &cursor.Walking, &cursor.WalkTop,
+		&cursor.WalkBefore, &cursor.WalkSkip)
+	if err == sql.ErrNoRows {
+		return cursor, nil
+	}
+	if err != nil {
+		log.Error("can not read activity cursor from database", "error", err)
+		return nil, err
+	}
+
+	return cursor, nil
+}
+
+// SaveContractActivity - saves the indexed transactions of a contract together with the indexer's cursor
+func (d *Database) SaveContractActivity(activities []*data.ContractActivity, cursor *data.ActivityCursor) error {
+	tx, err := d.sqldb.Begin()
+	if err != nil {
+		log.Error("can not save contract activity in database", "error", err)
+		return err
+	}
+
+	sql := "insert into ContractActivity(Hash, Contract, Sender, Function, Category, Args, Value, Amount, " +
+		"Timestamp, Status) values(?, ?, ?, ?, ?, ?, ?, ?, ?, ?) on conflict(Hash) do update set " +
+		"Contract = excluded.Contract, Sender = excluded.Sender, Function = excluded.Function, " +
+		"Category = excluded.Category, Args = excluded.Args, Value = excluded.Value, Amount = excluded.Amount, " +
+		"Timestamp = excluded.Timestamp, Status = excluded.Status"
+	statement, err := tx.Prepare(sql)
+	if err != nil {
+		_ = tx.Rollback()
+		log.Error("can not save contract activity in database", "error", err)
+		return err
+	}
+	defer statement.Close()
+
+	for _, a := range activities {
+		_, err = statement.Exec(a.Hash, a.Contract, a.Sender, a.Function, a.Category, strings.Join(a.Args, "@"),
+			a.Value, a.Amount, a.Timestamp, a.Status)
+		if err != nil {
+			_ = tx.Rollback()
+			log.Error("can not save contract activity in database", "hash", a.Hash, "error", err)
+			return err
+		}
+	}
+
+	_, err = tx.Exec("insert into ActivityCursors(Contract, Synced, Walking, WalkTop, WalkBefore, WalkSkip) "+
+		"values(?, ?, ?, ?, ?, ?) on conflict(Contract) do update set Synced = excluded.Synced, "+
+		"Walking = excluded.Walking, WalkTop = excluded.WalkTop, WalkBefore = excluded.WalkBefore, "+
+		"WalkSkip = excluded.WalkSkip", cursor.Contract, cursor.Synced, sqlBool(cursor.Walking), cursor.WalkTop,
+		cursor.WalkBefore, cursor.WalkSkip)
+	if err != nil {
+		_ = tx.Rollback()
+		log.Error("can not save activity cursor in database", "error", err)
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetContractActivity - returns the newest indexed transactions of a contract in a category, or in all the
+// categories if category is empty
+func (d *Database) GetContractActivity(contract string, category string, limit int) ([]*data.ContractActivity, error) {
+	sql := "select Hash, Contract, Sender, Function, Category, Args, Value, Amount, Timestamp, Status " +
+		"from ContractActivity where Contract = ?"
+	args := []interface{}{contract}
+	if category != "" {
+		sql += " and Category = ?"
+		args = append(args, category)
+	}
+	sql += " order by Timestamp desc limit ?"
+	args = append(args, limit)
+	row, err := d.sqldb.Query(sql, args...)
+	if err != nil {
+		log.Error("can not read contract activity from database", "error", err)
+		return nil, err
+	}
+
+	defer row.Close()
+	activities := make([]*data.ContractActivity, 0)
+	for row.Next() {
+		a := &data.ContractActivity{Value: data.NewAmount(nil), Amount: data.NewAmount(nil)}
+		var args string
+		err = row.Scan(&a.Hash, &a.Contract, &a.Sender, &a.Function, &a.Category, &args, a.Value, a.Amount,
+			&a.Timestamp, &a.Status)
+		if err != nil {
+			log.Warn("can not read contract activity row", "error", err)
+			continue
+		}
+
+		a.Args = make([]string, 0)
+		if args != "" {
+			a.Args = strings.Split(args, "@")
+		}
+		activities = append(activities, a)
+	}
+
+	return activities, nil
+}
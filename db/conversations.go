@@ -0,0 +1,45 @@
+package db
+
+import (
+	"database/sql"
+
+	"github.com/DrDelphi/ElrondDSSC/data"
+)
+
+// GetConversation - returns the conversation the bot has with a user, or nil if it is not waiting for an answer
+func (d *Database) GetConversation(tgID int64) (*data.Conversation, error) {
+	c := &data.Conversation{TgID: tgID}
+	err := d.sqldb.QueryRow("select State, Param, ExpiresAt from Conversations where TgID = ?", tgID).
+		Scan(&c.State, &c.Param, &c.ExpiresAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		log.Error("can not read conversation from database", "error", err)
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// SetConversation - saves the conversation the bot has with a user, replacing the previous one
+func (d *Database) SetConversation(c *data.Conversation) error {
+	_, err := d.sqldb.Exec("insert into Conversations(TgID, State, Param, ExpiresAt) values(?, ?, ?, ?) "+
+		"on conflict(TgID) do update set State = excluded.State, Param = excluded.Param, ExpiresAt = excluded.ExpiresAt",
+		c.TgID, c.State, c.Param, c.ExpiresAt)
+	if err != nil {
+		log.Error("can not save conversation in database", "error", err)
+	}
+
+	return err
+}
+
+// RemoveConversation - ends the conversation the bot has with a user
+func (d *Database) RemoveConversation(tgID int64) error {
+	_, err := d.sqldb.Exec("delete from Conversations where TgID = ?", tgID)
+	if err != nil {
+		log.Error("can not remove conversation from database", "error", err)
+	}
+
+	return err
+}
@@ -3,53 +3,40 @@ package db
 import (
 	"database/sql"
 	"encoding/base64"
-	"fmt"
-	"sync"
 
 	"github.com/DrDelphi/ElrondDSSC/data"
 	logger "github.com/ElrondNetwork/elrond-go-logger"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+	_ "github.com/lib/pq"           // Import PostgreSQL driver
 	_ "github.com/mattn/go-sqlite3" // Import go-sqlite3 library
 )
 
 var log = logger.GetOrCreate("database")
 
-// Database - holds the required fields of a database
-type Database struct {
-	path  string
-	sqldb *sql.DB
-
-	ownerAddress    string
-	ownerPrivateKey string
+var _ Store = (*Database)(nil)
 
-	users    map[int64]*data.User
-	usersMut sync.Mutex
+// Database - holds the required fields of a database. Every read goes to the database, so several
+// instances of the application can share it
+type Database struct {
+	sqldb *conn
 }
 
-// NewDatabase - creates a new Database object
-func NewDatabase(databasePath string) (*Database, error) {
-	sqldb, err := sql.Open("sqlite3", databasePath)
+// NewDatabase - creates a new Database object, connecting with the driver (DriverSQLite or DriverPostgres)
+// and applying the pending schema migrations
+func NewDatabase(driver string, dataSource string) (*Database, error) {
+	sqldb, err := openConn(driver, dataSource)
 	if err != nil {
 		log.Error("can not open database", "error", err)
 		return nil, err
 	}
 
 	db := &Database{
-		path:  databasePath,
 		sqldb: sqldb,
-		users: make(map[int64]*data.User),
-	}
-
-	err = db.getSettings()
-	if err != nil {
-		log.Error("can not read settings from database", "error", err)
-		_ = db.sqldb.Close()
-		return nil, err
 	}
 
-	err = db.getUsers()
+	_, err = migrate(db.sqldb)
 	if err != nil {
-		log.Error("can not read users from database", "error", err)
+		log.Error("can not migrate database schema", "error", err)
 		_ = db.sqldb.Close()
 		return nil, err
 	}
@@ -57,174 +44,106 @@ func NewDatabase(databasePath string) (*Database, error) {
 	return db, nil
 }
 
-// getSettings - reads the settings from the database
-// it is called by NewDatabase
-func (d *Database) getSettings() error {
-	sql := "select * from Settings"
-	row, err := d.sqldb.Query(sql)
+// Close - closes the database connections
+func (d *Database) Close() error {
+	return d.sqldb.Close()
+}
+
+// userWalletsQuery - selects the wallets which were not deleted, the condition on the users is appended
+const userWalletsQuery = "select ID, UserID, Address, Verified from UserWallets where (Deleted = 0)"
+
+// getWallets - runs a wallets query and returns the wallets grouped by their users' IDs
+func (d *Database) getWallets(query string, args ...interface{}) (map[uint64][]*data.UserWallet, error) {
+	row, err := d.sqldb.Query(query+" order by ID", args...)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	defer row.Close()
-	var (
-		address    string
-		privateKey string
-	)
+	wallets := make(map[uint64][]*data.UserWallet)
 	for row.Next() {
-		err = row.Scan(&address, &privateKey)
+		wallet := &data.UserWallet{}
+		err = row.Scan(&wallet.ID, &wallet.UserID, &wallet.Address, &wallet.Verified)
 		if err != nil {
-			log.Warn("can not read settings row from database", "error", err)
+			log.Warn("can not read user wallet row", "error", err)
 			continue
 		}
 
-		d.ownerAddress = address
-		d.ownerPrivateKey = privateKey
-		break
+		wallets[wallet.UserID] = append(wallets[wallet.UserID], wallet)
 	}
 
-	return nil
+	return wallets, row.Err()
 }
 
-// getUsers - reads the users from the database
-// it is called by NewDatabase
-func (d *Database) getUsers() error {
-	sql := "select * from Users"
-	row, err := d.sqldb.Query(sql)
+// getUserWallets - gets a user's wallets from the database
+func (d *Database) getUserWallets(userID uint64) ([]*data.UserWallet, error) {
+	wallets, err := d.getWallets(userWalletsQuery+" and (UserID = ?)", userID)
 	if err != nil {
-		return err
+		return nil, err
 	}
-
-	defer row.Close()
-	var (
-		id      uint64
-		tgID    int64
-		tgUser  string
-		tgFirst string
-		tgLast  string
-	)
-	for row.Next() {
-		err = row.Scan(&id, &tgID, &tgUser, &tgFirst, &tgLast)
-		if err != nil {
-			log.Warn("can not read user row from database", "error", err)
-			continue
-		}
-
-		first, err := base64.StdEncoding.DecodeString(tgFirst)
-		if err != nil {
-			first = []byte(tgFirst)
-		}
-
-		last, err := base64.StdEncoding.DecodeString(tgLast)
-		if err != nil {
-			last = []byte(tgLast)
-		}
-
-		user := &data.User{
-			ID:      id,
-			TgID:    tgID,
-			TgUser:  tgUser,
-			TgFirst: string(first),
-			TgLast:  string(last),
-		}
-
-		wallets, err := d.getUserWallets(id)
-		if err != nil {
-			log.Warn("can not read user wallets", "error", err, "user", id)
-			continue
-		}
-
-		user.Wallets = make([]*data.UserWallet, len(wallets))
-		copy(user.Wallets, wallets)
-
-		d.usersMut.Lock()
-		d.users[tgID] = user
-		d.usersMut.Unlock()
+	if wallets[userID] == nil {
+		return make([]*data.UserWallet, 0), nil
 	}
 
-	return nil
+	return wallets[userID], nil
 }
 
-// getUserWallets - gets a user's wallets from the database
-// it is called by getUsers
-func (d *Database) getUserWallets(userID uint64) ([]*data.UserWallet, error) {
-	sql := fmt.Sprintf("select * from UserWallets where (UserID = %v) and (Deleted = 0)", userID)
-	row, err := d.sqldb.Query(sql)
+// scanUser - reads a user row, without the user's wallets
+func scanUser(scan func(dest ...interface{}) error) (*data.User, error) {
+	user := &data.User{}
+	var tgFirst, tgLast string
+	err := scan(&user.ID, &user.TgID, &user.TgUser, &tgFirst, &tgLast, &user.LastMenuID)
 	if err != nil {
 		return nil, err
 	}
 
-	defer row.Close()
-	wallets := make([]*data.UserWallet, 0)
-	var (
-		id      uint64
-		uID     uint64
-		address string
-		deleted int
-	)
-	for row.Next() {
-		err = row.Scan(&id, &uID, &address, &deleted)
-		if err != nil {
-			log.Warn("can not read user wallet row", "error", err, "user", userID)
-			continue
-		}
+	user.TgFirst = decodeName(tgFirst)
+	user.TgLast = decodeName(tgLast)
 
-		wallet := &data.UserWallet{
-			ID:      id,
-			UserID:  uID,
-			Address: address,
-		}
-		wallets = append(wallets, wallet)
+	return user, nil
+}
+
+// decodeName - decodes a base64 encoded Telegram name, falling back to the raw value for older rows
+func decodeName(encoded string) string {
+	decoded, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return encoded
 	}
 
-	return wallets, nil
+	return string(decoded)
 }
 
 // AddUser - adds a telegram user to the database
 func (d *Database) AddUser(user *tgbotapi.User) error {
-	sql := "insert into Users(TgID, TgUser, TgFirst, TgLast) values (?, ?, ?, ?)"
-	statement, err := d.sqldb.Prepare(sql)
-	if err != nil {
-		log.Error("error adding user in database", "error", err)
-		return err
-	}
-
 	first := base64.StdEncoding.EncodeToString([]byte(user.FirstName))
 	last := base64.StdEncoding.EncodeToString([]byte(user.LastName))
 
-	res, err := statement.Exec(user.ID, user.UserName, first, last)
+	_, err := d.sqldb.dialect.insertID(d.sqldb, "insert into Users(TgID, TgUser, TgFirst, TgLast) values (?, ?, ?, ?)",
+		user.ID, user.UserName, first, last)
 	if err != nil {
 		log.Error("error adding user in database", "error", err)
 		return err
 	}
 
-	id, err := res.LastInsertId()
-	if err != nil {
-		log.Error("error adding user in database", "error", err)
-		return err
-	}
-
-	u := &data.User{
-		ID:      uint64(id),
-		TgID:    int64(user.ID),
-		TgUser:  user.UserName,
-		TgFirst: user.FirstName,
-		TgLast:  user.LastName,
-		Wallets: make([]*data.UserWallet, 0),
-	}
-	d.usersMut.Lock()
-	d.users[u.TgID] = u
-	d.usersMut.Unlock()
-
 	return nil
 }
 
-// GetUserByTgID - returns a user by its Telegram ID
+// GetUserByTgID - returns a user by its Telegram ID, or nil if the user is not registered
 func (d *Database) GetUserByTgID(tgID int64) *data.User {
-	d.usersMut.Lock()
-	defer d.usersMut.Unlock()
+	row := d.sqldb.QueryRow("select ID, TgID, TgUser, TgFirst, TgLast, LastMenuID from Users where TgID = ?", tgID)
+	user, err := scanUser(row.Scan)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err == nil {
+		user.Wallets, err = d.getUserWallets(user.ID)
+	}
+	if err != nil {
+		log.Error("can not read user from database", "error", err, "user", tgID)
+		return nil
+	}
 
-	return d.users[tgID]
+	return user
 }
 
 // GetUserByTgUser - same as GetUserByID with the addition that if the Telegram username,
@@ -245,15 +164,46 @@ func (d *Database) GetUserByTgUser(tgUser *tgbotapi.User) *data.User {
 	return user
 }
 
-// GetUsers - returns all registered users
+// GetUsers - returns all registered users. The wallets of all the users are read with a single query
 func (d *Database) GetUsers() map[int64]*data.User {
 	m := make(map[int64]*data.User)
 
-	d.usersMut.Lock()
-	for k, v := range d.users {
-		m[k] = v
+	row, err := d.sqldb.Query("select ID, TgID, TgUser, TgFirst, TgLast, LastMenuID from Users")
+	if err != nil {
+		log.Error("can not read users from database", "error", err)
+		return m
+	}
+
+	users := make([]*data.User, 0)
+	for row.Next() {
+		user, err := scanUser(row.Scan)
+		if err != nil {
+			log.Warn("can not read user row from database", "error", err)
+			continue
+		}
+
+		users = append(users, user)
+	}
+	err = row.Err()
+	_ = row.Close()
+	if err != nil {
+		log.Error("can not read users from database", "error", err)
+		return m
+	}
+
+	wallets, err := d.getWallets(userWalletsQuery)
+	if err != nil {
+		log.Error("can not read user wallets from database", "error", err)
+		return m
+	}
+
+	for _, user := range users {
+		user.Wallets = wallets[user.ID]
+		if user.Wallets == nil {
+			user.Wallets = make([]*data.UserWallet, 0)
+		}
+		m[user.TgID] = user
 	}
-	d.usersMut.Unlock()
 
 	return m
 }
@@ -263,94 +213,109 @@ func (d *Database) updateUser(user *data.User) error {
 	first := base64.StdEncoding.EncodeToString([]byte(user.TgFirst))
 	last := base64.StdEncoding.EncodeToString([]byte(user.TgLast))
 
-	sql := fmt.Sprintf("update Users set TgUser = ?, TgFirst = ?, TgLast = ? where ID = %v", user.ID)
-	statement, err := d.sqldb.Prepare(sql)
+	_, err := d.sqldb.Exec("update Users set TgUser = ?, TgFirst = ?, TgLast = ? where ID = ?",
+		user.TgUser, first, last, user.ID)
 	if err != nil {
 		log.Warn("can not update user in database", "error", err, "user", user)
 		return err
 	}
 
-	_, err = statement.Exec(user.TgUser, first, last)
+	return nil
+}
+
+// SetLastMenuID - saves the ID of the menu message last sent to a user, to be deleted when the next one is sent
+func (d *Database) SetLastMenuID(user *data.User, menuID int) error {
+	_, err := d.sqldb.Exec("update Users set LastMenuID = ? where ID = ?", menuID, user.ID)
 	if err != nil {
-		log.Warn("can not update user in database", "error", err, "user", user)
+		log.Warn("can not save last menu ID in database", "error", err, "user", user.TgID)
 		return err
 	}
 
+	user.LastMenuID = menuID
+
 	return nil
 }
 
-// GetOwnerAddress - returns the owner's address
-func (d *Database) GetOwnerAddress() string {
-	return d.ownerAddress
-}
+// getSetting - reads a column of the settings row, empty if not set
+func (d *Database) getSetting(column string) string {
+	var value sql.NullString
+	err := d.sqldb.QueryRow("select " + column + " from Settings limit 1").Scan(&value)
+	if err != nil && err != sql.ErrNoRows {
+		log.Error("can not read settings from database", "setting", column, "error", err)
+	}
 
-// GetOwnerPrivateKey - returns the owner's private key
-func (d *Database) GetOwnerPrivateKey() string {
-	return d.ownerPrivateKey
+	return value.String
 }
 
-// SetOwnerAddress - saves the owner's address in database
-func (d *Database) SetOwnerAddress(address string) error {
-	sql := fmt.Sprintf("update Settings set OwnerAddress = ?, OwnerPrivateKey = ? where OwnerAddress = '%s'", d.ownerAddress)
-	if d.ownerAddress == "" {
-		sql = "insert into Settings(OwnerAddress, OwnerPrivateKey) values(?, ?)"
-	}
-	statement, err := d.sqldb.Prepare(sql)
+// setSetting - saves a column of the settings row, creating the row if needed
+func (d *Database) setSetting(column string, value string) error {
+	tx, err := d.sqldb.Begin()
 	if err != nil {
-		log.Error("can not set owner address in database", "error", err)
 		return err
 	}
 
-	_, err = statement.Exec(address, d.ownerPrivateKey)
+	res, err := tx.Exec("update Settings set "+column+" = ?", value)
 	if err != nil {
-		log.Error("can not set owner address in database", "error", err)
+		_ = tx.Rollback()
 		return err
 	}
 
-	d.ownerAddress = address
-
-	return nil
-}
-
-// SetOwnerPrivateKey - saves the owner's private key in database
-func (d *Database) SetOwnerPrivateKey(privateKey string) error {
-	sql := fmt.Sprintf("update Settings set OwnerAddress = ?, OwnerPrivateKey = ? where OwnerAddress = '%s'", d.ownerAddress)
-	if d.ownerAddress == "" {
-		sql = "insert into Settings(OwnerAddress, OwnerPrivateKey) values(?, ?)"
+	updated, err := res.RowsAffected()
+	if err == nil && updated == 0 {
+		_, err = tx.Exec("insert into Settings(OwnerAddress, OwnerPrivateKey) values('', '')")
+		if err == nil {
+			_, err = tx.Exec("update Settings set "+column+" = ?", value)
+		}
 	}
-	statement, err := d.sqldb.Prepare(sql)
 	if err != nil {
-		log.Error("can not set owner private key in database", "error", err)
+		_ = tx.Rollback()
 		return err
 	}
 
-	_, err = statement.Exec(d.ownerAddress, privateKey)
+	return tx.Commit()
+}
+
+// GetOwnerAddress - returns the owner's address
+func (d *Database) GetOwnerAddress() string {
+	return d.getSetting("OwnerAddress")
+}
+
+// GetOwnerPrivateKey - returns the owner's private key, as sealed by the key vault
+func (d *Database) GetOwnerPrivateKey() string {
+	return d.getSetting("OwnerPrivateKey")
+}
+
+// SetOwnerAddress - saves the owner's address in database
+func (d *Database) SetOwnerAddress(address string) error {
+	err := d.setSetting("OwnerAddress", address)
 	if err != nil {
-		log.Error("can not set owner private key in database", "error", err)
-		return err
+		log.Error("can not set owner address in database", "error", err)
 	}
 
-	d.ownerPrivateKey = privateKey
-
-	return nil
+	return err
 }
 
-// AddUserWallet - adds a user wallet in the database
-func (d *Database) AddUserWallet(user *data.User, address string) error {
-	sql := "insert into UserWallets(UserID, Address) values(?, ?)"
-	statement, err := d.sqldb.Prepare(sql)
+// SetOwnerPrivateKey - saves the owner's private key, as sealed by the key vault, in database. The settings
+// are purged afterwards, so the replaced key can not be recovered from the database files
+func (d *Database) SetOwnerPrivateKey(privateKey string) error {
+	err := d.setSetting("OwnerPrivateKey", privateKey)
 	if err != nil {
-		log.Error("can not add user wallet in database", "error", err)
+		log.Error("can not set owner private key in database", "error", err)
 		return err
 	}
 
-	res, err := statement.Exec(user.ID, address)
+	err = d.sqldb.dialect.purge(d.sqldb, "Settings")
 	if err != nil {
-		log.Error("can not add user wallet in database", "error", err)
-		return err
+		log.Error("can not purge the replaced owner private key from database", "error", err)
 	}
 
-	id, err := res.LastInsertId()
+	return err
+}
+
+// AddUserWallet - adds a user wallet in the database
+func (d *Database) AddUserWallet(user *data.User, address string) error {
+	id, err := d.sqldb.dialect.insertID(d.sqldb, "insert into UserWallets(UserID, Address) values(?, ?)",
+		user.ID, address)
 	if err != nil {
 		log.Error("can not add user wallet in database", "error", err)
 		return err
@@ -368,14 +333,7 @@ func (d *Database) AddUserWallet(user *data.User, address string) error {
 
 // RemoveWallet - marks a user wallet as deleted in the database
 func (d *Database) RemoveWallet(id uint64) error {
-	sql := fmt.Sprintf("update UserWallets set Deleted = 1 where ID = %v", id)
-	statement, err := d.sqldb.Prepare(sql)
-	if err != nil {
-		log.Error("can not remove user wallet from database", "error", err)
-		return err
-	}
-
-	_, err = statement.Exec()
+	_, err := d.sqldb.Exec("update UserWallets set Deleted = 1 where ID = ?", id)
 	if err != nil {
 		log.Error("can not remove user wallet from database", "error", err)
 		return err
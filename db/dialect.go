@@ -0,0 +1,278 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// supported database drivers
+const (
+	DriverSQLite   = "sqlite3"
+	DriverPostgres = "postgres"
+)
+
+// dialect - hides the differences between the SQL of the supported databases. Queries are written for
+// SQLite, with ? placeholders and "on conflict" upserts, and adapted by the dialect. Booleans are stored
+// as 0 or 1 integers, see sqlBool
+type dialect interface {
+	// rebind - rewrites the placeholders of a query
+	rebind(query string) string
+	// ddl - rewrites the column types of a schema statement
+	ddl(statement string) string
+	// tableExists - returns true if the table was created
+	tableExists(c *conn, table string) (bool, error)
+	// addColumn - adds a column to a table, unless the table already has it
+	addColumn(tx *sqlTx, table string, column string, definition string) error
+	// insertID - runs an insert and returns the ID generated for the new row
+	insertID(c *conn, query string, args ...interface{}) (int64, error)
+	// purge - rewrites the storage of a table, so the old values of its updated or deleted rows are not
+	// left behind in the database files
+	purge(c *conn, table string) error
+	// lockMigrations - waits for the other instances migrating the database and keeps them waiting until
+	// the returned function is called
+	lockMigrations(c *conn) (func(), error)
+}
+
+// migrationsLockKey - the PostgreSQL advisory lock taken while migrating the schema ("DSSC")
+const migrationsLockKey = 0x44535343
+
+// sqliteMigrationsMut - serializes the migrations run by this process. An SQLite file is not shared between
+// instances of the application, so no lock between processes is needed
+var sqliteMigrationsMut sync.Mutex
+
+func newDialect(driver string) (dialect, error) {
+	switch driver {
+	case DriverSQLite:
+		return &sqliteDialect{}, nil
+	case DriverPostgres:
+		return &postgresDialect{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported database driver %q", driver)
+	}
+}
+
+type sqliteDialect struct{}
+
+func (sqliteDialect) rebind(query string) string {
+	return query
+}
+
+func (sqliteDialect) ddl(statement string) string {
+	return statement
+}
+
+func (sqliteDialect) tableExists(c *conn, table string) (bool, error) {
+	var found int
+	err := c.QueryRow("select count(*) from sqlite_master where type = 'table' and name = ?", table).Scan(&found)
+
+	return found > 0, err
+}
+
+func (sqliteDialect) addColumn(tx *sqlTx, table string, column string, definition string) error {
+	var found int
+	err := tx.QueryRow("select count(*) from pragma_table_info(?) where name = ?", table, column).Scan(&found)
+	if err != nil || found > 0 {
+		return err
+	}
+
+	_, err = tx.Exec(fmt.Sprintf("alter table %s add column %s %s", table, column, definition))
+
+	return err
+}
+
+func (sqliteDialect) insertID(c *conn, query string, args ...interface{}) (int64, error) {
+	res, err := c.Exec(query, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.LastInsertId()
+}
+
+// purge - rebuilds the whole database file. Updated values otherwise stay in the free pages, as
+// secure_delete is off by default
+func (sqliteDialect) purge(c *conn, _ string) error {
+	_, err := c.Exec("vacuum")
+
+	return err
+}
+
+func (sqliteDialect) lockMigrations(_ *conn) (func(), error) {
+	sqliteMigrationsMut.Lock()
+
+	return sqliteMigrationsMut.Unlock, nil
+}
+
+type postgresDialect struct{}
+
+// postgresTypes - the PostgreSQL types of the SQLite columns. SQLite integers are 64 bits wide
+var postgresTypes = strings.NewReplacer(
+	"INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT UNIQUE", "BIGSERIAL PRIMARY KEY",
+	"INTEGER", "BIGINT",
+	"REAL", "DOUBLE PRECISION",
+)
+
+// rebind - replaces the ? placeholders with $1, $2... Queries hold no ? in string literals
+func (postgresDialect) rebind(query string) string {
+	if !strings.Contains(query, "?") {
+		return query
+	}
+
+	var sb strings.Builder
+	n := 0
+	for _, r := range query {
+		if r != '?' {
+			sb.WriteRune(r)
+			continue
+		}
+		n++
+		sb.WriteString("$" + strconv.Itoa(n))
+	}
+
+	return sb.String()
+}
+
+func (postgresDialect) ddl(statement string) string {
+	return postgresTypes.Replace(statement)
+}
+
+func (postgresDialect) tableExists(c *conn, table string) (bool, error) {
+	var found int
+	err := c.QueryRow("select count(*) from information_schema.tables where table_schema = current_schema() "+
+		"and table_name = ?", strings.ToLower(table)).Scan(&found)
+
+	return found > 0, err
+}
+
+func (p postgresDialect) addColumn(tx *sqlTx, table string, column string, definition string) error {
+	_, err := tx.Exec(fmt.Sprintf("alter table %s add column if not exists %s %s", table, column, p.ddl(definition)))
+
+	return err
+}
+
+func (postgresDialect) insertID(c *conn, query string, args ...interface{}) (int64, error) {
+	var id int64
+	err := c.QueryRow(query+" returning ID", args...).Scan(&id)
+
+	return id, err
+}
+
+// purge - rewrites the table into a new file, dropping the dead row versions
+func (postgresDialect) purge(c *conn, table string) error {
+	_, err := c.Exec("vacuum full " + table)
+
+	return err
+}
+
+// lockMigrations - takes a session level advisory lock, so it is held by a connection kept out of the pool
+// until the lock is released
+func (postgresDialect) lockMigrations(c *conn) (func(), error) {
+	ctx := context.Background()
+	session, err := c.DB.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = session.ExecContext(ctx, "select pg_advisory_lock($1)", migrationsLockKey)
+	if err != nil {
+		_ = session.Close()
+		return nil, err
+	}
+
+	return func() {
+		_, err := session.ExecContext(ctx, "select pg_advisory_unlock($1)", migrationsLockKey)
+		if err != nil {
+			log.Warn("can not release the schema migrations lock", "error", err)
+		}
+		_ = session.Close()
+	}, nil
+}
+
+// sqlBool - returns the integer a boolean column is stored as
+func sqlBool(b bool) int {
+	if b {
+		return 1
+	}
+
+	return 0
+}
+
+// conn - a database connection pool which adapts the queries to the database's dialect
+type conn struct {
+	*sql.DB
+	dialect dialect
+}
+
+func openConn(driver string, dataSource string) (*conn, error) {
+	d, err := newDialect(driver)
+	if err != nil {
+		return nil, err
+	}
+
+	sqldb, err := sql.Open(driver, dataSource)
+	if err != nil {
+		return nil, err
+	}
+
+	return &conn{DB: sqldb, dialect: d}, nil
+}
+
+// Exec - see sql.DB.Exec
+func (c *conn) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return c.DB.Exec(c.dialect.rebind(query), args...)
+}
+
+// Query - see sql.DB.Query
+func (c *conn) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return c.DB.Query(c.dialect.rebind(query), args...)
+}
+
+// QueryRow - see sql.DB.QueryRow
+func (c *conn) QueryRow(query string, args ...interface{}) *sql.Row {
+	return c.DB.QueryRow(c.dialect.rebind(query), args...)
+}
+
+// Prepare - see sql.DB.Prepare
+func (c *conn) Prepare(query string) (*sql.Stmt, error) {
+	return c.DB.Prepare(c.dialect.rebind(query))
+}
+
+// Begin - see sql.DB.Begin
+func (c *conn) Begin() (*sqlTx, error) {
+	tx, err := c.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	return &sqlTx{Tx: tx, dialect: c.dialect}, nil
+}
+
+// sqlTx - a transaction which adapts the queries to the database's dialect
+type sqlTx struct {
+	*sql.Tx
+	dialect dialect
+}
+
+// Exec - see sql.Tx.Exec
+func (tx *sqlTx) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return tx.Tx.Exec(tx.dialect.rebind(query), args...)
+}
+
+// Query - see sql.Tx.Query
+func (tx *sqlTx) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return tx.Tx.Query(tx.dialect.rebind(query), args...)
+}
+
+// QueryRow - see sql.Tx.QueryRow
+func (tx *sqlTx) QueryRow(query string, args ...interface{}) *sql.Row {
+	return tx.Tx.QueryRow(tx.dialect.rebind(query), args...)
+}
+
+// Prepare - see sql.Tx.Prepare
+func (tx *sqlTx) Prepare(query string) (*sql.Stmt, error) {
+	return tx.Tx.Prepare(tx.dialect.rebind(query))
+}
@@ -0,0 +1,57 @@
+package db
+
+import (
+	"database/sql"
+
+	"github.com/DrDelphi/ElrondDSSC/data"
+)
+
+// AddHookIntent - saves the intent of a transaction link sent to the web wallet
+func (d *Database) AddHookIntent(intent *data.HookIntent) error {
+	_, err := d.sqldb.Exec("insert into HookIntents(Token, TgID, Description, Sender, Receiver, Value, Data, "+
+		"CreatedAt) values(?, ?, ?, ?, ?, ?, ?, ?)", intent.Token, intent.TgID, intent.Description, intent.Sender,
+		intent.Receiver, intent.Value, intent.Data, intent.CreatedAt)
+	if err != nil {
+		log.Error("can not add hook intent in database", "error", err)
+	}
+
+	return err
+}
+
+// TakeHookIntent - returns and removes the intent saved with the token, or nil if there is none
+func (d *Database) TakeHookIntent(token string) (*data.HookIntent, error) {
+	intent := &data.HookIntent{Token: token}
+	err := d.sqldb.QueryRow("select TgID, Description, Sender, Receiver, Value, Data, CreatedAt from HookIntents "+
+		"where Token = ?", token).Scan(&intent.TgID, &intent.Description, &intent.Sender, &intent.Receiver,
+		&intent.Value, &intent.Data, &intent.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		log.Error("can not read hook intent from database", "error", err)
+		return nil, err
+	}
+
+	// only the caller which removes the intent gets it, in case the wallet redirects twice
+	result, err := d.sqldb.Exec("delete from HookIntents where Token = ?", token)
+	if err != nil {
+		log.Error("can not remove hook intent from database", "error", err)
+		return nil, err
+	}
+	removed, err := result.RowsAffected()
+	if err != nil || removed == 0 {
+		return nil, err
+	}
+
+	return intent, nil
+}
+
+// RemoveHookIntentsBefore - removes the intents created before the given unix time
+func (d *Database) RemoveHookIntentsBefore(createdAt int64) error {
+	_, err := d.sqldb.Exec("delete from HookIntents where CreatedAt < ?", createdAt)
+	if err != nil {
+		log.Error("can not remove expired hook intents from database", "error", err)
+	}
+
+	return err
+}
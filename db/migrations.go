@@ -0,0 +1,304 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/DrDelphi/ElrondDSSC/data"
+)
+
+// ErrUnknownSchemaVersion - the database was migrated by a newer version of the application
+var ErrUnknownSchemaVersion = errors.New("unknown database schema version")
+
+// schemaMigration - an ordered step of the database schema. The statements run first, then apply, if set,
+// all in one transaction. Steps use "if not exists" since databases created before the versioning already
+// have some of the tables. Column types are written for SQLite and mapped by the dialect
+type schemaMigration struct {
+	version     int
+	description string
+	statements  []string
+	apply       func(tx *sqlTx) error
+}
+
+// schemaMigrations - the database schema, in order. Never change an applied step, add a new one instead
+var schemaMigrations = []*schemaMigration{
+	{
+		version:     1,
+		description: "settings, users and user wallets",
+		statements: []string{
+			"create table if not exists Settings(OwnerAddress TEXT NOT NULL, OwnerPrivateKey TEXT)",
+			"create table if not exists Users(ID INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT UNIQUE, " +
+				"TgID INTEGER NOT NULL UNIQUE, TgUser TEXT, TgFirst TEXT, TgLast TEXT)",
+			"create table if not exists UserWallets(ID INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT UNIQUE, " +
+				"UserID INTEGER NOT NULL, Address TEXT NOT NULL, Deleted INTEGER NOT NULL DEFAULT 0)",
+		},
+	},
+	{
+		version:     2,
+		description: "pending transactions",
+		statements: []string{
+			"create table if not exists PendingTransactions(Hash TEXT PRIMARY KEY, TgID INTEGER, Description TEXT, " +
+				"CreatedAt INTEGER)",
+		},
+	},
+	{
+		version:     3,
+		description: "properties",
+		statements: []string{
+			"create table if not exists Properties(Name TEXT PRIMARY KEY, Value TEXT)",
+		},
+	},
+	{
+		version:     4,
+		description: "node names",
+		statements: []string{
+			"create table if not exists NodeNames(BlsKey TEXT PRIMARY KEY, Name TEXT)",
+		},
+	},
+	{
+		version:     5,
+		description: "providers directory",
+		statements: []string{
+			"create table if not exists Providers(Address TEXT PRIMARY KEY, OwnerAddress TEXT, Name TEXT, Website TEXT, " +
+				"Identifier TEXT, ServiceFee REAL, MaxDelegationCap TEXT, TotalActiveStake TEXT, NumUsers INTEGER, " +
+				"UpdatedAt INTEGER)",
+		},
+	},
+	{
+		version:     6,
+		description: "wallet operations",
+		statements: []string{
+			"create table if not exists WalletOperations(Hash TEXT PRIMARY KEY, Address TEXT, Contract TEXT, " +
+				"Function TEXT, Amount TEXT, Timestamp INTEGER, Status TEXT)",
+			"create index if not exists WalletOperationsAddress on WalletOperations(Address, Contract)",
+		},
+	},
+	{
+		version:     7,
+		description: "contract activity",
+		statements: []string{
+			"create table if not exists ContractActivity(Hash TEXT PRIMARY KEY, Contract TEXT, Sender TEXT, " +
+				"Function TEXT, Category TEXT, Args TEXT, Value TEXT, Amount TEXT, Timestamp INTEGER, Status TEXT)",
+			"create index if not exists ContractActivityTimestamp on ContractActivity(Contract, Timestamp)",
+			"create table if not exists ActivityCursors(Contract TEXT PRIMARY KEY, Synced INTEGER, Walking INTEGER, " +
+				"WalkTop INTEGER, WalkBefore INTEGER, WalkSkip INTEGER)",
+		},
+	},
+	{
+		version:     8,
+		description: "wallet hook intents",
+		statements: []string{
+			"create table if not exists HookIntents(Token TEXT PRIMARY KEY, TgID INTEGER, Description TEXT, " +
+				"CreatedAt INTEGER)",
+		},
+	},
+	{
+		version:     9,
+		description: "wallet verification",
+		statements: []string{
+			"create table if not exists WalletChallenges(WalletID INTEGER PRIMARY KEY, Challenge TEXT, CreatedAt INTEGER)",
+		},
+		apply: func(tx *sqlTx) error {
+			return tx.dialect.addColumn(tx, "UserWallets", "Verified", "INTEGER NOT NULL DEFAULT 0")
+		},
+	},
+	{
+		version:     10,
+		description: "stake snapshots",
+		statements: []string{
+			"create table if not exists StakeSnapshots(Address TEXT NOT NULL, Contract TEXT NOT NULL, " +
+				"Timestamp INTEGER NOT NULL, Epoch INTEGER, ActiveStake TEXT, UnStaked TEXT, UnBondable TEXT, " +
+				"ClaimableRewards TEXT, Earned TEXT, primary key(Address, Contract, Timestamp))",
+		},
+	},
+	{
+		version:     11,
+		description: "conversations and last menus",
+		statements: []string{
+			"create table if not exists Conversations(TgID INTEGER PRIMARY KEY, State TEXT NOT NULL, Param TEXT, " +
+				"ExpiresAt INTEGER)",
+		},
+		apply: func(tx *sqlTx) error {
+			return tx.dialect.addColumn(tx, "Users", "LastMenuID", "INTEGER NOT NULL DEFAULT 0")
+		},
+	},
+	{
+		version:     12,
+		description: "wallet hook intent transactions",
+		apply: func(tx *sqlTx) error {
+			for _, column := range []string{"Sender", "Receiver", "Value", "Data"} {
+				err := tx.dialect.addColumn(tx, "HookIntents", column, "TEXT NOT NULL DEFAULT ''")
+				if err != nil {
+					return err
+				}
+			}
+
+			return nil
+		},
+	},
+	{
+		version:     13,
+		description: "stake snapshots cumulated rewards",
+		apply: func(tx *sqlTx) error {
+			return tx.dialect.addColumn(tx, "StakeSnapshots", "CumulatedRewards", "TEXT")
+		},
+	},
+}
+
+const migrationsTableSQL = "create table if not exists SchemaMigrations(Version INTEGER PRIMARY KEY, " +
+	"Description TEXT, AppliedAt INTEGER)"
+
+// Migrate - applies the pending schema migrations to the database, creating the SQLite file if it does
+// not exist, and returns the applied migrations
+func Migrate(driver string, dataSource string) ([]*data.SchemaMigration, error) {
+	sqldb, err := openConn(driver, dataSource)
+	if err != nil {
+		log.Error("can not open database", "error", err)
+		return nil, err
+	}
+	defer sqldb.Close()
+
+	return migrate(sqldb)
+}
+
+// SchemaStatus - returns all the schema migrations of an existing database, the pending ones included
+func SchemaStatus(driver string, dataSource string) ([]*data.SchemaMigration, error) {
+	if driver == DriverSQLite {
+		_, err := os.Stat(dataSource)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sqldb, err := openConn(driver, dataSource)
+	if err != nil {
+		log.Error("can not open database", "error", err)
+		return nil, err
+	}
+	defer sqldb.Close()
+
+	return schemaStatus(sqldb)
+}
+
+// migrate - applies the pending schema migrations, each one in a transaction. The whole run holds the
+// migrations lock, so instances starting together on a shared database apply each migration once
+func migrate(sqldb *conn) ([]*data.SchemaMigration, error) {
+	unlock, err := sqldb.dialect.lockMigrations(sqldb)
+	if err != nil {
+		log.Error("can not lock the schema migrations", "error", err)
+		return nil, err
+	}
+	defer unlock()
+
+	_, err = sqldb.Exec(sqldb.dialect.ddl(migrationsTableSQL))
+	if err != nil {
+		log.Error("can not create schema migrations table", "error", err)
+		return nil, err
+	}
+
+	status, err := schemaStatus(sqldb)
+	if err != nil {
+		return nil, err
+	}
+
+	applied := make([]*data.SchemaMigration, 0)
+	for i, m := range schemaMigrations {
+		if status[i].AppliedAt > 0 {
+			continue
+		}
+
+		err = applyMigration(sqldb, m, status[i])
+		if err != nil {
+			log.Error("can not apply schema migration", "version", m.version, "error", err)
+			return applied, fmt.Errorf("%w applying schema migration %v (%s)", err, m.version, m.description)
+		}
+		log.Info("schema migration applied", "version", m.version, "description", m.description)
+		applied = append(applied, status[i])
+	}
+
+	return applied, nil
+}
+
+// applyMigration - runs a migration and records it, in a single transaction
+func applyMigration(sqldb *conn, m *schemaMigration, status *data.SchemaMigration) error {
+	tx, err := sqldb.Begin()
+	if err != nil {
+		return err
+	}
+
+	for _, statement := range m.statements {
+		_, err = tx.Exec(tx.dialect.ddl(statement))
+		if err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+
+	if m.apply != nil {
+		err = m.apply(tx)
+		if err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+
+	appliedAt := time.Now().Unix()
+	_, err = tx.Exec("insert into SchemaMigrations(Version, Description, AppliedAt) values(?, ?, ?)",
+		m.version, m.description, appliedAt)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return err
+	}
+
+	status.AppliedAt = appliedAt
+
+	return nil
+}
+
+// schemaStatus - returns the known migrations with the time they were applied at. It fails if the database
+// has migrations this version of the application does not know
+func schemaStatus(sqldb *conn) ([]*data.SchemaMigration, error) {
+	status := make([]*data.SchemaMigration, len(schemaMigrations))
+	byVersion := make(map[int]*data.SchemaMigration)
+	for i, m := range schemaMigrations {
+		status[i] = &data.SchemaMigration{Version: m.version, Description: m.description}
+		byVersion[m.version] = status[i]
+	}
+
+	found, err := sqldb.dialect.tableExists(sqldb, "SchemaMigrations")
+	if err != nil || !found {
+		return status, err
+	}
+
+	rows, err := sqldb.Query("select Version, AppliedAt from SchemaMigrations")
+	if err != nil {
+		log.Error("can not read schema migrations", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var version int
+		var appliedAt int64
+		err = rows.Scan(&version, &appliedAt)
+		if err != nil {
+			log.Error("can not read schema migration row", "error", err)
+			return nil, err
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			return nil, fmt.Errorf("%w: %v", ErrUnknownSchemaVersion, version)
+		}
+		migration.AppliedAt = appliedAt
+	}
+
+	return status, rows.Err()
+}
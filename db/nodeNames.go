@@ -0,0 +1,40 @@
+package db
+
+// GetNodeNames - returns the names given by the owner to the nodes, indexed by hex BLS key
+func (d *Database) GetNodeNames() (map[string]string, error) {
+	rows, err := d.sqldb.Query("select BlsKey, Name from NodeNames")
+	if err != nil {
+		log.Error("can not read node names from database", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	names := make(map[string]string)
+	for rows.Next() {
+		var key, name string
+		err = rows.Scan(&key, &name)
+		if err != nil {
+			log.Error("can not read node name from database", "error", err)
+			return nil, err
+		}
+		names[key] = name
+	}
+
+	return names, rows.Err()
+}
+
+// SetNodeName - saves the name of a node. An empty name removes it
+func (d *Database) SetNodeName(blsKey string, name string) error {
+	var err error
+	if name == "" {
+		_, err = d.sqldb.Exec("delete from NodeNames where BlsKey = ?", blsKey)
+	} else {
+		_, err = d.sqldb.Exec("insert into NodeNames(BlsKey, Name) values(?, ?) "+
+			"on conflict(BlsKey) do update set Name = excluded.Name", blsKey, name)
+	}
+	if err != nil {
+		log.Error("can not set node name in database", "key", blsKey, "error", err)
+	}
+
+	return err
+}
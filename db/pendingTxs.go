@@ -0,0 +1,68 @@
+package db
+
+import (
+	"github.com/DrDelphi/ElrondDSSC/data"
+)
+
+// AddPendingTx - saves a broadcasted transaction whose outcome is awaited
+func (d *Database) AddPendingTx(tx *data.PendingTx) error {
+	sql := "insert into PendingTransactions(Hash, TgID, Description, CreatedAt) values(?, ?, ?, ?) " +
+		"on conflict(Hash) do update set TgID = excluded.TgID, Description = excluded.Description, " +
+		"CreatedAt = excluded.CreatedAt"
+	statement, err := d.sqldb.Prepare(sql)
+	if err != nil {
+		log.Error("can not add pending transaction in database", "error", err)
+		return err
+	}
+
+	_, err = statement.Exec(tx.Hash, tx.TgID, tx.Description, tx.CreatedAt)
+	if err != nil {
+		log.Error("can not add pending transaction in database", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// GetPendingTxs - returns all the transactions whose outcome is awaited
+func (d *Database) GetPendingTxs() ([]*data.PendingTx, error) {
+	sql := "select Hash, TgID, Description, CreatedAt from PendingTransactions"
+	row, err := d.sqldb.Query(sql)
+	if err != nil {
+		log.Error("can not read pending transactions from database", "error", err)
+		return nil, err
+	}
+
+	defer row.Close()
+	txs := make([]*data.PendingTx, 0)
+	for row.Next() {
+		tx := &data.PendingTx{}
+		err = row.Scan(&tx.Hash, &tx.TgID, &tx.Description, &tx.CreatedAt)
+		if err != nil {
+			log.Warn("can not read pending transaction row", "error", err)
+			continue
+		}
+
+		txs = append(txs, tx)
+	}
+
+	return txs, nil
+}
+
+// RemovePendingTx - removes a transaction whose outcome has been reported
+func (d *Database) RemovePendingTx(hash string) error {
+	sql := "delete from PendingTransactions where Hash = ?"
+	statement, err := d.sqldb.Prepare(sql)
+	if err != nil {
+		log.Error("can not remove pending transaction from database", "error", err)
+		return err
+	}
+
+	_, err = statement.Exec(hash)
+	if err != nil {
+		log.Error("can not remove pending transaction from database", "error", err)
+		return err
+	}
+
+	return nil
+}
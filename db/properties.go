@@ -0,0 +1,86 @@
+package db
+
+import (
+	"database/sql"
+)
+
+// property names
+const (
+	contractAddressProperty = "ContractAddress"
+	contractOwnerProperty   = "ContractOwner"
+	chainIDProperty         = "ChainID"
+)
+
+// getProperty - reads a named value from the Properties table. A missing property is returned as an empty string
+func (d *Database) getProperty(name string) (string, error) {
+	var value string
+	err := d.sqldb.QueryRow("select Value from Properties where Name = ?", name).Scan(&value)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+
+	return value, err
+}
+
+// setProperty - saves a named value in the Properties table
+func (d *Database) setProperty(name string, value string) error {
+	_, err := d.sqldb.Exec("insert into Properties(Name, Value) values(?, ?) "+
+		"on conflict(Name) do update set Value = excluded.Value", name, value)
+
+	return err
+}
+
+// GetContractAddress - returns the saved contract address and the owner it was resolved for
+func (d *Database) GetContractAddress() (string, string) {
+	address, err := d.getProperty(contractAddressProperty)
+	if err != nil {
+		log.Error("can not read contract address from database", "error", err)
+		return "", ""
+	}
+
+	owner, err := d.getProperty(contractOwnerProperty)
+	if err != nil {
+		log.Error("can not read contract owner from database", "error", err)
+		return "", ""
+	}
+
+	return address, owner
+}
+
+// SetContractAddress - saves the contract address and the owner it was resolved for
+func (d *Database) SetContractAddress(address string, owner string) error {
+	err := d.setProperty(contractAddressProperty, address)
+	if err != nil {
+		log.Error("can not set contract address in database", "error", err)
+		return err
+	}
+
+	err = d.setProperty(contractOwnerProperty, owner)
+	if err != nil {
+		log.Error("can not set contract owner in database", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// GetChainID - returns the chain ID of the network the database was used with, empty if not set yet
+func (d *Database) GetChainID() (string, error) {
+	chainID, err := d.getProperty(chainIDProperty)
+	if err != nil {
+		log.Error("can not read chain ID from database", "error", err)
+		return "", err
+	}
+
+	return chainID, nil
+}
+
+// SetChainID - saves the chain ID of the network the database is used with
+func (d *Database) SetChainID(chainID string) error {
+	err := d.setProperty(chainIDProperty, chainID)
+	if err != nil {
+		log.Error("can not set chain ID in database", "error", err)
+	}
+
+	return err
+}
@@ -0,0 +1,73 @@
+package db
+
+import (
+	"github.com/DrDelphi/ElrondDSSC/data"
+)
+
+// GetProviders - returns the cached provider directory
+func (d *Database) GetProviders() ([]*data.Provider, error) {
+	sql := "select Address, OwnerAddress, Name, Website, Identifier, ServiceFee, MaxDelegationCap, TotalActiveStake, " +
+		"NumUsers, UpdatedAt from Providers"
+	row, err := d.sqldb.Query(sql)
+	if err != nil {
+		log.Error("can not read providers from database", "error", err)
+		return nil, err
+	}
+
+	defer row.Close()
+	providers := make([]*data.Provider, 0)
+	for row.Next() {
+		provider := &data.Provider{
+			MaxDelegationCap: data.NewAmount(nil),
+			TotalActiveStake: data.NewAmount(nil),
+		}
+		err = row.Scan(&provider.Address, &provider.OwnerAddress, &provider.Name, &provider.Website, &provider.Identifier,
+			&provider.ServiceFee, provider.MaxDelegationCap, provider.TotalActiveStake, &provider.NumUsers, &provider.UpdatedAt)
+		if err != nil {
+			log.Warn("can not read provider row", "error", err)
+			continue
+		}
+
+		providers = append(providers, provider)
+	}
+
+	return providers, nil
+}
+
+// SetProviders - replaces the cached provider directory
+func (d *Database) SetProviders(providers []*data.Provider) error {
+	tx, err := d.sqldb.Begin()
+	if err != nil {
+		log.Error("can not save providers in database", "error", err)
+		return err
+	}
+
+	_, err = tx.Exec("delete from Providers")
+	if err != nil {
+		_ = tx.Rollback()
+		log.Error("can not save providers in database", "error", err)
+		return err
+	}
+
+	sql := "insert into Providers(Address, OwnerAddress, Name, Website, Identifier, ServiceFee, MaxDelegationCap, " +
+		"TotalActiveStake, NumUsers, UpdatedAt) values(?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+	statement, err := tx.Prepare(sql)
+	if err != nil {
+		_ = tx.Rollback()
+		log.Error("can not save providers in database", "error", err)
+		return err
+	}
+	defer statement.Close()
+
+	for _, p := range providers {
+		_, err = statement.Exec(p.Address, p.OwnerAddress, p.Name, p.Website, p.Identifier, p.ServiceFee,
+			p.MaxDelegationCap, p.TotalActiveStake, p.NumUsers, p.UpdatedAt)
+		if err != nil {
+			_ = tx.Rollback()
+			log.Error("can not save provider in database", "contract", p.Address, "error", err)
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
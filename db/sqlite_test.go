@@ -0,0 +1,11 @@
+package db_test
+
+import (
+	"testing"
+
+	"github.com/DrDelphi/ElrondDSSC/db/storetest"
+)
+
+func TestSQLiteStore(t *testing.T) {
+	storetest.Run(t, storetest.SQLite())
+}
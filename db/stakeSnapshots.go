@@ -0,0 +1,109 @@
+package db
+
+import (
+	"database/sql"
+
+	"github.com/DrDelphi/ElrondDSSC/data"
+)
+
+const stakeSnapshotColumns = "Address, Contract, Timestamp, Epoch, ActiveStake, UnStaked, UnBondable, " +
+	"ClaimableRewards, CumulatedRewards, Earned"
+
+// AddStakeSnapshots - saves the snapshots of the wallets' delegation balances
+func (d *Database) AddStakeSnapshots(snapshots []*data.StakeSnapshot) error {
+	tx, err := d.sqldb.Begin()
+	if err != nil {
+		log.Error("can not add stake snapshots in database", "error", err)
+		return err
+	}
+
+	sql := "insert into StakeSnapshots(" + stakeSnapshotColumns + ") values(?, ?, ?, ?, ?, ?, ?, ?, ?, ?) " +
+		"on conflict(Address, Contract, Timestamp) do update set Epoch = excluded.Epoch, " +
+		"ActiveStake = excluded.ActiveStake, UnStaked = excluded.UnStaked, UnBondable = excluded.UnBondable, " +
+		"ClaimableRewards = excluded.ClaimableRewards, CumulatedRewards = excluded.CumulatedRewards, " +
+		"Earned = excluded.Earned"
+	statement, err := tx.Prepare(sql)
+	if err != nil {
+		_ = tx.Rollback()
+		log.Error("can not add stake snapshots in database", "error", err)
+		return err
+	}
+	defer statement.Close()
+
+	for _, s := range snapshots {
+		var cumulated interface{}
+		if s.CumulatedRewards != nil {
+			cumulated = s.CumulatedRewards.AttoString()
+		}
+		_, err = statement.Exec(s.Address, s.Contract, s.Timestamp, s.Epoch, s.ActiveStake, s.UnStaked, s.UnBondable,
+			s.ClaimableRewards, cumulated, s.Earned)
+		if err != nil {
+			_ = tx.Rollback()
+			log.Error("can not add stake snapshot in database", "address", s.Address, "error", err)
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// scanStakeSnapshot - reads a row selected with stakeSnapshotColumns. The cumulated rewards are nil for the
+// snapshots taken before they were recorded
+func scanStakeSnapshot(scan func(dest ...interface{}) error) (*data.StakeSnapshot, error) {
+	s := &data.StakeSnapshot{
+		ActiveStake:      data.NewAmount(nil),
+		UnStaked:         data.NewAmount(nil),
+		UnBondable:       data.NewAmount(nil),
+		ClaimableRewards: data.NewAmount(nil),
+		Earned:           data.NewAmount(nil),
+	}
+	var cumulated sql.NullString
+	err := scan(&s.Address, &s.Contract, &s.Timestamp, &s.Epoch, s.ActiveStake, s.UnStaked, s.UnBondable,
+		s.ClaimableRewards, &cumulated, s.Earned)
+	if err != nil || !cumulated.Valid {
+		return s, err
+	}
+	s.CumulatedRewards, err = data.ParseAttoAmount(cumulated.String)
+
+	return s, err
+}
+
+// GetLastStakeSnapshot - returns the newest snapshot of a wallet's balances with a contract, or nil if there is none
+func (d *Database) GetLastStakeSnapshot(address string, contract string) (*data.StakeSnapshot, error) {
+	row := d.sqldb.QueryRow("select "+stakeSnapshotColumns+" from StakeSnapshots where Address = ? and Contract = ? "+
+		"order by Timestamp desc limit 1", address, contract)
+	snapshot, err := scanStakeSnapshot(row.Scan)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		log.Error("can not read stake snapshot from database", "error", err)
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
+// GetStakeSnapshots - returns the snapshots of a wallet's balances with a contract taken at or after since, oldest first
+func (d *Database) GetStakeSnapshots(address string, contract string, since int64) ([]*data.StakeSnapshot, error) {
+	row, err := d.sqldb.Query("select "+stakeSnapshotColumns+" from StakeSnapshots where Address = ? and Contract = ? "+
+		"and Timestamp >= ? order by Timestamp", address, contract, since)
+	if err != nil {
+		log.Error("can not read stake snapshots from database", "error", err)
+		return nil, err
+	}
+
+	defer row.Close()
+	snapshots := make([]*data.StakeSnapshot, 0)
+	for row.Next() {
+		snapshot, err := scanStakeSnapshot(row.Scan)
+		if err != nil {
+			log.Warn("can not read stake snapshot row", "error", err)
+			continue
+		}
+
+		snapshots = append(snapshots, snapshot)
+	}
+
+	return snapshots, nil
+}
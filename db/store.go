@@ -0,0 +1,82 @@
+package db
+
+import (
+	"github.com/DrDelphi/ElrondDSSC/data"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+)
+
+// UserStore - defines the storage of the Telegram users and their wallets
+type UserStore interface {
+	AddUser(user *tgbotapi.User) error
+	GetUserByTgID(tgID int64) *data.User
+	GetUserByTgUser(tgUser *tgbotapi.User) *data.User
+	GetUsers() map[int64]*data.User
+	AddUserWallet(user *data.User, address string) error
+	RemoveWallet(id uint64) error
+	SetWalletChallenge(walletID uint64, challenge string) error
+	GetWalletChallenge(walletID uint64) (string, int64, error)
+	SetWalletVerified(wallet *data.UserWallet) error
+	SetLastMenuID(user *data.User, menuID int) error
+}
+
+// ConversationStore - defines the storage of the answers the bot waits for from the users
+type ConversationStore interface {
+	GetConversation(tgID int64) (*data.Conversation, error)
+	SetConversation(c *data.Conversation) error
+	RemoveConversation(tgID int64) error
+}
+
+// SettingsStore - defines the storage of the owner settings and of the application properties
+type SettingsStore interface {
+	GetOwnerAddress() string
+	GetOwnerPrivateKey() string
+	SetOwnerAddress(address string) error
+	SetOwnerPrivateKey(privateKey string) error
+	GetContractAddress() (string, string)
+	SetContractAddress(address string, owner string) error
+	GetChainID() (string, error)
+	SetChainID(chainID string) error
+}
+
+// NodeStore - defines the storage of the names given to the nodes
+type NodeStore interface {
+	GetNodeNames() (map[string]string, error)
+	SetNodeName(blsKey string, name string) error
+}
+
+// TransactionStore - defines the storage of the tracked transactions and of the links waiting for a callback
+type TransactionStore interface {
+	AddPendingTx(tx *data.PendingTx) error
+	GetPendingTxs() ([]*data.PendingTx, error)
+	RemovePendingTx(hash string) error
+	AddHookIntent(intent *data.HookIntent) error
+	TakeHookIntent(token string) (*data.HookIntent, error)
+	RemoveHookIntentsBefore(createdAt int64) error
+}
+
+// HistoryStore - defines the storage of the indexed contract activity, wallet operations, stake snapshots
+// and providers
+type HistoryStore interface {
+	GetActivityCursor(contract string) (*data.ActivityCursor, error)
+	SaveContractActivity(activities []*data.ContractActivity, cursor *data.ActivityCursor) error
+	GetContractActivity(contract string, category string, limit int) ([]*data.ContractActivity, error)
+	AddWalletOperations(operations []*data.WalletOperation) error
+	HasWalletOperation(hash string) (bool, error)
+	GetWalletOperations(address string, contract string) ([]*data.WalletOperation, error)
+	GetProviders() ([]*data.Provider, error)
+	SetProviders(providers []*data.Provider) error
+	AddStakeSnapshots(snapshots []*data.StakeSnapshot) error
+	GetLastStakeSnapshot(address string, contract string) (*data.StakeSnapshot, error)
+	GetStakeSnapshots(address string, contract string, since int64) ([]*data.StakeSnapshot, error)
+}
+
+// Store - defines all the operations of the application's storage, whatever the database behind it
+type Store interface {
+	UserStore
+	ConversationStore
+	SettingsStore
+	NodeStore
+	TransactionStore
+	HistoryStore
+	Close() error
+}
@@ -0,0 +1,703 @@
+// Package storetest provides the conformance suite every db.Store backend must pass, and the factories
+// opening the supported backends on an empty database
+package storetest
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/DrDelphi/ElrondDSSC/data"
+	"github.com/DrDelphi/ElrondDSSC/db"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+)
+
+// Factory - creates an empty database and returns its driver and data source. The database is removed when
+// the test ends
+type Factory func(t *testing.T) (driver string, dataSource string)
+
+// SQLite - returns a factory creating a new SQLite file in the test's temporary directory
+func SQLite() Factory {
+	return func(t *testing.T) (string, string) {
+		return db.DriverSQLite, filepath.Join(t.TempDir(), "store.sqlite")
+	}
+}
+
+// Postgres - returns a factory creating a new schema in the PostgreSQL database at dataSource, dropped
+// when the test ends. dataSource is a URL (postgres://...) or a list of key=value settings
+func Postgres(dataSource string) Factory {
+	return func(t *testing.T) (string, string) {
+		admin, err := sql.Open(db.DriverPostgres, dataSource)
+		if err != nil {
+			t.Fatalf("can not open database: %v", err)
+		}
+		defer func() {
+			_ = admin.Close()
+		}()
+
+		schema := "storetest_" + randomHex(t, 8)
+		_, err = admin.Exec("create schema " + schema)
+		if err != nil {
+			t.Fatalf("can not create schema: %v", err)
+		}
+		t.Cleanup(func() {
+			cleanup, err := sql.Open(db.DriverPostgres, dataSource)
+			if err != nil {
+				return
+			}
+			_, _ = cleanup.Exec("drop schema " + schema + " cascade")
+			_ = cleanup.Close()
+		})
+
+		return db.DriverPostgres, withSearchPath(dataSource, schema)
+	}
+}
+
+func withSearchPath(dataSource string, schema string) string {
+	if strings.HasPrefix(dataSource, "postgres://") || strings.HasPrefix(dataSource, "postgresql://") {
+		separator := "?"
+		if strings.Contains(dataSource, "?") {
+			separator = "&"
+		}
+
+		return dataSource + separator + "search_path=" + schema
+	}
+
+	return dataSource + " search_path=" + schema
+}
+
+// openPair - opens two stores on the same database, as two instances of the application would. The stores
+// are closed when the test ends
+func openPair(t *testing.T, driver string, dataSource string) (db.Store, db.Store) {
+	first, err := db.NewDatabase(driver, dataSource)
+	if err != nil {
+		t.Fatalf("can not open store: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = first.Close()
+	})
+
+	second, err := db.NewDatabase(driver, dataSource)
+	if err != nil {
+		t.Fatalf("can not open second store: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = second.Close()
+	})
+
+	return first, second
+}
+
+func randomHex(t *testing.T, size int) string {
+	b := make([]byte, size)
+	_, err := rand.Read(b)
+	if err != nil {
+		t.Fatalf("can not generate random bytes: %v", err)
+	}
+
+	return hex.EncodeToString(b)
+}
+
+// Run - runs the conformance suite against the stores opened by the factory, each subtest on an empty database
+func Run(t *testing.T, open Factory) {
+	tests := []struct {
+		name string
+		run  func(t *testing.T, s db.Store, other db.Store)
+	}{
+		{"Users", testUsers},
+		{"Wallets", testWallets},
+		{"WalletVerification", testWalletVerification},
+		{"Conversations", testConversations},
+		{"Settings", testSettings},
+		{"Properties", testProperties},
+		{"NodeNames", testNodeNames},
+		{"PendingTxs", testPendingTxs},
+		{"HookIntents", testHookIntents},
+		{"Providers", testProviders},
+		{"WalletOperations", testWalletOperations},
+		{"ContractActivity", testContractActivity},
+		{"StakeSnapshots", testStakeSnapshots},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			driver, dataSource := open(t)
+			s, other := openPair(t, driver, dataSource)
+			tt.run(t, s, other)
+		})
+	}
+
+	t.Run("ConcurrentMigrations", func(t *testing.T) {
+		testConcurrentMigrations(t, open)
+	})
+}
+
+// testConcurrentMigrations - migrates an empty database from two instances at once, which must both succeed,
+// each migration being applied by only one of them
+func testConcurrentMigrations(t *testing.T, open Factory) {
+	driver, dataSource := open(t)
+
+	const instances = 2
+	applied := make([][]*data.SchemaMigration, instances)
+	errs := make([]error, instances)
+	var wg sync.WaitGroup
+	for i := 0; i < instances; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			applied[i], errs[i] = db.Migrate(driver, dataSource)
+		}(i)
+	}
+	wg.Wait()
+
+	versions := make(map[int]int)
+	for i := 0; i < instances; i++ {
+		if errs[i] != nil {
+			t.Fatalf("Migrate %v: %v", i+1, errs[i])
+		}
+		for _, m := range applied[i] {
+			versions[m.Version]++
+		}
+	}
+
+	status, err := db.SchemaStatus(driver, dataSource)
+	if err != nil {
+		t.Fatalf("SchemaStatus: %v", err)
+	}
+	for _, m := range status {
+		if m.AppliedAt == 0 || versions[m.Version] != 1 {
+			t.Errorf("migration %v applied %v times, at %v", m.Version, versions[m.Version], m.AppliedAt)
+		}
+	}
+	if len(versions) != len(status) {
+		t.Errorf("expected %v migrations applied, got %v", len(status), len(versions))
+	}
+}
+
+const (
+	addressA = "erd1qyu5wthldzr8wx5c9ucg8kjagg0jfs53s8nr3zpz3hypefsdd8ssycr6th"
+	addressB = "erd1spyavw0956vq68xj8y4tenjpq2wd5a9p2c6j8gsz7ztyrnpxrruqzu66jx"
+	contract = "erd1qqqqqqqqqqqqqqqpqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqhllllsajxzat"
+)
+
+func tgUser(id int) *tgbotapi.User {
+	return &tgbotapi.User{ID: id, UserName: "user", FirstName: "Fïrst 🥩", LastName: "Last"}
+}
+
+func addUser(t *testing.T, s db.Store, id int) *data.User {
+	err := s.AddUser(tgUser(id))
+	if err != nil {
+		t.Fatalf("AddUser: %v", err)
+	}
+
+	user := s.GetUserByTgID(int64(id))
+	if user == nil {
+		t.Fatalf("GetUserByTgID(%v): user not found after AddUser", id)
+	}
+
+	return user
+}
+
+func testUsers(t *testing.T, s db.Store, other db.Store) {
+	if s.GetUserByTgID(1) != nil {
+		t.Fatalf("GetUserByTgID on an empty store: expected nil")
+	}
+
+	user := addUser(t, s, 1)
+	if user.TgID != 1 || user.TgUser != "user" || user.TgFirst != "Fïrst 🥩" || user.TgLast != "Last" {
+		t.Fatalf("GetUserByTgID: unexpected user %+v", user)
+	}
+	if s.AddUser(tgUser(1)) == nil {
+		t.Fatalf("AddUser: expected an error adding the same Telegram user twice")
+	}
+
+	seen := other.GetUserByTgID(1)
+	if seen == nil || seen.ID != user.ID {
+		t.Fatalf("GetUserByTgID: user added by one store not seen by the other, got %+v", seen)
+	}
+
+	renamed := tgUser(1)
+	renamed.UserName = "renamed"
+	if got := s.GetUserByTgUser(renamed); got == nil || got.TgUser != "renamed" {
+		t.Fatalf("GetUserByTgUser: expected the username updated, got %+v", got)
+	}
+	if got := other.GetUserByTgID(1); got.TgUser != "renamed" {
+		t.Fatalf("GetUserByTgID: expected the renamed user, got %q", got.TgUser)
+	}
+
+	addUser(t, other, 2)
+	users := s.GetUsers()
+	if len(users) != 2 || users[1] == nil || users[2] == nil {
+		t.Fatalf("GetUsers: expected users 1 and 2, got %v", users)
+	}
+}
+
+func testWallets(t *testing.T, s db.Store, other db.Store) {
+	user := addUser(t, s, 1)
+	err := s.AddUserWallet(user, addressA)
+	if err == nil {
+		err = s.AddUserWallet(user, addressB)
+	}
+	if err != nil {
+		t.Fatalf("AddUserWallet: %v", err)
+	}
+	if len(user.Wallets) != 2 || user.Wallets[0].ID == 0 || user.Wallets[0].ID == user.Wallets[1].ID {
+		t.Fatalf("AddUserWallet: expected two wallets with distinct IDs, got %v", user.Wallets)
+	}
+
+	seen := other.GetUserByTgID(1)
+	if len(seen.Wallets) != 2 || seen.Wallets[0].Address != addressA || seen.Wallets[1].Address != addressB ||
+		seen.Wallets[0].UserID != user.ID || seen.Wallets[0].Verified {
+		t.Fatalf("GetUserByTgID: unexpected wallets %+v", seen.Wallets)
+	}
+
+	err = other.RemoveWallet(user.Wallets[0].ID)
+	if err != nil {
+		t.Fatalf("RemoveWallet: %v", err)
+	}
+	wallets := s.GetUserByTgID(1).Wallets
+	if len(wallets) != 1 || wallets[0].Address != addressB {
+		t.Fatalf("RemoveWallet: expected only %v left, got %+v", addressB, wallets)
+	}
+
+	second := addUser(t, other, 2)
+	err = other.AddUserWallet(second, addressA)
+	if err != nil {
+		t.Fatalf("AddUserWallet: %v", err)
+	}
+	addUser(t, other, 3)
+	users := s.GetUsers()
+	if len(users) != 3 || len(users[1].Wallets) != 1 || users[1].Wallets[0].Address != addressB ||
+		len(users[2].Wallets) != 1 || users[2].Wallets[0].Address != addressA ||
+		users[3].Wallets == nil || len(users[3].Wallets) != 0 {
+		t.Fatalf("GetUsers: unexpected users' wallets %+v", users)
+	}
+}
+
+func testWalletVerification(t *testing.T, s db.Store, other db.Store) {
+	user := addUser(t, s, 1)
+	err := s.AddUserWallet(user, addressA)
+	if err != nil {
+		t.Fatalf("AddUserWallet: %v", err)
+	}
+	wallet := user.Wallets[0]
+
+	challenge, _, err := s.GetWalletChallenge(wallet.ID)
+	if err != nil || challenge != "" {
+		t.Fatalf("GetWalletChallenge: expected no challenge before any was set, got %q %v", challenge, err)
+	}
+
+	err = s.SetWalletChallenge(wallet.ID, "first")
+	if err == nil {
+		err = other.SetWalletChallenge(wallet.ID, "second")
+	}
+	if err != nil {
+		t.Fatalf("SetWalletChallenge: %v", err)
+	}
+	var createdAt int64
+	challenge, createdAt, err = s.GetWalletChallenge(wallet.ID)
+	if err != nil || challenge != "second" || createdAt == 0 {
+		t.Fatalf("GetWalletChallenge: expected the replaced challenge, got %q %v %v", challenge, createdAt, err)
+	}
+
+	err = other.SetWalletVerified(wallet)
+	if err != nil || !wallet.Verified {
+		t.Fatalf("SetWalletVerified: %v, verified %v", err, wallet.Verified)
+	}
+	if !s.GetUserByTgID(1).Wallets[0].Verified {
+		t.Fatalf("SetWalletVerified: the wallet is not verified when read again")
+	}
+	challenge, _, err = s.GetWalletChallenge(wallet.ID)
+	if err != nil || challenge != "" {
+		t.Fatalf("SetWalletVerified: expected the challenge removed, got %q %v", challenge, err)
+	}
+}
+
+func testConversations(t *testing.T, s db.Store, other db.Store) {
+	user := addUser(t, s, 1)
+	if user.LastMenuID != 0 {
+		t.Fatalf("AddUser: expected no last menu, got %v", user.LastMenuID)
+	}
+	err := s.SetLastMenuID(user, 42)
+	if err != nil || user.LastMenuID != 42 {
+		t.Fatalf("SetLastMenuID: %v, last menu %v", err, user.LastMenuID)
+	}
+	if got := other.GetUserByTgID(1).LastMenuID; got != 42 {
+		t.Fatalf("SetLastMenuID: expected 42 read by the other store, got %v", got)
+	}
+
+	conversation, err := s.GetConversation(1)
+	if err != nil || conversation != nil {
+		t.Fatalf("GetConversation on an empty store: %+v %v", conversation, err)
+	}
+
+	err = s.SetConversation(&data.Conversation{TgID: 1, State: "first", Param: "p", ExpiresAt: 10})
+	if err == nil {
+		err = other.SetConversation(&data.Conversation{TgID: 1, State: "second", Param: addressA, ExpiresAt: 20})
+	}
+	if err != nil {
+		t.Fatalf("SetConversation: %v", err)
+	}
+	conversation, err = s.GetConversation(1)
+	if err != nil || conversation == nil || conversation.TgID != 1 || conversation.State != "second" ||
+		conversation.Param != addressA || conversation.ExpiresAt != 20 {
+		t.Fatalf("GetConversation: expected the replaced conversation, got %+v %v", conversation, err)
+	}
+
+	err = other.RemoveConversation(1)
+	if err != nil {
+		t.Fatalf("RemoveConversation: %v", err)
+	}
+	conversation, err = s.GetConversation(1)
+	if err != nil || conversation != nil {
+		t.Fatalf("RemoveConversation: expected no conversation, got %+v %v", conversation, err)
+	}
+}
+
+func testSettings(t *testing.T, s db.Store, other db.Store) {
+	if s.GetOwnerAddress() != "" || s.GetOwnerPrivateKey() != "" {
+		t.Fatalf("empty store: expected no owner address and key")
+	}
+
+	err := s.SetOwnerPrivateKey("sealed key")
+	if err == nil {
+		err = s.SetOwnerAddress(addressA)
+	}
+	if err != nil {
+		t.Fatalf("SetOwnerAddress: %v", err)
+	}
+	if other.GetOwnerAddress() != addressA || other.GetOwnerPrivateKey() != "sealed key" {
+		t.Fatalf("settings saved by one store not seen by the other: %q %q", other.GetOwnerAddress(),
+			other.GetOwnerPrivateKey())
+	}
+
+	err = other.SetOwnerAddress(addressB)
+	if err == nil {
+		err = other.SetOwnerPrivateKey("")
+	}
+	if err != nil {
+		t.Fatalf("SetOwnerPrivateKey: %v", err)
+	}
+	if s.GetOwnerAddress() != addressB || s.GetOwnerPrivateKey() != "" {
+		t.Fatalf("settings not replaced: %q %q", s.GetOwnerAddress(), s.GetOwnerPrivateKey())
+	}
+}
+
+func testProperties(t *testing.T, s db.Store, other db.Store) {
+	chainID, err := s.GetChainID()
+	if err != nil || chainID != "" {
+		t.Fatalf("GetChainID on an empty store: %q %v", chainID, err)
+	}
+
+	err = s.SetChainID("D")
+	if err == nil {
+		err = other.SetChainID("T")
+	}
+	if err != nil {
+		t.Fatalf("SetChainID: %v", err)
+	}
+	chainID, err = s.GetChainID()
+	if err != nil || chainID != "T" {
+		t.Fatalf("GetChainID: expected the replaced chain ID, got %q %v", chainID, err)
+	}
+
+	err = s.SetContractAddress(contract, addressA)
+	if err != nil {
+		t.Fatalf("SetContractAddress: %v", err)
+	}
+	address, owner := other.GetContractAddress()
+	if address != contract || owner != addressA {
+		t.Fatalf("GetContractAddress: got %q %q", address, owner)
+	}
+}
+
+func testNodeNames(t *testing.T, s db.Store, other db.Store) {
+	err := s.SetNodeName("key1", "first")
+	if err == nil {
+		err = s.SetNodeName("key2", "second")
+	}
+	if err == nil {
+		err = other.SetNodeName("key1", "renamed")
+	}
+	if err != nil {
+		t.Fatalf("SetNodeName: %v", err)
+	}
+
+	names, err := s.GetNodeNames()
+	if err != nil || len(names) != 2 || names["key1"] != "renamed" || names["key2"] != "second" {
+		t.Fatalf("GetNodeNames: got %v %v", names, err)
+	}
+
+	err = other.SetNodeName("key2", "")
+	if err != nil {
+		t.Fatalf("SetNodeName: %v", err)
+	}
+	names, _ = s.GetNodeNames()
+	if _, ok := names["key2"]; ok || len(names) != 1 {
+		t.Fatalf("SetNodeName: expected an empty name to remove the node name, got %v", names)
+	}
+}
+
+func testPendingTxs(t *testing.T, s db.Store, other db.Store) {
+	err := s.AddPendingTx(&data.PendingTx{Hash: "h1", TgID: 1, Description: "first", CreatedAt: 10})
+	if err == nil {
+		err = s.AddPendingTx(&data.PendingTx{Hash: "h2", TgID: 2, Description: "second", CreatedAt: 20})
+	}
+	if err == nil {
+		err = other.AddPendingTx(&data.PendingTx{Hash: "h1", TgID: 1, Description: "replaced", CreatedAt: 30})
+	}
+	if err != nil {
+		t.Fatalf("AddPendingTx: %v", err)
+	}
+
+	txs, err := s.GetPendingTxs()
+	if err != nil || len(txs) != 2 {
+		t.Fatalf("GetPendingTxs: got %v %v", txs, err)
+	}
+	byHash := make(map[string]*data.PendingTx)
+	for _, tx := range txs {
+		byHash[tx.Hash] = tx
+	}
+	if byHash["h1"] == nil || byHash["h1"].Description != "replaced" || byHash["h1"].CreatedAt != 30 ||
+		byHash["h2"] == nil || byHash["h2"].TgID != 2 {
+		t.Fatalf("GetPendingTxs: unexpected transactions %+v %+v", byHash["h1"], byHash["h2"])
+	}
+
+	err = other.RemovePendingTx("h1")
+	if err != nil {
+		t.Fatalf("RemovePendingTx: %v", err)
+	}
+	txs, _ = s.GetPendingTxs()
+	if len(txs) != 1 || txs[0].Hash != "h2" {
+		t.Fatalf("RemovePendingTx: expected only h2 left, got %v", txs)
+	}
+}
+
+func testHookIntents(t *testing.T, s db.Store, other db.Store) {
+	err := s.AddHookIntent(&data.HookIntent{Token: "t1", TgID: 1, Description: "Delegate", Sender: addressA,
+		Receiver: contract, Value: "1000000000000000000", Data: "delegate", CreatedAt: 10})
+	if err == nil {
+		err = s.AddHookIntent(&data.HookIntent{Token: "t2", TgID: 2, Description: "Claim", CreatedAt: 20})
+	}
+	if err != nil {
+		t.Fatalf("AddHookIntent: %v", err)
+	}
+
+	intent, err := other.TakeHookIntent("t1")
+	if err != nil || intent == nil || intent.Token != "t1" || intent.TgID != 1 || intent.Description != "Delegate" ||
+		intent.Sender != addressA || intent.Receiver != contract || intent.Value != "1000000000000000000" ||
+		intent.Data != "delegate" || intent.CreatedAt != 10 {
+		t.Fatalf("TakeHookIntent: got %+v %v", intent, err)
+	}
+	intent, _ = s.TakeHookIntent("t1")
+	if intent != nil {
+		t.Fatalf("TakeHookIntent: an intent can only be taken once, got %+v", intent)
+	}
+
+	err = other.RemoveHookIntentsBefore(30)
+	if err != nil {
+		t.Fatalf("RemoveHookIntentsBefore: %v", err)
+	}
+	intent, _ = s.TakeHookIntent("t2")
+	if intent != nil {
+		t.Fatalf("RemoveHookIntentsBefore: expected the old intent removed, got %+v", intent)
+	}
+}
+
+func testProviders(t *testing.T, s db.Store, other db.Store) {
+	providers := []*data.Provider{
+		{
+			Address:          contract,
+			OwnerAddress:     addressA,
+			Name:             "Provider",
+			Website:          "https://example.com",
+			Identifier:       "provider",
+			ServiceFee:       12.5,
+			MaxDelegationCap: data.MustParseAmount("5000"),
+			TotalActiveStake: data.MustParseAmount("1234.5"),
+			NumUsers:         42,
+			UpdatedAt:        100,
+		},
+	}
+	err := s.SetProviders(providers)
+	if err != nil {
+		t.Fatalf("SetProviders: %v", err)
+	}
+
+	saved, err := other.GetProviders()
+	if err != nil || len(saved) != 1 {
+		t.Fatalf("GetProviders: got %v %v", saved, err)
+	}
+	p := saved[0]
+	if p.Address != contract || p.OwnerAddress != addressA || p.Name != "Provider" || p.ServiceFee != 12.5 ||
+		p.MaxDelegationCap.Cmp(providers[0].MaxDelegationCap) != 0 ||
+		p.TotalActiveStake.Cmp(providers[0].TotalActiveStake) != 0 || p.NumUsers != 42 || p.UpdatedAt != 100 {
+		t.Fatalf("GetProviders: unexpected provider %+v", p)
+	}
+
+	err = other.SetProviders(nil)
+	if err != nil {
+		t.Fatalf("SetProviders: %v", err)
+	}
+	saved, _ = s.GetProviders()
+	if len(saved) != 0 {
+		t.Fatalf("SetProviders: expected the directory replaced, got %v", saved)
+	}
+}
+
+func testWalletOperations(t *testing.T, s db.Store, other db.Store) {
+	found, err := s.HasWalletOperation("h1")
+	if err != nil || found {
+		t.Fatalf("HasWalletOperation on an empty store: %v %v", found, err)
+	}
+
+	err = s.AddWalletOperations([]*data.WalletOperation{
+		{Hash: "h2", Address: addressA, Contract: contract, Function: "claimRewards",
+			Amount: data.MustParseAmount("1.5"), Timestamp: 20, Status: "pending"},
+		{Hash: "h1", Address: addressA, Contract: contract, Function: "delegate",
+			Amount: data.MustParseAmount("10"), Timestamp: 10, Status: "success"},
+		{Hash: "h3", Address: addressB, Contract: contract, Function: "delegate",
+			Amount: data.MustParseAmount("1"), Timestamp: 30, Status: "success"},
+	})
+	if err == nil {
+		err = other.AddWalletOperations([]*data.WalletOperation{
+			{Hash: "h2", Address: addressA, Contract: contract, Function: "claimRewards",
+				Amount: data.MustParseAmount("1.5"), Timestamp: 20, Status: "success"},
+		})
+	}
+	if err != nil {
+		t.Fatalf("AddWalletOperations: %v", err)
+	}
+
+	found, err = other.HasWalletOperation("h1")
+	if err != nil || !found {
+		t.Fatalf("HasWalletOperation: %v %v", found, err)
+	}
+
+	operations, err := s.GetWalletOperations(addressA, contract)
+	if err != nil || len(operations) != 2 {
+		t.Fatalf("GetWalletOperations: got %v %v", operations, err)
+	}
+	if operations[0].Hash != "h1" || operations[1].Hash != "h2" || operations[1].Status != "success" ||
+		operations[0].Amount.Cmp(data.MustParseAmount("10")) != 0 {
+		t.Fatalf("GetWalletOperations: unexpected operations %+v %+v", operations[0], operations[1])
+	}
+}
+
+func testContractActivity(t *testing.T, s db.Store, other db.Store) {
+	cursor, err := s.GetActivityCursor(contract)
+	if err != nil || cursor.Contract != contract || cursor.Synced != 0 || cursor.Walking {
+		t.Fatalf("GetActivityCursor on an empty store: %+v %v", cursor, err)
+	}
+
+	activities := []*data.ContractActivity{
+		{Hash: "h1", Contract: contract, Sender: addressA, Function: "delegate", Category: "delegation",
+			Args: []string{}, Value: data.MustParseAmount("10"), Amount: data.MustParseAmount("10"), Timestamp: 10,
+			Status: "success"},
+		{Hash: "h2", Contract: contract, Sender: addressB, Function: "unDelegate", Category: "undelegation",
+			Args: []string{"0a", "ff"}, Value: data.NewAmount(nil), Amount: data.MustParseAmount("2"), Timestamp: 20,
+			Status: "success"},
+	}
+	cursor = &data.ActivityCursor{Contract: contract, Synced: 5, Walking: true, WalkTop: 20, WalkBefore: 10, WalkSkip: 1}
+	err = s.SaveContractActivity(activities, cursor)
+	if err != nil {
+		t.Fatalf("SaveContractActivity: %v", err)
+	}
+
+	saved, err := other.GetActivityCursor(contract)
+	if err != nil || *saved != *cursor {
+		t.Fatalf("GetActivityCursor: expected %+v, got %+v %v", cursor, saved, err)
+	}
+
+	cursor = &data.ActivityCursor{Contract: contract, Synced: 20}
+	activities[0].Status = "fail"
+	err = other.SaveContractActivity(activities[:1], cursor)
+	if err != nil {
+		t.Fatalf("SaveContractActivity: %v", err)
+	}
+	saved, _ = s.GetActivityCursor(contract)
+	if *saved != *cursor {
+		t.Fatalf("GetActivityCursor: expected the replaced cursor %+v, got %+v", cursor, saved)
+	}
+
+	all, err := s.GetContractActivity(contract, "", 10)
+	if err != nil || len(all) != 2 || all[0].Hash != "h2" || all[1].Hash != "h1" || all[1].Status != "fail" {
+		t.Fatalf("GetContractActivity: expected h2 and the replaced h1, got %v %v", all, err)
+	}
+	if len(all[0].Args) != 2 || all[0].Args[1] != "ff" || all[0].Amount.Cmp(data.MustParseAmount("2")) != 0 {
+		t.Fatalf("GetContractActivity: unexpected activity %+v", all[0])
+	}
+
+	filtered, err := other.GetContractActivity(contract, "delegation", 10)
+	if err != nil || len(filtered) != 1 || filtered[0].Hash != "h1" {
+		t.Fatalf("GetContractActivity: expected only h1 in its category, got %v %v", filtered, err)
+	}
+	limited, _ := s.GetContractActivity(contract, "", 1)
+	if len(limited) != 1 || limited[0].Hash != "h2" {
+		t.Fatalf("GetContractActivity: expected only the newest activity, got %v", limited)
+	}
+}
+
+func testStakeSnapshots(t *testing.T, s db.Store, other db.Store) {
+	last, err := s.GetLastStakeSnapshot(addressA, contract)
+	if err != nil || last != nil {
+		t.Fatalf("GetLastStakeSnapshot on an empty store: %+v %v", last, err)
+	}
+
+	snapshot := func(address string, timestamp int64, claimable string) *data.StakeSnapshot {
+		return &data.StakeSnapshot{Address: address, Contract: contract, Epoch: uint32(timestamp / 100),
+			Timestamp: timestamp, ActiveStake: data.MustParseAmount("100"), UnStaked: data.MustParseAmount("1"),
+			UnBondable: data.NewAmount(nil), ClaimableRewards: data.MustParseAmount(claimable),
+			CumulatedRewards: data.MustParseAmount("1").Add(data.MustParseAmount(claimable)),
+			Earned:           data.MustParseAmount("0.01")}
+	}
+	err = s.AddStakeSnapshots([]*data.StakeSnapshot{
+		snapshot(addressA, 200, "0.02"),
+		snapshot(addressA, 100, "0.01"),
+		snapshot(addressB, 300, "5"),
+	})
+	if err == nil {
+		err = other.AddStakeSnapshots([]*data.StakeSnapshot{snapshot(addressA, 300, "0.03")})
+	}
+	if err != nil {
+		t.Fatalf("AddStakeSnapshots: %v", err)
+	}
+
+	last, err = other.GetLastStakeSnapshot(addressA, contract)
+	if err != nil || last == nil || last.Timestamp != 300 || last.Epoch != 3 ||
+		last.ClaimableRewards.Cmp(data.MustParseAmount("0.03")) != 0 ||
+		last.ActiveStake.Cmp(data.MustParseAmount("100")) != 0 || last.Earned.Cmp(data.MustParseAmount("0.01")) != 0 ||
+		last.CumulatedRewards == nil || last.CumulatedRewards.Cmp(data.MustParseAmount("1.03")) != 0 {
+		t.Fatalf("GetLastStakeSnapshot: got %+v %v", last, err)
+	}
+
+	snapshots, err := s.GetStakeSnapshots(addressA, contract, 200)
+	if err != nil || len(snapshots) != 2 || snapshots[0].Timestamp != 200 || snapshots[1].Timestamp != 300 {
+		t.Fatalf("GetStakeSnapshots: expected the snapshots at 200 and 300, got %v %v", snapshots, err)
+	}
+
+	err = other.AddStakeSnapshots([]*data.StakeSnapshot{snapshot(addressA, 300, "0.04")})
+	if err != nil {
+		t.Fatalf("AddStakeSnapshots: %v", err)
+	}
+	snapshots, _ = s.GetStakeSnapshots(addressA, contract, 0)
+	if len(snapshots) != 3 || snapshots[2].ClaimableRewards.Cmp(data.MustParseAmount("0.04")) != 0 {
+		t.Fatalf("AddStakeSnapshots: expected the snapshot at 300 replaced, got %v", snapshots)
+	}
+
+	withoutCumulated := snapshot(addressB, 400, "6")
+	withoutCumulated.CumulatedRewards = nil
+	err = s.AddStakeSnapshots([]*data.StakeSnapshot{withoutCumulated})
+	if err != nil {
+		t.Fatalf("AddStakeSnapshots: %v", err)
+	}
+	last, err = other.GetLastStakeSnapshot(addressB, contract)
+	if err != nil || last == nil || last.Timestamp != 400 || last.CumulatedRewards != nil {
+		t.Fatalf("GetLastStakeSnapshot: expected no cumulated rewards, got %+v %v", last, err)
+	}
+}
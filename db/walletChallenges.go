@@ -0,0 +1,67 @@
+package db
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/DrDelphi/ElrondDSSC/data"
+)
+
+// SetWalletChallenge - saves the challenge the owner of a wallet has to sign, replacing any previous one
+func (d *Database) SetWalletChallenge(walletID uint64, challenge string) error {
+	_, err := d.sqldb.Exec("insert into WalletChallenges(WalletID, Challenge, CreatedAt) values(?, ?, ?) "+
+		"on conflict(WalletID) do update set Challenge = excluded.Challenge, CreatedAt = excluded.CreatedAt",
+		walletID, challenge, time.Now().Unix())
+	if err != nil {
+		log.Error("can not save wallet challenge in database", "error", err)
+	}
+
+	return err
+}
+
+// GetWalletChallenge - returns the challenge issued for a wallet and the unix time it was issued at,
+// or an empty challenge if there is none
+func (d *Database) GetWalletChallenge(walletID uint64) (string, int64, error) {
+	var challenge string
+	var createdAt int64
+	err := d.sqldb.QueryRow("select Challenge, CreatedAt from WalletChallenges where WalletID = ?", walletID).
+		Scan(&challenge, &createdAt)
+	if err == sql.ErrNoRows {
+		return "", 0, nil
+	}
+	if err != nil {
+		log.Error("can not read wallet challenge from database", "error", err)
+		return "", 0, err
+	}
+
+	return challenge, createdAt, nil
+}
+
+// SetWalletVerified - marks a user wallet as verified and removes its challenge
+func (d *Database) SetWalletVerified(wallet *data.UserWallet) error {
+	tx, err := d.sqldb.Begin()
+	if err != nil {
+		log.Error("can not mark wallet as verified in database", "error", err)
+		return err
+	}
+
+	_, err = tx.Exec("update UserWallets set Verified = 1 where ID = ?", wallet.ID)
+	if err == nil {
+		_, err = tx.Exec("delete from WalletChallenges where WalletID = ?", wallet.ID)
+	}
+	if err != nil {
+		_ = tx.Rollback()
+		log.Error("can not mark wallet as verified in database", "error", err)
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		log.Error("can not mark wallet as verified in database", "error", err)
+		return err
+	}
+
+	wallet.Verified = true
+
+	return nil
+}
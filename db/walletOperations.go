@@ -0,0 +1,80 @@
+package db
+
+import (
+	"database/sql"
+
+	"github.com/DrDelphi/ElrondDSSC/data"
+)
+
+// AddWalletOperations - saves the delegation transactions of a wallet
+func (d *Database) AddWalletOperations(operations []*data.WalletOperation) error {
+	tx, err := d.sqldb.Begin()
+	if err != nil {
+		log.Error("can not add wallet operations in database", "error", err)
+		return err
+	}
+
+	sql := "insert into WalletOperations(Hash, Address, Contract, Function, Amount, Timestamp, Status) " +
+		"values(?, ?, ?, ?, ?, ?, ?) on conflict(Hash) do update set Address = excluded.Address, " +
+		"Contract = excluded.Contract, Function = excluded.Function, Amount = excluded.Amount, " +
+		"Timestamp = excluded.Timestamp, Status = excluded.Status"
+	statement, err := tx.Prepare(sql)
+	if err != nil {
+		_ = tx.Rollback()
+		log.Error("can not add wallet operations in database", "error", err)
+		return err
+	}
+	defer statement.Close()
+
+	for _, op := range operations {
+		_, err = statement.Exec(op.Hash, op.Address, op.Contract, op.Function, op.Amount, op.Timestamp, op.Status)
+		if err != nil {
+			_ = tx.Rollback()
+			log.Error("can not add wallet operation in database", "hash", op.Hash, "error", err)
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// HasWalletOperation - returns true if the transaction is already saved
+func (d *Database) HasWalletOperation(hash string) (bool, error) {
+	var found string
+	err := d.sqldb.QueryRow("select Hash from WalletOperations where Hash = ?", hash).Scan(&found)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		log.Error("can not read wallet operation from database", "error", err)
+		return false, err
+	}
+
+	return true, nil
+}
+
+// GetWalletOperations - returns the saved operations of a wallet with a contract, oldest first
+func (d *Database) GetWalletOperations(address string, contract string) ([]*data.WalletOperation, error) {
+	sql := "select Hash, Address, Contract, Function, Amount, Timestamp, Status from WalletOperations " +
+		"where Address = ? and Contract = ? order by Timestamp"
+	row, err := d.sqldb.Query(sql, address, contract)
+	if err != nil {
+		log.Error("can not read wallet operations from database", "error", err)
+		return nil, err
+	}
+
+	defer row.Close()
+	operations := make([]*data.WalletOperation, 0)
+	for row.Next() {
+		op := &data.WalletOperation{Amount: data.NewAmount(nil)}
+		err = row.Scan(&op.Hash, &op.Address, &op.Contract, &op.Function, op.Amount, &op.Timestamp, &op.Status)
+		if err != nil {
+			log.Warn("can not read wallet operation row", "error", err)
+			continue
+		}
+
+		operations = append(operations, op)
+	}
+
+	return operations, nil
+}
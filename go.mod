@@ -7,7 +7,10 @@ require (
 	github.com/ElrondNetwork/elrond-go-logger v1.0.4
 	github.com/ElrondNetwork/elrond-sdk/erdgo v0.0.0-20201221154408-19be3692ade5
 	github.com/go-telegram-bot-api/telegram-bot-api v4.6.4+incompatible
+	github.com/lib/pq v1.10.9
 	github.com/mattn/go-sqlite3 v1.14.6
 	github.com/technoweenie/multipartstreamer v1.0.1 // indirect
 	github.com/urfave/cli v1.22.5
+	golang.org/x/crypto v0.0.0-20201203163018-be400aefbc4c
+	golang.org/x/term v0.0.0-20201117132131-f5c789dd3221
 )
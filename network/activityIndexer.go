@@ -0,0 +1,229 @@
+package network
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/DrDelphi/ElrondDSSC/data"
+)
+
+// contract activity categories
+const (
+	ActivityDelegation = "delegation"
+	ActivityNodes      = "nodes"
+	ActivityAdmin      = "admin"
+	ActivityOther      = "other"
+)
+
+const (
+	activityPageSize       = 50
+	activityPagesPerSync   = 20
+	activitySyncInterval   = time.Minute
+	activityBackfillPause  = time.Second
+	activityTransferMethod = "transfer"
+)
+
+// activityCategories - the category of each function of the delegation contract
+var activityCategories = map[string]string{
+	FuncDelegate:                     ActivityDelegation,
+	FuncUnDelegate:                   ActivityDelegation,
+	FuncWithdraw:                     ActivityDelegation,
+	FuncClaimRewards:                 ActivityDelegation,
+	FuncReDelegateRewards:            ActivityDelegation,
+	"addNodes":                       ActivityNodes,
+	"removeNodes":                    ActivityNodes,
+	"stakeNodes":                     ActivityNodes,
+	"unStakeNodes":                   ActivityNodes,
+	"unBondNodes":                    ActivityNodes,
+	"unJailNodes":                    ActivityNodes,
+	"reStakeUnStakedNodes":           ActivityNodes,
+	"changeServiceFee":               ActivityAdmin,
+	"modifyTotalDelegationCap":       ActivityAdmin,
+	"setAutomaticActivation":         ActivityAdmin,
+	"setCheckCapOnReDelegateRewards": ActivityAdmin,
+	"setMetaData":                    ActivityAdmin,
+}
+
+// ContractActivityStorer - defines the persistence of the indexed contract transactions
+type ContractActivityStorer interface {
+	GetActivityCursor(contract string) (*data.ActivityCursor, error)
+	SaveContractActivity(activities []*data.ContractActivity, cursor *data.ActivityCursor) error
+	GetContractActivity(contract string, category string, limit int) ([]*data.ContractActivity, error)
+}
+
+// ActivityIndexer - indexes in the background all the transactions sent to the contract
+type ActivityIndexer struct {
+	lister    ContractTransactionLister
+	storer    ContractActivityStorer
+	contracts ContractAddressProvider
+
+	mut         sync.Mutex
+	startedOnce sync.Once
+}
+
+// NewActivityIndexer - creates a new ActivityIndexer object
+func NewActivityIndexer(lister ContractTransactionLister, storer ContractActivityStorer, contracts ContractAddressProvider) *ActivityIndexer {
+	return &ActivityIndexer{
+		lister:    lister,
+		storer:    storer,
+		contracts: contracts,
+	}
+}
+
+// Start - syncs the index every minute. A backfill of a contract with many transactions goes on
+// with a short pause between batches, and resumes from the saved cursor after a restart
+func (ai *ActivityIndexer) Start() {
+	ai.startedOnce.Do(func() {
+		go func() {
+			for {
+				_, done, err := ai.Sync()
+				if err != nil {
+					log.Warn("can not index contract activity", "error", err)
+				}
+				if done || err != nil {
+					time.Sleep(activitySyncInterval)
+				} else {
+					time.Sleep(activityBackfillPause)
+				}
+			}
+		}()
+	})
+}
+
+// Sync - pages through the contract's transactions newer than the cursor, newest first, saving each page
+// together with the advanced cursor. It returns the number of saved transactions and true when the index
+// caught up with the network. Transactions without a final status are left for a later sync
+func (ai *ActivityIndexer) Sync() (int, bool, error) {
+	ai.mut.Lock()
+	defer ai.mut.Unlock()
+
+	contract := ai.contracts.ContractAddress()
+	if contract == "" {
+		return 0, true, nil
+	}
+
+	cursor, err := ai.storer.GetActivityCursor(contract)
+	if err != nil {
+		return 0, false, err
+	}
+	if !cursor.Walking {
+		cursor.Walking = true
+		cursor.WalkTop = 0
+		cursor.WalkBefore = 0
+		cursor.WalkSkip = 0
+	}
+
+	saved := 0
+	for page := 0; page < activityPagesPerSync && cursor.Walking; page++ {
+		txs, err := ai.lister.GetContractTransactions(contract, cursor.Synced, cursor.WalkBefore, cursor.WalkSkip, activityPageSize)
+		if err != nil {
+			return saved, false, err
+		}
+
+		activities := make([]*data.ContractActivity, 0, len(txs))
+		if cursor.WalkBefore == 0 && cursor.WalkSkip == 0 && len(txs) > 0 {
+			cursor.WalkTop = txs[0].Timestamp
+		}
+		for _, tx := range txs {
+			activity := DecodeContractActivity(tx, contract)
+			if activity.Status == txStatusPending {
+				if tx.Timestamp-1 < cursor.WalkTop {
+					cursor.WalkTop = tx.Timestamp - 1
+				}
+				continue
+			}
+			activities = append(activities, activity)
+		}
+
+		advanceActivityCursor(cursor, txs)
+		if len(txs) < activityPageSize {
+			cursor.Walking = false
+			if cursor.WalkTop > cursor.Synced {
+				cursor.Synced = cursor.WalkTop
+			}
+		}
+
+		err = ai.storer.SaveContractActivity(activities, cursor)
+		if err != nil {
+			return saved, false, err
+		}
+		saved += len(activities)
+	}
+
+	if saved > 0 {
+		log.Debug("contract activity indexed", "contract", contract, "new transactions", saved,
+			"caught up", !cursor.Walking)
+	}
+
+	return saved, !cursor.Walking, nil
+}
+
+// GetActivity - returns the newest indexed transactions of the current contract in a category, or in
+// all the categories if category is empty
+func (ai *ActivityIndexer) GetActivity(category string, limit int) ([]*data.ContractActivity, error) {
+	return ai.storer.GetContractActivity(ai.contracts.ContractAddress(), category, limit)
+}
+
+// advanceActivityCursor - moves the walk past a page of transactions, sorted newest first. As the API's
+// before filter is inclusive, the transactions already seen at the oldest timestamp are skipped
+func advanceActivityCursor(cursor *data.ActivityCursor, txs []*data.APITransaction) {
+	if len(txs) == 0 {
+		return
+	}
+
+	oldest := txs[len(txs)-1].Timestamp
+	if oldest == cursor.WalkBefore {
+		cursor.WalkSkip += len(txs)
+		return
+	}
+
+	cursor.WalkBefore = oldest
+	cursor.WalkSkip = 0
+	for _, tx := range txs {
+		if tx.Timestamp == oldest {
+			cursor.WalkSkip++
+		}
+	}
+}
+
+// DecodeContractActivity - decodes the function and arguments of a transaction sent to the contract.
+// The delegation operations get their amount as decoded by DecodeWalletOperation, all other calls the
+// transaction's value
+func DecodeContractActivity(tx *data.APITransaction, contract string) *data.ContractActivity {
+	parts := strings.Split(string(tx.Data), "@")
+	function := parts[0]
+	if function == "" {
+		function = activityTransferMethod
+	}
+
+	category, ok := activityCategories[function]
+	if !ok {
+		category = ActivityOther
+	}
+
+	value, err := data.ParseAttoAmount(tx.Value)
+	if err != nil {
+		value = data.NewAmount(nil)
+	}
+
+	activity := &data.ContractActivity{
+		Hash:      tx.Hash,
+		Contract:  contract,
+		Sender:    tx.Sender,
+		Function:  function,
+		Category:  category,
+		Args:      parts[1:],
+		Value:     value,
+		Amount:    value,
+		Timestamp: tx.Timestamp,
+		Status:    apiTxStatus(tx),
+	}
+
+	operation := DecodeWalletOperation(tx, contract)
+	if operation != nil {
+		activity.Amount = operation.Amount
+	}
+
+	return activity
+}
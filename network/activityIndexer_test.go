@@ -0,0 +1,337 @@
+package network
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/DrDelphi/ElrondDSSC/data"
+)
+
+const (
+	activityContract = "erd1qqqqqqqqqqqqqqqpqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqq"
+	activitySender   = "erd1sender"
+)
+
+var errListerDown = errors.New("api down")
+
+// staticContract - always returns the same contract address
+type staticContract string
+
+func (c staticContract) ContractAddress() string {
+	return string(c)
+}
+
+// pagedLister - pages through transactions sorted newest first, with the API's inclusive time range.
+// It fails once failAfter pages were returned, if failAfter is positive
+type pagedLister struct {
+	mut       sync.Mutex
+	txs       []*data.APITransaction
+	calls     int
+	failAfter int
+}
+
+func (l *pagedLister) GetContractTransactions(contract string, after int64, before int64, from int, size int) ([]*data.APITransaction, error) {
+	l.mut.Lock()
+	defer l.mut.Unlock()
+
+	if l.failAfter > 0 && l.calls >= l.failAfter {
+		return nil, errListerDown
+	}
+	l.calls++
+
+	list := make([]*data.APITransaction, 0)
+	for _, tx := range l.txs {
+		if tx.Receiver != contract || tx.Timestamp < after || (before > 0 && tx.Timestamp > before) {
+			continue
+		}
+		list = append(list, tx)
+	}
+	if from >= len(list) {
+		return make([]*data.APITransaction, 0), nil
+	}
+	list = list[from:]
+	if size < len(list) {
+		list = list[:size]
+	}
+
+	return list, nil
+}
+
+// memoryActivity - keeps the indexed activity by hash and counts how many times each was saved
+type memoryActivity struct {
+	cursor     *data.ActivityCursor
+	activities map[string]*data.ContractActivity
+	saves      map[string]int
+}
+
+func newMemoryActivity() *memoryActivity {
+	return &memoryActivity{
+		activities: make(map[string]*data.ContractActivity),
+		saves:      make(map[string]int),
+	}
+}
+
+func (m *memoryActivity) GetActivityCursor(contract string) (*data.ActivityCursor, error) {
+	if m.cursor == nil {
+		return &data.ActivityCursor{Contract: contract}, nil
+	}
+	cursor := *m.cursor
+
+	return &cursor, nil
+}
+
+func (m *memoryActivity) SaveContractActivity(activities []*data.ContractActivity, cursor *data.ActivityCursor) error {
+	for _, a := range activities {
+		m.activities[a.Hash] = a
+		m.saves[a.Hash]++
+	}
+	saved := *cursor
+	m.cursor = &saved
+
+	return nil
+}
+
+func (m *memoryActivity) GetContractActivity(_ string, _ string, _ int) ([]*data.ContractActivity, error) {
+	return nil, nil
+}
+
+// activityTxs - returns transactions sent to the contract, newest first, with the given timestamps
+func activityTxs(prefix string, timestamps ...int64) []*data.APITransaction {
+	txs := make([]*data.APITransaction, 0, len(timestamps))
+	for i, timestamp := range timestamps {
+		txs = append(txs, &data.APITransaction{
+			Hash:      fmt.Sprintf("%s%04d", prefix, i),
+			Sender:    activitySender,
+			Receiver:  activityContract,
+			Value:     "1000000000000000000",
+			Data:      []byte(FuncDelegate),
+			Timestamp: timestamp,
+			Status:    TxStatusSuccess,
+		})
+	}
+
+	return txs
+}
+
+// descending - returns count timestamps going down from top, repeating each one as many times as given
+// in runs, or once
+func descending(top int64, count int, runs map[int64]int) []int64 {
+	timestamps := make([]int64, 0, count)
+	for timestamp := top; len(timestamps) < count; timestamp-- {
+		repeat := runs[timestamp]
+		if repeat == 0 {
+			repeat = 1
+		}
+		for i := 0; i < repeat && len(timestamps) < count; i++ {
+			timestamps = append(timestamps, timestamp)
+		}
+	}
+
+	return timestamps
+}
+
+// requireIndexed - checks every transaction, and nothing else, was indexed
+func requireIndexed(t *testing.T, storer *memoryActivity, txs []*data.APITransaction) {
+	t.Helper()
+
+	for _, tx := range txs {
+		if storer.activities[tx.Hash] == nil {
+			t.Fatalf("transaction %s at %d was not indexed", tx.Hash, tx.Timestamp)
+		}
+	}
+	if len(storer.activities) != len(txs) {
+		t.Fatalf("%d transactions indexed, want %d", len(storer.activities), len(txs))
+	}
+}
+
+// requireSavedOnce - checks no transaction was saved more than once
+func requireSavedOnce(t *testing.T, storer *memoryActivity) {
+	t.Helper()
+
+	for hash, saves := range storer.saves {
+		if saves != 1 {
+			t.Fatalf("transaction %s saved %d times", hash, saves)
+		}
+	}
+}
+
+func TestAdvanceActivityCursor(t *testing.T) {
+	tests := []struct {
+		name       string
+		before     int64
+		skip       int
+		timestamps []int64
+		wantBefore int64
+		wantSkip   int
+	}{
+		{"empty page", 500, 3, nil, 500, 3},
+		{"distinct timestamps", 0, 0, []int64{105, 104, 103}, 103, 1},
+		{"oldest timestamp repeated", 0, 0, []int64{105, 104, 104, 104}, 104, 3},
+		{"page older than before", 105, 2, []int64{105, 103, 102, 102}, 102, 2},
+		{"page at before only", 104, 3, []int64{104, 104}, 104, 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cursor := &data.ActivityCursor{WalkBefore: tt.before, WalkSkip: tt.skip}
+			advanceActivityCursor(cursor, activityTxs("tx", tt.timestamps...))
+			if cursor.WalkBefore != tt.wantBefore || cursor.WalkSkip != tt.wantSkip {
+				t.Fatalf("cursor before %d skip %d, want before %d skip %d", cursor.WalkBefore, cursor.WalkSkip,
+					tt.wantBefore, tt.wantSkip)
+			}
+		})
+	}
+}
+
+func TestActivityIndexerSameTimestampAcrossPages(t *testing.T) {
+	// a run straddling the first page boundary, and one longer than a whole page
+	timestamps := descending(10000, 3*activityPageSize, map[int64]int{
+		9960: 20,
+		9930: activityPageSize + 10,
+	})
+	lister := &pagedLister{txs: activityTxs("tx", timestamps...)}
+	storer := newMemoryActivity()
+	indexer := NewActivityIndexer(lister, storer, staticContract(activityContract))
+
+	saved, done, err := indexer.Sync()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !done || saved != len(timestamps) {
+		t.Fatalf("Sync() = %d, %v, want %d, true", saved, done, len(timestamps))
+	}
+	requireIndexed(t, storer, lister.txs)
+	requireSavedOnce(t, storer)
+	if storer.cursor.Walking || storer.cursor.Synced != 10000 {
+		t.Fatalf("cursor %+v, want synced up to 10000", storer.cursor)
+	}
+}
+
+func TestActivityIndexerRestartMidWalk(t *testing.T) {
+	timestamps := descending(10000, 4*activityPageSize, map[int64]int{9940: 30})
+	txs := activityTxs("tx", timestamps...)
+	storer := newMemoryActivity()
+
+	failing := &pagedLister{txs: txs, failAfter: 2}
+	saved, done, err := NewActivityIndexer(failing, storer, staticContract(activityContract)).Sync()
+	if err != errListerDown {
+		t.Fatalf("Sync() error = %v, want %v", err, errListerDown)
+	}
+	if done || saved != 2*activityPageSize || !storer.cursor.Walking {
+		t.Fatalf("Sync() = %d, %v, cursor %+v, want two pages saved mid-walk", saved, done, storer.cursor)
+	}
+
+	lister := &pagedLister{txs: txs}
+	saved, done, err = NewActivityIndexer(lister, storer, staticContract(activityContract)).Sync()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !done || saved != len(txs)-2*activityPageSize {
+		t.Fatalf("Sync() = %d, %v, want %d, true", saved, done, len(txs)-2*activityPageSize)
+	}
+	requireIndexed(t, storer, txs)
+	requireSavedOnce(t, storer)
+}
+
+func TestActivityIndexerResumesLongBackfill(t *testing.T) {
+	count := activityPagesPerSync*activityPageSize + 30
+	lister := &pagedLister{txs: activityTxs("tx", descending(100000, count, nil)...)}
+	storer := newMemoryActivity()
+	indexer := NewActivityIndexer(lister, storer, staticContract(activityContract))
+
+	saved, done, err := indexer.Sync()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if done || saved != activityPagesPerSync*activityPageSize {
+		t.Fatalf("Sync() = %d, %v, want %d, false", saved, done, activityPagesPerSync*activityPageSize)
+	}
+
+	saved, done, err = indexer.Sync()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !done || saved != 30 {
+		t.Fatalf("Sync() = %d, %v, want 30, true", saved, done)
+	}
+	requireIndexed(t, storer, lister.txs)
+	requireSavedOnce(t, storer)
+}
+
+func TestActivityIndexerPendingPickedUpLater(t *testing.T) {
+	txs := activityTxs("tx", descending(10000, 2*activityPageSize, nil)...)
+	pending := txs[70]
+	pending.Status = txStatusPending
+	lister := &pagedLister{txs: txs}
+	storer := newMemoryActivity()
+	indexer := NewActivityIndexer(lister, storer, staticContract(activityContract))
+
+	saved, done, err := indexer.Sync()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !done || saved != len(txs)-1 {
+		t.Fatalf("Sync() = %d, %v, want %d, true", saved, done, len(txs)-1)
+	}
+	if storer.activities[pending.Hash] != nil {
+		t.Fatalf("the pending transaction was indexed")
+	}
+	if storer.cursor.Synced >= pending.Timestamp {
+		t.Fatalf("synced up to %d, past the pending transaction at %d", storer.cursor.Synced, pending.Timestamp)
+	}
+
+	pending.Status = TxStatusSuccess
+	newer := activityTxs("new", 10002, 10001)
+	lister.txs = append(newer, txs...)
+
+	_, done, err = indexer.Sync()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !done {
+		t.Fatalf("Sync() did not catch up")
+	}
+	requireIndexed(t, storer, lister.txs)
+	if storer.activities[pending.Hash].Status != TxStatusSuccess {
+		t.Fatalf("the pending transaction indexed as %s", storer.activities[pending.Hash].Status)
+	}
+	if storer.cursor.Synced != 10002 {
+		t.Fatalf("synced up to %d, want 10002", storer.cursor.Synced)
+	}
+}
+
+func TestActivityIndexerCatchesUp(t *testing.T) {
+	txs := activityTxs("tx", descending(10000, activityPageSize+5, map[int64]int{10000: 3})...)
+	lister := &pagedLister{txs: txs}
+	storer := newMemoryActivity()
+	indexer := NewActivityIndexer(lister, storer, staticContract(activityContract))
+
+	_, done, err := indexer.Sync()
+	if err != nil || !done {
+		t.Fatalf("Sync() = %v, %v, want caught up", done, err)
+	}
+
+	// a transaction arriving in the same second as the newest indexed ones, then later ones
+	newer := append(activityTxs("new", 10003, 10001, 10001), activityTxs("late", 10000)...)
+	lister.txs = append(newer, txs...)
+	saved, done, err := indexer.Sync()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !done || saved != len(newer)+3 {
+		t.Fatalf("Sync() = %d, %v, want %d, true", saved, done, len(newer)+3)
+	}
+	requireIndexed(t, storer, lister.txs)
+
+	// only the transaction at the synced second is read again
+	saved, done, err = indexer.Sync()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !done || saved != 1 {
+		t.Fatalf("Sync() = %d, %v, want 1, true", saved, done)
+	}
+	requireIndexed(t, storer, lister.txs)
+}
@@ -0,0 +1,161 @@
+package network
+
+import (
+	"errors"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/DrDelphi/ElrondDSSC/data"
+)
+
+// staking economics of the Elrond mainnet
+const (
+	nodeStakeEGLD          = 2500
+	topUpFactor            = 0.25
+	topUpGradientPointEGLD = 3000000
+)
+
+const (
+	daysPerYear          = 365
+	defaultEpochDuration = time.Hour * 24
+	aprCacheDuration     = time.Minute * 10
+	nodeStateStaked      = "staked"
+)
+
+// ComputeAPR - estimates the gross and net APR of a contract from the network economics. The rewards
+// minted in an epoch are split between the base stake of all the nodes and the top-up (the stake above
+// the node price). The top-up share grows with the network's total top-up towards topUpFactor:
+//
+//	topUpRewards = 2 * topUpFactor * inflation / pi * atan(totalTopUp / topUpGradientPoint)
+//
+// The contract receives the base rewards in proportion with its staked nodes and the top-up rewards in
+// proportion with its stake above the nodes' price
+func ComputeAPR(metrics *data.EconomicsMetrics, stakedNodes uint64, totalActiveStake *data.Amount,
+	serviceFee float64, epochsPerYear float64) (*data.APREstimate, error) {
+	estimate := &data.APREstimate{
+		ServiceFee:       serviceFee,
+		StakedNodes:      stakedNodes,
+		TotalActiveStake: totalActiveStake,
+		Epoch:            metrics.EpochForEconomicsData,
+	}
+
+	activeStake := totalActiveStake.Float64()
+	if stakedNodes == 0 || activeStake == 0 {
+		return estimate, nil
+	}
+
+	inflation := metrics.Inflation.Float64()
+	networkTopUp := metrics.TotalTopUpValue.Float64()
+	networkBaseStake := metrics.TotalStakedValue.Float64() - networkTopUp
+	if inflation <= 0 || networkBaseStake <= 0 || epochsPerYear <= 0 {
+		return nil, errors.New("economics data not available")
+	}
+
+	topUpRewards := 2 * topUpFactor * inflation / math.Pi * math.Atan(networkTopUp/topUpGradientPointEGLD)
+	baseRewards := inflation - topUpRewards
+
+	baseStake := math.Min(float64(stakedNodes)*nodeStakeEGLD, activeStake)
+	topUp := activeStake - baseStake
+
+	epochRewards := baseRewards * baseStake / networkBaseStake
+	if networkTopUp > 0 {
+		epochRewards += topUpRewards * topUp / networkTopUp
+	}
+
+	estimate.GrossAPR = epochRewards * epochsPerYear / activeStake * 100
+	estimate.NetAPR = estimate.GrossAPR * (100 - serviceFee) / 100
+
+	return estimate, nil
+}
+
+// ProjectRewards - returns the rewards expected for an amount delegated for a number of days at the given
+// APR. With compound the rewards are considered redelegated daily
+func ProjectRewards(amount *data.Amount, apr float64, days uint64, compound bool) *data.Amount {
+	value := amount.Float64()
+	if compound {
+		return data.AmountFromFloat64(value * (math.Pow(1+apr/100/daysPerYear, float64(days)) - 1))
+	}
+
+	return data.AmountFromFloat64(value * apr / 100 * float64(days) / daysPerYear)
+}
+
+// APREstimator - estimates the APR of the delegation contract, caching the result for a few minutes
+type APREstimator struct {
+	economics EconomicsReader
+	contract  DelegationContract
+	contracts ContractAddressProvider
+	clock     *NetworkClock
+
+	estimate    *data.APREstimate
+	estimatedAt time.Time
+	mut         sync.Mutex
+}
+
+// NewAPREstimator - creates a new APREstimator object
+func NewAPREstimator(economics EconomicsReader, contract DelegationContract, contracts ContractAddressProvider,
+	clock *NetworkClock) *APREstimator {
+	return &APREstimator{
+		economics: economics,
+		contract:  contract,
+		contracts: contracts,
+		clock:     clock,
+	}
+}
+
+// Estimate - returns the current APR estimation of the delegation contract
+func (ae *APREstimator) Estimate() (*data.APREstimate, error) {
+	ae.mut.Lock()
+	defer ae.mut.Unlock()
+
+	if ae.estimate != nil && time.Since(ae.estimatedAt) < aprCacheDuration {
+		return ae.estimate, nil
+	}
+
+	contractAddress := ae.contracts.ContractAddress()
+	if contractAddress == "" {
+		return nil, errors.New("contract address not found")
+	}
+
+	metrics, err := ae.economics.GetNetworkEconomics()
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := ae.contract.GetContractInfo(contractAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	totalActiveStake, err := ae.contract.GetTotalActiveStake()
+	if err != nil {
+		return nil, err
+	}
+
+	nodes, err := ae.contract.GetAllNodeStates()
+	if err != nil {
+		return nil, err
+	}
+	stakedNodes := uint64(0)
+	for _, node := range nodes {
+		if node.DSSCState == nodeStateStaked {
+			stakedNodes++
+		}
+	}
+
+	epochDuration := ae.clock.EpochsDuration(1)
+	if epochDuration == 0 {
+		epochDuration = defaultEpochDuration
+	}
+	epochsPerYear := float64(daysPerYear*24*time.Hour) / float64(epochDuration)
+
+	estimate, err := ComputeAPR(metrics, stakedNodes, totalActiveStake, info.ServiceFee, epochsPerYear)
+	if err != nil {
+		return nil, err
+	}
+
+	ae.estimate = estimate
+	ae.estimatedAt = time.Now()
+
+	return estimate, nil
+}
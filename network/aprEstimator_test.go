@@ -0,0 +1,70 @@
+package network_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/DrDelphi/ElrondDSSC/data"
+	"github.com/DrDelphi/ElrondDSSC/network"
+	"github.com/DrDelphi/ElrondDSSC/network/networktest"
+)
+
+func TestComputeAPR(t *testing.T) {
+	for _, fixture := range networktest.EconomicsFixtures() {
+		fixture := fixture
+		t.Run(fixture.Name, func(t *testing.T) {
+			estimate, err := network.ComputeAPR(fixture.Metrics, fixture.StakedNodes, fixture.TotalActiveStake,
+				fixture.ServiceFee, fixture.EpochsPerYear)
+			if err != nil {
+				t.Fatalf("ComputeAPR: %v", err)
+			}
+
+			if math.Abs(estimate.GrossAPR-fixture.GrossAPR) > networktest.APRTolerance {
+				t.Errorf("gross APR = %f, want %f", estimate.GrossAPR, fixture.GrossAPR)
+			}
+			if math.Abs(estimate.NetAPR-fixture.NetAPR) > networktest.APRTolerance {
+				t.Errorf("net APR = %f, want %f", estimate.NetAPR, fixture.NetAPR)
+			}
+			if estimate.StakedNodes != fixture.StakedNodes || estimate.ServiceFee != fixture.ServiceFee {
+				t.Errorf("estimate does not echo the contract values: %+v", estimate)
+			}
+		})
+	}
+}
+
+func TestComputeAPRWithoutEconomics(t *testing.T) {
+	metrics := networktest.NewEconomicsMetrics("0", "12000000", "4500000")
+	_, err := network.ComputeAPR(metrics, 10, data.MustParseAmount("40000"), 10, 365)
+	if err == nil {
+		t.Fatal("expected an error for missing economics data")
+	}
+}
+
+func TestProjectRewards(t *testing.T) {
+	amount := data.MustParseAmount("1000")
+	for _, fixture := range networktest.EconomicsFixtures() {
+		fixture := fixture
+		t.Run(fixture.Name, func(t *testing.T) {
+			estimate, err := network.ComputeAPR(fixture.Metrics, fixture.StakedNodes, fixture.TotalActiveStake,
+				fixture.ServiceFee, fixture.EpochsPerYear)
+			if err != nil {
+				t.Fatalf("ComputeAPR: %v", err)
+			}
+
+			// a year of simple rewards is the APR itself
+			simple := network.ProjectRewards(amount, estimate.NetAPR, 365, false).Float64()
+			if want := 1000 * fixture.NetAPR / 100; math.Abs(simple-want) > 1000*networktest.APRTolerance/100 {
+				t.Errorf("simple yearly rewards = %f, want %f", simple, want)
+			}
+
+			compound := network.ProjectRewards(amount, estimate.NetAPR, 365, true).Float64()
+			want := 1000 * (math.Pow(1+fixture.NetAPR/100/365, 365) - 1)
+			if math.Abs(compound-want) > 1000*networktest.APRTolerance/100 {
+				t.Errorf("compound yearly rewards = %f, want %f", compound, want)
+			}
+			if compound < simple {
+				t.Errorf("compound rewards %f lower than simple ones %f", compound, simple)
+			}
+		})
+	}
+}
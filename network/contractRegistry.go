@@ -0,0 +1,189 @@
+package network
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/ElrondNetwork/elrond-sdk/erdgo"
+)
+
+const contractRegistryRefreshInterval = time.Second * 30
+
+// ContractAddressStorer - defines the persistence of the resolved contract address
+type ContractAddressStorer interface {
+	GetOwnerAddress() string
+	GetContractAddress() (address string, owner string)
+	SetContractAddress(address string, owner string) error
+}
+
+// ContractRegistry - resolves the owner's delegation contract through the delegation manager,
+// keeps it in the database and notifies its subscribers whenever it changes
+type ContractRegistry struct {
+	resolver ContractResolver
+	storer   ContractAddressStorer
+
+	address     string
+	owner       string
+	owners      map[string]string // contract -> owner, for the contracts already checked
+	handlers    []func(address string)
+	mut         sync.RWMutex
+	resolveMut  sync.Mutex
+	startedOnce sync.Once
+}
+
+// NewContractRegistry - creates a new ContractRegistry object, starting from the persisted address
+func NewContractRegistry(resolver ContractResolver, storer ContractAddressStorer) *ContractRegistry {
+	address, owner := storer.GetContractAddress()
+
+	return &ContractRegistry{
+		resolver: resolver,
+		storer:   storer,
+		address:  address,
+		owner:    owner,
+		owners:   make(map[string]string),
+		handlers: make([]func(address string), 0),
+	}
+}
+
+// ContractAddress - returns the current contract address or an empty string if not known yet
+func (cr *ContractRegistry) ContractAddress() string {
+	cr.mut.RLock()
+	defer cr.mut.RUnlock()
+
+	return cr.address
+}
+
+// OnChange - registers a handler called with the new address every time the contract address changes
+func (cr *ContractRegistry) OnChange(handler func(address string)) {
+	cr.mut.Lock()
+	cr.handlers = append(cr.handlers, handler)
+	cr.mut.Unlock()
+}
+
+// SetContractAddress - sets the contract address manually, on behalf of the current owner
+func (cr *ContractRegistry) SetContractAddress(address string) error {
+	if address != "" && !erdgo.IsValidBech32Address(address) {
+		return errors.New("invalid address")
+	}
+
+	return cr.set(address, cr.storer.GetOwnerAddress())
+}
+
+// Resolve - looks up the delegation contract owned by the current owner among all the
+// contracts known by the delegation manager. The owners of the contracts are cached, so only the
+// contracts not checked before are queried
+func (cr *ContractRegistry) Resolve() (string, error) {
+	cr.resolveMut.Lock()
+	defer cr.resolveMut.Unlock()
+
+	owner := cr.storer.GetOwnerAddress()
+	if owner == "" {
+		return "", errors.New("owner address not set")
+	}
+
+	contracts, err := cr.resolver.GetAllContractAddresses()
+	if err != nil {
+		log.Error("can not get delegation contracts", "error", err)
+		return "", err
+	}
+
+	// newest contracts are last, so start with them
+	address := ""
+	for i := len(contracts) - 1; i >= 0; i-- {
+		contractOwner, err := cr.contractOwner(contracts[i])
+		if err != nil {
+			log.Warn("can not get delegation contract owner", "contract", contracts[i], "error", err)
+			continue
+		}
+
+		if contractOwner == owner {
+			address = contracts[i]
+			break
+		}
+	}
+
+	err = cr.set(address, owner)
+	if err != nil {
+		return "", err
+	}
+
+	return address, nil
+}
+
+// contractOwner - returns the cached owner of a contract, querying it only the first time
+func (cr *ContractRegistry) contractOwner(contract string) (string, error) {
+	if owner, ok := cr.owners[contract]; ok {
+		return owner, nil
+	}
+
+	owner, err := cr.resolver.GetContractOwner(contract)
+	if err != nil {
+		return "", err
+	}
+	cr.owners[contract] = owner
+
+	return owner, nil
+}
+
+// Start - periodically resolves the contract address while it is not known or the owner has changed
+func (cr *ContractRegistry) Start() {
+	cr.startedOnce.Do(func() {
+		go func() {
+			for {
+				cr.refresh()
+				time.Sleep(contractRegistryRefreshInterval)
+			}
+		}()
+	})
+}
+
+func (cr *ContractRegistry) refresh() {
+	owner := cr.storer.GetOwnerAddress()
+	if owner == "" {
+		return
+	}
+
+	cr.mut.RLock()
+	upToDate := cr.address != "" && cr.owner == owner
+	cr.mut.RUnlock()
+	if upToDate {
+		return
+	}
+
+	address, err := cr.Resolve()
+	if err == nil && address != "" {
+		log.Info("delegation contract resolved", "address", address, "owner", owner)
+	}
+}
+
+func (cr *ContractRegistry) set(address string, owner string) error {
+	cr.mut.Lock()
+	if cr.address == address && cr.owner == owner {
+		cr.mut.Unlock()
+		return nil
+	}
+
+	err := cr.storer.SetContractAddress(address, owner)
+	if err != nil {
+		cr.mut.Unlock()
+		return err
+	}
+
+	changed := cr.address != address
+	cr.address = address
+	cr.owner = owner
+	handlers := make([]func(address string), len(cr.handlers))
+	copy(handlers, cr.handlers)
+	cr.mut.Unlock()
+
+	if changed {
+		for _, handler := range handlers {
+			handler(address)
+		}
+	}
+
+	return nil
+}
+
+var _ ContractAddressProvider = (*ContractRegistry)(nil)
@@ -0,0 +1,137 @@
+package network_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/DrDelphi/ElrondDSSC/network"
+	"github.com/DrDelphi/ElrondDSSC/network/networktest"
+)
+
+const (
+	testRegistryOwner = "erd1qyu5wthldzr8wx5c9ucg8kjagg0jfs53s8nr3zpz3hypefsdd8ssycr6th"
+	testOtherOwner    = "erd1spyavw0956vq68xj8y4tenjpq2wd5a9p2c6j8gsz7ztyrnpxrruqzu66jx"
+	testContractA     = "erd1qqqqqqqqqqqqqqqpqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqhllllsajxzat"
+	testContractB     = "erd1qqqqqqqqqqqqqqqpqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqrlllls8a5w6u"
+	testContractC     = "erd1qqqqqqqqqqqqqqqpqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqzlllllsmk3x3n"
+)
+
+// countingResolver - counts the owner queries made through a fake delegation manager
+type countingResolver struct {
+	*networktest.Fake
+	mut     sync.Mutex
+	queried map[string]int
+}
+
+func (cr *countingResolver) GetContractOwner(contract string) (string, error) {
+	cr.mut.Lock()
+	cr.queried[contract]++
+	cr.mut.Unlock()
+
+	return cr.Fake.GetContractOwner(contract)
+}
+
+func (cr *countingResolver) queries() int {
+	cr.mut.Lock()
+	defer cr.mut.Unlock()
+
+	total := 0
+	for _, n := range cr.queried {
+		total += n
+	}
+
+	return total
+}
+
+// memoryStorer - keeps the owner and the contract address in memory
+type memoryStorer struct {
+	owner         string
+	contract      string
+	contractOwner string
+}
+
+func (ms *memoryStorer) GetOwnerAddress() string {
+	return ms.owner
+}
+
+func (ms *memoryStorer) GetContractAddress() (string, string) {
+	return ms.contract, ms.contractOwner
+}
+
+func (ms *memoryStorer) SetContractAddress(address string, owner string) error {
+	ms.contract = address
+	ms.contractOwner = owner
+
+	return nil
+}
+
+func TestContractRegistryResolveCachesOwners(t *testing.T) {
+	resolver := &countingResolver{Fake: networktest.NewFake(), queried: make(map[string]int)}
+	resolver.AddContract(testContractA, testOtherOwner)
+	resolver.AddContract(testContractB, testOtherOwner)
+	registry := network.NewContractRegistry(resolver, &memoryStorer{owner: testRegistryOwner})
+
+	address, err := registry.Resolve()
+	if err != nil || address != "" {
+		t.Fatalf("Resolve = %q, %v, want no contract", address, err)
+	}
+	if got := resolver.queries(); got != 2 {
+		t.Fatalf("expected 2 owner queries, got %v", got)
+	}
+
+	address, err = registry.Resolve()
+	if err != nil || address != "" {
+		t.Fatalf("Resolve = %q, %v, want no contract", address, err)
+	}
+	if got := resolver.queries(); got != 2 {
+		t.Fatalf("checked contracts were queried again: %v queries", got)
+	}
+
+	resolver.AddContract(testContractC, testRegistryOwner)
+	address, err = registry.Resolve()
+	if err != nil || address != testContractC {
+		t.Fatalf("Resolve = %q, %v, want %v", address, err, testContractC)
+	}
+	if got := resolver.queries(); got != 3 || resolver.queried[testContractC] != 1 {
+		t.Fatalf("expected only the new contract to be queried, got %v", resolver.queried)
+	}
+	if registry.ContractAddress() != testContractC {
+		t.Errorf("ContractAddress = %q, want %v", registry.ContractAddress(), testContractC)
+	}
+}
+
+func TestContractRegistryResolveRetriesFailedOwners(t *testing.T) {
+	resolver := &countingResolver{Fake: networktest.NewFake(), queried: make(map[string]int)}
+	resolver.AddContract(testContractA, testRegistryOwner)
+	registry := network.NewContractRegistry(resolver, &memoryStorer{owner: testRegistryOwner})
+
+	resolver.SetError("GetContractOwner", errors.New("vm query failed"))
+	address, err := registry.Resolve()
+	if err != nil || address != "" {
+		t.Fatalf("Resolve = %q, %v, want no contract", address, err)
+	}
+
+	resolver.SetError("GetContractOwner", nil)
+	address, err = registry.Resolve()
+	if err != nil || address != testContractA {
+		t.Fatalf("Resolve = %q, %v, want %v", address, err, testContractA)
+	}
+	if resolver.queried[testContractA] != 2 {
+		t.Errorf("expected the failed query to be retried, got %v queries", resolver.queried[testContractA])
+	}
+}
+
+func TestContractRegistryResolveWithoutOwner(t *testing.T) {
+	resolver := &countingResolver{Fake: networktest.NewFake(), queried: make(map[string]int)}
+	resolver.AddContract(testContractA, testRegistryOwner)
+	registry := network.NewContractRegistry(resolver, &memoryStorer{})
+
+	_, err := registry.Resolve()
+	if err == nil {
+		t.Fatal("expected an error without an owner address")
+	}
+	if resolver.queries() != 0 {
+		t.Errorf("unexpected owner queries without an owner address")
+	}
+}
@@ -0,0 +1,144 @@
+package network
+
+import (
+	"math/big"
+
+	"github.com/DrDelphi/ElrondDSSC/data"
+)
+
+// DelegationManagerAddress - address of the delegation manager system smart contract
+const DelegationManagerAddress = data.DelegationManagerAddress
+
+// ValidatorSCAddress - address of the validator system smart contract
+const ValidatorSCAddress = data.ValidatorSCAddress
+
+// EndOfEpochAddress - the system address allowed to query a DSSC's cumulated rewards
+const EndOfEpochAddress = data.EndOfEpochAddress
+
+// blsKeyLength - the length of a node's BLS public key
+const blsKeyLength = 96
+
+// gas limits of the delegation system smart contract calls
+const (
+	gasLimitCreateContract = 60000000
+	gasLimitDelegation     = 12000000
+	gasLimitClaim          = 6000000
+	gasLimitNodes          = 12000000
+	gasLimitReStakeNodes   = 120000000
+	gasLimitAddNodes       = 6000000
+	gasLimitAdmin          = 6000000
+)
+
+var (
+	// CreationCost - the value required for creating a new delegation contract
+	CreationCost = data.MustParseAmount("1250")
+	// UnJailCost - the value required for unjailing one node
+	UnJailCost = data.MustParseAmount("2.5")
+)
+
+func newDSSCCall(contract string, gasLimit uint64, function string, args ...[]byte) *TxRequest {
+	return &TxRequest{
+		Receiver: contract,
+		Value:    data.NewAmount(nil),
+		Function: function,
+		Args:     args,
+		GasLimit: gasLimit,
+	}
+}
+
+// CreateDelegationContractTx - creates a new delegation contract with the given cap (0 for unlimited) and service fee
+func CreateDelegationContractTx(maxCap *data.Amount, serviceFee uint64) *TxRequest {
+	tx := newDSSCCall(DelegationManagerAddress, gasLimitCreateContract, "createNewDelegationContract",
+		AmountArg(maxCap), Uint64Arg(serviceFee))
+	tx.Value = CreationCost
+
+	return tx
+}
+
+// DelegateTx - delegates amount to the contract
+func DelegateTx(contract string, amount *data.Amount) *TxRequest {
+	tx := newDSSCCall(contract, gasLimitDelegation, "delegate")
+	tx.Value = amount
+
+	return tx
+}
+
+// UnDelegateTx - undelegates amount from the contract
+func UnDelegateTx(contract string, amount *data.Amount) *TxRequest {
+	return newDSSCCall(contract, gasLimitDelegation, "unDelegate", AmountArg(amount))
+}
+
+// WithdrawTx - withdraws the unbonded funds
+func WithdrawTx(contract string) *TxRequest {
+	return newDSSCCall(contract, gasLimitDelegation, "withdraw")
+}
+
+// ClaimRewardsTx - claims the delegation rewards
+func ClaimRewardsTx(contract string) *TxRequest {
+	return newDSSCCall(contract, gasLimitClaim, "claimRewards")
+}
+
+// ReDelegateRewardsTx - delegates the rewards back to the contract
+func ReDelegateRewardsTx(contract string) *TxRequest {
+	return newDSSCCall(contract, gasLimitDelegation, "reDelegateRewards")
+}
+
+// SetAutomaticActivationTx - enables or disables the automatic activation of the nodes
+func SetAutomaticActivationTx(contract string, enabled bool) *TxRequest {
+	return newDSSCCall(contract, gasLimitAdmin, "setAutomaticActivation", BoolArg(enabled))
+}
+
+// ChangeServiceFeeTx - changes the service fee, given in hundredths of a percent (1000 = 10%)
+func ChangeServiceFeeTx(contract string, serviceFee uint64) *TxRequest {
+	return newDSSCCall(contract, gasLimitAdmin, "changeServiceFee", Uint64Arg(serviceFee))
+}
+
+// ModifyTotalDelegationCapTx - changes the total delegation cap
+func ModifyTotalDelegationCapTx(contract string, maxCap *data.Amount) *TxRequest {
+	return newDSSCCall(contract, gasLimitAdmin, "modifyTotalDelegationCap", AmountArg(maxCap))
+}
+
+// SetMetaDataTx - sets the staking provider name, website and keybase identity
+func SetMetaDataTx(contract string, metadata *data.ContractMetadata) *TxRequest {
+	return newDSSCCall(contract, gasLimitAdmin, "setMetaData",
+		[]byte(metadata.Name), []byte(metadata.Website), []byte(metadata.Identifier))
+}
+
+// AddNodesTx - adds a node to the contract, given its BLS key and the BLS signature of the contract address
+func AddNodesTx(contract string, blsKey []byte, signature []byte) *TxRequest {
+	return newDSSCCall(contract, gasLimitAddNodes, "addNodes", blsKey, signature)
+}
+
+// StakeNodesTx - stakes the given nodes
+func StakeNodesTx(contract string, blsKeys ...[]byte) *TxRequest {
+	return newDSSCCall(contract, gasLimitNodes, "stakeNodes", blsKeys...)
+}
+
+// UnStakeNodesTx - unstakes the given nodes
+func UnStakeNodesTx(contract string, blsKeys ...[]byte) *TxRequest {
+	return newDSSCCall(contract, gasLimitNodes, "unStakeNodes", blsKeys...)
+}
+
+// UnBondNodesTx - unbonds the given nodes
+func UnBondNodesTx(contract string, blsKeys ...[]byte) *TxRequest {
+	return newDSSCCall(contract, gasLimitNodes, "unBondNodes", blsKeys...)
+}
+
+// ReStakeUnStakedNodesTx - stakes again the given unstaked nodes
+func ReStakeUnStakedNodesTx(contract string, blsKeys ...[]byte) *TxRequest {
+	return newDSSCCall(contract, gasLimitReStakeNodes, "reStakeUnStakedNodes", blsKeys...)
+}
+
+// UnJailNodesTx - unjails the given nodes, paying the unjail cost for each of them
+func UnJailNodesTx(contract string, blsKeys ...[]byte) *TxRequest {
+	tx := newDSSCCall(contract, gasLimitNodes, "unJailNodes", blsKeys...)
+	value := big.NewInt(0).Mul(UnJailCost.Int(), big.NewInt(int64(len(blsKeys))))
+	tx.Value = data.NewAmount(value)
+
+	return tx
+}
+
+// RemoveNodesTx - removes the given nodes from the contract
+func RemoveNodesTx(contract string, blsKeys ...[]byte) *TxRequest {
+	return newDSSCCall(contract, gasLimitNodes, "removeNodes", blsKeys...)
+}
@@ -0,0 +1,207 @@
+package network
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/DrDelphi/ElrondDSSC/data"
+)
+
+// web wallet callback statuses. HookStatusUnverified replaces success when the transaction is not found
+// on the network or does not match the intent
+const (
+	HookStatusSuccess    = "success"
+	HookStatusCancelled  = "cancelled"
+	HookStatusUnverified = "unverified"
+)
+
+const (
+	hookIntentMaxAge       = time.Hour * 24
+	hookIntentsCleanPeriod = time.Hour
+	hookTxLookupAttempts   = 3
+	hookTxLookupDelay      = time.Second * 2
+	hookCallbackPage       = "<html><body><h3>%s</h3><p>You can now return to Telegram.</p></body></html>"
+)
+
+// HookCallbackServer - serves the endpoint the web wallet redirects to after a transaction prepared by
+// a HookLinkBuilder link was signed or cancelled, and hands the callbacks to its handlers. The reported
+// transaction is looked up on the network and checked against the intent, since anyone can call the endpoint
+type HookCallbackServer struct {
+	listenAddress string
+	storer        HookIntentStorer
+	transactions  TransactionReader
+
+	server   *http.Server
+	handlers []func(callback *data.HookCallback)
+	mut      sync.Mutex
+	stop     chan struct{}
+}
+
+// NewHookCallbackServer - creates a new HookCallbackServer object
+func NewHookCallbackServer(listenAddress string, storer HookIntentStorer, transactions TransactionReader) *HookCallbackServer {
+	return &HookCallbackServer{
+		listenAddress: listenAddress,
+		storer:        storer,
+		transactions:  transactions,
+		handlers:      make([]func(callback *data.HookCallback), 0),
+	}
+}
+
+// OnCallback - registers a handler called for every callback matching a saved intent
+func (hs *HookCallbackServer) OnCallback(handler func(callback *data.HookCallback)) {
+	hs.mut.Lock()
+	hs.handlers = append(hs.handlers, handler)
+	hs.mut.Unlock()
+}
+
+// Start - starts listening and removes the expired intents every hour
+func (hs *HookCallbackServer) Start() error {
+	listener, err := net.Listen("tcp", hs.listenAddress)
+	if err != nil {
+		log.Error("can not start the wallet callback server", "address", hs.listenAddress, "error", err)
+		return err
+	}
+
+	hs.mut.Lock()
+	hs.server = &http.Server{Handler: hs}
+	hs.stop = make(chan struct{})
+	hs.mut.Unlock()
+
+	go func() {
+		err := hs.server.Serve(listener)
+		if err != nil && err != http.ErrServerClosed {
+			log.Error("wallet callback server stopped", "error", err)
+		}
+	}()
+	go hs.removeExpiredIntents()
+	log.Info("wallet callback server started", "address", listener.Addr().String())
+
+	return nil
+}
+
+// Close - stops the server
+func (hs *HookCallbackServer) Close() error {
+	hs.mut.Lock()
+	defer hs.mut.Unlock()
+
+	if hs.server == nil {
+		return nil
+	}
+	close(hs.stop)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	return hs.server.Shutdown(ctx)
+}
+
+// ServeHTTP - handles GET <HookCallbackPath><token>?status=...&txHash=...
+func (hs *HookCallbackServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet || !strings.HasPrefix(r.URL.Path, HookCallbackPath) {
+		http.NotFound(w, r)
+		return
+	}
+
+	token := strings.TrimPrefix(r.URL.Path, HookCallbackPath)
+	intent, err := hs.storer.TakeHookIntent(token)
+	if err != nil {
+		writeHookPage(w, http.StatusInternalServerError, "Something went wrong")
+		return
+	}
+	if intent == nil {
+		writeHookPage(w, http.StatusNotFound, "Unknown or expired link")
+		return
+	}
+
+	callback := &data.HookCallback{
+		Intent: intent,
+		Status: r.URL.Query().Get("status"),
+		TxHash: r.URL.Query().Get("txHash"),
+	}
+	log.Debug("wallet callback received", "description", intent.Description, "status", callback.Status,
+		"hash", callback.TxHash)
+	if callback.Status == HookStatusSuccess {
+		callback.Sender, err = hs.checkTransaction(intent, callback.TxHash)
+		if err != nil {
+			log.Warn("wallet callback transaction rejected", "description", intent.Description,
+				"hash", callback.TxHash, "error", err)
+			callback.Status = HookStatusUnverified
+		}
+	}
+
+	hs.mut.Lock()
+	handlers := make([]func(callback *data.HookCallback), len(hs.handlers))
+	copy(handlers, hs.handlers)
+	hs.mut.Unlock()
+	for _, handler := range handlers {
+		handler(callback)
+	}
+
+	switch callback.Status {
+	case HookStatusSuccess:
+		writeHookPage(w, http.StatusOK, "Transaction sent")
+	case HookStatusUnverified:
+		writeHookPage(w, http.StatusOK, "Transaction not recognized")
+	default:
+		writeHookPage(w, http.StatusOK, "Transaction not sent")
+	}
+}
+
+// checkTransaction - looks up the reported transaction, waiting for the network to see it, and returns its
+// sender if its receiver, value and data are the intent's ones and, if the intent has one, so is its sender
+func (hs *HookCallbackServer) checkTransaction(intent *data.HookIntent, hash string) (string, error) {
+	if intent.Receiver == "" {
+		return "", errors.New("the intent has no transaction")
+	}
+
+	var tx *data.TransactionOnNetwork
+	var err error
+	for attempt := 1; attempt <= hookTxLookupAttempts; attempt++ {
+		tx, err = hs.transactions.GetTransaction(hash)
+		if err == nil {
+			break
+		}
+		if attempt < hookTxLookupAttempts {
+			time.Sleep(hookTxLookupDelay)
+		}
+	}
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case tx.Receiver != intent.Receiver:
+		return "", fmt.Errorf("receiver %s differs from %s", tx.Receiver, intent.Receiver)
+	case tx.Value != intent.Value:
+		return "", fmt.Errorf("value %s differs from %s", tx.Value, intent.Value)
+	case string(tx.Data) != intent.Data:
+		return "", errors.New("data differs from the intent's one")
+	case intent.Sender != "" && tx.Sender != intent.Sender:
+		return "", fmt.Errorf("sender %s differs from %s", tx.Sender, intent.Sender)
+	}
+
+	return tx.Sender, nil
+}
+
+func (hs *HookCallbackServer) removeExpiredIntents() {
+	for {
+		_ = hs.storer.RemoveHookIntentsBefore(time.Now().Add(-hookIntentMaxAge).Unix())
+
+		select {
+		case <-hs.stop:
+			return
+		case <-time.After(hookIntentsCleanPeriod):
+		}
+	}
+}
+
+func writeHookPage(w http.ResponseWriter, status int, title string) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	_, _ = fmt.Fprintf(w, hookCallbackPage, title)
+}
@@ -0,0 +1,161 @@
+package network_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/DrDelphi/ElrondDSSC/data"
+	"github.com/DrDelphi/ElrondDSSC/network"
+	"github.com/DrDelphi/ElrondDSSC/network/networktest"
+)
+
+const testTxHash = "5f1c6a7e44b1e1c3b1c0a1f8de0e4f2a6a51d9b2a3c4d5e6f708192a3b4c5d6e"
+
+// memoryIntents - keeps the hook intents in memory
+type memoryIntents struct {
+	mut     sync.Mutex
+	intents map[string]*data.HookIntent
+}
+
+func (mi *memoryIntents) AddHookIntent(intent *data.HookIntent) error {
+	mi.mut.Lock()
+	defer mi.mut.Unlock()
+
+	mi.intents[intent.Token] = intent
+
+	return nil
+}
+
+func (mi *memoryIntents) TakeHookIntent(token string) (*data.HookIntent, error) {
+	mi.mut.Lock()
+	defer mi.mut.Unlock()
+
+	intent := mi.intents[token]
+	delete(mi.intents, token)
+
+	return intent, nil
+}
+
+func (mi *memoryIntents) RemoveHookIntentsBefore(_ int64) error {
+	return nil
+}
+
+// hookFixture - a link builder and a callback server sharing the intents, and the callbacks received
+type hookFixture struct {
+	links     *network.HookLinkBuilder
+	server    *network.HookCallbackServer
+	network   *networktest.Fake
+	callbacks []*data.HookCallback
+}
+
+func newHookFixture() *hookFixture {
+	intents := &memoryIntents{intents: make(map[string]*data.HookIntent)}
+	hf := &hookFixture{network: networktest.NewFake()}
+	hf.links = network.NewHookLinkBuilder("https://wallet.elrond.com", "https://bot.example.com", intents)
+	hf.server = network.NewHookCallbackServer("", intents, hf.network)
+	hf.server.OnCallback(func(callback *data.HookCallback) {
+		hf.callbacks = append(hf.callbacks, callback)
+	})
+
+	return hf
+}
+
+// redirect - calls the callback URL of the link the way the wallet does and returns the response
+func (hf *hookFixture) redirect(t *testing.T, link string, status string) *httptest.ResponseRecorder {
+	parsed, err := url.Parse(link)
+	if err != nil {
+		t.Fatalf("invalid link: %v", err)
+	}
+	callback, err := url.Parse(parsed.Query().Get("callbackUrl"))
+	if err != nil || !strings.HasPrefix(callback.Path, network.HookCallbackPath) {
+		t.Fatalf("invalid callback URL in %v", link)
+	}
+
+	query := url.Values{}
+	query.Set("status", status)
+	query.Set("txHash", testTxHash)
+	recorder := httptest.NewRecorder()
+	hf.server.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, callback.Path+"?"+query.Encode(), nil))
+
+	return recorder
+}
+
+func TestHookCallbackServer(t *testing.T) {
+	req := network.DelegateTx(testContractA, data.MustParseAmount("10"))
+	matching := &data.TransactionOnNetwork{
+		Hash:     testTxHash,
+		Sender:   testOtherOwner,
+		Receiver: testContractA,
+		Value:    req.Value.AttoString(),
+		Data:     []byte(req.Data()),
+		Status:   "pending",
+	}
+	withReceiver := *matching
+	withReceiver.Receiver = testContractB
+	withValue := *matching
+	withValue.Value = "1"
+	withData := *matching
+	withData.Data = []byte("unDelegate@8ac7230489e80000")
+
+	tests := []struct {
+		name       string
+		sender     string
+		tx         *data.TransactionOnNetwork
+		wantStatus string
+		wantPage   string
+	}{
+		{"matching", "", matching, network.HookStatusSuccess, "Transaction sent"},
+		{"matching sender", testOtherOwner, matching, network.HookStatusSuccess, "Transaction sent"},
+		{"other sender", testRegistryOwner, matching, network.HookStatusUnverified, "Transaction not recognized"},
+		{"other receiver", "", &withReceiver, network.HookStatusUnverified, "Transaction not recognized"},
+		{"other value", "", &withValue, network.HookStatusUnverified, "Transaction not recognized"},
+		{"other data", "", &withData, network.HookStatusUnverified, "Transaction not recognized"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			hf := newHookFixture()
+			hf.network.SetTransaction(tt.tx)
+			link := hf.links.Link(&network.TxIntent{Request: req, TgID: 5, Description: "Delegate", Sender: tt.sender})
+
+			recorder := hf.redirect(t, link, network.HookStatusSuccess)
+			if recorder.Code != http.StatusOK || !strings.Contains(recorder.Body.String(), tt.wantPage) {
+				t.Errorf("unexpected page %v %q", recorder.Code, recorder.Body.String())
+			}
+			if len(hf.callbacks) != 1 {
+				t.Fatalf("expected 1 callback, got %v", len(hf.callbacks))
+			}
+			callback := hf.callbacks[0]
+			if callback.Status != tt.wantStatus || callback.TxHash != testTxHash || callback.Intent.TgID != 5 {
+				t.Errorf("unexpected callback %+v", callback)
+			}
+			if tt.wantStatus == network.HookStatusSuccess && callback.Sender != testOtherOwner {
+				t.Errorf("callback sender = %q, want %v", callback.Sender, testOtherOwner)
+			}
+		})
+	}
+}
+
+func TestHookCallbackServerCancelled(t *testing.T) {
+	hf := newHookFixture()
+	req := network.ClaimRewardsTx(testContractA)
+	link := hf.links.Link(&network.TxIntent{Request: req, TgID: 5, Description: "Claim rewards"})
+
+	recorder := hf.redirect(t, link, network.HookStatusCancelled)
+	if !strings.Contains(recorder.Body.String(), "Transaction not sent") {
+		t.Errorf("unexpected page %q", recorder.Body.String())
+	}
+	if len(hf.callbacks) != 1 || hf.callbacks[0].Status != network.HookStatusCancelled {
+		t.Fatalf("unexpected callbacks %+v", hf.callbacks)
+	}
+
+	// the intent is taken by the first redirect
+	recorder = hf.redirect(t, link, network.HookStatusSuccess)
+	if recorder.Code != http.StatusNotFound || len(hf.callbacks) != 1 {
+		t.Errorf("expected the second redirect to be refused, got %v", recorder.Code)
+	}
+}
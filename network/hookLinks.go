@@ -0,0 +1,96 @@
+package network
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/DrDelphi/ElrondDSSC/data"
+)
+
+// HookCallbackPath - path of the endpoint receiving the web wallet's redirects, followed by the intent's token
+const HookCallbackPath = "/hook/callback/"
+
+const hookIntentTokenLength = 16
+
+// HookIntentStorer - defines the persistence of the transaction links waiting for the wallet's callback
+type HookIntentStorer interface {
+	AddHookIntent(intent *data.HookIntent) error
+	TakeHookIntent(token string) (*data.HookIntent, error)
+	RemoveHookIntentsBefore(createdAt int64) error
+}
+
+// TxIntent - holds a transaction to be signed in the web wallet, the user it is prepared for and its purpose.
+// Sender is set when only one address may send the transaction
+type TxIntent struct {
+	Request     *TxRequest
+	TgID        int64
+	Description string
+	Sender      string
+}
+
+// HookLinkBuilder - builds the web wallet links which prepare transactions for signing
+type HookLinkBuilder struct {
+	walletHook  string
+	callbackURL string
+	storer      HookIntentStorer
+}
+
+// NewHookLinkBuilder - creates a new HookLinkBuilder object. Without a callback URL, the links
+// do not ask the wallet for a redirect
+func NewHookLinkBuilder(walletHook string, callbackURL string, storer HookIntentStorer) *HookLinkBuilder {
+	return &HookLinkBuilder{
+		walletHook:  strings.TrimSuffix(walletHook, "/"),
+		callbackURL: strings.TrimSuffix(callbackURL, "/"),
+		storer:      storer,
+	}
+}
+
+// Link - returns the web wallet link for the intent. If a callback URL is set and the intent has
+// a user, the wallet redirects to it with a token identifying the intent
+func (hb *HookLinkBuilder) Link(intent *TxIntent) string {
+	req := intent.Request
+	params := url.Values{}
+	params.Set("receiver", req.Receiver)
+	params.Set("value", req.Value.AttoString())
+	params.Set("gasLimit", strconv.FormatUint(req.GasLimit, 10))
+	params.Set("data", req.Data())
+	params.Set("callbackUrl", hb.callback(intent))
+
+	return hb.walletHook + "/hook/transaction?" + params.Encode()
+}
+
+// callback - saves the intent, with the transaction the wallet is expected to send, and returns its
+// callback URL, or "none"
+func (hb *HookLinkBuilder) callback(intent *TxIntent) string {
+	if hb.callbackURL == "" || intent.TgID == 0 {
+		return "none"
+	}
+
+	token := make([]byte, hookIntentTokenLength)
+	_, err := rand.Read(token)
+	if err != nil {
+		log.Warn("can not generate hook intent token", "error", err)
+		return "none"
+	}
+
+	hookIntent := &data.HookIntent{
+		Token:       hex.EncodeToString(token),
+		TgID:        intent.TgID,
+		Description: intent.Description,
+		Sender:      intent.Sender,
+		Receiver:    intent.Request.Receiver,
+		Value:       intent.Request.Value.AttoString(),
+		Data:        intent.Request.Data(),
+		CreatedAt:   time.Now().Unix(),
+	}
+	err = hb.storer.AddHookIntent(hookIntent)
+	if err != nil {
+		return "none"
+	}
+
+	return hb.callbackURL + HookCallbackPath + hookIntent.Token
+}
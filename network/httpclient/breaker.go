@@ -0,0 +1,59 @@
+package httpclient
+
+import (
+	"sync"
+	"time"
+)
+
+// breaker - a consecutive failures circuit breaker. Once open, it lets a single trial
+// request through after the cooldown; its outcome closes or re-opens the circuit
+type breaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	failures  int
+	openUntil time.Time
+	trial     bool
+	mut       sync.Mutex
+}
+
+func newBreaker(threshold int, cooldown time.Duration) *breaker {
+	return &breaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+	}
+}
+
+// allow - returns true if a request may be sent
+func (b *breaker) allow() bool {
+	b.mut.Lock()
+	defer b.mut.Unlock()
+
+	if b.failures < b.threshold {
+		return true
+	}
+	if time.Now().Before(b.openUntil) || b.trial {
+		return false
+	}
+
+	b.trial = true
+
+	return true
+}
+
+func (b *breaker) success() {
+	b.mut.Lock()
+	b.failures = 0
+	b.trial = false
+	b.mut.Unlock()
+}
+
+func (b *breaker) failure() {
+	b.mut.Lock()
+	b.failures++
+	b.trial = false
+	if b.failures >= b.threshold {
+		b.openUntil = time.Now().Add(b.cooldown)
+	}
+	b.mut.Unlock()
+}
@@ -0,0 +1,266 @@
+// Package httpclient provides the HTTP client used for talking to the Elrond proxies,
+// observers and API: per-request deadlines, retries with exponential backoff, a circuit
+// breaker per endpoint and round-robin failover across several endpoints
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/DrDelphi/ElrondDSSC/data"
+	logger "github.com/ElrondNetwork/elrond-go-logger"
+)
+
+var log = logger.GetOrCreate("httpclient")
+
+// Config - holds the client's timeouts, retry and circuit breaker settings
+type Config struct {
+	Timeout          time.Duration
+	MaxRetries       int
+	InitialBackoff   time.Duration
+	MaxBackoff       time.Duration
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
+}
+
+// DefaultConfig - returns the settings used when the application config does not override them
+func DefaultConfig() Config {
+	return Config{
+		Timeout:          time.Second * 10,
+		MaxRetries:       3,
+		InitialBackoff:   time.Millisecond * 250,
+		MaxBackoff:       time.Second * 4,
+		BreakerThreshold: 5,
+		BreakerCooldown:  time.Second * 30,
+	}
+}
+
+// NewConfig - returns the default settings overridden by the non-zero application config values
+func NewConfig(cfg *data.HTTPClientConfig) Config {
+	c := DefaultConfig()
+	if cfg == nil {
+		return c
+	}
+
+	if cfg.TimeoutSeconds > 0 {
+		c.Timeout = time.Duration(cfg.TimeoutSeconds) * time.Second
+	}
+	if cfg.MaxRetries > 0 {
+		c.MaxRetries = cfg.MaxRetries
+	}
+	if cfg.BreakerThreshold > 0 {
+		c.BreakerThreshold = cfg.BreakerThreshold
+	}
+	if cfg.BreakerCooldownSeconds > 0 {
+		c.BreakerCooldown = time.Duration(cfg.BreakerCooldownSeconds) * time.Second
+	}
+
+	return c
+}
+
+type endpoint struct {
+	url     string
+	breaker *breaker
+}
+
+// envelope - the error fields common to all proxy and observer responses
+type envelope struct {
+	Error string `json:"error"`
+	Code  string `json:"code"`
+}
+
+// Client - sends requests to a list of equivalent endpoints
+type Client struct {
+	cfg        Config
+	endpoints  []*endpoint
+	next       uint32
+	httpClient *http.Client
+}
+
+// NewClient - creates a new Client object for the provided base URLs
+func NewClient(urls []string, cfg Config) (*Client, error) {
+	endpoints := make([]*endpoint, 0, len(urls))
+	for _, url := range urls {
+		url = strings.TrimSuffix(strings.TrimSpace(url), "/")
+		if url == "" {
+			continue
+		}
+		endpoints = append(endpoints, &endpoint{
+			url:     url,
+			breaker: newBreaker(cfg.BreakerThreshold, cfg.BreakerCooldown),
+		})
+	}
+	if len(endpoints) == 0 {
+		return nil, ErrNoEndpoints
+	}
+
+	return &Client{
+		cfg:        cfg,
+		endpoints:  endpoints,
+		httpClient: &http.Client{},
+	}, nil
+}
+
+// URLs - returns the endpoints' base URLs
+func (c *Client) URLs() []string {
+	urls := make([]string, 0, len(c.endpoints))
+	for _, e := range c.endpoints {
+		urls = append(urls, e.url)
+	}
+
+	return urls
+}
+
+// Get - sends a GET request for path and returns the response body
+func (c *Client) Get(ctx context.Context, path string) ([]byte, error) {
+	return c.do(ctx, http.MethodGet, path, nil)
+}
+
+// Post - sends body, JSON encoded, to path and returns the response body
+func (c *Client) Post(ctx context.Context, path string, body interface{}) ([]byte, error) {
+	content, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.do(ctx, http.MethodPost, path, content)
+}
+
+// GetJSON - sends a GET request for path and decodes the proxy response into v
+func (c *Client) GetJSON(ctx context.Context, path string, v interface{}) error {
+	content, err := c.Get(ctx, path)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(content, v)
+}
+
+// PostJSON - posts body to path and decodes the proxy response into v
+func (c *Client) PostJSON(ctx context.Context, path string, body interface{}, v interface{}) error {
+	content, err := c.Post(ctx, path, body)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(content, v)
+}
+
+// do - sends the request to the endpoints in round-robin order, retrying transport failures and
+// server errors with exponential backoff. Other errors reported by the proxy are returned right away
+func (c *Client) do(ctx context.Context, method string, path string, body []byte) ([]byte, error) {
+	var lastErr error
+	backoff := c.cfg.InitialBackoff
+	for attempt := 0; attempt <= c.cfg.MaxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, lastErr
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+			if backoff > c.cfg.MaxBackoff {
+				backoff = c.cfg.MaxBackoff
+			}
+		}
+
+		e := c.pickEndpoint()
+		if e == nil {
+			lastErr = ErrCircuitOpen
+			continue
+		}
+
+		content, err := c.send(ctx, e, method, path, body)
+		if err == nil {
+			e.breaker.success()
+			return content, nil
+		}
+
+		if !isRetriable(err) {
+			e.breaker.success()
+			return nil, err
+		}
+
+		e.breaker.failure()
+		lastErr = err
+		log.Debug("request failed", "method", method, "url", e.url+path, "attempt", attempt+1, "error", err)
+		if ctx.Err() != nil {
+			return nil, lastErr
+		}
+	}
+
+	return nil, lastErr
+}
+
+// isRetriable - transport failures and server errors are worth retrying, while the errors
+// reported by the proxy for a bad request will not go away
+func isRetriable(err error) bool {
+	var proxyErr *ProxyError
+	if errors.As(err, &proxyErr) {
+		return proxyErr.StatusCode >= http.StatusInternalServerError
+	}
+
+	var transportErr *TransportError
+	return errors.As(err, &transportErr)
+}
+
+// pickEndpoint - returns the next endpoint whose circuit allows a request, or nil
+func (c *Client) pickEndpoint() *endpoint {
+	start := atomic.AddUint32(&c.next, 1) - 1
+	for i := 0; i < len(c.endpoints); i++ {
+		e := c.endpoints[(int(start)+i)%len(c.endpoints)]
+		if e.breaker.allow() {
+			return e
+		}
+	}
+
+	return nil
+}
+
+func (c *Client) send(ctx context.Context, e *endpoint, method string, path string, body []byte) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.cfg.Timeout)
+	defer cancel()
+
+	url := e.url + path
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, &TransportError{URL: url, Err: err}
+	}
+	defer resp.Body.Close()
+
+	content, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, &TransportError{URL: url, StatusCode: resp.StatusCode, Err: err}
+	}
+
+	env := &envelope{}
+	isEnvelope := json.Unmarshal(content, env) == nil
+	if isEnvelope && env.Error != "" {
+		return nil, &ProxyError{URL: url, StatusCode: resp.StatusCode, Code: env.Code, Message: env.Error}
+	}
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return nil, &TransportError{URL: url, StatusCode: resp.StatusCode, Err: errors.New(http.StatusText(resp.StatusCode))}
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		return nil, &ProxyError{URL: url, StatusCode: resp.StatusCode, Message: fmt.Sprintf("status %v", resp.StatusCode)}
+	}
+
+	return content, nil
+}
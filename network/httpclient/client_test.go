@@ -0,0 +1,334 @@
+package httpclient_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/DrDelphi/ElrondDSSC/network/httpclient"
+)
+
+const okBody = `{"data":{"ok":true},"error":"","code":"successful"}`
+
+// testConfig - short timeouts, so the tests run fast
+func testConfig() httpclient.Config {
+	return httpclient.Config{
+		Timeout:          time.Millisecond * 100,
+		MaxRetries:       0,
+		InitialBackoff:   time.Millisecond * 20,
+		MaxBackoff:       time.Millisecond * 30,
+		BreakerThreshold: 100,
+		BreakerCooldown:  time.Millisecond * 100,
+	}
+}
+
+// scriptedServer - answers each request with the next handler, repeating the last one, and counts the requests
+type scriptedServer struct {
+	*httptest.Server
+	calls    int32
+	handlers []http.HandlerFunc
+}
+
+func newScriptedServer(t *testing.T, handlers ...http.HandlerFunc) *scriptedServer {
+	s := &scriptedServer{handlers: handlers}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		call := int(atomic.AddInt32(&s.calls, 1)) - 1
+		if call >= len(s.handlers) {
+			call = len(s.handlers) - 1
+		}
+		s.handlers[call](w, r)
+	}))
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+func (s *scriptedServer) requests() int {
+	return int(atomic.LoadInt32(&s.calls))
+}
+
+// downURL - returns the URL of a server which refuses connections
+func downURL() string {
+	s := httptest.NewServer(http.NotFoundHandler())
+	s.Close()
+
+	return s.URL
+}
+
+func respond(status int, body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}
+}
+
+// hang - answers only after the request was cancelled
+func hang(w http.ResponseWriter, r *http.Request) {
+	<-r.Context().Done()
+}
+
+func newClient(t *testing.T, cfg httpclient.Config, urls ...string) *httpclient.Client {
+	c, err := httpclient.NewClient(urls, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return c
+}
+
+func TestNewClientWithoutURLs(t *testing.T) {
+	_, err := httpclient.NewClient([]string{"", " "}, testConfig())
+	if err != httpclient.ErrNoEndpoints {
+		t.Fatalf("NewClient() error = %v, want %v", err, httpclient.ErrNoEndpoints)
+	}
+}
+
+func TestRequestDeadline(t *testing.T) {
+	server := newScriptedServer(t, hang)
+	c := newClient(t, testConfig(), server.URL)
+
+	start := time.Now()
+	_, err := c.Get(context.Background(), "/slow")
+	elapsed := time.Since(start)
+	if !httpclient.IsTransportError(err) {
+		t.Fatalf("Get() error = %v, want a transport error", err)
+	}
+	if elapsed > time.Second {
+		t.Fatalf("Get() took %v, the deadline was not applied", elapsed)
+	}
+}
+
+func TestRetryOnTimeout(t *testing.T) {
+	server := newScriptedServer(t, hang, respond(http.StatusOK, okBody))
+	cfg := testConfig()
+	cfg.MaxRetries = 2
+	c := newClient(t, cfg, server.URL)
+
+	content, err := c.Get(context.Background(), "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != okBody || server.requests() != 2 {
+		t.Fatalf("Get() = %s after %d requests, want the second answer", content, server.requests())
+	}
+}
+
+func TestRetryServerErrorsWithBackoff(t *testing.T) {
+	server := newScriptedServer(t,
+		respond(http.StatusBadGateway, "bad gateway"),
+		respond(http.StatusInternalServerError, `{"error":"node busy","code":"internal_issue"}`),
+		respond(http.StatusOK, okBody))
+	cfg := testConfig()
+	cfg.MaxRetries = 3
+	c := newClient(t, cfg, server.URL)
+
+	start := time.Now()
+	content, err := c.Get(context.Background(), "/")
+	elapsed := time.Since(start)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != okBody || server.requests() != 3 {
+		t.Fatalf("Get() = %s after %d requests, want the third answer", content, server.requests())
+	}
+	// 20ms, then 40ms capped to 30ms
+	if elapsed < cfg.InitialBackoff+cfg.MaxBackoff {
+		t.Fatalf("Get() took %v, shorter than the backoff", elapsed)
+	}
+}
+
+func TestRetriesExhausted(t *testing.T) {
+	server := newScriptedServer(t, respond(http.StatusServiceUnavailable, "unavailable"))
+	cfg := testConfig()
+	cfg.MaxRetries = 2
+	c := newClient(t, cfg, server.URL)
+
+	_, err := c.Get(context.Background(), "/")
+	if !httpclient.IsTransportError(err) || server.requests() != 3 {
+		t.Fatalf("Get() error = %v after %d requests, want a transport error after 3", err, server.requests())
+	}
+}
+
+func TestNoRetryOnClientErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+	}{
+		{"proxy error", respond(http.StatusBadRequest, `{"error":"invalid address","code":"bad_request"}`)},
+		{"plain status", respond(http.StatusNotFound, "not found")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newScriptedServer(t, tt.handler, respond(http.StatusOK, okBody))
+			cfg := testConfig()
+			cfg.MaxRetries = 3
+			c := newClient(t, cfg, server.URL)
+
+			_, err := c.Get(context.Background(), "/")
+			if !httpclient.IsProxyError(err) || server.requests() != 1 {
+				t.Fatalf("Get() error = %v after %d requests, want a proxy error after 1", err, server.requests())
+			}
+		})
+	}
+}
+
+func TestErrorClassification(t *testing.T) {
+	tests := []struct {
+		name       string
+		handler    http.HandlerFunc
+		proxy      bool
+		statusCode int
+		code       string
+	}{
+		{"error field with 200", respond(http.StatusOK, `{"error":"account not found","code":"not_found"}`), true, http.StatusOK, "not_found"},
+		{"error field with 400", respond(http.StatusBadRequest, `{"error":"bad nonce","code":"bad_request"}`), true, http.StatusBadRequest, "bad_request"},
+		{"error field with 500", respond(http.StatusInternalServerError, `{"error":"vm error","code":"internal_issue"}`), true, http.StatusInternalServerError, "internal_issue"},
+		{"plain 4xx", respond(http.StatusForbidden, "forbidden"), true, http.StatusForbidden, ""},
+		{"plain 5xx", respond(http.StatusInternalServerError, "oops"), false, http.StatusInternalServerError, ""},
+		{"timeout", hang, false, 0, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newScriptedServer(t, tt.handler)
+			c := newClient(t, testConfig(), server.URL)
+
+			_, err := c.Get(context.Background(), "/")
+			if httpclient.IsProxyError(err) != tt.proxy || httpclient.IsTransportError(err) == tt.proxy {
+				t.Fatalf("Get() error = %v (%T), want proxy error %v", err, err, tt.proxy)
+			}
+
+			if tt.proxy {
+				var proxyErr *httpclient.ProxyError
+				errors.As(err, &proxyErr)
+				if proxyErr.StatusCode != tt.statusCode || proxyErr.Code != tt.code {
+					t.Fatalf("proxy error status %d code %q, want %d %q", proxyErr.StatusCode, proxyErr.Code,
+						tt.statusCode, tt.code)
+				}
+				return
+			}
+
+			var transportErr *httpclient.TransportError
+			errors.As(err, &transportErr)
+			if transportErr.StatusCode != tt.statusCode || transportErr.URL != server.URL+"/" {
+				t.Fatalf("transport error status %d url %s, want %d %s", transportErr.StatusCode, transportErr.URL,
+					tt.statusCode, server.URL+"/")
+			}
+		})
+	}
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	server := newScriptedServer(t,
+		respond(http.StatusInternalServerError, "down"),
+		respond(http.StatusInternalServerError, "down"),
+		respond(http.StatusInternalServerError, "still down"),
+		respond(http.StatusOK, okBody))
+	cfg := testConfig()
+	cfg.BreakerThreshold = 2
+	c := newClient(t, cfg, server.URL)
+
+	for i := 0; i < cfg.BreakerThreshold; i++ {
+		_, err := c.Get(context.Background(), "/")
+		if !httpclient.IsTransportError(err) {
+			t.Fatalf("Get() error = %v, want a transport error", err)
+		}
+	}
+
+	// open: the endpoint is not tried
+	_, err := c.Get(context.Background(), "/")
+	if err != httpclient.ErrCircuitOpen || server.requests() != 2 {
+		t.Fatalf("Get() error = %v after %d requests, want %v after 2", err, server.requests(), httpclient.ErrCircuitOpen)
+	}
+
+	// half-open: a single trial, whose failure re-opens the circuit
+	time.Sleep(cfg.BreakerCooldown)
+	_, err = c.Get(context.Background(), "/")
+	if !httpclient.IsTransportError(err) || server.requests() != 3 {
+		t.Fatalf("Get() error = %v after %d requests, want the failed trial", err, server.requests())
+	}
+	_, err = c.Get(context.Background(), "/")
+	if err != httpclient.ErrCircuitOpen || server.requests() != 3 {
+		t.Fatalf("Get() error = %v after %d requests, want %v", err, server.requests(), httpclient.ErrCircuitOpen)
+	}
+
+	// a successful trial closes it
+	time.Sleep(cfg.BreakerCooldown)
+	_, err = c.Get(context.Background(), "/")
+	if err != nil {
+		t.Fatalf("trial request error = %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		_, err = c.Get(context.Background(), "/")
+		if err != nil {
+			t.Fatalf("Get() error = %v with the circuit closed", err)
+		}
+	}
+	if server.requests() != 7 {
+		t.Fatalf("%d requests, want 7", server.requests())
+	}
+}
+
+func TestFailoverToSecondURL(t *testing.T) {
+	server := newScriptedServer(t, respond(http.StatusOK, okBody))
+	cfg := testConfig()
+	cfg.MaxRetries = 1
+	cfg.BreakerThreshold = 1
+	c := newClient(t, cfg, downURL(), server.URL)
+
+	for i := 0; i < 4; i++ {
+		content, err := c.Get(context.Background(), "/")
+		if err != nil {
+			t.Fatalf("request %d: %v", i, err)
+		}
+		if string(content) != okBody {
+			t.Fatalf("request %d: %s", i, content)
+		}
+	}
+	if server.requests() != 4 {
+		t.Fatalf("%d requests reached the second URL, want 4", server.requests())
+	}
+}
+
+func TestRoundRobin(t *testing.T) {
+	first := newScriptedServer(t, respond(http.StatusOK, okBody))
+	second := newScriptedServer(t, respond(http.StatusOK, okBody))
+	c := newClient(t, testConfig(), first.URL, second.URL+"/")
+
+	for i := 0; i < 6; i++ {
+		_, err := c.Get(context.Background(), "/")
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if first.requests() != 3 || second.requests() != 3 {
+		t.Fatalf("requests split %d / %d, want 3 / 3", first.requests(), second.requests())
+	}
+}
+
+func TestPostJSON(t *testing.T) {
+	var contentType string
+	server := newScriptedServer(t, func(w http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get("Content-Type")
+		_, _ = w.Write([]byte(okBody))
+	})
+	c := newClient(t, testConfig(), server.URL)
+
+	response := struct {
+		Data struct {
+			OK bool `json:"ok"`
+		} `json:"data"`
+	}{}
+	err := c.PostJSON(context.Background(), "/transaction/send", map[string]string{"nonce": "1"}, &response)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !response.Data.OK || contentType != "application/json" {
+		t.Fatalf("response %+v, content type %q", response, contentType)
+	}
+}
@@ -0,0 +1,64 @@
+package httpclient
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNoEndpoints - returned when a client is created without any URL
+var ErrNoEndpoints = errors.New("no endpoints provided")
+
+// ErrCircuitOpen - returned when all the endpoints are unhealthy and none of them may be tried yet
+var ErrCircuitOpen = errors.New("circuit breaker open for all endpoints")
+
+// ProxyError - the endpoint answered, but reported an error through its error / code fields
+// or, for non-JSON answers, through a 4xx status code
+type ProxyError struct {
+	URL        string
+	StatusCode int
+	Code       string
+	Message    string
+}
+
+// Error - implements the error interface
+func (e *ProxyError) Error() string {
+	if e.Code == "" {
+		return e.Message
+	}
+
+	return fmt.Sprintf("%s (%s)", e.Message, e.Code)
+}
+
+// TransportError - the request did not get an answer from the proxy: connection failures,
+// timeouts and server errors without an error message all end up here
+type TransportError struct {
+	URL        string
+	StatusCode int
+	Err        error
+}
+
+// Error - implements the error interface
+func (e *TransportError) Error() string {
+	if e.StatusCode != 0 {
+		return fmt.Sprintf("%s: status %v: %v", e.URL, e.StatusCode, e.Err)
+	}
+
+	return fmt.Sprintf("%s: %v", e.URL, e.Err)
+}
+
+// Unwrap - returns the underlying error
+func (e *TransportError) Unwrap() error {
+	return e.Err
+}
+
+// IsProxyError - returns true if err was reported by the proxy itself
+func IsProxyError(err error) bool {
+	var proxyErr *ProxyError
+	return errors.As(err, &proxyErr)
+}
+
+// IsTransportError - returns true if err is a transport failure
+func IsTransportError(err error) bool {
+	var transportErr *TransportError
+	return errors.As(err, &transportErr) || errors.Is(err, ErrCircuitOpen)
+}
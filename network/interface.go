@@ -0,0 +1,113 @@
+package network
+
+import (
+	"github.com/DrDelphi/ElrondDSSC/data"
+	"github.com/ElrondNetwork/elrond-sdk/erdgo"
+)
+
+// DelegationContract - defines the read operations available on the delegation system smart contract
+type DelegationContract interface {
+	GetUserActiveStake(address string) (*data.Amount, error)
+	GetUserUnBondable(address string) (*data.Amount, error)
+	GetUserUnStakedValue(address string) (*data.Amount, error)
+	GetClaimableRewards(address string) (*data.Amount, error)
+	GetUserCumulatedRewards(address string) (*data.Amount, error)
+	GetUserUnDelegatedList(address string) ([]*data.UnDelegatedFund, error)
+	GetContractInfo(address string) (*data.ContractInfo, error)
+	GetContractMetadata(address string) (*data.ContractMetadata, error)
+	GetTotalActiveStake() (*data.Amount, error)
+	GetTotalUnStaked() (*data.Amount, error)
+	GetTotalCumulatedRewards() (*data.Amount, error)
+	GetTotalUnStakedFromNodes() (*data.Amount, error)
+	GetTotalUnBondedFromNodes() (*data.Amount, error)
+	GetNumUsers() (uint64, error)
+	GetNumNodes() (uint64, error)
+	GetAllNodeStates() ([]*data.NodeInfo, error)
+}
+
+// ValidatorReader - defines the read operations available on the validators
+type ValidatorReader interface {
+	GetBlsKeysStatus(address string) (map[string]string, error)
+	GetValidatorStatistics() (map[string]*data.ValidatorStatistics, error)
+}
+
+// ContractResolver - defines the lookups needed for finding a delegation contract
+type ContractResolver interface {
+	GetAllContractAddresses() ([]string, error)
+	GetContractOwner(contract string) (string, error)
+}
+
+// ProviderReader - defines the read operations needed for listing all the staking providers
+type ProviderReader interface {
+	GetAllContractAddresses() ([]string, error)
+	GetContractInfo(address string) (*data.ContractInfo, error)
+	GetContractMetadata(address string) (*data.ContractMetadata, error)
+	GetContractTotalActiveStake(address string) (*data.Amount, error)
+	GetContractNumUsers(address string) (uint64, error)
+}
+
+// ContractAddressProvider - defines the source of the delegation contract address
+type ContractAddressProvider interface {
+	ContractAddress() string
+}
+
+// NetworkStatusReader - defines the reading of a shard's current epoch, round and nonce
+type NetworkStatusReader interface {
+	GetNetworkStatus(shard uint32) (*data.NetworkStatus, error)
+}
+
+// EconomicsReader - defines the reading of the network's staking and inflation values
+type EconomicsReader interface {
+	GetNetworkEconomics() (*data.EconomicsMetrics, error)
+}
+
+// AccountReader - defines the read operations available on accounts
+type AccountReader interface {
+	GetAccount(address string) (*erdgo.Account, error)
+	GetLastTxs(address string, size int, inout string) ([]*data.APITransaction, error)
+}
+
+// TransactionReader - defines the read operations available on broadcasted transactions
+type TransactionReader interface {
+	GetTransactionStatus(hash string) (string, error)
+	GetTransaction(hash string) (*data.TransactionOnNetwork, error)
+}
+
+// TransactionLister - defines the paging through the transactions indexed by the API
+type TransactionLister interface {
+	GetTransactions(sender string, receiver string, from int, size int) ([]*data.APITransaction, error)
+}
+
+// ContractTransactionLister - defines the paging through the transactions sent to a contract within a time range
+type ContractTransactionLister interface {
+	GetContractTransactions(contract string, after int64, before int64, from int, size int) ([]*data.APITransaction, error)
+}
+
+// TransactionBroadcaster - defines the broadcasting of signed transactions
+type TransactionBroadcaster interface {
+	SendTransaction(tx *erdgo.Transaction) (string, error)
+}
+
+// TransactionSimulator - defines the prediction of a transaction's outcome before it is sent
+type TransactionSimulator interface {
+	SimulateTransaction(sender string, req *TxRequest) (*data.TxSimulation, error)
+}
+
+// TransactionSender - defines the operations which broadcast transactions
+type TransactionSender interface {
+	TransactionBroadcaster
+	CreateDSSC(privateKey []byte) (string, error)
+}
+
+var _ DelegationContract = (*NetworkManager)(nil)
+var _ ContractResolver = (*NetworkManager)(nil)
+var _ ProviderReader = (*NetworkManager)(nil)
+var _ ValidatorReader = (*NetworkManager)(nil)
+var _ NetworkStatusReader = (*NetworkManager)(nil)
+var _ EconomicsReader = (*NetworkManager)(nil)
+var _ AccountReader = (*NetworkManager)(nil)
+var _ TransactionReader = (*NetworkManager)(nil)
+var _ TransactionLister = (*NetworkManager)(nil)
+var _ ContractTransactionLister = (*NetworkManager)(nil)
+var _ TransactionSimulator = (*NetworkManager)(nil)
+var _ TransactionSender = (*NetworkManager)(nil)
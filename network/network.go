@@ -1,134 +1,297 @@
 package network
 
 import (
+	"context"
 	"encoding/hex"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"math/big"
+	"net/url"
+	"strconv"
+	"time"
 
 	"github.com/DrDelphi/ElrondDSSC/data"
-	"github.com/DrDelphi/ElrondDSSC/utils"
+	"github.com/DrDelphi/ElrondDSSC/network/httpclient"
 	logger "github.com/ElrondNetwork/elrond-go-logger"
-	"github.com/ElrondNetwork/elrond-go/core/indexer"
 	"github.com/ElrondNetwork/elrond-sdk/erdgo"
 )
 
 var log = logger.GetOrCreate("network")
 
+// requestDeadline - upper limit for a request, including its retries
+const requestDeadline = time.Second * 30
+
 // NetworkManager - holds the required fields of a network manager
 type NetworkManager struct {
 	networkConfig *data.NetworkConfig
-	networkAPI    string
-	networkProxy  string
-	metaObserver  string
-	fDenomination *big.Float
-	Proxy         *erdgo.ElrondProxy
+	apiClient     *httpclient.Client
+	proxyClient   *httpclient.Client
+	metaClient    *httpclient.Client
+	txBuilder     *TxBuilder
+	contracts     ContractAddressProvider
+	systemSCs     data.SystemSCAddresses
 }
 
 // NewNetworkManager - creates a new NetworkManager object
 func NewNetworkManager(cfg *data.AppConfig) (*NetworkManager, error) {
-	bytes, err := utils.GetHTTP(cfg.MetaObserver + "/network/config")
+	clientConfig := httpclient.NewConfig(cfg.HTTPClient)
+	apiClient, err := httpclient.NewClient([]string{cfg.NetworkAPI}, clientConfig)
+	if err != nil {
+		log.Error("invalid network API address", "error", err)
+		return nil, err
+	}
+	proxyClient, err := httpclient.NewClient(cfg.NetworkProxyURLs(), clientConfig)
 	if err != nil {
-		log.Error("can not get network config from meta observer", "error", err)
+		log.Error("invalid network proxy addresses", "error", err)
 		return nil, err
 	}
+	metaClient, err := httpclient.NewClient(cfg.MetaObserverURLs(), clientConfig)
+	if err != nil {
+		log.Warn("no meta observer addresses, using the network proxy instead")
+		metaClient = proxyClient
+	}
 
-	networkConfig := &data.NetworkConfig{}
-	err = json.Unmarshal(bytes, networkConfig)
+	networkConfig, err := getNetworkConfig(metaClient)
+	if err != nil && metaClient != proxyClient {
+		log.Warn("can not get network config from meta observer, trying the proxy", "error", err)
+		networkConfig, err = getNetworkConfig(proxyClient)
+	}
 	if err != nil {
-		log.Error("can not unmarshal network config", "error", err)
+		log.Error("can not get network config", "error", err)
 		return nil, err
 	}
 
-	fDenomination := big.NewFloat(1)
-	for i := 0; i < networkConfig.Data.Config.ErdDenomination; i++ {
-		fDenomination.Mul(fDenomination, big.NewFloat(10))
+	if networkConfig.Data.Config.ErdDenomination != data.Denomination {
+		log.Warn("unexpected network denomination", "expected", data.Denomination,
+			"received", networkConfig.Data.Config.ErdDenomination)
+	}
+
+	chainID := networkConfig.Data.Config.ErdChainID
+	if cfg.ChainID != "" && chainID != cfg.ChainID {
+		err = fmt.Errorf("%w: expected %q, the network reports %q", ErrChainIDMismatch, cfg.ChainID, chainID)
+		log.Error("wrong network", "network", cfg.Network, "error", err)
+		return nil, err
 	}
 
 	networkManager := &NetworkManager{
 		networkConfig: networkConfig,
-		networkAPI:    cfg.NetworkAPI,
-		networkProxy:  cfg.NetworkProxy,
-		metaObserver:  cfg.MetaObserver,
-		fDenomination: fDenomination,
-		Proxy:         erdgo.NewElrondProxy(cfg.NetworkProxy),
+		apiClient:     apiClient,
+		proxyClient:   proxyClient,
+		metaClient:    metaClient,
+		systemSCs:     *data.DefaultSystemSCAddresses().Merge(cfg.SystemSCs),
 	}
+	networkManager.txBuilder = NewTxBuilder(proxyClient, networkConfig, networkManager, networkManager)
 
 	return networkManager, nil
 }
 
-// GetDenominator - returns a big.Float of 10 ^ decimals
-func (nm *NetworkManager) GetDenominator() *big.Float {
-	return nm.fDenomination
+// ErrChainIDMismatch - the network's chain ID differs from the configured one
+var ErrChainIDMismatch = errors.New("chain ID mismatch")
+
+// ChainID - returns the chain ID reported by the network
+func (nm *NetworkManager) ChainID() string {
+	return nm.networkConfig.Data.Config.ErdChainID
+}
+
+func newRequestContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), requestDeadline)
 }
 
 // GetUserActiveStake - retrieves an address' active stake delegated in the DSSC
-func (nm *NetworkManager) GetUserActiveStake(address string) (*big.Float, error) {
+func (nm *NetworkManager) GetUserActiveStake(address string) (*data.Amount, error) {
 	pubkey, _ := erdgo.Bech32ToPubkey(address)
 	hexAddress := hex.EncodeToString(pubkey)
-	iStake, err := nm.queryScIntResult(utils.ContractAddress, "getUserActiveStake", []string{hexAddress})
+	iStake, err := nm.queryScIntResult(nm.contractAddress(), "getUserActiveStake", []string{hexAddress})
 	if err != nil {
 		return nil, err
 	}
-	fStake := big.NewFloat(0).SetInt(iStake)
-	fStake.Quo(fStake, nm.fDenomination)
 
-	return fStake, nil
+	return data.NewAmount(iStake), nil
 }
 
 // GetUserUnBondable - retrieves an address' unbondable stake from the DSSC
-func (nm *NetworkManager) GetUserUnBondable(address string) (*big.Float, error) {
+func (nm *NetworkManager) GetUserUnBondable(address string) (*data.Amount, error) {
 	pubkey, _ := erdgo.Bech32ToPubkey(address)
 	hexAddress := hex.EncodeToString(pubkey)
-	iStake, err := nm.queryScIntResult(utils.ContractAddress, "getUserUnBondable", []string{hexAddress})
+	iStake, err := nm.queryScIntResult(nm.contractAddress(), "getUserUnBondable", []string{hexAddress})
 	if err != nil {
 		return nil, err
 	}
-	fStake := big.NewFloat(0).SetInt(iStake)
-	fStake.Quo(fStake, nm.fDenomination)
 
-	return fStake, nil
+	return data.NewAmount(iStake), nil
 }
 
 // GetUserUnStakedValue - retrieves an address' unstaked value from the DSSC
-func (nm *NetworkManager) GetUserUnStakedValue(address string) (*big.Float, error) {
+func (nm *NetworkManager) GetUserUnStakedValue(address string) (*data.Amount, error) {
 	pubkey, _ := erdgo.Bech32ToPubkey(address)
 	hexAddress := hex.EncodeToString(pubkey)
-	iStake, err := nm.queryScIntResult(utils.ContractAddress, "getUserUnStakedValue", []string{hexAddress})
+	iStake, err := nm.queryScIntResult(nm.contractAddress(), "getUserUnStakedValue", []string{hexAddress})
 	if err != nil {
 		return nil, err
 	}
-	fStake := big.NewFloat(0).SetInt(iStake)
-	fStake.Quo(fStake, nm.fDenomination)
 
-	return fStake, nil
+	return data.NewAmount(iStake), nil
 }
 
 // GetClaimableRewards - retrieves an address' claimable rewards from the DSSC
-func (nm *NetworkManager) GetClaimableRewards(address string) (*big.Float, error) {
+func (nm *NetworkManager) GetClaimableRewards(address string) (*data.Amount, error) {
 	pubkey, _ := erdgo.Bech32ToPubkey(address)
 	hexAddress := hex.EncodeToString(pubkey)
-	iStake, err := nm.queryScIntResult(utils.ContractAddress, "getClaimableRewards", []string{hexAddress})
+	iStake, err := nm.queryScIntResult(nm.contractAddress(), "getClaimableRewards", []string{hexAddress})
 	if err != nil {
 		return nil, err
 	}
-	fStake := big.NewFloat(0).SetInt(iStake)
-	fStake.Quo(fStake, nm.fDenomination)
 
-	return fStake, nil
+	return data.NewAmount(iStake), nil
 }
 
-// GetLastTxs - retrieves from the API the last in / out transactions to / from a specified address
-func (nm *NetworkManager) GetLastTxs(address string, size int, inout string) ([]*indexer.Transaction, error) {
-	endpoint := fmt.Sprintf("%s/transactions?from=0&size=%v&%s=%s", nm.networkAPI, size, inout, address)
-	bytes, err := utils.GetHTTP(endpoint)
+// GetUserCumulatedRewards - retrieves all the rewards a delegator earned with the DSSC, claimed or not
+func (nm *NetworkManager) GetUserCumulatedRewards(address string) (*data.Amount, error) {
+	pubkey, _ := erdgo.Bech32ToPubkey(address)
+	hexAddress := hex.EncodeToString(pubkey)
+	iRewards, err := nm.queryScIntResult(nm.contractAddress(), "getTotalCumulatedRewardsForUser", []string{hexAddress})
+	if err != nil {
+		return nil, err
+	}
+
+	return data.NewAmount(iRewards), nil
+}
+
+// GetNetworkConfig - returns the network configuration read at startup
+func (nm *NetworkManager) GetNetworkConfig() *data.NetworkConfig {
+	return nm.networkConfig
+}
+
+// SetContractAddressProvider - sets the source of the address used for the DSSC queries
+func (nm *NetworkManager) SetContractAddressProvider(contracts ContractAddressProvider) {
+	nm.contracts = contracts
+}
+
+func (nm *NetworkManager) contractAddress() string {
+	if nm.contracts == nil {
+		return ""
+	}
+
+	return nm.contracts.ContractAddress()
+}
+
+// GetTxBuilder - returns the transaction builder used for the transactions signed by the application
+func (nm *NetworkManager) GetTxBuilder() *TxBuilder {
+	return nm.txBuilder
+}
+
+// getNetworkConfig - reads the network config from a node or proxy
+func getNetworkConfig(client *httpclient.Client) (*data.NetworkConfig, error) {
+	ctx, cancel := newRequestContext()
+	defer cancel()
+
+	networkConfig := &data.NetworkConfig{}
+	err := client.GetJSON(ctx, "/network/config", networkConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return networkConfig, nil
+}
+
+// GetNetworkStatus - retrieves a shard's current epoch, round and nonce from the proxy
+func (nm *NetworkManager) GetNetworkStatus(shard uint32) (*data.NetworkStatus, error) {
+	ctx, cancel := newRequestContext()
+	defer cancel()
+
+	status := &data.NetworkStatus{}
+	err := nm.proxyClient.GetJSON(ctx, fmt.Sprintf("/network/status/%v", shard), status)
+	if err != nil {
+		return nil, err
+	}
+
+	return status, nil
+}
+
+// GetNetworkEconomics - retrieves the total supply, staked value, top-up and inflation from the proxy
+func (nm *NetworkManager) GetNetworkEconomics() (*data.EconomicsMetrics, error) {
+	ctx, cancel := newRequestContext()
+	defer cancel()
+
+	economics := &data.NetworkEconomics{}
+	err := nm.proxyClient.GetJSON(ctx, "/network/economics", economics)
+	if err != nil {
+		log.Error("can not get network economics", "error", err)
+		return nil, err
+	}
+
+	return &economics.Data.Metrics, nil
+}
+
+// GetAccount - retrieves an account's nonce and balance from the proxy
+func (nm *NetworkManager) GetAccount(address string) (*erdgo.Account, error) {
+	ctx, cancel := newRequestContext()
+	defer cancel()
+
+	res := &erdgo.AccountResponse{}
+	err := nm.proxyClient.GetJSON(ctx, "/address/"+address, res)
 	if err != nil {
 		return nil, err
 	}
+	if res.Data.Account == nil {
+		return nil, errors.New("invalid response")
+	}
+
+	return res.Data.Account, nil
+}
 
-	list := make([]*indexer.Transaction, 0)
-	err = json.Unmarshal(bytes, &list)
+// SendTransaction - broadcasts a signed transaction and returns its hash
+func (nm *NetworkManager) SendTransaction(tx *erdgo.Transaction) (string, error) {
+	ctx, cancel := newRequestContext()
+	defer cancel()
+
+	res := &erdgo.SendTransactionResponse{}
+	err := nm.proxyClient.PostJSON(ctx, "/transaction/send", tx, res)
+	if err != nil {
+		return "", err
+	}
+
+	return res.Data.TxHash, nil
+}
+
+// GetTransactionStatus - retrieves a transaction's status from the proxy
+func (nm *NetworkManager) GetTransactionStatus(hash string) (string, error) {
+	ctx, cancel := newRequestContext()
+	defer cancel()
+
+	res := &data.TransactionStatusResponse{}
+	err := nm.proxyClient.GetJSON(ctx, fmt.Sprintf("/transaction/%s/status", hash), res)
+	if err != nil {
+		return "", err
+	}
+
+	return res.Data.Status, nil
+}
+
+// GetTransaction - retrieves a transaction's details, including its smart contract results, from the proxy
+func (nm *NetworkManager) GetTransaction(hash string) (*data.TransactionOnNetwork, error) {
+	ctx, cancel := newRequestContext()
+	defer cancel()
+
+	res := &data.TransactionResponse{}
+	err := nm.proxyClient.GetJSON(ctx, fmt.Sprintf("/transaction/%s?withResults=true", hash), res)
+	if err != nil {
+		return nil, err
+	}
+	if res.Data.Transaction == nil {
+		return nil, errors.New("invalid response")
+	}
+
+	return res.Data.Transaction, nil
+}
+
+// GetLastTxs - retrieves from the API the last in / out transactions to / from a specified address
+func (nm *NetworkManager) GetLastTxs(address string, size int, inout string) ([]*data.APITransaction, error) {
+	ctx, cancel := newRequestContext()
+	defer cancel()
+
+	list := make([]*data.APITransaction, 0)
+	err := nm.apiClient.GetJSON(ctx, fmt.Sprintf("/transactions?from=0&size=%v&%s=%s", size, inout, address), &list)
 	if err != nil {
 		return nil, err
 	}
@@ -136,29 +299,92 @@ func (nm *NetworkManager) GetLastTxs(address string, size int, inout string) ([]
 	return list, nil
 }
 
-func (nm *NetworkManager) queryScIntResult(scAddress, funcName string, args []string) (*big.Int, error) {
-	query := &data.ScQuery{
-		ScAddress: scAddress,
-		FuncName:  funcName,
-		Args:      args,
+// GetTransactions - retrieves from the API a page of the transactions sent by sender to receiver, newest
+// first, together with their smart contract results. An empty sender or receiver matches any address
+func (nm *NetworkManager) GetTransactions(sender string, receiver string, from int, size int) ([]*data.APITransaction, error) {
+	params := url.Values{}
+	if sender != "" {
+		params.Set("sender", sender)
 	}
-	host := fmt.Sprintf("%s/vm-values/int", nm.networkProxy)
-	body, err := json.Marshal(query)
+	if receiver != "" {
+		params.Set("receiver", receiver)
+	}
+
+	return nm.getTransactions(params, from, size)
+}
+
+// SimulateTransaction - predicts the outcome and the fee of a transaction sent from sender, without sending it
+func (nm *NetworkManager) SimulateTransaction(sender string, req *TxRequest) (*data.TxSimulation, error) {
+	simulation, err := nm.txBuilder.Simulate(sender, req)
 	if err != nil {
+		log.Error("can not simulate transaction", "sender", sender, "function", req.Function, "error", err)
 		return nil, err
 	}
-	bytes, err := utils.PostHTTP(host, string(body))
+
+	return simulation, nil
+}
+
+// HandleTxOutcome - lets the transaction builder reset the nonce of the sender of a dropped or invalid transaction
+func (nm *NetworkManager) HandleTxOutcome(outcome *TxOutcome) {
+	nm.txBuilder.HandleTxOutcome(outcome)
+}
+
+// GetContractTransactions - retrieves a page of the transactions sent to a contract, newest first, with the
+// timestamp between after and before, both inclusive. A zero after or before leaves that end open
+func (nm *NetworkManager) GetContractTransactions(contract string, after int64, before int64, from int, size int) ([]*data.APITransaction, error) {
+	params := url.Values{}
+	params.Set("receiver", contract)
+	if after > 0 {
+		params.Set("after", strconv.FormatInt(after, 10))
+	}
+	if before > 0 {
+		params.Set("before", strconv.FormatInt(before, 10))
+	}
+
+	return nm.getTransactions(params, from, size)
+}
+
+func (nm *NetworkManager) getTransactions(params url.Values, from int, size int) ([]*data.APITransaction, error) {
+	ctx, cancel := newRequestContext()
+	defer cancel()
+
+	params.Set("from", strconv.Itoa(from))
+	params.Set("size", strconv.Itoa(size))
+	params.Set("withScResults", "true")
+
+	list := make([]*data.APITransaction, 0)
+	err := nm.apiClient.GetJSON(ctx, "/transactions?"+params.Encode(), &list)
 	if err != nil {
+		log.Error("can not get transactions", "sender", params.Get("sender"), "receiver", params.Get("receiver"),
+			"error", err)
 		return nil, err
 	}
+
+	return list, nil
+}
+
+func (nm *NetworkManager) queryScIntResult(scAddress, funcName string, args []string) (*big.Int, error) {
+	query := &data.ScQuery{
+		ScAddress: scAddress,
+		FuncName:  funcName,
+		Args:      args,
+	}
+
+	return nm.executeIntQuery(query)
+}
+
+func (nm *NetworkManager) executeIntQuery(query *data.ScQuery) (*big.Int, error) {
+	ctx, cancel := newRequestContext()
+	defer cancel()
+
 	res := &data.ScIntResult{}
-	err = json.Unmarshal(bytes, res)
+	err := nm.proxyClient.PostJSON(ctx, "/vm-values/int", query, res)
 	if err != nil {
 		return nil, err
 	}
 	intRes, ok := big.NewInt(0).SetString(res.Data.Data, 10)
 	if !ok {
-		return nil, errors.New("invalid result: " + res.Error)
+		return nil, errors.New("invalid result: " + res.Data.Data)
 	}
 
 	return intRes, nil
@@ -170,17 +396,16 @@ func (nm *NetworkManager) queryScQueryResult(scAddress, funcName string, args []
 		FuncName:  funcName,
 		Args:      args,
 	}
-	host := fmt.Sprintf("%s/vm-values/query", nm.networkProxy)
-	body, err := json.Marshal(query)
-	if err != nil {
-		return nil, err
-	}
-	bytes, err := utils.PostHTTP(host, string(body))
-	if err != nil {
-		return nil, err
-	}
+
+	return nm.executeQuery(query)
+}
+
+func (nm *NetworkManager) executeQuery(query *data.ScQuery) ([][]byte, error) {
+	ctx, cancel := newRequestContext()
+	defer cancel()
+
 	res := &data.ScQueryResult{}
-	err = json.Unmarshal(bytes, res)
+	err := nm.proxyClient.PostJSON(ctx, "/vm-values/query", query, res)
 	if err != nil {
 		return nil, err
 	}
@@ -189,31 +414,52 @@ func (nm *NetworkManager) queryScQueryResult(scAddress, funcName string, args []
 }
 
 // CreateDSSC - sends a create DSSC transaction
-func (nm *NetworkManager) CreateDSSC(privateKey string) (string, error) {
-	privateKeyBytes, _ := hex.DecodeString(privateKey)
-	address, _ := erdgo.GetAddressFromPrivateKey(privateKeyBytes)
-	account, err := nm.Proxy.GetAccount(address)
+func (nm *NetworkManager) CreateDSSC(privateKey []byte) (string, error) {
+	tx := CreateDelegationContractTx(data.NewAmount(nil), 0)
+	tx.Receiver = nm.systemSCs.DelegationManager
+
+	return nm.txBuilder.Send(privateKey, tx)
+}
+
+// GetAllContractAddresses - retrieves the addresses of all the contracts created by the delegation manager
+func (nm *NetworkManager) GetAllContractAddresses() ([]string, error) {
+	query := &data.ScQuery{
+		ScAddress: nm.systemSCs.DelegationManager,
+		FuncName:  "getAllContractAddresses",
+		Args:      make([]string, 0),
+		Caller:    nm.systemSCs.DelegationManager,
+	}
+	returnData, err := nm.executeQuery(query)
 	if err != nil {
-		return "", err
+		log.Error("can not get delegation contracts", "error", err)
+		return nil, err
 	}
 
-	tx := &erdgo.Transaction{
-		Nonce:    account.Nonce,
-		RcvAddr:  "erd1qqqqqqqqqqqqqqqpqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqylllslmq6y6",
-		SndAddr:  address,
-		Value:    "1250000000000000000000",
-		GasPrice: 1000000000,
-		GasLimit: 60000000,
-		ChainID:  nm.networkConfig.Data.Config.ErdChainID,
-		Version:  1,
-		Data:     []byte("createNewDelegationContract@00@00"),
+	addresses := make([]string, 0, len(returnData))
+	for _, pubkey := range returnData {
+		address, err := erdgo.PubkeyToBech32(pubkey)
+		if err != nil {
+			log.Warn("invalid delegation contract address", "pubkey", hex.EncodeToString(pubkey))
+			continue
+		}
+		addresses = append(addresses, address)
 	}
-	err = erdgo.SignTransaction(tx, privateKeyBytes)
+
+	return addresses, nil
+}
+
+// GetContractOwner - retrieves the owner of a delegation contract from its config
+func (nm *NetworkManager) GetContractOwner(contract string) (string, error) {
+	query, err := nm.queryScQueryResult(contract, "getContractConfig", make([]string, 0))
 	if err != nil {
 		return "", err
 	}
 
-	return nm.Proxy.SendTransaction(tx)
+	if len(query) != 9 {
+		return "", errors.New("invalid response")
+	}
+
+	return erdgo.PubkeyToBech32(query[0])
 }
 
 // GetContractInfo - retrieves details about the DSSC
@@ -228,29 +474,25 @@ func (nm *NetworkManager) GetContractInfo(address string) (*data.ContractInfo, e
 		return nil, errors.New("invalid response")
 	}
 
+	ownerAddress, err := erdgo.PubkeyToBech32(query[0])
+	if err != nil {
+		log.Warn("invalid contract owner", "error", err)
+	}
+
 	iServiceFee := big.NewInt(0).SetBytes(query[1])
 	fServiceFee := big.NewFloat(0).SetInt(iServiceFee)
 	fServiceFee.Quo(fServiceFee, big.NewFloat(100))
 	serviceFee, _ := fServiceFee.Float64()
 
-	iMaxDelegationCap := big.NewInt(0).SetBytes(query[2])
-	fMaxDelegationCap := big.NewFloat(0).SetInt(iMaxDelegationCap)
-	fMaxDelegationCap.Quo(fMaxDelegationCap, nm.fDenomination)
-	maxDelegationCap, _ := fMaxDelegationCap.Float64()
-
-	iInitialOwnerFunds := big.NewInt(0).SetBytes(query[3])
-	fInitialOwnerFunds := big.NewFloat(0).SetInt(iInitialOwnerFunds)
-	fInitialOwnerFunds.Quo(fInitialOwnerFunds, nm.fDenomination)
-	initialOwnerFunds, _ := fInitialOwnerFunds.Float64()
-
 	iCreatedNonce := big.NewInt(0).SetBytes(query[7])
 
 	iUnBondPeriod := big.NewInt(0).SetBytes(query[8])
 
 	info := &data.ContractInfo{
+		OwnerAddress:         ownerAddress,
 		ServiceFee:           serviceFee,
-		MaxDelegationCap:     maxDelegationCap,
-		InitialOwnerFunds:    initialOwnerFunds,
+		MaxDelegationCap:     data.NewAmountFromBytes(query[2]),
+		InitialOwnerFunds:    data.NewAmountFromBytes(query[3]),
 		AutomaticActivation:  string(query[4]) == "true",
 		WithDelegationCap:    string(query[5]) == "true",
 		ChangeableServiceFee: string(query[6]) == "true",
@@ -261,74 +503,91 @@ func (nm *NetworkManager) GetContractInfo(address string) (*data.ContractInfo, e
 	return info, nil
 }
 
-func (nm *NetworkManager) getScIntNoArgs(fnc string) (*big.Float, error) {
-	i, err := nm.queryScIntResult(utils.ContractAddress, fnc, make([]string, 0))
+// GetContractMetadata - retrieves the staking provider name, website and keybase identity of a DSSC
+func (nm *NetworkManager) GetContractMetadata(address string) (*data.ContractMetadata, error) {
+	query, err := nm.queryScQueryResult(address, "getContractMetadata", make([]string, 0))
 	if err != nil {
-		log.Error("can not get SC int result", "error", err)
+		log.Error("can not get contract metadata", "error", err)
 		return nil, err
 	}
 
-	f := big.NewFloat(0).SetInt(i)
-	f.Quo(f, nm.fDenomination)
+	if len(query) != 3 {
+		return nil, errors.New("invalid response")
+	}
+
+	metadata := &data.ContractMetadata{
+		Name:       string(query[0]),
+		Website:    string(query[1]),
+		Identifier: string(query[2]),
+	}
+
+	return metadata, nil
+}
+
+func (nm *NetworkManager) getScIntNoArgs(fnc string) (*data.Amount, error) {
+	i, err := nm.queryScIntResult(nm.contractAddress(), fnc, make([]string, 0))
+	if err != nil {
+		log.Error("can not get SC int result", "error", err)
+		return nil, err
+	}
 
-	return f, nil
+	return data.NewAmount(i), nil
 }
 
 // GetTotalActiveStake - retrieves the total active stake from the DSSC
-func (nm *NetworkManager) GetTotalActiveStake() (*big.Float, error) {
-	return nm.getScIntNoArgs("getTotalActiveStake")
+func (nm *NetworkManager) GetTotalActiveStake() (*data.Amount, error) {
+	return nm.GetContractTotalActiveStake(nm.contractAddress())
+}
+
+// GetContractTotalActiveStake - retrieves the total active stake of any delegation contract
+func (nm *NetworkManager) GetContractTotalActiveStake(address string) (*data.Amount, error) {
+	i, err := nm.queryScIntResult(address, "getTotalActiveStake", make([]string, 0))
+	if err != nil {
+		log.Error("can not get total active stake", "contract", address, "error", err)
+		return nil, err
+	}
+
+	return data.NewAmount(i), nil
 }
 
 // GetTotalUnStaked - retrieves the total active stake from the DSSC
-func (nm *NetworkManager) GetTotalUnStaked() (*big.Float, error) {
+func (nm *NetworkManager) GetTotalUnStaked() (*data.Amount, error) {
 	return nm.getScIntNoArgs("getTotalUnStaked")
 }
 
 // GetTotalCumulatedRewards - retrieves the total cumulated rewards from the DSSC
-func (nm *NetworkManager) GetTotalCumulatedRewards() (*big.Float, error) {
+func (nm *NetworkManager) GetTotalCumulatedRewards() (*data.Amount, error) {
 	query := &data.ScQuery{
-		ScAddress: utils.ContractAddress,
+		ScAddress: nm.contractAddress(),
 		FuncName:  "getTotalCumulatedRewards",
-		Caller:    "erd1qqqqqqqqqqqqqqqpqqqqqqqqlllllllllllllllllllllllllllsr9gav8",
+		Caller:    nm.systemSCs.EndOfEpoch,
 	}
-	host := fmt.Sprintf("%s/vm-values/int", nm.networkProxy)
-	body, err := json.Marshal(query)
-	if err != nil {
-		return nil, err
-	}
-	bytes, err := utils.PostHTTP(host, string(body))
-	if err != nil {
-		return nil, err
-	}
-	res := &data.ScIntResult{}
-	err = json.Unmarshal(bytes, res)
+	intRes, err := nm.executeIntQuery(query)
 	if err != nil {
 		return nil, err
 	}
-	intRes, ok := big.NewInt(0).SetString(res.Data.Data, 10)
-	if !ok {
-		return nil, errors.New("invalid result: " + res.Error)
-	}
-
-	f := big.NewFloat(0).SetInt(intRes)
-	f.Quo(f, nm.fDenomination)
 
-	return f, nil
+	return data.NewAmount(intRes), nil
 }
 
 // GetTotalUnStakedFromNodes - retrieves the total unstaked from nodes from the DSSC
-func (nm *NetworkManager) GetTotalUnStakedFromNodes() (*big.Float, error) {
+func (nm *NetworkManager) GetTotalUnStakedFromNodes() (*data.Amount, error) {
 	return nm.getScIntNoArgs("getTotalUnStakedFromNodes")
 }
 
 // GetTotalUnBondedFromNodes - retrieves the total unbonded from nodes from the DSSC
-func (nm *NetworkManager) GetTotalUnBondedFromNodes() (*big.Float, error) {
+func (nm *NetworkManager) GetTotalUnBondedFromNodes() (*data.Amount, error) {
 	return nm.getScIntNoArgs("getTotalUnBondedFromNodes")
 }
 
 // GetNumUsers - retrieves the number of delegators from the DSSC
 func (nm *NetworkManager) GetNumUsers() (uint64, error) {
-	iUsers, err := nm.queryScIntResult(utils.ContractAddress, "getNumUsers", make([]string, 0))
+	return nm.GetContractNumUsers(nm.contractAddress())
+}
+
+// GetContractNumUsers - retrieves the number of delegators of any delegation contract
+func (nm *NetworkManager) GetContractNumUsers(address string) (uint64, error) {
+	iUsers, err := nm.queryScIntResult(address, "getNumUsers", make([]string, 0))
 	if err != nil {
 		return 0, err
 	}
@@ -338,7 +597,7 @@ func (nm *NetworkManager) GetNumUsers() (uint64, error) {
 
 // GetNumNodes - retrieves the number of nodes from the DSSC
 func (nm *NetworkManager) GetNumNodes() (uint64, error) {
-	iNodes, err := nm.queryScIntResult(utils.ContractAddress, "getNumNodes", make([]string, 0))
+	iNodes, err := nm.queryScIntResult(nm.contractAddress(), "getNumNodes", make([]string, 0))
 	if err != nil {
 		return 0, err
 	}
@@ -347,10 +606,10 @@ func (nm *NetworkManager) GetNumNodes() (uint64, error) {
 }
 
 // GetUserUnDelegatedList - retrieves an address' undelegated list from the DSSC
-func (nm *NetworkManager) GetUserUnDelegatedList(address string) ([][]byte, error) {
+func (nm *NetworkManager) GetUserUnDelegatedList(address string) ([]*data.UnDelegatedFund, error) {
 	pubkey, _ := erdgo.Bech32ToPubkey(address)
 	hexAddress := hex.EncodeToString(pubkey)
-	query, err := nm.queryScQueryResult(utils.ContractAddress, "getUserUnDelegatedList", []string{hexAddress})
+	query, err := nm.queryScQueryResult(nm.contractAddress(), "getUserUnDelegatedList", []string{hexAddress})
 	if err != nil {
 		log.Error("can not get user undelegated list", "error", err)
 		return nil, err
@@ -360,16 +619,95 @@ func (nm *NetworkManager) GetUserUnDelegatedList(address string) ([][]byte, erro
 		return nil, errors.New("invalid response")
 	}
 
-	return query, nil
+	list := make([]*data.UnDelegatedFund, 0, len(query)/2)
+	for i := 0; i < len(query); i += 2 {
+		fund := &data.UnDelegatedFund{
+			Amount:          data.NewAmountFromBytes(query[i]),
+			RemainingRounds: big.NewInt(0).SetBytes(query[i+1]).Uint64(),
+		}
+		list = append(list, fund)
+	}
+
+	return list, nil
 }
 
-// GetAllNodeStates - retrieves all nodes states list from the DSSC
-func (nm *NetworkManager) GetAllNodeStates() ([][]byte, error) {
-	query, err := nm.queryScQueryResult(utils.ContractAddress, "getAllNodeStates", make([]string, 0))
+// GetAllNodeStates - retrieves the contract's nodes and their states from the DSSC. The view returns
+// each state label followed by the BLS keys in that state
+func (nm *NetworkManager) GetAllNodeStates() ([]*data.NodeInfo, error) {
+	query, err := nm.queryScQueryResult(nm.contractAddress(), "getAllNodeStates", make([]string, 0))
 	if err != nil {
 		log.Error("can not get nodes states list", "error", err)
 		return nil, err
 	}
 
-	return query, nil
+	return parseNodeStates(query), nil
+}
+
+func parseNodeStates(list [][]byte) []*data.NodeInfo {
+	nodes := make([]*data.NodeInfo, 0, len(list))
+	state := "unknown"
+	for _, entry := range list {
+		if len(entry) != blsKeyLength {
+			state = string(entry)
+			continue
+		}
+
+		nodes = append(nodes, &data.NodeInfo{
+			BlsKey:    entry,
+			DSSCState: state,
+		})
+	}
+
+	return nodes
+}
+
+// GetBlsKeysStatus - retrieves from the validator system SC the status of the BLS keys registered by an
+// address, indexed by hex BLS key
+func (nm *NetworkManager) GetBlsKeysStatus(address string) (map[string]string, error) {
+	pubkey, err := erdgo.Bech32ToPubkey(address)
+	if err != nil {
+		return nil, err
+	}
+
+	query := &data.ScQuery{
+		ScAddress: nm.systemSCs.Validator,
+		FuncName:  "getBlsKeysStatus",
+		Args:      []string{hex.EncodeToString(pubkey)},
+		Caller:    nm.systemSCs.Validator,
+	}
+	returnData, err := nm.executeQuery(query)
+	if err != nil {
+		log.Error("can not get BLS keys status", "error", err)
+		return nil, err
+	}
+
+	if len(returnData)%2 != 0 {
+		return nil, errors.New("invalid response")
+	}
+
+	statuses := make(map[string]string)
+	for i := 0; i < len(returnData); i += 2 {
+		statuses[hex.EncodeToString(returnData[i])] = string(returnData[i+1])
+	}
+
+	return statuses, nil
+}
+
+// GetValidatorStatistics - retrieves the rating and the leader / validator success counts of all
+// the network's validators, indexed by hex BLS key
+func (nm *NetworkManager) GetValidatorStatistics() (map[string]*data.ValidatorStatistics, error) {
+	ctx, cancel := newRequestContext()
+	defer cancel()
+
+	res := &data.ValidatorStatisticsResponse{}
+	err := nm.proxyClient.GetJSON(ctx, "/validator/statistics", res)
+	if err != nil {
+		log.Error("can not get validator statistics", "error", err)
+		return nil, err
+	}
+	if res.Data.Statistics == nil {
+		return make(map[string]*data.ValidatorStatistics), nil
+	}
+
+	return res.Data.Statistics, nil
 }
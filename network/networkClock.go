@@ -0,0 +1,200 @@
+package network
+
+import (
+	"sync"
+	"time"
+
+	"github.com/DrDelphi/ElrondDSSC/data"
+)
+
+// MetachainShardID - the shard ID of the metachain, where the system smart contracts live
+const MetachainShardID = uint32(0xFFFFFFFF)
+
+const networkClockPollInterval = time.Second * 30
+
+// NetworkClock - follows the current epoch, round and nonce of a shard and converts
+// rounds and epochs into durations and wall-clock timestamps
+type NetworkClock struct {
+	reader        NetworkStatusReader
+	shard         uint32
+	roundDuration time.Duration
+
+	status      *data.NetworkStatus
+	fetchedAt   time.Time
+	handlers    []func(epoch uint32)
+	mut         sync.RWMutex
+	startedOnce sync.Once
+}
+
+// NewNetworkClock - creates a new NetworkClock object for a shard, using the network config's round duration
+func NewNetworkClock(reader NetworkStatusReader, networkConfig *data.NetworkConfig, shard uint32) *NetworkClock {
+	roundDuration := time.Duration(networkConfig.Data.Config.ErdRoundDuration) * time.Millisecond
+	if roundDuration <= 0 {
+		roundDuration = time.Second * 6
+	}
+
+	return &NetworkClock{
+		reader:        reader,
+		shard:         shard,
+		roundDuration: roundDuration,
+		handlers:      make([]func(epoch uint32), 0),
+	}
+}
+
+// OnEpochChange - registers a handler called with the new epoch every time the epoch changes
+func (nc *NetworkClock) OnEpochChange(handler func(epoch uint32)) {
+	nc.mut.Lock()
+	nc.handlers = append(nc.handlers, handler)
+	nc.mut.Unlock()
+}
+
+// Start - reads the network status right away and then polls it periodically
+func (nc *NetworkClock) Start() {
+	nc.startedOnce.Do(func() {
+		nc.refresh()
+		go func() {
+			for {
+				time.Sleep(networkClockPollInterval)
+				nc.refresh()
+			}
+		}()
+	})
+}
+
+func (nc *NetworkClock) refresh() {
+	status, err := nc.reader.GetNetworkStatus(nc.shard)
+	if err != nil {
+		log.Warn("can not get network status", "shard", nc.shard, "error", err)
+		return
+	}
+
+	nc.mut.Lock()
+	oldStatus := nc.status
+	nc.status = status
+	nc.fetchedAt = time.Now()
+	handlers := make([]func(epoch uint32), len(nc.handlers))
+	copy(handlers, nc.handlers)
+	nc.mut.Unlock()
+
+	epoch := status.Data.Status.ErdEpochNumber
+	if oldStatus == nil || oldStatus.Data.Status.ErdEpochNumber == epoch {
+		return
+	}
+
+	log.Info("new epoch", "epoch", epoch, "round", status.Data.Status.ErdCurrentRound)
+	for _, handler := range handlers {
+		handler(epoch)
+	}
+}
+
+// Synced - returns true once the network status was read at least once
+func (nc *NetworkClock) Synced() bool {
+	nc.mut.RLock()
+	defer nc.mut.RUnlock()
+
+	return nc.status != nil
+}
+
+// RoundDuration - returns the duration of a round
+func (nc *NetworkClock) RoundDuration() time.Duration {
+	return nc.roundDuration
+}
+
+// CurrentRound - returns the current round, extrapolated from the last read status
+func (nc *NetworkClock) CurrentRound() uint64 {
+	nc.mut.RLock()
+	defer nc.mut.RUnlock()
+
+	return nc.currentRound()
+}
+
+func (nc *NetworkClock) currentRound() uint64 {
+	if nc.status == nil {
+		return 0
+	}
+
+	return nc.status.Data.Status.ErdCurrentRound + uint64(time.Since(nc.fetchedAt)/nc.roundDuration)
+}
+
+// CurrentEpoch - returns the current epoch
+func (nc *NetworkClock) CurrentEpoch() uint32 {
+	return nc.EpochAtRound(nc.CurrentRound())
+}
+
+// CurrentNonce - returns the last read block nonce
+func (nc *NetworkClock) CurrentNonce() uint64 {
+	nc.mut.RLock()
+	defer nc.mut.RUnlock()
+
+	if nc.status == nil {
+		return 0
+	}
+
+	return nc.status.Data.Status.ErdNonce
+}
+
+// RoundsDuration - converts a number of rounds into a duration
+func (nc *NetworkClock) RoundsDuration(rounds uint64) time.Duration {
+	return time.Duration(rounds) * nc.roundDuration
+}
+
+// EpochsDuration - converts a number of epochs into a duration
+func (nc *NetworkClock) EpochsDuration(epochs uint64) time.Duration {
+	return nc.RoundsDuration(epochs * nc.RoundsPerEpoch())
+}
+
+// RoundsPerEpoch - returns the number of rounds in an epoch, or 0 if not synced yet
+func (nc *NetworkClock) RoundsPerEpoch() uint64 {
+	nc.mut.RLock()
+	defer nc.mut.RUnlock()
+
+	if nc.status == nil {
+		return 0
+	}
+
+	return nc.status.Data.Status.ErdRoundsPerEpoch
+}
+
+// RoundTime - returns the wall-clock time of a round
+func (nc *NetworkClock) RoundTime(round uint64) time.Time {
+	nc.mut.RLock()
+	defer nc.mut.RUnlock()
+
+	if nc.status == nil {
+		return time.Now()
+	}
+
+	current := nc.status.Data.Status.ErdCurrentRound
+	if round >= current {
+		return nc.fetchedAt.Add(time.Duration(round-current) * nc.roundDuration)
+	}
+
+	return nc.fetchedAt.Add(-time.Duration(current-round) * nc.roundDuration)
+}
+
+// TimeAfterRounds - returns the wall-clock time when the given number of rounds will have passed
+func (nc *NetworkClock) TimeAfterRounds(rounds uint64) time.Time {
+	return time.Now().Add(nc.RoundsDuration(rounds))
+}
+
+// EpochAtRound - returns the epoch a round belongs to, assuming the current epoch length
+func (nc *NetworkClock) EpochAtRound(round uint64) uint32 {
+	nc.mut.RLock()
+	defer nc.mut.RUnlock()
+
+	if nc.status == nil {
+		return 0
+	}
+
+	status := nc.status.Data.Status
+	if round < status.ErdRoundAtEpochStart || status.ErdRoundsPerEpoch == 0 {
+		return status.ErdEpochNumber
+	}
+
+	return status.ErdEpochNumber + uint32((round-status.ErdRoundAtEpochStart)/status.ErdRoundsPerEpoch)
+}
+
+// EpochAfterRounds - returns the epoch in which the given number of rounds will have passed
+func (nc *NetworkClock) EpochAfterRounds(rounds uint64) uint32 {
+	return nc.EpochAtRound(nc.CurrentRound() + rounds)
+}
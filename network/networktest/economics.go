@@ -0,0 +1,88 @@
+package networktest
+
+import (
+	"github.com/DrDelphi/ElrondDSSC/data"
+)
+
+// APRTolerance - the maximum difference accepted between a computed APR and the fixture's expected one
+const APRTolerance = 0.0001
+
+// EconomicsFixture - fixed network economics and contract values together with the APR expected from
+// network.ComputeAPR
+type EconomicsFixture struct {
+	Name             string
+	Metrics          *data.EconomicsMetrics
+	StakedNodes      uint64
+	TotalActiveStake *data.Amount
+	ServiceFee       float64
+	EpochsPerYear    float64
+	GrossAPR         float64
+	NetAPR           float64
+}
+
+// EconomicsFixtures - returns the APR estimation fixtures. Each call returns new objects
+func EconomicsFixtures() []*EconomicsFixture {
+	return []*EconomicsFixture{
+		{
+			Name:             "mainnet-like, provider with top-up",
+			Metrics:          NewEconomicsMetrics("5348", "12000000", "4500000"),
+			StakedNodes:      10,
+			TotalActiveStake: data.MustParseAmount("40000"),
+			ServiceFee:       10,
+			EpochsPerYear:    365,
+			GrossAPR:         16.266833,
+			NetAPR:           14.640150,
+		},
+		{
+			Name:             "mainnet-like, provider without top-up",
+			Metrics:          NewEconomicsMetrics("5348", "12000000", "4500000"),
+			StakedNodes:      3,
+			TotalActiveStake: data.MustParseAmount("7500"),
+			ServiceFee:       5,
+			EpochsPerYear:    365,
+			GrossAPR:         21.955892,
+			NetAPR:           20.858097,
+		},
+		{
+			Name:             "network without top-up",
+			Metrics:          NewEconomicsMetrics("5348", "7500000", "0"),
+			StakedNodes:      4,
+			TotalActiveStake: data.MustParseAmount("10000"),
+			ServiceFee:       0,
+			EpochsPerYear:    365,
+			GrossAPR:         26.026933,
+			NetAPR:           26.026933,
+		},
+		{
+			Name:             "six hours epochs",
+			Metrics:          NewEconomicsMetrics("6000", "10000000", "2000000"),
+			StakedNodes:      20,
+			TotalActiveStake: data.MustParseAmount("100000"),
+			ServiceFee:       12.5,
+			EpochsPerYear:    365 * 4,
+			GrossAPR:         70.121093,
+			NetAPR:           61.355957,
+		},
+		{
+			Name:             "no staked nodes",
+			Metrics:          NewEconomicsMetrics("5348", "12000000", "4500000"),
+			StakedNodes:      0,
+			TotalActiveStake: data.MustParseAmount("1000"),
+			ServiceFee:       10,
+			EpochsPerYear:    365,
+			GrossAPR:         0,
+			NetAPR:           0,
+		},
+	}
+}
+
+// NewEconomicsMetrics - returns economics metrics from eGLD amounts. It panics on invalid amounts
+func NewEconomicsMetrics(inflation, totalStakedValue, totalTopUpValue string) *data.EconomicsMetrics {
+	return &data.EconomicsMetrics{
+		TotalSupply:      data.MustParseAmount("20000000"),
+		TotalStakedValue: data.MustParseAmount(totalStakedValue),
+		TotalTopUpValue:  data.MustParseAmount(totalTopUpValue),
+		Inflation:        data.MustParseAmount(inflation),
+		DevRewards:       data.NewAmount(nil),
+	}
+}
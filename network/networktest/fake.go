@@ -0,0 +1,787 @@
+// Package networktest provides an in-memory implementation of the network interfaces,
+// meant for exercising the bot flows without an Elrond proxy
+package networktest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/DrDelphi/ElrondDSSC/data"
+	"github.com/DrDelphi/ElrondDSSC/network"
+	"github.com/ElrondNetwork/elrond-sdk/erdgo"
+)
+
+var (
+	errAccountNotFound     = errors.New("account not found")
+	errTransactionNotFound = errors.New("transaction not found")
+)
+
+// UserState - holds a delegator's balances inside the fake contract
+type UserState struct {
+	ActiveStake      *data.Amount
+	UnBondable       *data.Amount
+	UnStakedValue    *data.Amount
+	ClaimableRewards *data.Amount
+	CumulatedRewards *data.Amount
+	UnDelegated      []*data.UnDelegatedFund
+}
+
+// Totals - holds the contract wide values of the fake contract
+type Totals struct {
+	TotalActiveStake       *data.Amount
+	TotalUnStaked          *data.Amount
+	TotalCumulatedRewards  *data.Amount
+	TotalUnStakedFromNodes *data.Amount
+	TotalUnBondedFromNodes *data.Amount
+	NumUsers               uint64
+	NumNodes               uint64
+}
+
+type nodeState struct {
+	state string
+	keys  [][]byte
+}
+
+// Fake - configurable in-memory implementation of network.DelegationContract, network.ContractResolver,
+// network.ProviderReader, network.ValidatorReader, network.NetworkStatusReader, network.EconomicsReader,
+// network.AccountReader, network.TransactionReader, network.TransactionLister, network.ContractTransactionLister,
+// network.TransactionSimulator and network.TransactionSender. All methods are safe for concurrent use
+type Fake struct {
+	mut sync.RWMutex
+
+	accounts     map[string]*erdgo.Account
+	users        map[string]*UserState
+	contractInfo map[string]*data.ContractInfo
+	metadata     map[string]*data.ContractMetadata
+	contracts    []string
+	owners       map[string]string
+	totals       Totals
+	stakes       map[string]*data.Amount
+	numUsers     map[string]uint64
+	nodes        []*nodeState
+	blsStatus    map[string]string
+	statistics   map[string]*data.ValidatorStatistics
+	lastTxs      map[string][]*data.APITransaction
+	apiTxs       []*data.APITransaction
+	txResults    map[string]*data.TransactionOnNetwork
+	status       *data.NetworkStatus
+	economics    *data.EconomicsMetrics
+	simulations  map[string]*data.TxSimulation
+	sent         []*erdgo.Transaction
+	errs         map[string]error
+}
+
+// NewFake - creates a new, empty, Fake object
+func NewFake() *Fake {
+	return &Fake{
+		accounts:     make(map[string]*erdgo.Account),
+		users:        make(map[string]*UserState),
+		contractInfo: make(map[string]*data.ContractInfo),
+		metadata:     make(map[string]*data.ContractMetadata),
+		contracts:    make([]string, 0),
+		owners:       make(map[string]string),
+		stakes:       make(map[string]*data.Amount),
+		numUsers:     make(map[string]uint64),
+		nodes:        make([]*nodeState, 0),
+		blsStatus:    make(map[string]string),
+		statistics:   make(map[string]*data.ValidatorStatistics),
+		lastTxs:      make(map[string][]*data.APITransaction),
+		txResults:    make(map[string]*data.TransactionOnNetwork),
+		simulations:  make(map[string]*data.TxSimulation),
+		sent:         make([]*erdgo.Transaction, 0),
+		errs:         make(map[string]error),
+	}
+}
+
+// SetError - makes the method with the given name (e.g. "GetUserActiveStake") fail with err.
+// A nil err restores the normal behavior
+func (f *Fake) SetError(method string, err error) {
+	f.mut.Lock()
+	defer f.mut.Unlock()
+
+	if err == nil {
+		delete(f.errs, method)
+		return
+	}
+	f.errs[method] = err
+}
+
+// SetAccount - sets an account's balance and nonce
+func (f *Fake) SetAccount(address string, balance *data.Amount, nonce uint64) {
+	f.mut.Lock()
+	defer f.mut.Unlock()
+
+	f.accounts[address] = &erdgo.Account{
+		Address: address,
+		Nonce:   nonce,
+		Balance: balance.AttoString(),
+	}
+}
+
+// SetUser - sets a delegator's balances inside the contract
+func (f *Fake) SetUser(address string, state *UserState) {
+	f.mut.Lock()
+	defer f.mut.Unlock()
+
+	f.users[address] = state
+}
+
+// SetContractInfo - sets the configuration returned for the contract at address
+func (f *Fake) SetContractInfo(address string, info *data.ContractInfo) {
+	f.mut.Lock()
+	defer f.mut.Unlock()
+
+	f.contractInfo[address] = info
+}
+
+// SetContractMetadata - sets the metadata returned for a contract
+func (f *Fake) SetContractMetadata(address string, metadata *data.ContractMetadata) {
+	f.mut.Lock()
+	defer f.mut.Unlock()
+
+	f.metadata[address] = metadata
+}
+
+// AddContract - adds a delegation contract to the ones known by the delegation manager
+func (f *Fake) AddContract(address string, owner string) {
+	f.mut.Lock()
+	defer f.mut.Unlock()
+
+	f.contracts = append(f.contracts, address)
+	f.owners[address] = owner
+}
+
+// SetNetworkEconomics - sets the metrics returned by GetNetworkEconomics
+func (f *Fake) SetNetworkEconomics(metrics *data.EconomicsMetrics) {
+	f.mut.Lock()
+	defer f.mut.Unlock()
+
+	f.economics = metrics
+}
+
+// SetContractTotals - sets the total active stake and number of delegators of a contract, as
+// returned by GetContractTotalActiveStake and GetContractNumUsers
+func (f *Fake) SetContractTotals(address string, totalActiveStake *data.Amount, numUsers uint64) {
+	f.mut.Lock()
+	defer f.mut.Unlock()
+
+	f.stakes[address] = totalActiveStake
+	f.numUsers[address] = numUsers
+}
+
+// SetNetworkStatus - sets the status returned by GetNetworkStatus, for all shards
+func (f *Fake) SetNetworkStatus(status *data.NetworkStatus) {
+	f.mut.Lock()
+	defer f.mut.Unlock()
+
+	f.status = status
+}
+
+// SetTotals - sets the contract wide values
+func (f *Fake) SetTotals(totals Totals) {
+	f.mut.Lock()
+	defer f.mut.Unlock()
+
+	f.totals = totals
+}
+
+// AddNode - adds a BLS key in the given DSSC state (e.g. "staked", "notStaked")
+func (f *Fake) AddNode(state string, blsKey []byte) {
+	f.mut.Lock()
+	defer f.mut.Unlock()
+
+	for _, n := range f.nodes {
+		if n.state == state {
+			n.keys = append(n.keys, blsKey)
+			return
+		}
+	}
+	f.nodes = append(f.nodes, &nodeState{state: state, keys: [][]byte{blsKey}})
+}
+
+// SetLastTxs - sets the transactions returned by GetLastTxs for an address
+func (f *Fake) SetLastTxs(address string, txs []*data.APITransaction) {
+	f.mut.Lock()
+	defer f.mut.Unlock()
+
+	f.lastTxs[address] = txs
+}
+
+// SetTransactions - sets the transactions, newest first, paged through by GetTransactions
+func (f *Fake) SetTransactions(txs []*data.APITransaction) {
+	f.mut.Lock()
+	defer f.mut.Unlock()
+
+	f.apiTxs = txs
+}
+
+// SetSimulation - sets the outcome returned by SimulateTransaction for the transactions calling a function.
+// The Sender and GasLimit fields are filled in from the simulated transaction
+func (f *Fake) SetSimulation(function string, simulation *data.TxSimulation) {
+	f.mut.Lock()
+	defer f.mut.Unlock()
+
+	f.simulations[function] = simulation
+}
+
+// SentTransactions - returns the transactions broadcasted so far
+func (f *Fake) SentTransactions() []*erdgo.Transaction {
+	f.mut.RLock()
+	defer f.mut.RUnlock()
+
+	sent := make([]*erdgo.Transaction, len(f.sent))
+	copy(sent, f.sent)
+
+	return sent
+}
+
+func (f *Fake) getError(method string) error {
+	f.mut.RLock()
+	defer f.mut.RUnlock()
+
+	return f.errs[method]
+}
+
+func (f *Fake) getUser(method string, address string) (*UserState, error) {
+	if err := f.getError(method); err != nil {
+		return nil, err
+	}
+
+	f.mut.RLock()
+	defer f.mut.RUnlock()
+
+	user, ok := f.users[address]
+	if !ok {
+		return &UserState{}, nil
+	}
+
+	return user, nil
+}
+
+func (f *Fake) getTotal(method string, value func(t *Totals) *data.Amount) (*data.Amount, error) {
+	if err := f.getError(method); err != nil {
+		return nil, err
+	}
+
+	f.mut.RLock()
+	defer f.mut.RUnlock()
+
+	return data.NewAmount(value(&f.totals).Int()), nil
+}
+
+// GetUserActiveStake -
+func (f *Fake) GetUserActiveStake(address string) (*data.Amount, error) {
+	user, err := f.getUser("GetUserActiveStake", address)
+	if err != nil {
+		return nil, err
+	}
+
+	return data.NewAmount(user.ActiveStake.Int()), nil
+}
+
+// GetUserUnBondable -
+func (f *Fake) GetUserUnBondable(address string) (*data.Amount, error) {
+	user, err := f.getUser("GetUserUnBondable", address)
+	if err != nil {
+		return nil, err
+	}
+
+	return data.NewAmount(user.UnBondable.Int()), nil
+}
+
+// GetUserUnStakedValue -
+func (f *Fake) GetUserUnStakedValue(address string) (*data.Amount, error) {
+	user, err := f.getUser("GetUserUnStakedValue", address)
+	if err != nil {
+		return nil, err
+	}
+
+	return data.NewAmount(user.UnStakedValue.Int()), nil
+}
+
+// GetClaimableRewards -
+func (f *Fake) GetClaimableRewards(address string) (*data.Amount, error) {
+	user, err := f.getUser("GetClaimableRewards", address)
+	if err != nil {
+		return nil, err
+	}
+
+	return data.NewAmount(user.ClaimableRewards.Int()), nil
+}
+
+// GetUserCumulatedRewards -
+func (f *Fake) GetUserCumulatedRewards(address string) (*data.Amount, error) {
+	user, err := f.getUser("GetUserCumulatedRewards", address)
+	if err != nil {
+		return nil, err
+	}
+
+	return data.NewAmount(user.CumulatedRewards.Int()), nil
+}
+
+// GetUserUnDelegatedList -
+func (f *Fake) GetUserUnDelegatedList(address string) ([]*data.UnDelegatedFund, error) {
+	user, err := f.getUser("GetUserUnDelegatedList", address)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*data.UnDelegatedFund, len(user.UnDelegated))
+	copy(list, user.UnDelegated)
+
+	return list, nil
+}
+
+// GetContractInfo -
+func (f *Fake) GetContractInfo(address string) (*data.ContractInfo, error) {
+	if err := f.getError("GetContractInfo"); err != nil {
+		return nil, err
+	}
+
+	f.mut.RLock()
+	defer f.mut.RUnlock()
+
+	info, ok := f.contractInfo[address]
+	if !ok {
+		return nil, errors.New("invalid response")
+	}
+	infoCopy := *info
+
+	return &infoCopy, nil
+}
+
+// GetContractMetadata - returns the metadata set for a contract, or an empty one
+func (f *Fake) GetContractMetadata(address string) (*data.ContractMetadata, error) {
+	if err := f.getError("GetContractMetadata"); err != nil {
+		return nil, err
+	}
+
+	f.mut.RLock()
+	defer f.mut.RUnlock()
+
+	metadata, ok := f.metadata[address]
+	if !ok {
+		return &data.ContractMetadata{}, nil
+	}
+	metadataCopy := *metadata
+
+	return &metadataCopy, nil
+}
+
+// GetTotalActiveStake -
+func (f *Fake) GetTotalActiveStake() (*data.Amount, error) {
+	return f.getTotal("GetTotalActiveStake", func(t *Totals) *data.Amount { return t.TotalActiveStake })
+}
+
+// GetContractTotalActiveStake - returns the stake set with SetContractTotals, or zero
+func (f *Fake) GetContractTotalActiveStake(address string) (*data.Amount, error) {
+	if err := f.getError("GetContractTotalActiveStake"); err != nil {
+		return nil, err
+	}
+
+	f.mut.RLock()
+	defer f.mut.RUnlock()
+
+	return data.NewAmount(f.stakes[address].Int()), nil
+}
+
+// GetContractNumUsers - returns the number of delegators set with SetContractTotals, or zero
+func (f *Fake) GetContractNumUsers(address string) (uint64, error) {
+	if err := f.getError("GetContractNumUsers"); err != nil {
+		return 0, err
+	}
+
+	f.mut.RLock()
+	defer f.mut.RUnlock()
+
+	return f.numUsers[address], nil
+}
+
+// GetTotalUnStaked -
+func (f *Fake) GetTotalUnStaked() (*data.Amount, error) {
+	return f.getTotal("GetTotalUnStaked", func(t *Totals) *data.Amount { return t.TotalUnStaked })
+}
+
+// GetTotalCumulatedRewards -
+func (f *Fake) GetTotalCumulatedRewards() (*data.Amount, error) {
+	return f.getTotal("GetTotalCumulatedRewards", func(t *Totals) *data.Amount { return t.TotalCumulatedRewards })
+}
+
+// GetTotalUnStakedFromNodes -
+func (f *Fake) GetTotalUnStakedFromNodes() (*data.Amount, error) {
+	return f.getTotal("GetTotalUnStakedFromNodes", func(t *Totals) *data.Amount { return t.TotalUnStakedFromNodes })
+}
+
+// GetTotalUnBondedFromNodes -
+func (f *Fake) GetTotalUnBondedFromNodes() (*data.Amount, error) {
+	return f.getTotal("GetTotalUnBondedFromNodes", func(t *Totals) *data.Amount { return t.TotalUnBondedFromNodes })
+}
+
+// GetNumUsers -
+func (f *Fake) GetNumUsers() (uint64, error) {
+	if err := f.getError("GetNumUsers"); err != nil {
+		return 0, err
+	}
+
+	f.mut.RLock()
+	defer f.mut.RUnlock()
+
+	return f.totals.NumUsers, nil
+}
+
+// GetNumNodes -
+func (f *Fake) GetNumNodes() (uint64, error) {
+	if err := f.getError("GetNumNodes"); err != nil {
+		return 0, err
+	}
+
+	f.mut.RLock()
+	defer f.mut.RUnlock()
+
+	return f.totals.NumNodes, nil
+}
+
+// GetAllNodeStates - returns the nodes added with AddNode, grouped by state
+func (f *Fake) GetAllNodeStates() ([]*data.NodeInfo, error) {
+	if err := f.getError("GetAllNodeStates"); err != nil {
+		return nil, err
+	}
+
+	f.mut.RLock()
+	defer f.mut.RUnlock()
+
+	nodes := make([]*data.NodeInfo, 0)
+	for _, n := range f.nodes {
+		for _, key := range n.keys {
+			nodes = append(nodes, &data.NodeInfo{BlsKey: key, DSSCState: n.state})
+		}
+	}
+
+	return nodes, nil
+}
+
+// SetBlsKeyStatus - sets the validator system SC status (e.g. "staked", "jailed") of a BLS key
+func (f *Fake) SetBlsKeyStatus(blsKey []byte, status string) {
+	f.mut.Lock()
+	defer f.mut.Unlock()
+
+	f.blsStatus[hex.EncodeToString(blsKey)] = status
+}
+
+// GetBlsKeysStatus - returns the statuses set with SetBlsKeyStatus. The address is ignored
+func (f *Fake) GetBlsKeysStatus(_ string) (map[string]string, error) {
+	if err := f.getError("GetBlsKeysStatus"); err != nil {
+		return nil, err
+	}
+
+	f.mut.RLock()
+	defer f.mut.RUnlock()
+
+	statuses := make(map[string]string, len(f.blsStatus))
+	for key, status := range f.blsStatus {
+		statuses[key] = status
+	}
+
+	return statuses, nil
+}
+
+// SetValidatorStatistics - sets the validator statistics of a BLS key
+func (f *Fake) SetValidatorStatistics(blsKey []byte, statistics *data.ValidatorStatistics) {
+	f.mut.Lock()
+	defer f.mut.Unlock()
+
+	f.statistics[hex.EncodeToString(blsKey)] = statistics
+}
+
+// GetValidatorStatistics - returns the statistics set with SetValidatorStatistics
+func (f *Fake) GetValidatorStatistics() (map[string]*data.ValidatorStatistics, error) {
+	if err := f.getError("GetValidatorStatistics"); err != nil {
+		return nil, err
+	}
+
+	f.mut.RLock()
+	defer f.mut.RUnlock()
+
+	statistics := make(map[string]*data.ValidatorStatistics, len(f.statistics))
+	for key, stats := range f.statistics {
+		statistics[key] = stats
+	}
+
+	return statistics, nil
+}
+
+// GetAllContractAddresses - returns the contracts added with AddContract
+func (f *Fake) GetAllContractAddresses() ([]string, error) {
+	if err := f.getError("GetAllContractAddresses"); err != nil {
+		return nil, err
+	}
+
+	f.mut.RLock()
+	defer f.mut.RUnlock()
+
+	contracts := make([]string, len(f.contracts))
+	copy(contracts, f.contracts)
+
+	return contracts, nil
+}
+
+// GetContractOwner - returns the owner of a contract added with AddContract
+func (f *Fake) GetContractOwner(contract string) (string, error) {
+	if err := f.getError("GetContractOwner"); err != nil {
+		return "", err
+	}
+
+	f.mut.RLock()
+	defer f.mut.RUnlock()
+
+	owner, ok := f.owners[contract]
+	if !ok {
+		return "", errAccountNotFound
+	}
+
+	return owner, nil
+}
+
+// GetNetworkStatus - returns the status set with SetNetworkStatus
+func (f *Fake) GetNetworkStatus(_ uint32) (*data.NetworkStatus, error) {
+	if err := f.getError("GetNetworkStatus"); err != nil {
+		return nil, err
+	}
+
+	f.mut.RLock()
+	defer f.mut.RUnlock()
+
+	if f.status == nil {
+		return nil, errors.New("network status not set")
+	}
+
+	return f.status, nil
+}
+
+// GetNetworkEconomics - returns the metrics set with SetNetworkEconomics
+func (f *Fake) GetNetworkEconomics() (*data.EconomicsMetrics, error) {
+	if err := f.getError("GetNetworkEconomics"); err != nil {
+		return nil, err
+	}
+
+	f.mut.RLock()
+	defer f.mut.RUnlock()
+
+	if f.economics == nil {
+		return nil, errors.New("network economics not set")
+	}
+
+	return f.economics, nil
+}
+
+// GetAccount -
+func (f *Fake) GetAccount(address string) (*erdgo.Account, error) {
+	if err := f.getError("GetAccount"); err != nil {
+		return nil, err
+	}
+
+	f.mut.RLock()
+	defer f.mut.RUnlock()
+
+	account, ok := f.accounts[address]
+	if !ok {
+		return nil, errAccountNotFound
+	}
+	accountCopy := *account
+
+	return &accountCopy, nil
+}
+
+// GetLastTxs -
+func (f *Fake) GetLastTxs(address string, size int, _ string) ([]*data.APITransaction, error) {
+	if err := f.getError("GetLastTxs"); err != nil {
+		return nil, err
+	}
+
+	f.mut.RLock()
+	defer f.mut.RUnlock()
+
+	txs := f.lastTxs[address]
+	if len(txs) > size {
+		txs = txs[:size]
+	}
+	list := make([]*data.APITransaction, len(txs))
+	copy(list, txs)
+
+	return list, nil
+}
+
+// GetTransactions - returns a page of the transactions set with SetTransactions matching sender and receiver
+func (f *Fake) GetTransactions(sender string, receiver string, from int, size int) ([]*data.APITransaction, error) {
+	if err := f.getError("GetTransactions"); err != nil {
+		return nil, err
+	}
+
+	f.mut.RLock()
+	defer f.mut.RUnlock()
+
+	list := make([]*data.APITransaction, 0)
+	for _, tx := range f.apiTxs {
+		if (sender == "" || tx.Sender == sender) && (receiver == "" || tx.Receiver == receiver) {
+			list = append(list, tx)
+		}
+	}
+	if from >= len(list) {
+		return make([]*data.APITransaction, 0), nil
+	}
+	list = list[from:]
+	if size < len(list) {
+		list = list[:size]
+	}
+
+	return list, nil
+}
+
+// GetContractTransactions - returns a page of the transactions set with SetTransactions sent to the contract
+// within the timestamp range
+func (f *Fake) GetContractTransactions(contract string, after int64, before int64, from int, size int) ([]*data.APITransaction, error) {
+	if err := f.getError("GetContractTransactions"); err != nil {
+		return nil, err
+	}
+
+	f.mut.RLock()
+	defer f.mut.RUnlock()
+
+	list := make([]*data.APITransaction, 0)
+	for _, tx := range f.apiTxs {
+		if tx.Receiver != contract || tx.Timestamp < after || (before > 0 && tx.Timestamp > before) {
+			continue
+		}
+		list = append(list, tx)
+	}
+	if from >= len(list) {
+		return make([]*data.APITransaction, 0), nil
+	}
+	list = list[from:]
+	if size < len(list) {
+		list = list[:size]
+	}
+
+	return list, nil
+}
+
+// SimulateTransaction - returns the outcome set with SetSimulation or, if not set, a successful one
+// using the whole gas limit and no fee
+func (f *Fake) SimulateTransaction(sender string, req *network.TxRequest) (*data.TxSimulation, error) {
+	if err := f.getError("SimulateTransaction"); err != nil {
+		return nil, err
+	}
+
+	f.mut.RLock()
+	defer f.mut.RUnlock()
+
+	simulation := &data.TxSimulation{GasUnits: req.GasLimit, Fee: data.NewAmount(nil)}
+	if set, ok := f.simulations[req.Function]; ok {
+		copied := *set
+		simulation = &copied
+	}
+	simulation.Sender = sender
+	simulation.GasLimit = req.GasLimit
+
+	return simulation, nil
+}
+
+// SendTransaction - records the transaction and returns a deterministic hash
+func (f *Fake) SendTransaction(tx *erdgo.Transaction) (string, error) {
+	if err := f.getError("SendTransaction"); err != nil {
+		return "", err
+	}
+
+	f.mut.Lock()
+	defer f.mut.Unlock()
+
+	f.sent = append(f.sent, tx)
+	hash := sha256.Sum256([]byte(fmt.Sprintf("%s-%v-%v", tx.SndAddr, tx.Nonce, len(f.sent))))
+	txHash := hex.EncodeToString(hash[:])
+	f.txResults[txHash] = &data.TransactionOnNetwork{
+		Hash:     txHash,
+		Nonce:    tx.Nonce,
+		Value:    tx.Value,
+		Receiver: tx.RcvAddr,
+		Sender:   tx.SndAddr,
+		GasPrice: tx.GasPrice,
+		GasLimit: tx.GasLimit,
+		Data:     tx.Data,
+		Status:   "pending",
+	}
+
+	return txHash, nil
+}
+
+// SetTransaction - sets the details (status and smart contract results) returned for a transaction.
+// Transactions sent through the Fake are pending until set otherwise
+func (f *Fake) SetTransaction(tx *data.TransactionOnNetwork) {
+	f.mut.Lock()
+	defer f.mut.Unlock()
+
+	f.txResults[tx.Hash] = tx
+}
+
+// GetTransactionStatus - returns the status of a transaction sent or set with SetTransaction
+func (f *Fake) GetTransactionStatus(hash string) (string, error) {
+	tx, err := f.GetTransaction(hash)
+	if err != nil {
+		return "", err
+	}
+
+	return tx.Status, nil
+}
+
+// GetTransaction - returns the details of a transaction sent or set with SetTransaction
+func (f *Fake) GetTransaction(hash string) (*data.TransactionOnNetwork, error) {
+	if err := f.getError("GetTransaction"); err != nil {
+		return nil, err
+	}
+
+	f.mut.RLock()
+	defer f.mut.RUnlock()
+
+	tx, ok := f.txResults[hash]
+	if !ok {
+		return nil, errTransactionNotFound
+	}
+
+	return tx, nil
+}
+
+// CreateDSSC - records a create delegation contract transaction
+func (f *Fake) CreateDSSC(privateKey []byte) (string, error) {
+	if err := f.getError("CreateDSSC"); err != nil {
+		return "", err
+	}
+
+	address, err := erdgo.GetAddressFromPrivateKey(privateKey)
+	if err != nil {
+		return "", err
+	}
+
+	tx := &erdgo.Transaction{
+		RcvAddr: network.DelegationManagerAddress,
+		SndAddr: address,
+		Value:   network.CreationCost.AttoString(),
+		Data:    []byte(network.CreateDelegationContractTx(data.NewAmount(nil), 0).Data()),
+	}
+
+	return f.SendTransaction(tx)
+}
+
+var _ network.DelegationContract = (*Fake)(nil)
+var _ network.ContractResolver = (*Fake)(nil)
+var _ network.ProviderReader = (*Fake)(nil)
+var _ network.ValidatorReader = (*Fake)(nil)
+var _ network.NetworkStatusReader = (*Fake)(nil)
+var _ network.EconomicsReader = (*Fake)(nil)
+var _ network.AccountReader = (*Fake)(nil)
+var _ network.TransactionReader = (*Fake)(nil)
+var _ network.TransactionLister = (*Fake)(nil)
+var _ network.ContractTransactionLister = (*Fake)(nil)
+var _ network.TransactionSimulator = (*Fake)(nil)
+var _ network.TransactionSender = (*Fake)(nil)
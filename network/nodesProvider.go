@@ -0,0 +1,66 @@
+package network
+
+import (
+	"github.com/DrDelphi/ElrondDSSC/data"
+)
+
+// NodeNameStorer - defines the reading of the names given by the owner to the nodes
+type NodeNameStorer interface {
+	GetNodeNames() (map[string]string, error)
+}
+
+// NodesProvider - combines the DSSC node states with the validator system SC status, the
+// validator statistics and the owner-defined names into a single view of the contract's nodes
+type NodesProvider struct {
+	contract   DelegationContract
+	validators ValidatorReader
+	contracts  ContractAddressProvider
+	names      NodeNameStorer
+}
+
+// NewNodesProvider - creates a new NodesProvider object
+func NewNodesProvider(contract DelegationContract, validators ValidatorReader, contracts ContractAddressProvider,
+	names NodeNameStorer) *NodesProvider {
+	return &NodesProvider{
+		contract:   contract,
+		validators: validators,
+		contracts:  contracts,
+		names:      names,
+	}
+}
+
+// GetNodes - returns the contract's nodes. Only the DSSC node states are mandatory, the other
+// sources are skipped with a warning if they are not available
+func (np *NodesProvider) GetNodes() ([]*data.NodeInfo, error) {
+	nodes, err := np.contract.GetAllNodeStates()
+	if err != nil {
+		return nil, err
+	}
+
+	statuses, err := np.validators.GetBlsKeysStatus(np.contracts.ContractAddress())
+	if err != nil {
+		log.Warn("can not get BLS keys status", "error", err)
+		statuses = make(map[string]string)
+	}
+
+	statistics, err := np.validators.GetValidatorStatistics()
+	if err != nil {
+		log.Warn("can not get validator statistics", "error", err)
+		statistics = make(map[string]*data.ValidatorStatistics)
+	}
+
+	names, err := np.names.GetNodeNames()
+	if err != nil {
+		log.Warn("can not get node names", "error", err)
+		names = make(map[string]string)
+	}
+
+	for _, node := range nodes {
+		key := node.HexKey()
+		node.SCStatus = statuses[key]
+		node.Statistics = statistics[key]
+		node.Name = names[key]
+	}
+
+	return nodes, nil
+}
@@ -0,0 +1,245 @@
+package network
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/DrDelphi/ElrondDSSC/data"
+)
+
+// provider ranking criteria
+const (
+	ProviderSortFee          = "fee"
+	ProviderSortStake        = "stake"
+	ProviderSortDelegators   = "delegators"
+	ProviderSortRemainingCap = "cap"
+)
+
+const (
+	providerRefreshInterval = time.Hour
+	providerWorkers         = 4
+)
+
+// ProviderStorer - defines the persistence of the provider directory
+type ProviderStorer interface {
+	GetProviders() ([]*data.Provider, error)
+	SetProviders(providers []*data.Provider) error
+}
+
+// ProviderDirectory - keeps the list of all the delegation contracts on the network, refreshed
+// periodically and cached in the database
+type ProviderDirectory struct {
+	reader ProviderReader
+	storer ProviderStorer
+
+	providers   []*data.Provider
+	updatedAt   time.Time
+	mut         sync.RWMutex
+	refreshMut  sync.Mutex
+	startedOnce sync.Once
+}
+
+// NewProviderDirectory - creates a new ProviderDirectory object
+func NewProviderDirectory(reader ProviderReader, storer ProviderStorer) *ProviderDirectory {
+	return &ProviderDirectory{
+		reader:    reader,
+		storer:    storer,
+		providers: make([]*data.Provider, 0),
+	}
+}
+
+// Start - loads the cached directory and refreshes it every hour. The first refresh is done
+// right away if the cache is empty or older than the refresh interval
+func (pd *ProviderDirectory) Start() {
+	pd.startedOnce.Do(func() {
+		providers, err := pd.storer.GetProviders()
+		if err != nil {
+			log.Error("can not read providers", "error", err)
+		}
+		pd.setProviders(providers)
+
+		go func() {
+			wait := providerRefreshInterval - time.Since(pd.UpdatedAt())
+			for {
+				if wait > 0 {
+					time.Sleep(wait)
+				}
+				err := pd.Refresh()
+				if err != nil {
+					log.Warn("can not refresh provider directory", "error", err)
+				}
+				wait = providerRefreshInterval
+			}
+		}()
+	})
+}
+
+// Providers - returns the providers in the directory
+func (pd *ProviderDirectory) Providers() []*data.Provider {
+	pd.mut.RLock()
+	defer pd.mut.RUnlock()
+
+	providers := make([]*data.Provider, len(pd.providers))
+	copy(providers, pd.providers)
+
+	return providers
+}
+
+// UpdatedAt - returns the time of the last refresh, or the zero time if the directory is empty
+func (pd *ProviderDirectory) UpdatedAt() time.Time {
+	pd.mut.RLock()
+	defer pd.mut.RUnlock()
+
+	return pd.updatedAt
+}
+
+// Refresh - reads the details of all the delegation contracts and saves them in the database.
+// Contracts whose config can not be read are left out
+func (pd *ProviderDirectory) Refresh() error {
+	pd.refreshMut.Lock()
+	defer pd.refreshMut.Unlock()
+
+	addresses, err := pd.reader.GetAllContractAddresses()
+	if err != nil {
+		return err
+	}
+
+	jobs := make(chan string)
+	results := make(chan *data.Provider, len(addresses))
+	wg := sync.WaitGroup{}
+	for i := 0; i < providerWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for address := range jobs {
+				results <- pd.readProvider(address)
+			}
+		}()
+	}
+	for _, address := range addresses {
+		jobs <- address
+	}
+	close(jobs)
+	wg.Wait()
+	close(results)
+
+	providers := make([]*data.Provider, 0, len(addresses))
+	for provider := range results {
+		if provider != nil {
+			providers = append(providers, provider)
+		}
+	}
+	if len(providers) == 0 && len(addresses) > 0 {
+		return errors.New("no provider could be read")
+	}
+
+	err = pd.storer.SetProviders(providers)
+	if err != nil {
+		log.Error("can not save providers", "error", err)
+	}
+	pd.setProviders(providers)
+	log.Debug("provider directory refreshed", "contracts", len(addresses), "providers", len(providers))
+
+	return nil
+}
+
+func (pd *ProviderDirectory) readProvider(address string) *data.Provider {
+	info, err := pd.reader.GetContractInfo(address)
+	if err != nil {
+		log.Warn("can not read provider config", "contract", address, "error", err)
+		return nil
+	}
+
+	stake, err := pd.reader.GetContractTotalActiveStake(address)
+	if err != nil {
+		log.Warn("can not read provider stake", "contract", address, "error", err)
+		return nil
+	}
+
+	numUsers, err := pd.reader.GetContractNumUsers(address)
+	if err != nil {
+		log.Warn("can not read provider delegators", "contract", address, "error", err)
+		return nil
+	}
+
+	provider := &data.Provider{
+		Address:          address,
+		OwnerAddress:     info.OwnerAddress,
+		ServiceFee:       info.ServiceFee,
+		MaxDelegationCap: info.MaxDelegationCap,
+		TotalActiveStake: stake,
+		NumUsers:         numUsers,
+		UpdatedAt:        time.Now().Unix(),
+	}
+	if !info.WithDelegationCap {
+		provider.MaxDelegationCap = data.NewAmount(nil)
+	}
+
+	// older contracts have no metadata
+	metadata, err := pd.reader.GetContractMetadata(address)
+	if err == nil {
+		provider.Name = metadata.Name
+		provider.Website = metadata.Website
+		provider.Identifier = metadata.Identifier
+	}
+
+	return provider
+}
+
+func (pd *ProviderDirectory) setProviders(providers []*data.Provider) {
+	updatedAt := time.Time{}
+	for _, provider := range providers {
+		t := time.Unix(provider.UpdatedAt, 0)
+		if updatedAt.IsZero() || t.Before(updatedAt) {
+			updatedAt = t
+		}
+	}
+
+	pd.mut.Lock()
+	pd.providers = providers
+	pd.updatedAt = updatedAt
+	pd.mut.Unlock()
+}
+
+// RankProviders - returns a copy of the providers sorted by one of the ProviderSort criteria: ascending
+// by service fee, descending by stake, delegators or remaining cap. Uncapped contracts rank first by
+// remaining cap. Ties are broken by stake
+func RankProviders(providers []*data.Provider, by string) []*data.Provider {
+	ranked := make([]*data.Provider, len(providers))
+	copy(ranked, providers)
+
+	byStake := func(a, b *data.Provider) bool {
+		cmp := a.TotalActiveStake.Cmp(b.TotalActiveStake)
+		if cmp != 0 {
+			return cmp > 0
+		}
+		return a.Address < b.Address
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		a, b := ranked[i], ranked[j]
+		switch by {
+		case ProviderSortFee:
+			if a.ServiceFee != b.ServiceFee {
+				return a.ServiceFee < b.ServiceFee
+			}
+		case ProviderSortDelegators:
+			if a.NumUsers != b.NumUsers {
+				return a.NumUsers > b.NumUsers
+			}
+		case ProviderSortRemainingCap:
+			if a.Capped() != b.Capped() {
+				return !a.Capped()
+			}
+			cmp := a.RemainingCap().Cmp(b.RemainingCap())
+			if cmp != 0 {
+				return cmp > 0
+			}
+		}
+		return byStake(a, b)
+	})
+
+	return ranked
+}
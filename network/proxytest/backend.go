@@ -0,0 +1,422 @@
+package proxytest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/DrDelphi/ElrondDSSC/data"
+	"github.com/ElrondNetwork/elrond-sdk/erdgo"
+)
+
+// DefaultChainID - chain ID reported by the StaticBackend unless changed
+const DefaultChainID = "local-testnet"
+
+// DefaultRoundsPerEpoch - epoch length reported by the StaticBackend unless a network status is set
+const DefaultRoundsPerEpoch = 14400
+
+// Backend - answers the proxy and API requests which are not served from a cassette
+type Backend interface {
+	GetNetworkConfig() *data.NetworkConfig
+	GetNetworkStatus(shard uint32) (*data.NetworkStatus, error)
+	GetNetworkEconomics() (*data.EconomicsMetrics, error)
+	GetAccount(address string) (*erdgo.Account, error)
+	ExecuteQuery(query *data.ScQuery) ([][]byte, error)
+	SendTransaction(tx *erdgo.Transaction) (string, error)
+	EstimateTransactionCost(tx *erdgo.Transaction) (*data.TransactionCost, error)
+	GetTransactions(params url.Values) ([]*data.APITransaction, error)
+	GetTransaction(hash string) (*data.TransactionOnNetwork, error)
+	GetValidatorStatistics() (map[string]*data.ValidatorStatistics, error)
+}
+
+// StaticBackend - in-memory Backend serving values set by the caller
+type StaticBackend struct {
+	mut sync.RWMutex
+
+	networkConfig *data.NetworkConfig
+	networkStatus *data.NetworkStatus
+	economics     *data.EconomicsMetrics
+	accounts      map[string]*erdgo.Account
+	queries       map[string][][]byte
+	transactions  []*data.APITransaction
+	statistics    map[string]*data.ValidatorStatistics
+	costs         map[string]*data.TransactionCost
+	sent          []*erdgo.Transaction
+}
+
+// NewStaticBackend - creates a new StaticBackend with a local testnet network config
+func NewStaticBackend() *StaticBackend {
+	return &StaticBackend{
+		networkConfig: NewNetworkConfig(DefaultChainID),
+		accounts:      make(map[string]*erdgo.Account),
+		queries:       make(map[string][][]byte),
+		transactions:  make([]*data.APITransaction, 0),
+		statistics:    make(map[string]*data.ValidatorStatistics),
+		costs:         make(map[string]*data.TransactionCost),
+		sent:          make([]*erdgo.Transaction, 0),
+	}
+}
+
+// NewNetworkConfig - returns a network config with the usual Elrond values and the provided chain ID
+func NewNetworkConfig(chainID string) *data.NetworkConfig {
+	cfg := &data.NetworkConfig{Code: "successful"}
+	cfg.Data.Config.ErdChainID = chainID
+	cfg.Data.Config.ErdDenomination = data.Denomination
+	cfg.Data.Config.ErdGasPerDataByte = 1500
+	cfg.Data.Config.ErdGasPriceModifier = "0.01"
+	cfg.Data.Config.ErdMinGasLimit = 50000
+	cfg.Data.Config.ErdMinGasPrice = 1000000000
+	cfg.Data.Config.ErdMinTransactionVersion = 1
+	cfg.Data.Config.ErdNumShardsWithoutMeta = 1
+	cfg.Data.Config.ErdRoundDuration = 6000
+	cfg.Data.Config.ErdStartTime = time.Now().Unix()
+
+	return cfg
+}
+
+// SetNetworkConfig - sets the network config served on /network/config
+func (sb *StaticBackend) SetNetworkConfig(cfg *data.NetworkConfig) {
+	sb.mut.Lock()
+	defer sb.mut.Unlock()
+
+	sb.networkConfig = cfg
+}
+
+// SetNetworkStatus - sets the status served on /network/status/{shard}, for all shards.
+// Unless set, the status is computed from the network config's start time and round duration
+func (sb *StaticBackend) SetNetworkStatus(status *data.NetworkStatus) {
+	sb.mut.Lock()
+	defer sb.mut.Unlock()
+
+	sb.networkStatus = status
+}
+
+// SetNetworkEconomics - sets the metrics served on /network/economics
+func (sb *StaticBackend) SetNetworkEconomics(metrics *data.EconomicsMetrics) {
+	sb.mut.Lock()
+	defer sb.mut.Unlock()
+
+	sb.economics = metrics
+}
+
+// SetAccount - sets an account served on /address/{address}
+func (sb *StaticBackend) SetAccount(address string, balance *data.Amount, nonce uint64) {
+	sb.mut.Lock()
+	defer sb.mut.Unlock()
+
+	sb.accounts[address] = &erdgo.Account{
+		Address: address,
+		Nonce:   nonce,
+		Balance: balance.AttoString(),
+	}
+}
+
+// SetQueryResult - sets the return data of a smart contract view. A nil args
+// matches the view called with any arguments
+func (sb *StaticBackend) SetQueryResult(scAddress, funcName string, args []string, returnData [][]byte) {
+	sb.mut.Lock()
+	defer sb.mut.Unlock()
+
+	sb.queries[queryKey(scAddress, funcName, args)] = returnData
+}
+
+// SetTransactions - sets the transactions served by the API on /transactions
+func (sb *StaticBackend) SetTransactions(txs []*data.APITransaction) {
+	sb.mut.Lock()
+	defer sb.mut.Unlock()
+
+	sb.transactions = txs
+}
+
+// SetTransactionCost - sets the answer of /transaction/cost for the transactions calling a function
+func (sb *StaticBackend) SetTransactionCost(function string, cost *data.TransactionCost) {
+	sb.mut.Lock()
+	defer sb.mut.Unlock()
+
+	sb.costs[function] = cost
+}
+
+// SetValidatorStatistics - sets the statistics served on /validator/statistics for a hex BLS key
+func (sb *StaticBackend) SetValidatorStatistics(blsKey string, statistics *data.ValidatorStatistics) {
+	sb.mut.Lock()
+	defer sb.mut.Unlock()
+
+	sb.statistics[blsKey] = statistics
+}
+
+// SentTransactions - returns the transactions received on /transaction/send
+func (sb *StaticBackend) SentTransactions() []*erdgo.Transaction {
+	sb.mut.RLock()
+	defer sb.mut.RUnlock()
+
+	sent := make([]*erdgo.Transaction, len(sb.sent))
+	copy(sent, sb.sent)
+
+	return sent
+}
+
+// GetNetworkConfig -
+func (sb *StaticBackend) GetNetworkConfig() *data.NetworkConfig {
+	sb.mut.RLock()
+	defer sb.mut.RUnlock()
+
+	return sb.networkConfig
+}
+
+// GetNetworkStatus - returns the status set with SetNetworkStatus or one computed from the network config
+func (sb *StaticBackend) GetNetworkStatus(_ uint32) (*data.NetworkStatus, error) {
+	sb.mut.RLock()
+	defer sb.mut.RUnlock()
+
+	if sb.networkStatus != nil {
+		return sb.networkStatus, nil
+	}
+
+	cfg := sb.networkConfig.Data.Config
+	round := uint64(0)
+	if cfg.ErdRoundDuration > 0 {
+		elapsed := time.Now().Unix() - cfg.ErdStartTime
+		if elapsed > 0 {
+			round = uint64(elapsed * 1000 / cfg.ErdRoundDuration)
+		}
+	}
+
+	return NewNetworkStatus(round, DefaultRoundsPerEpoch), nil
+}
+
+// NewNetworkStatus - returns the network status of a chain started at round 0 with fixed length epochs
+func NewNetworkStatus(round uint64, roundsPerEpoch uint64) *data.NetworkStatus {
+	status := &data.NetworkStatus{Code: "successful"}
+	s := &status.Data.Status
+	s.ErdCurrentRound = round
+	s.ErdNonce = round
+	s.ErdHighestFinalNonce = round
+	s.ErdRoundsPerEpoch = roundsPerEpoch
+	s.ErdEpochNumber = uint32(round / roundsPerEpoch)
+	s.ErdRoundAtEpochStart = round - round%roundsPerEpoch
+	s.ErdNonceAtEpochStart = s.ErdRoundAtEpochStart
+	s.ErdRoundsPassedInCurrentEpoch = round % roundsPerEpoch
+	s.ErdNoncesPassedInCurrentEpoch = s.ErdRoundsPassedInCurrentEpoch
+
+	return status
+}
+
+// GetNetworkEconomics - returns the metrics set with SetNetworkEconomics
+func (sb *StaticBackend) GetNetworkEconomics() (*data.EconomicsMetrics, error) {
+	sb.mut.RLock()
+	defer sb.mut.RUnlock()
+
+	if sb.economics == nil {
+		return nil, errors.New("network economics not set")
+	}
+
+	return sb.economics, nil
+}
+
+// GetAccount - returns the account set for address or an empty account
+func (sb *StaticBackend) GetAccount(address string) (*erdgo.Account, error) {
+	if !erdgo.IsValidBech32Address(address) {
+		return nil, errors.New("invalid address")
+	}
+
+	sb.mut.RLock()
+	defer sb.mut.RUnlock()
+
+	account, ok := sb.accounts[address]
+	if !ok {
+		return &erdgo.Account{Address: address, Balance: "0"}, nil
+	}
+
+	return account, nil
+}
+
+// ExecuteQuery -
+func (sb *StaticBackend) ExecuteQuery(query *data.ScQuery) ([][]byte, error) {
+	sb.mut.RLock()
+	defer sb.mut.RUnlock()
+
+	returnData, ok := sb.queries[queryKey(query.ScAddress, query.FuncName, query.Args)]
+	if ok {
+		return returnData, nil
+	}
+	returnData, ok = sb.queries[queryKey(query.ScAddress, query.FuncName, nil)]
+	if ok {
+		return returnData, nil
+	}
+
+	return nil, fmt.Errorf("function %s not found", query.FuncName)
+}
+
+// SendTransaction - records the transaction and returns a deterministic hash
+func (sb *StaticBackend) SendTransaction(tx *erdgo.Transaction) (string, error) {
+	sb.mut.Lock()
+	defer sb.mut.Unlock()
+
+	sb.sent = append(sb.sent, tx)
+
+	return TxHash(tx), nil
+}
+
+// GetTransactions - returns the transactions matching the sender / receiver filters and the from / size window
+func (sb *StaticBackend) GetTransactions(params url.Values) ([]*data.APITransaction, error) {
+	sb.mut.RLock()
+	defer sb.mut.RUnlock()
+
+	return FilterTransactions(sb.transactions, params), nil
+}
+
+// GetTransaction - returns a transaction received on /transaction/send or set with SetTransactions.
+// Sent transactions are reported as successful
+func (sb *StaticBackend) GetTransaction(hash string) (*data.TransactionOnNetwork, error) {
+	sb.mut.RLock()
+	defer sb.mut.RUnlock()
+
+	for _, tx := range sb.transactions {
+		if tx.Hash == hash {
+			return NewTransactionOnNetwork(tx), nil
+		}
+	}
+	for _, tx := range sb.sent {
+		if TxHash(tx) == hash {
+			return &data.TransactionOnNetwork{
+				Hash:     hash,
+				Nonce:    tx.Nonce,
+				Value:    tx.Value,
+				Receiver: tx.RcvAddr,
+				Sender:   tx.SndAddr,
+				GasPrice: tx.GasPrice,
+				GasLimit: tx.GasLimit,
+				Data:     tx.Data,
+				Status:   "success",
+			}, nil
+		}
+	}
+
+	return nil, errors.New("transaction not found")
+}
+
+// EstimateTransactionCost - returns the cost set with SetTransactionCost for the called function or, if
+// not set, the move balance cost of the transaction
+func (sb *StaticBackend) EstimateTransactionCost(tx *erdgo.Transaction) (*data.TransactionCost, error) {
+	sb.mut.RLock()
+	defer sb.mut.RUnlock()
+
+	function := strings.Split(string(tx.Data), "@")[0]
+	if cost, ok := sb.costs[function]; ok {
+		return cost, nil
+	}
+
+	cfg := sb.networkConfig.Data.Config
+	gas := cfg.ErdMinGasLimit + cfg.ErdGasPerDataByte*uint64(len(tx.Data))
+
+	return &data.TransactionCost{TxGasUnits: gas}, nil
+}
+
+// GetValidatorStatistics - returns the statistics set with SetValidatorStatistics
+func (sb *StaticBackend) GetValidatorStatistics() (map[string]*data.ValidatorStatistics, error) {
+	sb.mut.RLock()
+	defer sb.mut.RUnlock()
+
+	statistics := make(map[string]*data.ValidatorStatistics, len(sb.statistics))
+	for key, stats := range sb.statistics {
+		statistics[key] = stats
+	}
+
+	return statistics, nil
+}
+
+// NewTransactionOnNetwork - converts an API transaction to the proxy's transaction details format
+func NewTransactionOnNetwork(tx *data.APITransaction) *data.TransactionOnNetwork {
+	scrs := make([]*data.SmartContractResult, 0, len(tx.Results))
+	for _, scr := range tx.Results {
+		scrs = append(scrs, &data.SmartContractResult{
+			Hash:          scr.Hash,
+			Nonce:         scr.Nonce,
+			Value:         scr.Value,
+			Receiver:      scr.Receiver,
+			Sender:        scr.Sender,
+			Data:          string(scr.Data),
+			ReturnMessage: scr.ReturnMessage,
+		})
+	}
+
+	return &data.TransactionOnNetwork{
+		Hash:                 tx.Hash,
+		Nonce:                tx.Nonce,
+		Value:                tx.Value,
+		Receiver:             tx.Receiver,
+		Sender:               tx.Sender,
+		GasPrice:             tx.GasPrice,
+		GasLimit:             tx.GasLimit,
+		Data:                 tx.Data,
+		Status:               tx.Status,
+		SmartContractResults: scrs,
+	}
+}
+
+// TxHash - computes a deterministic hash for a transaction received by a stand-in
+func TxHash(tx *erdgo.Transaction) string {
+	hash := sha256.Sum256([]byte(fmt.Sprintf("%s-%s-%v-%s", tx.SndAddr, tx.RcvAddr, tx.Nonce, tx.Signature)))
+
+	return hex.EncodeToString(hash[:])
+}
+
+// FilterTransactions - applies the API's sender, receiver, after, before, from and size parameters on a list
+// of transactions
+func FilterTransactions(txs []*data.APITransaction, params url.Values) []*data.APITransaction {
+	sender := params.Get("sender")
+	receiver := params.Get("receiver")
+	after := int64(parseUint(params.Get("after"), 0))
+	before := int64(parseUint(params.Get("before"), 0))
+	from := parseUint(params.Get("from"), 0)
+	size := parseUint(params.Get("size"), 25)
+
+	list := make([]*data.APITransaction, 0)
+	for _, tx := range txs {
+		if sender != "" && tx.Sender != sender {
+			continue
+		}
+		if receiver != "" && tx.Receiver != receiver {
+			continue
+		}
+		if tx.Timestamp < after || (before > 0 && tx.Timestamp > before) {
+			continue
+		}
+		list = append(list, tx)
+	}
+
+	if from >= len(list) {
+		return make([]*data.APITransaction, 0)
+	}
+	list = list[from:]
+	if size < len(list) {
+		list = list[:size]
+	}
+
+	return list
+}
+
+func parseUint(s string, defaultValue int) int {
+	var v int
+	_, err := fmt.Sscanf(s, "%d", &v)
+	if err != nil || v < 0 {
+		return defaultValue
+	}
+
+	return v
+}
+
+func queryKey(scAddress, funcName string, args []string) string {
+	if args == nil {
+		return scAddress + "|" + funcName + "|*"
+	}
+
+	return scAddress + "|" + funcName + "|" + strings.Join(args, "@")
+}
+
+var _ Backend = (*StaticBackend)(nil)
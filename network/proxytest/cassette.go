@@ -0,0 +1,128 @@
+package proxytest
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/url"
+	"sync"
+)
+
+// Interaction - holds a recorded request and the response received for it
+type Interaction struct {
+	Method       string `json:"method"`
+	Path         string `json:"path"`
+	RequestBody  string `json:"requestBody,omitempty"`
+	StatusCode   int    `json:"statusCode"`
+	ResponseBody string `json:"responseBody"`
+}
+
+// Cassette - holds a list of recorded interactions, saved as a JSON file
+type Cassette struct {
+	Interactions []*Interaction `json:"interactions"`
+
+	path   string
+	played map[string]int
+	mut    sync.Mutex
+}
+
+// NewCassette - creates a new, empty, cassette which will be saved at path
+func NewCassette(path string) *Cassette {
+	return &Cassette{
+		Interactions: make([]*Interaction, 0),
+		path:         path,
+		played:       make(map[string]int),
+	}
+}
+
+// LoadCassette - reads a cassette from the provided path
+func LoadCassette(path string) (*Cassette, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	cassette := NewCassette(path)
+	err = json.Unmarshal(content, cassette)
+	if err != nil {
+		return nil, err
+	}
+
+	return cassette, nil
+}
+
+// Save - writes the cassette in its file
+func (c *Cassette) Save() error {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	content, err := json.MarshalIndent(c, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(c.path, content, 0644)
+}
+
+// Record - appends an interaction to the cassette
+func (c *Cassette) Record(interaction *Interaction) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	interaction.Path = normalizePath(interaction.Path)
+	interaction.RequestBody = normalizeBody(interaction.RequestBody)
+	c.Interactions = append(c.Interactions, interaction)
+}
+
+// Find - returns the next recorded interaction matching the request. Interactions recorded
+// for the same request are played in order and the last one is repeated afterwards
+func (c *Cassette) Find(method, path, body string) (*Interaction, bool) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	path = normalizePath(path)
+	body = normalizeBody(body)
+	key := method + " " + path + " " + body
+
+	matches := make([]*Interaction, 0)
+	for _, i := range c.Interactions {
+		if i.Method == method && i.Path == path && i.RequestBody == body {
+			matches = append(matches, i)
+		}
+	}
+	if len(matches) == 0 {
+		return nil, false
+	}
+
+	idx := c.played[key]
+	if idx >= len(matches) {
+		idx = len(matches) - 1
+	}
+	c.played[key] = idx + 1
+
+	return matches[idx], true
+}
+
+// normalizePath - sorts the query parameters so that their order does not matter when matching
+func normalizePath(path string) string {
+	u, err := url.Parse(path)
+	if err != nil {
+		return path
+	}
+	if u.RawQuery == "" {
+		return u.Path
+	}
+
+	return u.Path + "?" + u.Query().Encode()
+}
+
+// normalizeBody - removes the insignificant whitespace from JSON bodies
+func normalizeBody(body string) string {
+	buff := &bytes.Buffer{}
+	err := json.Compact(buff, []byte(body))
+	if err != nil {
+		return body
+	}
+
+	return buff.String()
+}
@@ -0,0 +1,346 @@
+// Package proxytest provides a local stand-in for the Elrond proxy and API. It can serve
+// responses from an in-memory Backend, record the responses of a real proxy into a
+// cassette file, or replay a cassette offline
+package proxytest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/DrDelphi/ElrondDSSC/data"
+	logger "github.com/ElrondNetwork/elrond-go-logger"
+	"github.com/ElrondNetwork/elrond-sdk/erdgo"
+)
+
+var log = logger.GetOrCreate("proxytest")
+
+// Mode - defines how a Server answers the requests
+type Mode int
+
+const (
+	// ModeFake - requests are answered by the Backend
+	ModeFake Mode = iota
+	// ModeRecord - requests are forwarded to the upstream and the responses are saved in the cassette
+	ModeRecord
+	// ModeReplay - requests are answered from the cassette, falling back to the Backend if any
+	ModeReplay
+)
+
+// ParseMode - parses "fake", "record" or "replay"
+func ParseMode(s string) (Mode, error) {
+	switch strings.ToLower(s) {
+	case "fake":
+		return ModeFake, nil
+	case "record":
+		return ModeRecord, nil
+	case "replay":
+		return ModeReplay, nil
+	default:
+		return ModeFake, errors.New("unknown stand-in mode: " + s)
+	}
+}
+
+// Server - holds the required fields of a proxy / API stand-in server
+type Server struct {
+	mode     Mode
+	upstream string
+	cassette *Cassette
+	backend  Backend
+	client   *http.Client
+	server   *httptest.Server
+}
+
+// NewFakeServer - starts a server answering the requests from backend
+func NewFakeServer(backend Backend) *Server {
+	s := &Server{
+		mode:    ModeFake,
+		backend: backend,
+	}
+	s.server = httptest.NewServer(s)
+
+	return s
+}
+
+// NewRecordingServer - starts a server forwarding the requests to upstream and recording
+// the responses into a new cassette saved at cassettePath
+func NewRecordingServer(upstream string, cassettePath string) *Server {
+	s := &Server{
+		mode:     ModeRecord,
+		upstream: strings.TrimSuffix(upstream, "/"),
+		cassette: NewCassette(cassettePath),
+		client:   &http.Client{Timeout: time.Minute},
+	}
+	s.server = httptest.NewServer(s)
+
+	return s
+}
+
+// NewReplayServer - starts a server answering the requests from the cassette at cassettePath.
+// Requests missing from the cassette are answered by fallback, if not nil
+func NewReplayServer(cassettePath string, fallback Backend) (*Server, error) {
+	cassette, err := LoadCassette(cassettePath)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{
+		mode:     ModeReplay,
+		cassette: cassette,
+		backend:  fallback,
+	}
+	s.server = httptest.NewServer(s)
+
+	return s, nil
+}
+
+// URL - returns the base URL of the server, to be used instead of the proxy or API URL
+func (s *Server) URL() string {
+	return s.server.URL
+}
+
+// Close - stops the server. In record mode, it also saves the cassette
+func (s *Server) Close() error {
+	s.server.Close()
+	if s.mode == ModeRecord {
+		return s.cassette.Save()
+	}
+
+	return nil
+}
+
+// ServeHTTP - implements http.Handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	switch s.mode {
+	case ModeRecord:
+		s.record(w, r, body)
+		return
+	case ModeReplay:
+		interaction, ok := s.cassette.Find(r.Method, r.URL.RequestURI(), string(body))
+		if ok {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(interaction.StatusCode)
+			_, _ = w.Write([]byte(interaction.ResponseBody))
+			return
+		}
+		if s.backend == nil {
+			log.Warn("request not found in cassette", "method", r.Method, "path", r.URL.RequestURI())
+			writeError(w, http.StatusNotFound, errors.New("request not found in cassette"))
+			return
+		}
+	}
+
+	s.serveFromBackend(w, r, body)
+}
+
+func (s *Server) record(w http.ResponseWriter, r *http.Request, body []byte) {
+	req, err := http.NewRequest(r.Method, s.upstream+r.URL.RequestURI(), bytes.NewReader(body))
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		log.Warn("can not forward request to upstream", "path", r.URL.RequestURI(), "error", err)
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+
+	s.cassette.Record(&Interaction{
+		Method:       r.Method,
+		Path:         r.URL.RequestURI(),
+		RequestBody:  string(body),
+		StatusCode:   resp.StatusCode,
+		ResponseBody: string(respBody),
+	})
+	err = s.cassette.Save()
+	if err != nil {
+		log.Warn("can not save cassette", "error", err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(resp.StatusCode)
+	_, _ = w.Write(respBody)
+}
+
+func (s *Server) serveFromBackend(w http.ResponseWriter, r *http.Request, body []byte) {
+	path := r.URL.Path
+	switch {
+	case r.Method == http.MethodGet && path == "/network/config":
+		writeJSON(w, http.StatusOK, s.backend.GetNetworkConfig())
+
+	case r.Method == http.MethodGet && strings.HasPrefix(path, "/network/status/"):
+		shard, err := strconv.ParseUint(strings.TrimPrefix(path, "/network/status/"), 10, 32)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		status, err := s.backend.GetNetworkStatus(uint32(shard))
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, status)
+
+	case r.Method == http.MethodGet && path == "/network/economics":
+		metrics, err := s.backend.GetNetworkEconomics()
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		response := &data.NetworkEconomics{Code: "successful"}
+		response.Data.Metrics = *metrics
+		writeJSON(w, http.StatusOK, response)
+
+	case r.Method == http.MethodGet && strings.HasPrefix(path, "/address/"):
+		account, err := s.backend.GetAccount(strings.TrimPrefix(path, "/address/"))
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		response := &erdgo.AccountResponse{Code: "successful"}
+		response.Data.Account = account
+		writeJSON(w, http.StatusOK, response)
+
+	case r.Method == http.MethodPost && (path == "/vm-values/int" || path == "/vm-values/query"):
+		query := &data.ScQuery{}
+		err := json.Unmarshal(body, query)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		returnData, err := s.backend.ExecuteQuery(query)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		if path == "/vm-values/int" {
+			response := &data.ScIntResult{Code: "successful"}
+			response.Data.Data = "0"
+			if len(returnData) > 0 {
+				response.Data.Data = big.NewInt(0).SetBytes(returnData[0]).String()
+			}
+			writeJSON(w, http.StatusOK, response)
+			return
+		}
+		response := &data.ScQueryResult{Code: "successful"}
+		response.Data.Data.ReturnData = returnData
+		writeJSON(w, http.StatusOK, response)
+
+	case r.Method == http.MethodPost && path == "/transaction/send":
+		tx := &erdgo.Transaction{}
+		err := json.Unmarshal(body, tx)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		hash, err := s.backend.SendTransaction(tx)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		response := &erdgo.SendTransactionResponse{Code: "successful"}
+		response.Data.TxHash = hash
+		writeJSON(w, http.StatusOK, response)
+
+	case r.Method == http.MethodPost && path == "/transaction/cost":
+		tx := &erdgo.Transaction{}
+		err := json.Unmarshal(body, tx)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		cost, err := s.backend.EstimateTransactionCost(tx)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		response := &data.TransactionCostResponse{Data: *cost, Code: "successful"}
+		writeJSON(w, http.StatusOK, response)
+
+	case r.Method == http.MethodGet && strings.HasPrefix(path, "/transaction/"):
+		hash := strings.TrimPrefix(path, "/transaction/")
+		hash = strings.TrimSuffix(hash, "/status")
+		tx, err := s.backend.GetTransaction(hash)
+		if err != nil {
+			writeError(w, http.StatusNotFound, err)
+			return
+		}
+		if strings.HasSuffix(path, "/status") {
+			response := &data.TransactionStatusResponse{Code: "successful"}
+			response.Data.Status = tx.Status
+			writeJSON(w, http.StatusOK, response)
+			return
+		}
+		response := &data.TransactionResponse{Code: "successful"}
+		response.Data.Transaction = tx
+		writeJSON(w, http.StatusOK, response)
+
+	case r.Method == http.MethodGet && path == "/validator/statistics":
+		statistics, err := s.backend.GetValidatorStatistics()
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		response := &data.ValidatorStatisticsResponse{Code: "successful"}
+		response.Data.Statistics = statistics
+		writeJSON(w, http.StatusOK, response)
+
+	case r.Method == http.MethodGet && path == "/transactions":
+		txs, err := s.backend.GetTransactions(r.URL.Query())
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, txs)
+
+	default:
+		writeError(w, http.StatusNotFound, errors.New("endpoint not supported: "+path))
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	content, err := json.Marshal(v)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(content)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	content, _ := json.Marshal(map[string]interface{}{
+		"data":  nil,
+		"error": err.Error(),
+		"code":  "internal_issue",
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(content)
+}
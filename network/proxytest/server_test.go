@@ -0,0 +1,288 @@
+package proxytest_test
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/DrDelphi/ElrondDSSC/data"
+	"github.com/DrDelphi/ElrondDSSC/network"
+	"github.com/DrDelphi/ElrondDSSC/network/proxytest"
+	"github.com/ElrondNetwork/elrond-sdk/erdgo"
+)
+
+// request - a request sent to a stand-in
+type request struct {
+	method string
+	path   string
+	body   string
+}
+
+// response - the status and body a stand-in answered with
+type response struct {
+	status int
+	body   string
+}
+
+func testAddress(t *testing.T, b byte) string {
+	t.Helper()
+
+	address, err := erdgo.PubkeyToBech32(bytes.Repeat([]byte{b}, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return address
+}
+
+func fetch(t *testing.T, baseURL string, req request) response {
+	t.Helper()
+
+	httpReq, err := http.NewRequest(req.method, baseURL+req.path, strings.NewReader(req.body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.DefaultClient.Do(httpReq)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	content, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return response{status: resp.StatusCode, body: string(content)}
+}
+
+// countingUpstream - serves the backend through an httptest server, counting the requests
+func countingUpstream(t *testing.T, backend proxytest.Backend) (*httptest.Server, *int32) {
+	t.Helper()
+
+	fake := proxytest.NewFakeServer(backend)
+	t.Cleanup(func() {
+		_ = fake.Close()
+	})
+
+	calls := int32(0)
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		fake.ServeHTTP(w, r)
+	}))
+	t.Cleanup(upstream.Close)
+
+	return upstream, &calls
+}
+
+func TestRecordThenReplay(t *testing.T) {
+	address := testAddress(t, 1)
+	contract := testAddress(t, 2)
+	backend := proxytest.NewStaticBackend()
+	backend.SetAccount(address, data.MustParseAmount("12.5"), 1)
+	backend.SetQueryResult(contract, "getTotalActiveStake", nil, [][]byte{{0x03, 0xe8}})
+	upstream, calls := countingUpstream(t, backend)
+
+	cassette := filepath.Join(t.TempDir(), "cassette.json")
+	recorder := proxytest.NewRecordingServer(upstream.URL, cassette)
+
+	queryBody := `{"scAddress":"` + contract + `","funcName":"getTotalActiveStake","args":[]}`
+	requests := []request{
+		{http.MethodGet, "/network/config", ""},
+		{http.MethodGet, "/address/" + address, ""},
+		{http.MethodGet, "/address/" + address, ""},
+		{http.MethodPost, "/vm-values/query", queryBody},
+		{http.MethodGet, "/transactions?sender=" + address + "&from=0&size=5", ""},
+		{http.MethodGet, "/transaction/unknown", ""},
+	}
+	recorded := make([]response, 0, len(requests))
+	for i, req := range requests {
+		if i == 2 {
+			// the same request answered differently the second time
+			backend.SetAccount(address, data.MustParseAmount("10"), 2)
+		}
+		recorded = append(recorded, fetch(t, recorder.URL(), req))
+	}
+	if recorded[1].body == recorded[2].body {
+		t.Fatalf("the second account request got the same answer: %s", recorded[2].body)
+	}
+	if recorded[5].status != http.StatusNotFound {
+		t.Fatalf("unknown transaction recorded with status %d", recorded[5].status)
+	}
+	err := recorder.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	upstream.Close()
+	upstreamCalls := atomic.LoadInt32(calls)
+
+	replayer, err := proxytest.NewReplayServer(cassette, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = replayer.Close()
+	}()
+
+	// the same requests, with a reformatted body and reordered query parameters
+	requests[3].body = strings.Replace(strings.Replace(queryBody, ",", ", ", -1), ":", ": ", -1)
+	requests[4].path = "/transactions?size=5&from=0&sender=" + address
+	for i, req := range requests {
+		replayed := fetch(t, replayer.URL(), req)
+		if replayed != recorded[i] {
+			t.Fatalf("%s %s replayed %+v, recorded %+v", req.method, req.path, replayed, recorded[i])
+		}
+	}
+
+	// the last answer to a request is repeated
+	again := fetch(t, replayer.URL(), requests[2])
+	if again != recorded[2] {
+		t.Fatalf("repeated request replayed %+v, want %+v", again, recorded[2])
+	}
+	if atomic.LoadInt32(calls) != upstreamCalls {
+		t.Fatalf("the replay reached the upstream")
+	}
+}
+
+func TestReplayUnknownRequest(t *testing.T) {
+	backend := proxytest.NewStaticBackend()
+	upstream, _ := countingUpstream(t, backend)
+
+	cassette := filepath.Join(t.TempDir(), "cassette.json")
+	recorder := proxytest.NewRecordingServer(upstream.URL, cassette)
+	fetch(t, recorder.URL(), request{http.MethodGet, "/network/config", ""})
+	err := recorder.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	replayer, err := proxytest.NewReplayServer(cassette, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = replayer.Close()
+	}()
+
+	resp := fetch(t, replayer.URL(), request{http.MethodGet, "/network/status/4294967295", ""})
+	if resp.status != http.StatusNotFound || !strings.Contains(resp.body, "request not found in cassette") {
+		t.Fatalf("unknown request answered %+v", resp)
+	}
+
+	nm, err := network.NewNetworkManager(&data.AppConfig{
+		NetworkAPI:   replayer.URL(),
+		NetworkProxy: replayer.URL(),
+		ChainID:      proxytest.DefaultChainID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = nm.GetAccount(testAddress(t, 3))
+	if err == nil {
+		t.Fatalf("GetAccount() of an unrecorded address succeeded")
+	}
+}
+
+func TestReplayMissingCassette(t *testing.T) {
+	_, err := proxytest.NewReplayServer(filepath.Join(t.TempDir(), "missing.json"), nil)
+	if err == nil {
+		t.Fatalf("NewReplayServer() succeeded without a cassette")
+	}
+}
+
+func TestNetworkManagerOnFakeServer(t *testing.T) {
+	address := testAddress(t, 1)
+	backend := proxytest.NewStaticBackend()
+	backend.SetAccount(address, data.MustParseAmount("7"), 3)
+	server := proxytest.NewFakeServer(backend)
+	defer func() {
+		_ = server.Close()
+	}()
+
+	cfg := &data.AppConfig{
+		NetworkAPI:   server.URL(),
+		NetworkProxy: server.URL(),
+		MetaObserver: server.URL(),
+		ChainID:      proxytest.DefaultChainID,
+	}
+	nm, err := network.NewNetworkManager(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if nm.ChainID() != proxytest.DefaultChainID {
+		t.Fatalf("ChainID() = %s, want %s", nm.ChainID(), proxytest.DefaultChainID)
+	}
+
+	account, err := nm.GetAccount(address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if account.Nonce != 3 || account.Balance != data.MustParseAmount("7").AttoString() {
+		t.Fatalf("GetAccount() = %+v", account)
+	}
+	status, err := nm.GetNetworkStatus(network.MetachainShardID)
+	if err != nil || status.Data.Status.ErdRoundsPerEpoch != proxytest.DefaultRoundsPerEpoch {
+		t.Fatalf("GetNetworkStatus() = %+v, %v", status, err)
+	}
+
+	cfg.ChainID = "1"
+	_, err = network.NewNetworkManager(cfg)
+	if !errors.Is(err, network.ErrChainIDMismatch) {
+		t.Fatalf("NewNetworkManager() error = %v, want %v", err, network.ErrChainIDMismatch)
+	}
+}
+
+func TestNetworkManagerReplaysRecording(t *testing.T) {
+	address := testAddress(t, 1)
+	backend := proxytest.NewStaticBackend()
+	backend.SetAccount(address, data.MustParseAmount("7"), 3)
+	upstream, _ := countingUpstream(t, backend)
+
+	cassette := filepath.Join(t.TempDir(), "cassette.json")
+	recorder := proxytest.NewRecordingServer(upstream.URL, cassette)
+	cfg := &data.AppConfig{
+		NetworkAPI:   recorder.URL(),
+		NetworkProxy: recorder.URL(),
+		ChainID:      proxytest.DefaultChainID,
+	}
+	nm, err := network.NewNetworkManager(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorded, err := nm.GetAccount(address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = recorder.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	upstream.Close()
+
+	replayer, err := proxytest.NewReplayServer(cassette, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = replayer.Close()
+	}()
+	cfg.NetworkAPI = replayer.URL()
+	cfg.NetworkProxy = replayer.URL()
+	nm, err = network.NewNetworkManager(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	replayed, err := nm.GetAccount(address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if recorded.Nonce != 3 || replayed.Nonce != recorded.Nonce || replayed.Balance != recorded.Balance {
+		t.Fatalf("GetAccount() replayed %+v, recorded %+v", replayed, recorded)
+	}
+}
@@ -0,0 +1,248 @@
+package network
+
+import (
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/DrDelphi/ElrondDSSC/data"
+)
+
+// delegation functions tracked in the wallets' history
+const (
+	FuncDelegate          = "delegate"
+	FuncUnDelegate        = "unDelegate"
+	FuncWithdraw          = "withdraw"
+	FuncClaimRewards      = "claimRewards"
+	FuncReDelegateRewards = "reDelegateRewards"
+)
+
+const (
+	historyPageSize = 50
+	historyMaxTxs   = 10000 // the API does not page beyond from + size = 10000
+)
+
+// WalletOperationStorer - defines the persistence of the wallets' operations
+type WalletOperationStorer interface {
+	AddWalletOperations(operations []*data.WalletOperation) error
+	HasWalletOperation(hash string) (bool, error)
+	GetWalletOperations(address string, contract string) ([]*data.WalletOperation, error)
+}
+
+// RewardsHistory - collects the delegation transactions sent by wallets to the contract and sums up
+// the amounts they moved
+type RewardsHistory struct {
+	lister    TransactionLister
+	storer    WalletOperationStorer
+	contracts ContractAddressProvider
+
+	mut sync.Mutex
+}
+
+// NewRewardsHistory - creates a new RewardsHistory object
+func NewRewardsHistory(lister TransactionLister, storer WalletOperationStorer, contracts ContractAddressProvider) *RewardsHistory {
+	return &RewardsHistory{
+		lister:    lister,
+		storer:    storer,
+		contracts: contracts,
+	}
+}
+
+// Sync - pages through the wallet's transactions to the contract, newest first, until reaching one
+// already stored, and saves the new ones. Transactions without a final status, and the ones newer than them,
+// are left for a later sync, which would otherwise stop at the newer ones before reaching them
+func (rh *RewardsHistory) Sync(address string) (int, error) {
+	rh.mut.Lock()
+	defer rh.mut.Unlock()
+
+	contract := rh.contracts.ContractAddress()
+	operations := make([]*data.WalletOperation, 0)
+	for from := 0; from+historyPageSize <= historyMaxTxs; from += historyPageSize {
+		txs, err := rh.lister.GetTransactions(address, contract, from, historyPageSize)
+		if err != nil {
+			return 0, err
+		}
+
+		known := false
+		for _, tx := range txs {
+			known, err = rh.storer.HasWalletOperation(tx.Hash)
+			if err != nil {
+				return 0, err
+			}
+			if known {
+				break
+			}
+
+			operation := DecodeWalletOperation(tx, contract)
+			if operation == nil {
+				continue
+			}
+			if operation.Status == txStatusPending {
+				operations = operations[:0]
+				continue
+			}
+			operations = append(operations, operation)
+		}
+
+		if known || len(txs) < historyPageSize {
+			break
+		}
+	}
+
+	if len(operations) == 0 {
+		return 0, nil
+	}
+
+	err := rh.storer.AddWalletOperations(operations)
+	if err != nil {
+		return 0, err
+	}
+	log.Debug("wallet history synced", "address", address, "new operations", len(operations))
+
+	return len(operations), nil
+}
+
+// GetHistory - returns the lifetime totals of the wallet's successful operations with the current contract
+func (rh *RewardsHistory) GetHistory(address string) (*data.WalletHistory, error) {
+	operations, err := rh.storer.GetWalletOperations(address, rh.contracts.ContractAddress())
+	if err != nil {
+		return nil, err
+	}
+
+	return SummarizeWalletOperations(address, operations), nil
+}
+
+// SummarizeWalletOperations - sums up the amounts of the successful operations
+func SummarizeWalletOperations(address string, operations []*data.WalletOperation) *data.WalletHistory {
+	history := &data.WalletHistory{
+		Address:     address,
+		Delegated:   data.NewAmount(nil),
+		UnDelegated: data.NewAmount(nil),
+		Withdrawn:   data.NewAmount(nil),
+		Claimed:     data.NewAmount(nil),
+		Compounded:  data.NewAmount(nil),
+	}
+
+	for _, operation := range operations {
+		if operation.Status != TxStatusSuccess {
+			continue
+		}
+
+		switch operation.Function {
+		case FuncDelegate:
+			history.Delegated = history.Delegated.Add(operation.Amount)
+		case FuncUnDelegate:
+			history.UnDelegated = history.UnDelegated.Add(operation.Amount)
+		case FuncWithdraw:
+			history.Withdrawn = history.Withdrawn.Add(operation.Amount)
+		case FuncClaimRewards:
+			history.Claimed = history.Claimed.Add(operation.Amount)
+		case FuncReDelegateRewards:
+			history.Compounded = history.Compounded.Add(operation.Amount)
+		default:
+			continue
+		}
+
+		history.Operations++
+		if history.FirstOperation == 0 || operation.Timestamp < history.FirstOperation {
+			history.FirstOperation = operation.Timestamp
+		}
+		if operation.Timestamp > history.LastOperation {
+			history.LastOperation = operation.Timestamp
+		}
+	}
+
+	return history
+}
+
+// DecodeWalletOperation - returns the delegation operation made by a transaction to the contract, or nil
+// if the transaction calls another function. The amount is taken from:
+//   - delegate: the transaction value
+//   - unDelegate: the function argument
+//   - withdraw, claimRewards: the smart contract results sending eGLD from the contract to the wallet
+//   - reDelegateRewards: the smart contract results staking the rewards through the validator system SC
+func DecodeWalletOperation(tx *data.APITransaction, contract string) *data.WalletOperation {
+	parts := strings.Split(string(tx.Data), "@")
+	function := parts[0]
+	if tx.Receiver != contract {
+		return nil
+	}
+
+	operation := &data.WalletOperation{
+		Hash:      tx.Hash,
+		Address:   tx.Sender,
+		Contract:  contract,
+		Function:  function,
+		Amount:    data.NewAmount(nil),
+		Timestamp: tx.Timestamp,
+		Status:    apiTxStatus(tx),
+	}
+
+	switch function {
+	case FuncDelegate:
+		value, err := data.ParseAttoAmount(tx.Value)
+		if err == nil {
+			operation.Amount = value
+		}
+	case FuncUnDelegate:
+		if len(parts) > 1 {
+			value, ok := big.NewInt(0).SetString(parts[1], 16)
+			if ok {
+				operation.Amount = data.NewAmount(value)
+			}
+		}
+	case FuncWithdraw, FuncClaimRewards:
+		operation.Amount = sumScResults(tx.Results, contract, func(receiver string) bool { return receiver == tx.Sender })
+	case FuncReDelegateRewards:
+		operation.Amount = sumScResults(tx.Results, contract, func(receiver string) bool { return receiver == ValidatorSCAddress })
+	default:
+		return nil
+	}
+
+	return operation
+}
+
+// apiTxStatus - returns success, fail or pending, taking into account the smart contract call's return code.
+// Only a transaction the API reports as pending is pending, any status other than a success is a failure
+func apiTxStatus(tx *data.APITransaction) string {
+	switch tx.Status {
+	case TxStatusSuccess, TxStatusExecuted:
+	case txStatusPending:
+		return txStatusPending
+	default:
+		return TxStatusFail
+	}
+
+	results := make([]*data.SmartContractResult, 0, len(tx.Results))
+	for _, scr := range tx.Results {
+		results = append(results, &data.SmartContractResult{
+			Hash:          scr.Hash,
+			Sender:        scr.Sender,
+			Receiver:      scr.Receiver,
+			Value:         scr.Value,
+			Data:          string(scr.Data),
+			ReturnMessage: scr.ReturnMessage,
+		})
+	}
+	ok, _ := ParseScResults(results)
+	if !ok {
+		return TxStatusFail
+	}
+
+	return TxStatusSuccess
+}
+
+func sumScResults(results []*data.APIScResult, sender string, receiverMatches func(receiver string) bool) *data.Amount {
+	sum := data.NewAmount(nil)
+	for _, scr := range results {
+		if scr.Sender != sender || !receiverMatches(scr.Receiver) {
+			continue
+		}
+		value, err := data.ParseAttoAmount(scr.Value)
+		if err == nil {
+			sum = sum.Add(value)
+		}
+	}
+
+	return sum
+}
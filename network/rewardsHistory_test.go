@@ -0,0 +1,316 @@
+package network
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/DrDelphi/ElrondDSSC/data"
+)
+
+// fixtureAddresses - replaces the placeholders of the API transaction fixtures
+var fixtureAddresses = strings.NewReplacer(
+	"CONTRACT", activityContract,
+	"WALLET", activitySender,
+	"VALIDATOR", ValidatorSCAddress,
+)
+
+// API transactions, as returned by the API, with the data fields base64 encoded
+const (
+	delegateFixture = `{"txHash":"a1","value":"10000000000000000000","sender":"WALLET","receiver":"CONTRACT",
+		"data":"ZGVsZWdhdGU=","timestamp":1000,"status":"success",
+		"results":[{"hash":"r1","value":"0","sender":"CONTRACT","receiver":"WALLET","data":"QDZmNmI="}]}`
+	unDelegateFixture = `{"txHash":"a2","value":"0","sender":"WALLET","receiver":"CONTRACT",
+		"data":"dW5EZWxlZ2F0ZUA4YWM3MjMwNDg5ZTgwMDAw","timestamp":1001,"status":"success"}`
+	withdrawFixture = `{"txHash":"a3","value":"0","sender":"WALLET","receiver":"CONTRACT",
+		"data":"d2l0aGRyYXc=","timestamp":1002,"status":"success",
+		"results":[
+			{"hash":"r1","value":"5000000000000000000","sender":"VALIDATOR","receiver":"CONTRACT"},
+			{"hash":"r2","value":"5000000000000000000","sender":"CONTRACT","receiver":"WALLET"},
+			{"hash":"r3","value":"0","sender":"CONTRACT","receiver":"WALLET","data":"QDZmNmI="}]}`
+	claimFixture = `{"txHash":"a4","value":"0","sender":"WALLET","receiver":"CONTRACT",
+		"data":"Y2xhaW1SZXdhcmRz","timestamp":1003,"status":"success",
+		"results":[
+			{"hash":"r1","value":"0","sender":"CONTRACT","receiver":"WALLET","data":"QDZmNmI="},
+			{"hash":"r2","value":"1500000000000000000","sender":"CONTRACT","receiver":"WALLET"}]}`
+	claimFailedFixture = `{"txHash":"a5","value":"0","sender":"WALLET","receiver":"CONTRACT",
+		"data":"Y2xhaW1SZXdhcmRz","timestamp":1004,"status":"fail"}`
+	claimUserErrorFixture = `{"txHash":"a6","value":"0","sender":"WALLET","receiver":"CONTRACT",
+		"data":"Y2xhaW1SZXdhcmRz","timestamp":1005,"status":"success",
+		"results":[{"hash":"r1","value":"0","sender":"CONTRACT","receiver":"WALLET",
+			"data":"QDc1NzM2NTcyMjA2NTcyNzI2Zjcy","returnMessage":"no rewards to claim"}]}`
+	reDelegateFixture = `{"txHash":"a7","value":"0","sender":"WALLET","receiver":"CONTRACT",
+		"data":"cmVEZWxlZ2F0ZVJld2FyZHM=","timestamp":1006,"status":"success",
+		"results":[
+			{"hash":"r1","value":"2000000000000000000","sender":"CONTRACT","receiver":"VALIDATOR","data":"c3Rha2U="},
+			{"hash":"r2","value":"0","sender":"CONTRACT","receiver":"WALLET","data":"QDZmNmI="}]}`
+	pendingFixture = `{"txHash":"a8","value":"10000000000000000000","sender":"WALLET","receiver":"CONTRACT",
+		"data":"ZGVsZWdhdGU=","timestamp":1007,"status":"pending"}`
+	otherFunctionFixture = `{"txHash":"a9","value":"0","sender":"WALLET","receiver":"CONTRACT",
+		"data":"Y2hhbmdlU2VydmljZUZlZUAwMWY0","timestamp":1008,"status":"success"}`
+	otherReceiverFixture = `{"txHash":"a10","value":"10000000000000000000","sender":"WALLET","receiver":"WALLET",
+		"data":"ZGVsZWdhdGU=","timestamp":1009,"status":"success"}`
+)
+
+func apiTxFixture(t *testing.T, fixture string) *data.APITransaction {
+	t.Helper()
+
+	tx := &data.APITransaction{}
+	err := json.Unmarshal([]byte(fixtureAddresses.Replace(fixture)), tx)
+	if err != nil {
+		t.Fatalf("fixture: %v", err)
+	}
+
+	return tx
+}
+
+func TestDecodeWalletOperation(t *testing.T) {
+	tests := []struct {
+		name     string
+		fixture  string
+		function string
+		amount   string
+		status   string
+	}{
+		{"delegate", delegateFixture, FuncDelegate, "10", TxStatusSuccess},
+		{"unDelegate", unDelegateFixture, FuncUnDelegate, "10", TxStatusSuccess},
+		{"withdraw", withdrawFixture, FuncWithdraw, "5", TxStatusSuccess},
+		{"claim", claimFixture, FuncClaimRewards, "1.5", TxStatusSuccess},
+		{"failed claim", claimFailedFixture, FuncClaimRewards, "0", TxStatusFail},
+		{"claim with user error", claimUserErrorFixture, FuncClaimRewards, "0", TxStatusFail},
+		{"redelegate to the validator SC", reDelegateFixture, FuncReDelegateRewards, "2", TxStatusSuccess},
+		{"pending", pendingFixture, FuncDelegate, "10", txStatusPending},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := apiTxFixture(t, tt.fixture)
+			operation := DecodeWalletOperation(tx, activityContract)
+			if operation == nil {
+				t.Fatalf("DecodeWalletOperation() = nil")
+			}
+			if operation.Hash != tx.Hash || operation.Address != activitySender || operation.Contract != activityContract ||
+				operation.Timestamp != tx.Timestamp {
+				t.Fatalf("operation %+v does not match the transaction", operation)
+			}
+			if operation.Function != tt.function || operation.Status != tt.status {
+				t.Fatalf("operation %s %s, want %s %s", operation.Function, operation.Status, tt.function, tt.status)
+			}
+			if operation.Amount.Cmp(data.MustParseAmount(tt.amount)) != 0 {
+				t.Fatalf("amount %s, want %s", operation.Amount, tt.amount)
+			}
+		})
+	}
+}
+
+func TestDecodeWalletOperationIgnored(t *testing.T) {
+	tests := []struct {
+		name    string
+		fixture string
+	}{
+		{"other function", otherFunctionFixture},
+		{"other receiver", otherReceiverFixture},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			operation := DecodeWalletOperation(apiTxFixture(t, tt.fixture), activityContract)
+			if operation != nil {
+				t.Fatalf("DecodeWalletOperation() = %+v, want nil", operation)
+			}
+		})
+	}
+}
+
+func TestAPITxStatus(t *testing.T) {
+	tests := []struct {
+		name    string
+		fixture string
+		status  string
+		want    string
+	}{
+		{"success", claimFixture, TxStatusSuccess, TxStatusSuccess},
+		{"executed", claimFixture, TxStatusExecuted, TxStatusSuccess},
+		{"success with a user error", claimUserErrorFixture, TxStatusSuccess, TxStatusFail},
+		{"success without results", unDelegateFixture, TxStatusSuccess, TxStatusSuccess},
+		{"fail", claimFixture, TxStatusFail, TxStatusFail},
+		{"invalid", claimFixture, TxStatusInvalid, TxStatusFail},
+		{"not executed", claimFixture, TxStatusNotExecuted, TxStatusFail},
+		{"pending", claimFixture, txStatusPending, txStatusPending},
+		{"received", claimFixture, txStatusReceived, TxStatusFail},
+		{"missing", claimFixture, "", TxStatusFail},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := apiTxFixture(t, tt.fixture)
+			tx.Status = tt.status
+			if status := apiTxStatus(tx); status != tt.want {
+				t.Fatalf("apiTxStatus() = %s, want %s", status, tt.want)
+			}
+		})
+	}
+}
+
+// walletLister - pages through the wallet's transactions, newest first, and counts the pages requested
+type walletLister struct {
+	txs   []*data.APITransaction
+	calls int
+}
+
+func (l *walletLister) GetTransactions(sender string, receiver string, from int, size int) ([]*data.APITransaction, error) {
+	l.calls++
+
+	list := make([]*data.APITransaction, 0)
+	for _, tx := range l.txs {
+		if tx.Sender == sender && tx.Receiver == receiver {
+			list = append(list, tx)
+		}
+	}
+	if from >= len(list) {
+		return make([]*data.APITransaction, 0), nil
+	}
+	list = list[from:]
+	if size < len(list) {
+		list = list[:size]
+	}
+
+	return list, nil
+}
+
+// memoryOperations - keeps the wallet operations by hash and counts how many times each was saved
+type memoryOperations struct {
+	operations map[string]*data.WalletOperation
+	saves      map[string]int
+}
+
+func newMemoryOperations() *memoryOperations {
+	return &memoryOperations{
+		operations: make(map[string]*data.WalletOperation),
+		saves:      make(map[string]int),
+	}
+}
+
+func (m *memoryOperations) AddWalletOperations(operations []*data.WalletOperation) error {
+	for _, operation := range operations {
+		m.operations[operation.Hash] = operation
+		m.saves[operation.Hash]++
+	}
+
+	return nil
+}
+
+func (m *memoryOperations) HasWalletOperation(hash string) (bool, error) {
+	return m.operations[hash] != nil, nil
+}
+
+func (m *memoryOperations) GetWalletOperations(_ string, _ string) ([]*data.WalletOperation, error) {
+	return nil, nil
+}
+
+// walletTxs - returns delegations sent by the wallet to the contract, newest first
+func walletTxs(prefix string, count int) []*data.APITransaction {
+	txs := activityTxs(prefix, descending(int64(10000+count), count, nil)...)
+	for _, tx := range txs {
+		tx.Results = []*data.APIScResult{{Sender: activityContract, Receiver: activitySender, Data: []byte("@6f6b")}}
+	}
+
+	return txs
+}
+
+// requireStored - checks every transaction, and nothing else, was stored once
+func requireStored(t *testing.T, storer *memoryOperations, txs []*data.APITransaction) {
+	t.Helper()
+
+	for _, tx := range txs {
+		if storer.saves[tx.Hash] != 1 {
+			t.Fatalf("transaction %s saved %d times", tx.Hash, storer.saves[tx.Hash])
+		}
+	}
+	if len(storer.operations) != len(txs) {
+		t.Fatalf("%d operations stored, want %d", len(storer.operations), len(txs))
+	}
+}
+
+func TestRewardsHistorySyncPages(t *testing.T) {
+	lister := &walletLister{txs: walletTxs("tx", 2*historyPageSize+10)}
+	lister.txs = append(lister.txs, apiTxFixture(t, otherFunctionFixture), apiTxFixture(t, otherReceiverFixture))
+	storer := newMemoryOperations()
+
+	saved, err := NewRewardsHistory(lister, storer, staticContract(activityContract)).Sync(activitySender)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved != 2*historyPageSize+10 || lister.calls != 3 {
+		t.Fatalf("Sync() = %d after %d pages, want %d after 3", saved, lister.calls, 2*historyPageSize+10)
+	}
+	requireStored(t, storer, lister.txs[:2*historyPageSize+10])
+}
+
+func TestRewardsHistorySyncStopsAtKnownHash(t *testing.T) {
+	txs := walletTxs("tx", 3*historyPageSize)
+	lister := &walletLister{txs: txs[historyPageSize:]}
+	storer := newMemoryOperations()
+	history := NewRewardsHistory(lister, storer, staticContract(activityContract))
+
+	_, err := history.Sync(activitySender)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// ten new transactions: the first page ends at the newest known one, the next pages are not requested
+	lister.txs = txs[historyPageSize-10:]
+	lister.calls = 0
+	saved, err := history.Sync(activitySender)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved != 10 || lister.calls != 1 {
+		t.Fatalf("Sync() = %d after %d pages, want 10 after 1", saved, lister.calls)
+	}
+	requireStored(t, storer, lister.txs)
+
+	lister.calls = 0
+	saved, err = history.Sync(activitySender)
+	if err != nil || saved != 0 || lister.calls != 1 {
+		t.Fatalf("Sync() = %d, %v after %d pages, want nothing new after 1", saved, err, lister.calls)
+	}
+}
+
+func TestRewardsHistorySyncSkipsPending(t *testing.T) {
+	txs := walletTxs("tx", 10)
+	pending := txs[4]
+	pending.Status = txStatusPending
+	lister := &walletLister{txs: txs}
+	storer := newMemoryOperations()
+	history := NewRewardsHistory(lister, storer, staticContract(activityContract))
+
+	saved, err := history.Sync(activitySender)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the newer transactions are left too, or the next sync would stop at them
+	if saved != 5 {
+		t.Fatalf("Sync() = %d, want 5", saved)
+	}
+	requireStored(t, storer, txs[5:])
+
+	// still pending: nothing is saved
+	saved, err = history.Sync(activitySender)
+	if err != nil || saved != 0 {
+		t.Fatalf("Sync() = %d, %v, want nothing saved", saved, err)
+	}
+
+	pending.Status = TxStatusSuccess
+	saved, err = history.Sync(activitySender)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved != 5 {
+		t.Fatalf("Sync() = %d, want 5", saved)
+	}
+	requireStored(t, storer, txs)
+	if storer.operations[pending.Hash].Status != TxStatusSuccess {
+		t.Fatalf("the pending transaction stored as %s", storer.operations[pending.Hash].Status)
+	}
+}
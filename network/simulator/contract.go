@@ -0,0 +1,651 @@
+package simulator
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/DrDelphi/ElrondDSSC/network"
+	"github.com/ElrondNetwork/elrond-sdk/erdgo"
+)
+
+const (
+	stateNotStaked = "notStaked"
+	stateStaked    = "staked"
+	stateUnStaked  = "unStaked"
+
+	maxServiceFee = 10000
+	ppm           = 1000000
+)
+
+var (
+	errOnlyOwner      = errors.New("only owner can call this function")
+	errInvalidArgs    = errors.New("invalid number of arguments")
+	errUnknownNode    = errors.New("unknown BLS key")
+	errNothingToClaim = errors.New("no rewards to claim")
+)
+
+type undelegation struct {
+	amount      *big.Int
+	unlockRound uint64
+}
+
+type delegator struct {
+	active      *big.Int
+	rewards     *big.Int
+	cumulated   *big.Int // all the rewards received, claimed or not
+	undelegated []*undelegation
+}
+
+type node struct {
+	key   []byte
+	state string
+}
+
+// contract - holds the state of the simulated delegation contract
+type contract struct {
+	address string
+	owner   string
+	cfg     Config
+
+	serviceFee          uint64
+	maxCap              *big.Int
+	initialOwnerFunds   *big.Int
+	automaticActivation bool
+	withCap             bool
+	changeableFee       bool
+	createdNonce        uint64
+	unBondPeriod        uint64
+	metadata            [][]byte
+
+	delegators        map[string]*delegator
+	delegatorsOrder   []string
+	nodes             []*node
+	cumulatedRewards  *big.Int
+	unStakedFromNodes *big.Int
+	unBondedFromNodes *big.Int
+}
+
+func newContract(address string, owner string, cfg Config, round uint64) *contract {
+	return &contract{
+		address:           address,
+		owner:             owner,
+		cfg:               cfg,
+		maxCap:            big.NewInt(0),
+		initialOwnerFunds: big.NewInt(0),
+		changeableFee:     true,
+		createdNonce:      round,
+		unBondPeriod:      cfg.UnBondPeriod,
+		metadata:          [][]byte{{}, {}, {}},
+		delegators:        make(map[string]*delegator),
+		delegatorsOrder:   make([]string, 0),
+		nodes:             make([]*node, 0),
+		cumulatedRewards:  big.NewInt(0),
+		unStakedFromNodes: big.NewInt(0),
+		unBondedFromNodes: big.NewInt(0),
+	}
+}
+
+// clone - returns a deep copy of the contract's state, used for executing transactions without effects
+func (c *contract) clone() *contract {
+	cloned := *c
+	cloned.maxCap = big.NewInt(0).Set(c.maxCap)
+	cloned.initialOwnerFunds = big.NewInt(0).Set(c.initialOwnerFunds)
+	cloned.metadata = append([][]byte{}, c.metadata...)
+	cloned.delegators = make(map[string]*delegator, len(c.delegators))
+	for address, d := range c.delegators {
+		undelegated := make([]*undelegation, 0, len(d.undelegated))
+		for _, u := range d.undelegated {
+			undelegated = append(undelegated, &undelegation{amount: big.NewInt(0).Set(u.amount), unlockRound: u.unlockRound})
+		}
+		cloned.delegators[address] = &delegator{
+			active:      big.NewInt(0).Set(d.active),
+			rewards:     big.NewInt(0).Set(d.rewards),
+			cumulated:   big.NewInt(0).Set(d.cumulated),
+			undelegated: undelegated,
+		}
+	}
+	cloned.delegatorsOrder = append([]string{}, c.delegatorsOrder...)
+	cloned.nodes = make([]*node, 0, len(c.nodes))
+	for _, n := range c.nodes {
+		cloned.nodes = append(cloned.nodes, &node{key: n.key, state: n.state})
+	}
+	cloned.cumulatedRewards = big.NewInt(0).Set(c.cumulatedRewards)
+	cloned.unStakedFromNodes = big.NewInt(0).Set(c.unStakedFromNodes)
+	cloned.unBondedFromNodes = big.NewInt(0).Set(c.unBondedFromNodes)
+
+	return &cloned
+}
+
+func (c *contract) getDelegator(address string) *delegator {
+	d, ok := c.delegators[address]
+	if !ok {
+		d = &delegator{
+			active:      big.NewInt(0),
+			rewards:     big.NewInt(0),
+			cumulated:   big.NewInt(0),
+			undelegated: make([]*undelegation, 0),
+		}
+		c.delegators[address] = d
+		c.delegatorsOrder = append(c.delegatorsOrder, address)
+	}
+
+	return d
+}
+
+func (c *contract) totalActiveStake() *big.Int {
+	total := big.NewInt(0)
+	for _, d := range c.delegators {
+		total.Add(total, d.active)
+	}
+
+	return total
+}
+
+func (c *contract) totalUnStaked() *big.Int {
+	total := big.NewInt(0)
+	for _, d := range c.delegators {
+		for _, u := range d.undelegated {
+			total.Add(total, u.amount)
+		}
+	}
+
+	return total
+}
+
+func (c *contract) numNodesInState(state string) int {
+	n := 0
+	for _, nd := range c.nodes {
+		if nd.state == state {
+			n++
+		}
+	}
+
+	return n
+}
+
+func (c *contract) findNode(key []byte) *node {
+	for _, nd := range c.nodes {
+		if string(nd.key) == string(key) {
+			return nd
+		}
+	}
+
+	return nil
+}
+
+// distributeRewards - distributes one epoch's rewards pro rata to the active stake, after the service fee.
+// Returns the distributed rewards, which the contract receives until they are claimed
+func (c *contract) distributeRewards(rate uint64) *big.Int {
+	rewards := big.NewInt(0)
+	if c.numNodesInState(stateStaked) == 0 {
+		return rewards
+	}
+
+	total := c.totalActiveStake()
+	if total.Sign() == 0 {
+		return rewards
+	}
+
+	rewards.Mul(total, big.NewInt(int64(rate)))
+	rewards.Quo(rewards, big.NewInt(ppm))
+	if rewards.Sign() == 0 {
+		return rewards
+	}
+	c.cumulatedRewards.Add(c.cumulatedRewards, rewards)
+
+	fee := big.NewInt(0).Mul(rewards, big.NewInt(int64(c.serviceFee)))
+	fee.Quo(fee, big.NewInt(maxServiceFee))
+	remaining := big.NewInt(0).Sub(rewards, fee)
+
+	distributed := big.NewInt(0)
+	for _, address := range c.delegatorsOrder {
+		d := c.delegators[address]
+		share := big.NewInt(0).Mul(remaining, d.active)
+		share.Quo(share, total)
+		d.rewards.Add(d.rewards, share)
+		d.cumulated.Add(d.cumulated, share)
+		distributed.Add(distributed, share)
+	}
+
+	// the service fee and the rounding dust go to the owner
+	ownerShare := big.NewInt(0).Sub(rewards, distributed)
+	owner := c.getDelegator(c.owner)
+	owner.rewards.Add(owner.rewards, ownerShare)
+	owner.cumulated.Add(owner.cumulated, ownerShare)
+
+	return rewards
+}
+
+// execute - runs a contract function. The value has already been taken from the sender's balance
+func (c *contract) execute(s *Simulator, sender string, value *big.Int, funcName string, args [][]byte) ([][]byte, error) {
+	if value.Sign() != 0 && funcName != "delegate" && funcName != "unJailNodes" {
+		return nil, errors.New("function does not accept eGLD payment")
+	}
+
+	switch funcName {
+	case "delegate":
+		err := c.delegate(sender, value)
+		if err != nil {
+			return nil, err
+		}
+		return nil, s.stake(value)
+	case "unDelegate":
+		if len(args) != 1 {
+			return nil, errInvalidArgs
+		}
+		return nil, c.unDelegate(sender, big.NewInt(0).SetBytes(args[0]), s.round)
+	case "withdraw":
+		amount, err := c.withdraw(sender, s.round)
+		if err != nil {
+			return nil, err
+		}
+		// the unbonded funds come back from the validator system SC
+		err = s.transfer(network.ValidatorSCAddress, c.address, amount)
+		if err != nil {
+			return nil, err
+		}
+		return nil, s.transfer(c.address, sender, amount)
+	case "claimRewards":
+		d, ok := c.delegators[sender]
+		if !ok || d.rewards.Sign() == 0 {
+			return nil, errNothingToClaim
+		}
+		err := s.transfer(c.address, sender, d.rewards)
+		if err != nil {
+			return nil, err
+		}
+		d.rewards = big.NewInt(0)
+		return nil, nil
+	case "reDelegateRewards":
+		d, ok := c.delegators[sender]
+		if !ok || d.rewards.Sign() == 0 {
+			return nil, errNothingToClaim
+		}
+		// the rewards are staked as top-up through the validator system SC
+		err := s.transfer(c.address, network.ValidatorSCAddress, d.rewards)
+		if err != nil {
+			return nil, err
+		}
+		d.active.Add(d.active, d.rewards)
+		d.rewards = big.NewInt(0)
+		return nil, nil
+	}
+
+	if sender != c.owner {
+		return nil, errOnlyOwner
+	}
+
+	switch funcName {
+	case "addNodes":
+		return nil, c.addNodes(args)
+	case "stakeNodes":
+		return nil, c.stakeNodes(args)
+	case "unStakeNodes":
+		return nil, c.changeNodesState(args, stateStaked, stateUnStaked)
+	case "unBondNodes":
+		return nil, c.changeNodesState(args, stateUnStaked, stateNotStaked)
+	case "reStakeUnStakedNodes":
+		return nil, c.changeNodesState(args, stateUnStaked, stateStaked)
+	case "removeNodes":
+		return nil, c.removeNodes(args)
+	case "unJailNodes":
+		err := c.changeNodesState(args, stateStaked, stateStaked)
+		if err != nil {
+			return nil, err
+		}
+		return nil, s.stake(value)
+	case "changeServiceFee":
+		return nil, c.changeServiceFee(args)
+	case "modifyTotalDelegationCap":
+		return nil, c.modifyTotalDelegationCap(args)
+	case "setAutomaticActivation":
+		if len(args) != 1 || (string(args[0]) != "yes" && string(args[0]) != "no") {
+			return nil, errInvalidArgs
+		}
+		c.automaticActivation = string(args[0]) == "yes"
+		return nil, nil
+	case "setMetaData":
+		if len(args) != 3 {
+			return nil, errInvalidArgs
+		}
+		c.metadata = args
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("invalid function %s", funcName)
+	}
+}
+
+func (c *contract) delegate(sender string, value *big.Int) error {
+	if value.Cmp(c.cfg.MinDelegation.Int()) < 0 {
+		return fmt.Errorf("delegation value must be at least %s eGLD", c.cfg.MinDelegation)
+	}
+
+	total := c.totalActiveStake()
+	total.Add(total, value)
+	if c.withCap && total.Cmp(c.maxCap) > 0 {
+		return errors.New("total delegation cap reached")
+	}
+
+	d := c.getDelegator(sender)
+	d.active.Add(d.active, value)
+
+	if c.automaticActivation {
+		c.activateNodes()
+	}
+
+	return nil
+}
+
+func (c *contract) unDelegate(sender string, amount *big.Int, round uint64) error {
+	d, ok := c.delegators[sender]
+	if !ok || d.active.Cmp(amount) < 0 {
+		return errors.New("invalid value to undelegate")
+	}
+	if amount.Sign() == 0 {
+		return errors.New("invalid value to undelegate")
+	}
+
+	remaining := big.NewInt(0).Sub(d.active, amount)
+	if remaining.Sign() > 0 && remaining.Cmp(c.cfg.MinDelegation.Int()) < 0 {
+		return fmt.Errorf("remaining delegation must be 0 or at least %s eGLD", c.cfg.MinDelegation)
+	}
+	if sender == c.owner && remaining.Cmp(c.initialOwnerFunds) < 0 && c.numNodesInState(stateStaked) > 0 {
+		return errors.New("owner can not go below the initial owner funds while nodes are staked")
+	}
+
+	d.active = remaining
+	d.undelegated = append(d.undelegated, &undelegation{
+		amount:      amount,
+		unlockRound: round + c.unBondPeriod,
+	})
+
+	return nil
+}
+
+func (c *contract) withdraw(sender string, round uint64) (*big.Int, error) {
+	d, ok := c.delegators[sender]
+	if !ok {
+		return nil, errors.New("nothing to unBond")
+	}
+
+	amount := big.NewInt(0)
+	remaining := make([]*undelegation, 0)
+	for _, u := range d.undelegated {
+		if u.unlockRound <= round {
+			amount.Add(amount, u.amount)
+			continue
+		}
+		remaining = append(remaining, u)
+	}
+	if amount.Sign() == 0 {
+		return nil, errors.New("nothing to unBond")
+	}
+	d.undelegated = remaining
+
+	return amount, nil
+}
+
+func (c *contract) addNodes(args [][]byte) error {
+	if len(args) == 0 || len(args)%2 != 0 {
+		return errInvalidArgs
+	}
+
+	for i := 0; i < len(args); i += 2 {
+		if len(args[i]) != 96 {
+			return errors.New("invalid BLS key length")
+		}
+		if c.findNode(args[i]) != nil {
+			return errors.New("BLS key already added: " + hex.EncodeToString(args[i]))
+		}
+	}
+	for i := 0; i < len(args); i += 2 {
+		c.nodes = append(c.nodes, &node{key: args[i], state: stateNotStaked})
+	}
+
+	if c.automaticActivation {
+		c.activateNodes()
+	}
+
+	return nil
+}
+
+func (c *contract) stakeNodes(args [][]byte) error {
+	if len(args) == 0 {
+		return errInvalidArgs
+	}
+
+	nodes := make([]*node, 0, len(args))
+	for _, key := range args {
+		nd := c.findNode(key)
+		if nd == nil {
+			return errUnknownNode
+		}
+		if nd.state != stateNotStaked {
+			return errors.New("node is not in the notStaked state")
+		}
+		nodes = append(nodes, nd)
+	}
+
+	required := big.NewInt(int64(c.numNodesInState(stateStaked) + len(nodes)))
+	required.Mul(required, c.cfg.NodePrice.Int())
+	if c.totalActiveStake().Cmp(required) < 0 {
+		return errors.New("not enough stake to stake nodes")
+	}
+
+	for _, nd := range nodes {
+		nd.state = stateStaked
+	}
+
+	return nil
+}
+
+// activateNodes - stakes as many notStaked nodes as the active stake allows
+func (c *contract) activateNodes() {
+	total := c.totalActiveStake()
+	staked := int64(c.numNodesInState(stateStaked))
+	for _, nd := range c.nodes {
+		if nd.state != stateNotStaked {
+			continue
+		}
+		required := big.NewInt(0).Mul(big.NewInt(staked+1), c.cfg.NodePrice.Int())
+		if total.Cmp(required) < 0 {
+			return
+		}
+		nd.state = stateStaked
+		staked++
+	}
+}
+
+func (c *contract) changeNodesState(args [][]byte, from, to string) error {
+	if len(args) == 0 {
+		return errInvalidArgs
+	}
+
+	nodes := make([]*node, 0, len(args))
+	for _, key := range args {
+		nd := c.findNode(key)
+		if nd == nil {
+			return errUnknownNode
+		}
+		if nd.state != from {
+			return fmt.Errorf("node is not in the %s state", from)
+		}
+		nodes = append(nodes, nd)
+	}
+	for _, nd := range nodes {
+		nd.state = to
+	}
+
+	return nil
+}
+
+func (c *contract) removeNodes(args [][]byte) error {
+	err := c.changeNodesState(args, stateNotStaked, stateNotStaked)
+	if err != nil {
+		return err
+	}
+
+	remaining := make([]*node, 0, len(c.nodes))
+	for _, nd := range c.nodes {
+		removed := false
+		for _, key := range args {
+			if string(nd.key) == string(key) {
+				removed = true
+				break
+			}
+		}
+		if !removed {
+			remaining = append(remaining, nd)
+		}
+	}
+	c.nodes = remaining
+
+	return nil
+}
+
+func (c *contract) changeServiceFee(args [][]byte) error {
+	if len(args) != 1 {
+		return errInvalidArgs
+	}
+	if !c.changeableFee {
+		return errors.New("service fee is not changeable")
+	}
+
+	fee := big.NewInt(0).SetBytes(args[0])
+	if !fee.IsUint64() || fee.Uint64() > maxServiceFee {
+		return errors.New("invalid service fee")
+	}
+	c.serviceFee = fee.Uint64()
+
+	return nil
+}
+
+func (c *contract) modifyTotalDelegationCap(args [][]byte) error {
+	if len(args) != 1 {
+		return errInvalidArgs
+	}
+
+	newCap := big.NewInt(0).SetBytes(args[0])
+	if newCap.Sign() > 0 && newCap.Cmp(c.totalActiveStake()) < 0 {
+		return errors.New("cannot set total delegation cap below the total active stake")
+	}
+	c.maxCap = newCap
+	c.withCap = newCap.Sign() > 0
+
+	return nil
+}
+
+// query - answers the contract views with the same layouts as the real delegation contract
+func (c *contract) query(funcName string, args []string, round uint64) ([][]byte, error) {
+	switch funcName {
+	case "getContractConfig":
+		owner, err := erdgo.Bech32ToPubkey(c.owner)
+		if err != nil {
+			return nil, err
+		}
+		return [][]byte{
+			owner,
+			big.NewInt(int64(c.serviceFee)).Bytes(),
+			c.maxCap.Bytes(),
+			c.initialOwnerFunds.Bytes(),
+			boolBytes(c.automaticActivation),
+			boolBytes(c.withCap),
+			boolBytes(c.changeableFee),
+			big.NewInt(0).SetUint64(c.createdNonce).Bytes(),
+			big.NewInt(0).SetUint64(c.unBondPeriod).Bytes(),
+		}, nil
+	case "getTotalActiveStake":
+		return [][]byte{c.totalActiveStake().Bytes()}, nil
+	case "getTotalUnStaked":
+		return [][]byte{c.totalUnStaked().Bytes()}, nil
+	case "getTotalCumulatedRewards":
+		return [][]byte{c.cumulatedRewards.Bytes()}, nil
+	case "getTotalUnStakedFromNodes":
+		return [][]byte{c.unStakedFromNodes.Bytes()}, nil
+	case "getTotalUnBondedFromNodes":
+		return [][]byte{c.unBondedFromNodes.Bytes()}, nil
+	case "getNumUsers":
+		n := int64(0)
+		for _, d := range c.delegators {
+			if d.active.Sign() > 0 || len(d.undelegated) > 0 || d.rewards.Sign() > 0 {
+				n++
+			}
+		}
+		return [][]byte{big.NewInt(n).Bytes()}, nil
+	case "getNumNodes":
+		return [][]byte{big.NewInt(int64(len(c.nodes))).Bytes()}, nil
+	case "getAllNodeStates":
+		list := make([][]byte, 0)
+		for _, state := range []string{stateStaked, stateNotStaked, stateUnStaked} {
+			keys := make([][]byte, 0)
+			for _, nd := range c.nodes {
+				if nd.state == state {
+					keys = append(keys, nd.key)
+				}
+			}
+			if len(keys) > 0 {
+				list = append(list, []byte(state))
+				list = append(list, keys...)
+			}
+		}
+		return list, nil
+	case "getContractMetadata":
+		return c.metadata, nil
+	}
+
+	if len(args) != 1 {
+		return nil, errInvalidArgs
+	}
+	pubkey, err := hex.DecodeString(args[0])
+	if err != nil {
+		return nil, err
+	}
+	address, err := erdgo.PubkeyToBech32(pubkey)
+	if err != nil {
+		return nil, err
+	}
+	d, ok := c.delegators[address]
+	if !ok {
+		return nil, errors.New("view function works only for existing delegators")
+	}
+
+	switch funcName {
+	case "getUserActiveStake":
+		return [][]byte{d.active.Bytes()}, nil
+	case "getClaimableRewards":
+		return [][]byte{d.rewards.Bytes()}, nil
+	case "getTotalCumulatedRewardsForUser":
+		return [][]byte{d.cumulated.Bytes()}, nil
+	case "getUserUnStakedValue", "getUserUnBondable":
+		total := big.NewInt(0)
+		for _, u := range d.undelegated {
+			if funcName == "getUserUnBondable" && u.unlockRound > round {
+				continue
+			}
+			total.Add(total, u.amount)
+		}
+		return [][]byte{total.Bytes()}, nil
+	case "getUserUnDelegatedList":
+		list := make([][]byte, 0, 2*len(d.undelegated))
+		for _, u := range d.undelegated {
+			remaining := uint64(0)
+			if u.unlockRound > round {
+				remaining = u.unlockRound - round
+			}
+			list = append(list, u.amount.Bytes(), big.NewInt(0).SetUint64(remaining).Bytes())
+		}
+		return list, nil
+	default:
+		return nil, fmt.Errorf("invalid function %s", funcName)
+	}
+}
+
+func boolBytes(b bool) []byte {
+	if b {
+		return []byte("true")
+	}
+
+	return []byte("false")
+}
@@ -0,0 +1,645 @@
+// Package simulator implements an in-memory delegation system smart contract. It is exposed
+// through the proxytest stand-in, allowing the bot to run in sandbox mode without any network
+package simulator
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/DrDelphi/ElrondDSSC/data"
+	"github.com/DrDelphi/ElrondDSSC/network"
+	"github.com/DrDelphi/ElrondDSSC/network/proxytest"
+	logger "github.com/ElrondNetwork/elrond-go-logger"
+	"github.com/ElrondNetwork/elrond-sdk/erdgo"
+)
+
+var log = logger.GetOrCreate("simulator")
+
+// scCallGas - gas reported for executing a smart contract call
+const scCallGas = 5000000
+
+var (
+	errInvalidNonce        = errors.New("invalid nonce")
+	errInsufficientFunds   = errors.New("insufficient funds")
+	errTransactionNotFound = errors.New("transaction not found")
+)
+
+// Config - holds the parameters of the simulated network and contract
+type Config struct {
+	ChainID         string
+	RoundDuration   time.Duration
+	RoundsPerEpoch  uint64
+	UnBondPeriod    uint64 // rounds
+	EpochRewardRate uint64 // millionths of the total active stake distributed each epoch
+	MinDelegation   *data.Amount
+	NodePrice       *data.Amount
+	CreationCost    *data.Amount
+	FaucetAmount    *data.Amount // balance of an account when it is first seen
+}
+
+// DefaultConfig - returns a configuration with short epochs, suitable for demos
+func DefaultConfig() Config {
+	return Config{
+		ChainID:         "sandbox",
+		RoundDuration:   time.Second * 6,
+		RoundsPerEpoch:  600,
+		UnBondPeriod:    1200,
+		EpochRewardRate: 274,
+		MinDelegation:   data.MustParseAmount("10"),
+		NodePrice:       data.MustParseAmount("2500"),
+		CreationCost:    data.MustParseAmount("1250"),
+		FaucetAmount:    data.MustParseAmount("10000"),
+	}
+}
+
+type account struct {
+	nonce   uint64
+	balance *big.Int
+}
+
+// transfer - a value sent by a smart contract while executing a transaction
+type transfer struct {
+	sender   string
+	receiver string
+	value    *big.Int
+}
+
+// Simulator - holds the state of the simulated network and delegation contract
+type Simulator struct {
+	mut sync.RWMutex
+
+	cfg       Config
+	startTime time.Time
+	round     uint64
+	accounts  map[string]*account
+	contract  *contract
+	txs       []*data.APITransaction
+	transfers []*transfer // made by the transaction being executed
+	stop      chan struct{}
+}
+
+// NewSimulator - creates a new Simulator object. The contract is created by the
+// first createNewDelegationContract transaction sent to the delegation manager
+func NewSimulator(cfg Config) *Simulator {
+	return &Simulator{
+		cfg:       cfg,
+		startTime: time.Now(),
+		accounts: map[string]*account{
+			// holds the contract's staked and unbonding funds
+			network.ValidatorSCAddress: {balance: big.NewInt(0)},
+		},
+		txs: make([]*data.APITransaction, 0),
+	}
+}
+
+// Start - advances the simulated rounds in real time, every RoundDuration
+func (s *Simulator) Start() {
+	s.mut.Lock()
+	if s.stop != nil {
+		s.mut.Unlock()
+		return
+	}
+	s.stop = make(chan struct{})
+	stop := s.stop
+	s.mut.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(s.cfg.RoundDuration)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				s.AdvanceRounds(1)
+			}
+		}
+	}()
+}
+
+// Close - stops the real time clock started by Start
+func (s *Simulator) Close() {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	if s.stop != nil {
+		close(s.stop)
+		s.stop = nil
+	}
+}
+
+// CurrentRound - returns the current simulated round
+func (s *Simulator) CurrentRound() uint64 {
+	s.mut.RLock()
+	defer s.mut.RUnlock()
+
+	return s.round
+}
+
+// CurrentEpoch - returns the current simulated epoch
+func (s *Simulator) CurrentEpoch() uint64 {
+	s.mut.RLock()
+	defer s.mut.RUnlock()
+
+	return s.round / s.cfg.RoundsPerEpoch
+}
+
+// AdvanceRounds - moves the simulated clock n rounds ahead, distributing the rewards at each epoch change
+func (s *Simulator) AdvanceRounds(n uint64) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	for i := uint64(0); i < n; i++ {
+		s.round++
+		if s.round%s.cfg.RoundsPerEpoch == 0 && s.contract != nil {
+			rewards := s.contract.distributeRewards(s.cfg.EpochRewardRate)
+			s.credit(s.contract.address, rewards)
+		}
+	}
+}
+
+// AdvanceEpochs - moves the simulated clock to the start of the n-th next epoch
+func (s *Simulator) AdvanceEpochs(n uint64) {
+	if n == 0 {
+		return
+	}
+
+	s.mut.RLock()
+	rounds := s.cfg.RoundsPerEpoch - s.round%s.cfg.RoundsPerEpoch + (n-1)*s.cfg.RoundsPerEpoch
+	s.mut.RUnlock()
+
+	s.AdvanceRounds(rounds)
+}
+
+// ContractAddress - returns the address of the simulated contract, or an empty string if not created yet
+func (s *Simulator) ContractAddress() string {
+	s.mut.RLock()
+	defer s.mut.RUnlock()
+
+	if s.contract == nil {
+		return ""
+	}
+
+	return s.contract.address
+}
+
+// Fund - adds value to an account's balance
+func (s *Simulator) Fund(address string, value *data.Amount) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	acc := s.getAccount(address)
+	acc.balance.Add(acc.balance, value.Int())
+}
+
+// getAccount - returns an account, creating it with the faucet amount if it does not exist
+// the caller must hold the write lock
+func (s *Simulator) getAccount(address string) *account {
+	acc, ok := s.accounts[address]
+	if !ok {
+		acc = &account{balance: s.cfg.FaucetAmount.Int()}
+		s.accounts[address] = acc
+	}
+
+	return acc
+}
+
+// GetNetworkConfig - implements proxytest.Backend
+func (s *Simulator) GetNetworkConfig() *data.NetworkConfig {
+	cfg := proxytest.NewNetworkConfig(s.cfg.ChainID)
+	cfg.Data.Config.ErdRoundDuration = s.cfg.RoundDuration.Milliseconds()
+	cfg.Data.Config.ErdStartTime = s.startTime.Unix()
+
+	return cfg
+}
+
+// GetNetworkStatus - implements proxytest.Backend
+func (s *Simulator) GetNetworkStatus(_ uint32) (*data.NetworkStatus, error) {
+	s.mut.RLock()
+	defer s.mut.RUnlock()
+
+	return proxytest.NewNetworkStatus(s.round, s.cfg.RoundsPerEpoch), nil
+}
+
+// GetNetworkEconomics - implements proxytest.Backend. The simulated contract is the whole network
+// and the inflation is the reward distributed by the contract every epoch
+func (s *Simulator) GetNetworkEconomics() (*data.EconomicsMetrics, error) {
+	s.mut.RLock()
+	defer s.mut.RUnlock()
+
+	staked := big.NewInt(0)
+	topUp := big.NewInt(0)
+	inflation := big.NewInt(0)
+	if s.contract != nil {
+		staked = s.contract.totalActiveStake()
+		base := big.NewInt(0).Mul(s.cfg.NodePrice.Int(), big.NewInt(int64(s.contract.numNodesInState(stateStaked))))
+		if staked.Cmp(base) > 0 {
+			topUp.Sub(staked, base)
+		}
+		inflation.Mul(staked, big.NewInt(int64(s.cfg.EpochRewardRate)))
+		inflation.Quo(inflation, big.NewInt(ppm))
+	}
+
+	return &data.EconomicsMetrics{
+		TotalSupply:           data.MustParseAmount("20000000"),
+		TotalStakedValue:      data.NewAmount(staked),
+		TotalTopUpValue:       data.NewAmount(topUp),
+		Inflation:             data.NewAmount(inflation),
+		DevRewards:            data.NewAmount(nil),
+		EpochForEconomicsData: uint32(s.round / s.cfg.RoundsPerEpoch),
+	}, nil
+}
+
+// GetAccount - implements proxytest.Backend
+func (s *Simulator) GetAccount(address string) (*erdgo.Account, error) {
+	if !erdgo.IsValidBech32Address(address) {
+		return nil, errors.New("invalid address")
+	}
+
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	acc := s.getAccount(address)
+
+	return &erdgo.Account{
+		Address: address,
+		Nonce:   acc.nonce,
+		Balance: acc.balance.String(),
+	}, nil
+}
+
+// ExecuteQuery - implements proxytest.Backend
+func (s *Simulator) ExecuteQuery(query *data.ScQuery) ([][]byte, error) {
+	s.mut.RLock()
+	defer s.mut.RUnlock()
+
+	if query.ScAddress == network.DelegationManagerAddress {
+		return s.queryManager(query)
+	}
+	if query.ScAddress == network.ValidatorSCAddress {
+		return s.queryValidators(query)
+	}
+
+	if s.contract == nil || query.ScAddress != s.contract.address {
+		return nil, fmt.Errorf("account not found: %s", query.ScAddress)
+	}
+
+	return s.contract.query(query.FuncName, query.Args, s.round)
+}
+
+// queryManager - answers the delegation manager views
+// the caller must hold the read lock
+func (s *Simulator) queryManager(query *data.ScQuery) ([][]byte, error) {
+	if query.FuncName != "getAllContractAddresses" {
+		return nil, fmt.Errorf("invalid function %s", query.FuncName)
+	}
+	if query.Caller != network.DelegationManagerAddress {
+		return nil, errors.New("invalid caller")
+	}
+
+	if s.contract == nil {
+		return make([][]byte, 0), nil
+	}
+
+	pubkey, err := erdgo.Bech32ToPubkey(s.contract.address)
+	if err != nil {
+		return nil, err
+	}
+
+	return [][]byte{pubkey}, nil
+}
+
+// queryValidators - answers the validator system SC views. Only the contract's staked and unstaked
+// nodes are registered in the validator SC
+// the caller must hold the read lock
+func (s *Simulator) queryValidators(query *data.ScQuery) ([][]byte, error) {
+	if query.FuncName != "getBlsKeysStatus" {
+		return nil, fmt.Errorf("invalid function %s", query.FuncName)
+	}
+	if query.Caller != network.ValidatorSCAddress {
+		return nil, errors.New("invalid caller")
+	}
+	if len(query.Args) != 1 {
+		return nil, errInvalidArgs
+	}
+
+	returnData := make([][]byte, 0)
+	if s.contract == nil {
+		return returnData, nil
+	}
+
+	pubkey, err := erdgo.Bech32ToPubkey(s.contract.address)
+	if err != nil {
+		return nil, err
+	}
+	if query.Args[0] != hex.EncodeToString(pubkey) {
+		return returnData, nil
+	}
+
+	for _, nd := range s.contract.nodes {
+		if nd.state == stateNotStaked {
+			continue
+		}
+		returnData = append(returnData, nd.key, []byte(nd.state))
+	}
+
+	return returnData, nil
+}
+
+// GetValidatorStatistics - implements proxytest.Backend. The contract's staked nodes are
+// reported as eligible validators with a perfect rating
+func (s *Simulator) GetValidatorStatistics() (map[string]*data.ValidatorStatistics, error) {
+	s.mut.RLock()
+	defer s.mut.RUnlock()
+
+	statistics := make(map[string]*data.ValidatorStatistics)
+	if s.contract == nil {
+		return statistics, nil
+	}
+
+	for _, nd := range s.contract.nodes {
+		if nd.state != stateStaked {
+			continue
+		}
+		statistics[hex.EncodeToString(nd.key)] = &data.ValidatorStatistics{
+			TempRating:      100,
+			Rating:          100,
+			RatingModifier:  1,
+			ValidatorStatus: "eligible",
+		}
+	}
+
+	return statistics, nil
+}
+
+// SendTransaction - implements proxytest.Backend. The transaction is executed right away
+// and its result is available through GetTransaction and GetTransactions
+func (s *Simulator) SendTransaction(tx *erdgo.Transaction) (string, error) {
+	if !erdgo.IsValidBech32Address(tx.SndAddr) || !erdgo.IsValidBech32Address(tx.RcvAddr) {
+		return "", errors.New("invalid address")
+	}
+	value, ok := big.NewInt(0).SetString(tx.Value, 10)
+	if !ok || value.Sign() < 0 {
+		return "", errors.New("invalid value")
+	}
+
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	sender := s.getAccount(tx.SndAddr)
+	if tx.Nonce != sender.nonce {
+		return "", errInvalidNonce
+	}
+	if sender.balance.Cmp(value) < 0 {
+		return "", errInsufficientFunds
+	}
+
+	sender.nonce++
+	sender.balance.Sub(sender.balance, value)
+
+	hash := proxytest.TxHash(tx)
+	s.transfers = make([]*transfer, 0)
+	returnData, err := s.execute(tx, value)
+	status := "success"
+	scr := &data.APIScResult{
+		Hash:           hash,
+		Sender:         tx.RcvAddr,
+		Receiver:       tx.SndAddr,
+		Value:          "0",
+		PrevTxHash:     hash,
+		OriginalTxHash: hash,
+	}
+	results := []*data.APIScResult{scr}
+	if err != nil {
+		status = "fail"
+		sender.balance.Add(sender.balance, value)
+		scr.Value = value.String()
+		scr.Data = []byte("@" + hex.EncodeToString([]byte("user error")))
+		scr.ReturnMessage = err.Error()
+		log.Debug("simulated transaction failed", "function", string(tx.Data), "error", err)
+	} else {
+		scrData := "@" + hex.EncodeToString([]byte("ok"))
+		for _, d := range returnData {
+			scrData += "@" + hex.EncodeToString(d)
+		}
+		scr.Data = []byte(scrData)
+		for i, t := range s.transfers {
+			results = append(results, &data.APIScResult{
+				Hash:           fmt.Sprintf("%s%02x", hash[:len(hash)-2], i+1),
+				Sender:         t.sender,
+				Receiver:       t.receiver,
+				Value:          t.value.String(),
+				PrevTxHash:     hash,
+				OriginalTxHash: hash,
+			})
+		}
+	}
+
+	indexed := &data.APITransaction{
+		Hash:      hash,
+		Nonce:     tx.Nonce,
+		Round:     s.round,
+		Value:     tx.Value,
+		Receiver:  tx.RcvAddr,
+		Sender:    tx.SndAddr,
+		GasPrice:  tx.GasPrice,
+		GasLimit:  tx.GasLimit,
+		Data:      tx.Data,
+		Signature: tx.Signature,
+		Timestamp: time.Now().Unix(),
+		Status:    status,
+		Results:   results,
+	}
+	s.txs = append([]*data.APITransaction{indexed}, s.txs...)
+
+	return hash, nil
+}
+
+// EstimateTransactionCost - implements proxytest.Backend. The transaction is executed on a copy
+// of the state, so it has no effect. Smart contract calls use scCallGas on top of the move balance cost
+func (s *Simulator) EstimateTransactionCost(tx *erdgo.Transaction) (*data.TransactionCost, error) {
+	value, ok := big.NewInt(0).SetString(tx.Value, 10)
+	if !ok || value.Sign() < 0 {
+		return nil, errors.New("invalid value")
+	}
+
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	sender := s.getAccount(tx.SndAddr)
+	if sender.balance.Cmp(value) < 0 {
+		return &data.TransactionCost{ReturnMessage: errInsufficientFunds.Error()}, nil
+	}
+
+	contract := s.contract
+	accounts := s.accounts
+	if contract != nil {
+		s.contract = contract.clone()
+	}
+	s.accounts = make(map[string]*account, len(accounts))
+	for address, acc := range accounts {
+		s.accounts[address] = &account{nonce: acc.nonce, balance: big.NewInt(0).Set(acc.balance)}
+	}
+	s.transfers = make([]*transfer, 0)
+
+	_, err := s.execute(tx, value)
+	s.contract = contract
+	s.accounts = accounts
+	if err != nil {
+		return &data.TransactionCost{ReturnMessage: err.Error()}, nil
+	}
+
+	cfg := proxytest.NewNetworkConfig(s.cfg.ChainID).Data.Config
+	gas := cfg.ErdMinGasLimit + cfg.ErdGasPerDataByte*uint64(len(tx.Data))
+	if len(tx.Data) > 0 {
+		gas += scCallGas
+	}
+
+	return &data.TransactionCost{TxGasUnits: gas}, nil
+}
+
+// GetTransactions - implements proxytest.Backend. Transactions are returned newest first, like the API does
+func (s *Simulator) GetTransactions(params url.Values) ([]*data.APITransaction, error) {
+	s.mut.RLock()
+	defer s.mut.RUnlock()
+
+	return proxytest.FilterTransactions(s.txs, params), nil
+}
+
+// GetTransaction - implements proxytest.Backend
+func (s *Simulator) GetTransaction(hash string) (*data.TransactionOnNetwork, error) {
+	s.mut.RLock()
+	defer s.mut.RUnlock()
+
+	for _, tx := range s.txs {
+		if tx.Hash == hash {
+			return proxytest.NewTransactionOnNetwork(tx), nil
+		}
+	}
+
+	return nil, errTransactionNotFound
+}
+
+// transfer - sends value from a smart contract to an account, recording it as a smart contract result
+// the caller must hold the write lock
+func (s *Simulator) transfer(sender string, receiver string, value *big.Int) error {
+	from := s.getAccount(sender)
+	if from.balance.Cmp(value) < 0 {
+		return errInsufficientFunds
+	}
+
+	from.balance.Sub(from.balance, value)
+	s.credit(receiver, value)
+	s.transfers = append(s.transfers, &transfer{sender: sender, receiver: receiver, value: big.NewInt(0).Set(value)})
+
+	return nil
+}
+
+// credit - adds value to an account's balance, for the values received with a transaction or minted
+// as rewards
+// the caller must hold the write lock
+func (s *Simulator) credit(address string, value *big.Int) {
+	acc := s.getAccount(address)
+	acc.balance.Add(acc.balance, value)
+}
+
+// execute - runs a transaction against the delegation manager or the contract
+// the caller must hold the write lock
+func (s *Simulator) execute(tx *erdgo.Transaction, value *big.Int) ([][]byte, error) {
+	funcName, args, err := parseData(string(tx.Data))
+	if err != nil {
+		return nil, err
+	}
+
+	if tx.RcvAddr == network.DelegationManagerAddress {
+		if funcName != "createNewDelegationContract" {
+			return nil, fmt.Errorf("invalid function %s", funcName)
+		}
+		return s.createContract(tx.SndAddr, value, args)
+	}
+
+	if s.contract == nil || tx.RcvAddr != s.contract.address {
+		if funcName != "" {
+			return nil, errors.New("receiver is not a smart contract")
+		}
+		s.credit(tx.RcvAddr, value)
+		return nil, nil
+	}
+
+	return s.contract.execute(s, tx.SndAddr, value, funcName, args)
+}
+
+func (s *Simulator) createContract(owner string, value *big.Int, args [][]byte) ([][]byte, error) {
+	if s.contract != nil {
+		return nil, errors.New("the sandbox supports a single delegation contract")
+	}
+	if len(args) != 2 {
+		return nil, errors.New("invalid number of arguments")
+	}
+	if value.Cmp(s.cfg.CreationCost.Int()) != 0 {
+		return nil, fmt.Errorf("invalid value, %s eGLD required", s.cfg.CreationCost)
+	}
+
+	pubkey := make([]byte, 32)
+	pubkey[9] = 1
+	pubkey[30] = 0xff
+	pubkey[31] = 0xff
+	address, err := erdgo.PubkeyToBech32(pubkey)
+	if err != nil {
+		return nil, err
+	}
+
+	c := newContract(address, owner, s.cfg, s.round)
+	c.maxCap.SetBytes(args[0])
+	c.withCap = c.maxCap.Sign() > 0
+	c.serviceFee = big.NewInt(0).SetBytes(args[1]).Uint64()
+	c.initialOwnerFunds.Set(value)
+	c.getDelegator(owner).active.Set(value)
+	s.contract = c
+
+	// the initial owner funds are staked through the validator system SC
+	s.accounts[address] = &account{balance: big.NewInt(0)}
+	err = s.stake(value)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info("simulated delegation contract created", "address", address, "owner", owner)
+
+	return [][]byte{pubkey}, nil
+}
+
+// stake - receives the value paid to the contract and passes it on to the validator system SC
+// the caller must hold the write lock
+func (s *Simulator) stake(value *big.Int) error {
+	s.credit(s.contract.address, value)
+
+	return s.transfer(s.contract.address, network.ValidatorSCAddress, value)
+}
+
+// parseData - splits a transaction data field into the function name and the decoded arguments
+func parseData(txData string) (string, [][]byte, error) {
+	if txData == "" {
+		return "", nil, nil
+	}
+
+	parts := strings.Split(txData, "@")
+	args := make([][]byte, 0, len(parts)-1)
+	for _, p := range parts[1:] {
+		arg, err := hex.DecodeString(p)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid argument %s", p)
+		}
+		args = append(args, arg)
+	}
+
+	return parts[0], args, nil
+}
+
+var _ proxytest.Backend = (*Simulator)(nil)
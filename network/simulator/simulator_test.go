@@ -0,0 +1,320 @@
+package simulator_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/DrDelphi/ElrondDSSC/data"
+	"github.com/DrDelphi/ElrondDSSC/network"
+	"github.com/DrDelphi/ElrondDSSC/network/proxytest"
+	"github.com/DrDelphi/ElrondDSSC/network/simulator"
+	"github.com/ElrondNetwork/elrond-sdk/erdgo"
+)
+
+const serviceFee = 1000 // 10%
+
+// staticContract - always returns the same contract address
+type staticContract string
+
+func (c staticContract) ContractAddress() string {
+	return string(c)
+}
+
+// testKey - returns a private key made of the given byte and its address
+func testKey(t *testing.T, b byte) ([]byte, string) {
+	t.Helper()
+
+	privateKey := bytes.Repeat([]byte{b}, 32)
+	address, err := erdgo.GetAddressFromPrivateKey(privateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return privateKey, address
+}
+
+// sandbox - a NetworkManager talking to a simulator through the proxy stand-in
+type sandbox struct {
+	t   *testing.T
+	sim *simulator.Simulator
+	cfg simulator.Config
+	nm  *network.NetworkManager
+}
+
+func newSandbox(t *testing.T) *sandbox {
+	cfg := simulator.DefaultConfig()
+	cfg.RoundsPerEpoch = 10
+	cfg.UnBondPeriod = 25
+	cfg.EpochRewardRate = 1000
+
+	sim := simulator.NewSimulator(cfg)
+	server := proxytest.NewFakeServer(sim)
+	t.Cleanup(func() {
+		_ = server.Close()
+	})
+
+	nm, err := network.NewNetworkManager(&data.AppConfig{
+		NetworkAPI:   server.URL(),
+		NetworkProxy: server.URL(),
+		MetaObserver: server.URL(),
+		ChainID:      cfg.ChainID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &sandbox{t: t, sim: sim, cfg: cfg, nm: nm}
+}
+
+// send - sends a transaction and checks it succeeded
+func (s *sandbox) send(privateKey []byte, req *network.TxRequest) string {
+	s.t.Helper()
+
+	hash, err := s.nm.GetTxBuilder().Send(privateKey, req)
+	if err != nil {
+		s.t.Fatalf("%s: %v", req.Function, err)
+	}
+	status, err := s.nm.GetTransactionStatus(hash)
+	if err != nil {
+		s.t.Fatalf("%s: %v", req.Function, err)
+	}
+	if status != network.TxStatusSuccess {
+		tx, _ := s.nm.GetTransaction(hash)
+		s.t.Fatalf("%s: status %s, %+v", req.Function, status, tx)
+	}
+
+	return hash
+}
+
+func (s *sandbox) requireAmount(name string, get func(string) (*data.Amount, error), address string, want string) {
+	s.t.Helper()
+
+	got, err := get(address)
+	if err != nil {
+		s.t.Fatalf("%s: %v", name, err)
+	}
+	if got.Cmp(data.MustParseAmount(want)) != 0 {
+		s.t.Fatalf("%s = %v, want %s", name, got, want)
+	}
+}
+
+func (s *sandbox) balance(address string) *data.Amount {
+	s.t.Helper()
+
+	account, err := s.nm.GetAccount(address)
+	if err != nil {
+		s.t.Fatal(err)
+	}
+	balance, err := data.ParseAttoAmount(account.Balance)
+	if err != nil {
+		s.t.Fatal(err)
+	}
+
+	return balance
+}
+
+// requireBalances - checks the validator SC holds the active and the undelegated stake, and the contract
+// the rewards not claimed yet
+func (s *sandbox) requireBalances(contract string, delegators ...string) {
+	s.t.Helper()
+
+	staked := big.NewInt(0)
+	rewards := big.NewInt(0)
+	for _, address := range delegators {
+		for _, get := range []func(string) (*data.Amount, error){s.nm.GetUserActiveStake, s.nm.GetUserUnStakedValue} {
+			amount, err := get(address)
+			if err != nil {
+				s.t.Fatal(err)
+			}
+			staked.Add(staked, amount.Int())
+		}
+		claimable, err := s.nm.GetClaimableRewards(address)
+		if err != nil {
+			s.t.Fatal(err)
+		}
+		rewards.Add(rewards, claimable.Int())
+	}
+
+	if s.balance(network.ValidatorSCAddress).Cmp(data.NewAmount(staked)) != 0 {
+		s.t.Fatalf("validator SC balance %v, want %v", s.balance(network.ValidatorSCAddress), data.NewAmount(staked))
+	}
+	if s.balance(contract).Cmp(data.NewAmount(rewards)) != 0 {
+		s.t.Fatalf("contract balance %v, want %v", s.balance(contract), data.NewAmount(rewards))
+	}
+}
+
+func TestSimulatorThroughNetworkManager(t *testing.T) {
+	s := newSandbox(t)
+	ownerKey, owner := testKey(t, 1)
+	userKey, user := testKey(t, 2)
+	blsKey := bytes.Repeat([]byte{0xb1}, 96)
+
+	// contract creation and setup
+	s.send(ownerKey, network.CreateDelegationContractTx(data.NewAmount(nil), serviceFee))
+	addresses, err := s.nm.GetAllContractAddresses()
+	if err != nil || len(addresses) != 1 {
+		t.Fatalf("GetAllContractAddresses() = %v, %v", addresses, err)
+	}
+	contract := addresses[0]
+	s.nm.SetContractAddressProvider(staticContract(contract))
+
+	metadata := &data.ContractMetadata{Name: "Sandbox", Website: "https://example.com", Identifier: "sandbox"}
+	s.send(ownerKey, network.SetMetaDataTx(contract, metadata))
+	s.send(ownerKey, network.AddNodesTx(contract, blsKey, bytes.Repeat([]byte{0x5}, 48)))
+	s.send(userKey, network.DelegateTx(contract, data.MustParseAmount("1500")))
+	s.send(ownerKey, network.StakeNodesTx(contract, blsKey))
+
+	info, err := s.nm.GetContractInfo(contract)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.OwnerAddress != owner || info.ServiceFee != 10 || info.InitialOwnerFunds.Cmp(network.CreationCost) != 0 ||
+		info.WithDelegationCap || info.UnBondPeriod != s.cfg.UnBondPeriod {
+		t.Fatalf("GetContractInfo() = %+v", info)
+	}
+	contractOwner, err := s.nm.GetContractOwner(contract)
+	if err != nil || contractOwner != owner {
+		t.Fatalf("GetContractOwner() = %s, %v, want %s", contractOwner, err, owner)
+	}
+	gotMetadata, err := s.nm.GetContractMetadata(contract)
+	if err != nil || *gotMetadata != *metadata {
+		t.Fatalf("GetContractMetadata() = %+v, %v", gotMetadata, err)
+	}
+
+	total, err := s.nm.GetTotalActiveStake()
+	if err != nil || total.Cmp(data.MustParseAmount("2750")) != 0 {
+		t.Fatalf("GetTotalActiveStake() = %v, %v, want 2750", total, err)
+	}
+	users, err := s.nm.GetNumUsers()
+	if err != nil || users != 2 {
+		t.Fatalf("GetNumUsers() = %d, %v, want 2", users, err)
+	}
+	numNodes, err := s.nm.GetNumNodes()
+	if err != nil || numNodes != 1 {
+		t.Fatalf("GetNumNodes() = %d, %v, want 1", numNodes, err)
+	}
+	nodes, err := s.nm.GetAllNodeStates()
+	if err != nil || len(nodes) != 1 || !bytes.Equal(nodes[0].BlsKey, blsKey) || nodes[0].DSSCState != "staked" {
+		t.Fatalf("GetAllNodeStates() = %+v, %v", nodes, err)
+	}
+	statuses, err := s.nm.GetBlsKeysStatus(contract)
+	if err != nil || statuses[hex.EncodeToString(blsKey)] != "staked" {
+		t.Fatalf("GetBlsKeysStatus() = %v, %v", statuses, err)
+	}
+	statistics, err := s.nm.GetValidatorStatistics()
+	if err != nil || statistics[hex.EncodeToString(blsKey)] == nil {
+		t.Fatalf("GetValidatorStatistics() = %v, %v", statistics, err)
+	}
+	s.requireAmount("user active stake", s.nm.GetUserActiveStake, user, "1500")
+	s.requireAmount("owner active stake", s.nm.GetUserActiveStake, owner, "1250")
+	s.requireBalances(contract, owner, user)
+
+	// one epoch of rewards: 0.1% of 2750, of which 10% service fee for the owner
+	s.sim.AdvanceEpochs(1)
+	s.requireAmount("user rewards", s.nm.GetClaimableRewards, user, "1.35")
+	s.requireAmount("owner rewards", s.nm.GetClaimableRewards, owner, "1.4")
+	s.requireAmount("user cumulated rewards", s.nm.GetUserCumulatedRewards, user, "1.35")
+	cumulated, err := s.nm.GetTotalCumulatedRewards()
+	if err != nil || cumulated.Cmp(data.MustParseAmount("2.75")) != 0 {
+		t.Fatalf("GetTotalCumulatedRewards() = %v, %v, want 2.75", cumulated, err)
+	}
+	economics, err := s.nm.GetNetworkEconomics()
+	if err != nil || economics.TotalStakedValue.Cmp(data.MustParseAmount("2750")) != 0 ||
+		economics.TotalTopUpValue.Cmp(data.MustParseAmount("250")) != 0 {
+		t.Fatalf("GetNetworkEconomics() = %+v, %v", economics, err)
+	}
+	s.requireBalances(contract, owner, user)
+
+	userBalance := s.balance(user)
+	s.send(userKey, network.ClaimRewardsTx(contract))
+	s.requireAmount("user rewards after claim", s.nm.GetClaimableRewards, user, "0")
+	s.requireAmount("user cumulated rewards after claim", s.nm.GetUserCumulatedRewards, user, "1.35")
+	if s.balance(user).Cmp(userBalance.Add(data.MustParseAmount("1.35"))) != 0 {
+		t.Fatalf("user balance %v after the claim", s.balance(user))
+	}
+	s.send(ownerKey, network.ReDelegateRewardsTx(contract))
+	s.requireAmount("owner active stake after redelegation", s.nm.GetUserActiveStake, owner, "1251.4")
+	s.requireBalances(contract, owner, user)
+
+	// undelegate, wait for the unbond period, withdraw
+	s.send(userKey, network.UnDelegateTx(contract, data.MustParseAmount("500")))
+	s.requireAmount("user active stake", s.nm.GetUserActiveStake, user, "1000")
+	s.requireAmount("user unstaked", s.nm.GetUserUnStakedValue, user, "500")
+	s.requireAmount("user unbondable", s.nm.GetUserUnBondable, user, "0")
+	list, err := s.nm.GetUserUnDelegatedList(user)
+	if err != nil || len(list) != 1 || list[0].Amount.Cmp(data.MustParseAmount("500")) != 0 ||
+		list[0].RemainingRounds != s.cfg.UnBondPeriod {
+		t.Fatalf("GetUserUnDelegatedList() = %+v, %v", list, err)
+	}
+	s.requireBalances(contract, owner, user)
+
+	s.sim.AdvanceRounds(s.cfg.UnBondPeriod)
+	s.requireAmount("user unbondable", s.nm.GetUserUnBondable, user, "500")
+
+	userBalance = s.balance(user)
+	withdrawHash := s.send(userKey, network.WithdrawTx(contract))
+	s.requireAmount("user unstaked after withdraw", s.nm.GetUserUnStakedValue, user, "0")
+	if s.balance(user).Cmp(userBalance.Add(data.MustParseAmount("500"))) != 0 {
+		t.Fatalf("user balance %v after the withdraw", s.balance(user))
+	}
+	s.requireBalances(contract, owner, user)
+
+	// the indexed transactions decode to the wallet's operations
+	txs, err := s.nm.GetTransactions(user, contract, 0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	operations := make(map[string]string)
+	for _, tx := range txs {
+		operation := network.DecodeWalletOperation(tx, contract)
+		if operation == nil || operation.Status != network.TxStatusSuccess {
+			t.Fatalf("DecodeWalletOperation(%s) = %+v", tx.Hash, operation)
+		}
+		operations[operation.Function] = operation.Amount.String()
+		if tx.Hash == withdrawHash && operation.Amount.Cmp(data.MustParseAmount("500")) != 0 {
+			t.Fatalf("withdraw decoded as %v", operation.Amount)
+		}
+	}
+	want := map[string]string{
+		network.FuncDelegate:     data.MustParseAmount("1500").String(),
+		network.FuncClaimRewards: data.MustParseAmount("1.35").String(),
+		network.FuncUnDelegate:   data.MustParseAmount("500").String(),
+		network.FuncWithdraw:     data.MustParseAmount("500").String(),
+	}
+	if len(operations) != len(want) {
+		t.Fatalf("decoded operations %v, want %v", operations, want)
+	}
+	for function, amount := range want {
+		if operations[function] != amount {
+			t.Fatalf("%s decoded as %s, want %s", function, operations[function], amount)
+		}
+	}
+}
+
+func TestSimulatorFailedTransactionKeepsBalances(t *testing.T) {
+	s := newSandbox(t)
+	ownerKey, _ := testKey(t, 1)
+	userKey, user := testKey(t, 2)
+
+	s.send(ownerKey, network.CreateDelegationContractTx(data.NewAmount(nil), serviceFee))
+	contract := s.sim.ContractAddress()
+	s.nm.SetContractAddressProvider(staticContract(contract))
+
+	before := s.balance(user)
+	hash, err := s.nm.GetTxBuilder().Send(userKey, network.DelegateTx(contract, data.MustParseAmount("1")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	status, err := s.nm.GetTransactionStatus(hash)
+	if err != nil || status != network.TxStatusFail {
+		t.Fatalf("GetTransactionStatus() = %s, %v, want fail", status, err)
+	}
+	if s.balance(user).Cmp(before) != 0 {
+		t.Fatalf("user balance %v after a failed delegation, want %v", s.balance(user), before)
+	}
+	if s.balance(contract).Sign() != 0 || s.balance(network.ValidatorSCAddress).Cmp(network.CreationCost) != 0 {
+		t.Fatalf("contract balance %v, validator SC balance %v", s.balance(contract), s.balance(network.ValidatorSCAddress))
+	}
+}
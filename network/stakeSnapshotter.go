@@ -0,0 +1,263 @@
+package network
+
+import (
+	"sync"
+	"time"
+
+	"github.com/DrDelphi/ElrondDSSC/data"
+)
+
+// RewardsPeriods - the periods the recent rewards are summed up over
+var RewardsPeriods = []time.Duration{24 * time.Hour, 7 * 24 * time.Hour, 30 * 24 * time.Hour}
+
+// snapshotEpochDelay - the wait after an epoch change before taking the snapshots, giving the
+// delegation contract time to distribute the rewards of the ended epoch
+const snapshotEpochDelay = time.Minute
+
+// StakeSnapshotStorer - defines the persistence of the stake snapshots
+type StakeSnapshotStorer interface {
+	AddStakeSnapshots(snapshots []*data.StakeSnapshot) error
+	GetLastStakeSnapshot(address string, contract string) (*data.StakeSnapshot, error)
+	GetStakeSnapshots(address string, contract string, since int64) ([]*data.StakeSnapshot, error)
+}
+
+// UserLister - defines the listing of the users, whose wallets are monitored
+type UserLister interface {
+	GetUsers() map[int64]*data.User
+}
+
+// StakeSnapshotter - records the delegation balances of all the users' wallets at each epoch change,
+// or at a fixed interval, and sums up the rewards they earned
+type StakeSnapshotter struct {
+	contract  DelegationContract
+	storer    StakeSnapshotStorer
+	users     UserLister
+	contracts ContractAddressProvider
+	clock     *NetworkClock
+	interval  time.Duration
+
+	mut         sync.Mutex
+	startedOnce sync.Once
+}
+
+// NewStakeSnapshotter - creates a new StakeSnapshotter object. With a zero interval the snapshots are
+// taken at each epoch change
+func NewStakeSnapshotter(contract DelegationContract, storer StakeSnapshotStorer, users UserLister,
+	contracts ContractAddressProvider, clock *NetworkClock, interval time.Duration) *StakeSnapshotter {
+	return &StakeSnapshotter{
+		contract:  contract,
+		storer:    storer,
+		users:     users,
+		contracts: contracts,
+		clock:     clock,
+		interval:  interval,
+	}
+}
+
+// Start - schedules the snapshots
+func (ss *StakeSnapshotter) Start() {
+	ss.startedOnce.Do(func() {
+		if ss.interval <= 0 {
+			ss.clock.OnEpochChange(func(epoch uint32) {
+				go func() {
+					time.Sleep(snapshotEpochDelay)
+					ss.snapshotAndLog()
+				}()
+			})
+			return
+		}
+
+		go func() {
+			for {
+				time.Sleep(ss.interval)
+				ss.snapshotAndLog()
+			}
+		}()
+	})
+}
+
+func (ss *StakeSnapshotter) snapshotAndLog() {
+	count, err := ss.Snapshot()
+	if err != nil {
+		log.Warn("can not take stake snapshots", "error", err)
+		return
+	}
+
+	log.Debug("stake snapshots taken", "wallets", count)
+}
+
+// Snapshot - reads and saves the balances of all the users' wallets with the contract, and returns the
+// number of snapshots saved. Wallets which never delegated are skipped
+func (ss *StakeSnapshotter) Snapshot() (int, error) {
+	ss.mut.Lock()
+	defer ss.mut.Unlock()
+
+	contract := ss.contracts.ContractAddress()
+	if contract == "" {
+		return 0, nil
+	}
+
+	now := time.Now().Unix()
+	epoch := ss.clock.CurrentEpoch()
+	snapshots := make([]*data.StakeSnapshot, 0)
+	for _, address := range ss.walletAddresses() {
+		snapshot, err := ss.readSnapshot(address, contract)
+		if err != nil {
+			log.Warn("can not read wallet balances", "address", address, "error", err)
+			continue
+		}
+		snapshot.Epoch = epoch
+		snapshot.Timestamp = now
+
+		previous, err := ss.storer.GetLastStakeSnapshot(address, contract)
+		if err != nil {
+			return 0, err
+		}
+		if previous == nil && snapshot.ActiveStake.IsZero() && snapshot.UnStaked.IsZero() &&
+			snapshot.UnBondable.IsZero() && snapshot.ClaimableRewards.IsZero() {
+			continue
+		}
+
+		snapshot.Earned = earnedSince(previous, snapshot)
+		snapshots = append(snapshots, snapshot)
+	}
+
+	if len(snapshots) == 0 {
+		return 0, nil
+	}
+
+	return len(snapshots), ss.storer.AddStakeSnapshots(snapshots)
+}
+
+// walletAddresses - returns the addresses of all the users' wallets, each one once
+func (ss *StakeSnapshotter) walletAddresses() []string {
+	seen := make(map[string]bool)
+	addresses := make([]string, 0)
+	for _, user := range ss.users.GetUsers() {
+		for _, wallet := range user.Wallets {
+			if seen[wallet.Address] {
+				continue
+			}
+
+			seen[wallet.Address] = true
+			addresses = append(addresses, wallet.Address)
+		}
+	}
+
+	return addresses
+}
+
+func (ss *StakeSnapshotter) readSnapshot(address string, contract string) (*data.StakeSnapshot, error) {
+	activeStake, err := ss.contract.GetUserActiveStake(address)
+	if err != nil {
+		return nil, err
+	}
+	unStaked, err := ss.contract.GetUserUnStakedValue(address)
+	if err != nil {
+		return nil, err
+	}
+	unBondable, err := ss.contract.GetUserUnBondable(address)
+	if err != nil {
+		return nil, err
+	}
+	claimable, err := ss.contract.GetClaimableRewards(address)
+	if err != nil {
+		return nil, err
+	}
+	cumulated, err := ss.contract.GetUserCumulatedRewards(address)
+	if err != nil {
+		return nil, err
+	}
+
+	return &data.StakeSnapshot{
+		Address:          address,
+		Contract:         contract,
+		ActiveStake:      activeStake,
+		UnStaked:         unStaked,
+		UnBondable:       unBondable,
+		ClaimableRewards: claimable,
+		CumulatedRewards: cumulated,
+	}, nil
+}
+
+// earnedSince - returns the rewards earned since the previous snapshot, from the contract's cumulated rewards
+// of the wallet, which claiming or compounding does not reset. Nothing is counted without a previous snapshot
+// holding the cumulated rewards
+func earnedSince(previous *data.StakeSnapshot, current *data.StakeSnapshot) *data.Amount {
+	if previous == nil || previous.CumulatedRewards == nil || current.CumulatedRewards == nil ||
+		current.CumulatedRewards.Cmp(previous.CumulatedRewards) < 0 {
+		return data.NewAmount(nil)
+	}
+
+	return current.CumulatedRewards.Sub(previous.CumulatedRewards)
+}
+
+// RecentRewards - returns the rewards a wallet earned with the contract over each of the RewardsPeriods
+func (ss *StakeSnapshotter) RecentRewards(address string) ([]*data.RewardsEarned, error) {
+	contract := ss.contracts.ContractAddress()
+	now := time.Now()
+
+	// also read the snapshot before the longest period, the base of the first one inside it
+	margin := 2 * 24 * time.Hour
+	if 2*ss.interval > margin {
+		margin = 2 * ss.interval
+	}
+	longest := RewardsPeriods[len(RewardsPeriods)-1]
+	snapshots, err := ss.storer.GetStakeSnapshots(address, contract, now.Add(-longest-margin).Unix())
+	if err != nil {
+		return nil, err
+	}
+
+	rewards := make([]*data.RewardsEarned, 0, len(RewardsPeriods))
+	for _, period := range RewardsPeriods {
+		rewards = append(rewards, sumRewards(snapshots, period, now))
+	}
+
+	return rewards, nil
+}
+
+// sumRewards - sums up the rewards earned by the snapshots taken within the period. The first snapshot
+// inside the period only counts if the previous one is known, as it holds the rewards earned since then
+func sumRewards(snapshots []*data.StakeSnapshot, period time.Duration, now time.Time) *data.RewardsEarned {
+	start := now.Add(-period).Unix()
+	earned := data.NewAmount(nil)
+	base := int64(-1)
+	last := int64(-1)
+	for _, s := range snapshots {
+		if s.Timestamp <= start || base < 0 {
+			base = s.Timestamp
+			continue
+		}
+
+		earned = earned.Add(s.Earned)
+		last = s.Timestamp
+	}
+
+	covered := time.Duration(0)
+	if last > base {
+		covered = time.Duration(last-base) * time.Second
+	}
+
+	return &data.RewardsEarned{
+		Period:  period,
+		Covered: covered,
+		Earned:  earned,
+	}
+}
+
+// History - returns the newest snapshots of a wallet's balances with the contract, newest first
+func (ss *StakeSnapshotter) History(address string, limit int) ([]*data.StakeSnapshot, error) {
+	contract := ss.contracts.ContractAddress()
+	longest := RewardsPeriods[len(RewardsPeriods)-1]
+	snapshots, err := ss.storer.GetStakeSnapshots(address, contract, time.Now().Add(-longest).Unix())
+	if err != nil {
+		return nil, err
+	}
+
+	history := make([]*data.StakeSnapshot, 0, limit)
+	for i := len(snapshots) - 1; i >= 0 && len(history) < limit; i-- {
+		history = append(history, snapshots[i])
+	}
+
+	return history, nil
+}
@@ -0,0 +1,89 @@
+package network
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DrDelphi/ElrondDSSC/data"
+)
+
+// rewardsSnapshot - a snapshot with the given claimable and cumulated rewards. An empty cumulated value
+// stands for a snapshot taken before the cumulated rewards were recorded
+func rewardsSnapshot(timestamp int64, claimable string, cumulated string) *data.StakeSnapshot {
+	s := &data.StakeSnapshot{Timestamp: timestamp, ClaimableRewards: data.MustParseAmount(claimable)}
+	if cumulated != "" {
+		s.CumulatedRewards = data.MustParseAmount(cumulated)
+	}
+
+	return s
+}
+
+func TestEarnedSince(t *testing.T) {
+	tests := []struct {
+		name     string
+		previous *data.StakeSnapshot
+		current  *data.StakeSnapshot
+		want     string
+	}{
+		{"first snapshot", nil, rewardsSnapshot(200, "1", "5"), "0"},
+		{"rewards accrued", rewardsSnapshot(100, "1", "5"), rewardsSnapshot(200, "2.2", "6.2"), "1.2"},
+		{"claim then rewards", rewardsSnapshot(100, "1", "5"), rewardsSnapshot(200, "1.2", "6.2"), "1.2"},
+		{"compound then rewards", rewardsSnapshot(100, "3", "5"), rewardsSnapshot(200, "0.5", "5.5"), "0.5"},
+		{"claim without rewards", rewardsSnapshot(100, "1", "5"), rewardsSnapshot(200, "0", "5"), "0"},
+		{"previous without cumulated", rewardsSnapshot(100, "1", ""), rewardsSnapshot(200, "1.2", "6.2"), "0"},
+		{"current without cumulated", rewardsSnapshot(100, "1", "5"), rewardsSnapshot(200, "1.2", ""), "0"},
+		{"cumulated decreased", rewardsSnapshot(100, "1", "5"), rewardsSnapshot(200, "1", "4"), "0"},
+	}
+	for _, tt := range tests {
+		earned := earnedSince(tt.previous, tt.current)
+		if earned.Cmp(data.MustParseAmount(tt.want)) != 0 {
+			t.Errorf("%s: earned %v, want %v", tt.name, earned, tt.want)
+		}
+	}
+}
+
+func TestSumRewards(t *testing.T) {
+	now := time.Unix(1000000, 0)
+	day := int64(24 * time.Hour / time.Second)
+	at := func(daysAgo float64, earned string) *data.StakeSnapshot {
+		return &data.StakeSnapshot{
+			Timestamp: now.Unix() - int64(daysAgo*float64(day)),
+			Earned:    data.MustParseAmount(earned),
+		}
+	}
+
+	tests := []struct {
+		name        string
+		snapshots   []*data.StakeSnapshot
+		period      time.Duration
+		wantEarned  string
+		wantCovered time.Duration
+	}{
+		{"no snapshots", nil, 24 * time.Hour, "0", 0},
+		{
+			"base before the period",
+			[]*data.StakeSnapshot{at(3, "9"), at(1.5, "1"), at(0.5, "1.5"), at(0.25, "0.5")},
+			24 * time.Hour, "2", 30 * time.Hour,
+		},
+		{
+			"no base snapshot",
+			[]*data.StakeSnapshot{at(0.75, "7"), at(0.5, "1"), at(0.25, "2")},
+			24 * time.Hour, "3", 12 * time.Hour,
+		},
+		{"single snapshot", []*data.StakeSnapshot{at(0.5, "4")}, 24 * time.Hour, "0", 0},
+		{"all before the period", []*data.StakeSnapshot{at(3, "1"), at(2, "1")}, 24 * time.Hour, "0", 0},
+		{
+			"longer period",
+			[]*data.StakeSnapshot{at(8, "9"), at(6, "1"), at(3, "1"), at(1, "1"), at(0.5, "1")},
+			7 * 24 * time.Hour, "4", 180 * time.Hour,
+		},
+	}
+	for _, tt := range tests {
+		rewards := sumRewards(tt.snapshots, tt.period, now)
+		if rewards.Earned.Cmp(data.MustParseAmount(tt.wantEarned)) != 0 || rewards.Covered != tt.wantCovered ||
+			rewards.Period != tt.period {
+			t.Errorf("%s: earned %v over %v, want %v over %v", tt.name, rewards.Earned, rewards.Covered,
+				tt.wantEarned, tt.wantCovered)
+		}
+	}
+}
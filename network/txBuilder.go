@@ -0,0 +1,367 @@
+package network
+
+import (
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/DrDelphi/ElrondDSSC/data"
+	"github.com/DrDelphi/ElrondDSSC/network/httpclient"
+	"github.com/ElrondNetwork/elrond-sdk/erdgo"
+)
+
+// gasEstimationMargin - percent added on top of the proxy's gas estimation
+const gasEstimationMargin = 10
+
+// TxRequest - holds the fields of a transaction to be built, before the sender is known
+type TxRequest struct {
+	Receiver string
+	Value    *data.Amount
+	Function string
+	Args     [][]byte
+	GasLimit uint64 // if 0, the gas limit is estimated by the proxy
+}
+
+// Data - returns the func@arg@arg data field of the transaction
+func (tr *TxRequest) Data() string {
+	parts := make([]string, 0, len(tr.Args)+1)
+	parts = append(parts, tr.Function)
+	for _, arg := range tr.Args {
+		parts = append(parts, hex.EncodeToString(arg))
+	}
+
+	return strings.Join(parts, "@")
+}
+
+// BigIntArg - encodes a big integer argument. Zero is encoded as a single 0x00 byte
+func BigIntArg(v *big.Int) []byte {
+	if v == nil || v.Sign() == 0 {
+		return []byte{0}
+	}
+
+	return v.Bytes()
+}
+
+// AmountArg - encodes an eGLD amount argument, in atto-eGLD
+func AmountArg(a *data.Amount) []byte {
+	return BigIntArg(a.Int())
+}
+
+// Uint64Arg - encodes an unsigned integer argument
+func Uint64Arg(v uint64) []byte {
+	return BigIntArg(big.NewInt(0).SetUint64(v))
+}
+
+// BoolArg - encodes a boolean argument the way the system smart contracts expect it ("yes" / "no")
+func BoolArg(b bool) []byte {
+	if b {
+		return []byte("yes")
+	}
+
+	return []byte("no")
+}
+
+// TxBuilder - builds, signs and broadcasts transactions, keeping track of the senders' nonces
+type TxBuilder struct {
+	client        *httpclient.Client
+	networkConfig *data.NetworkConfig
+	accounts      AccountReader
+	broadcaster   TransactionBroadcaster
+
+	nonces    map[string]uint64
+	sent      map[string]string // the senders of the broadcasted transactions, by hash, until their outcome
+	noncesMut sync.Mutex
+}
+
+// NewTxBuilder - creates a new TxBuilder object
+func NewTxBuilder(client *httpclient.Client, networkConfig *data.NetworkConfig, accounts AccountReader, broadcaster TransactionBroadcaster) *TxBuilder {
+	return &TxBuilder{
+		client:        client,
+		networkConfig: networkConfig,
+		accounts:      accounts,
+		broadcaster:   broadcaster,
+		nonces:        make(map[string]uint64),
+		sent:          make(map[string]string),
+	}
+}
+
+// Build - creates an unsigned transaction from sender, reserving the next available nonce. The nonce is
+// released if the transaction can not be built
+func (tb *TxBuilder) Build(sender string, req *TxRequest) (*erdgo.Transaction, error) {
+	nonce, err := tb.reserveNonce(sender)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := tb.build(sender, req, nonce)
+	if err != nil {
+		tb.releaseNonce(sender, nonce)
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+// build - creates an unsigned transaction with the given nonce, estimating its gas limit if not set
+func (tb *TxBuilder) build(sender string, req *TxRequest, nonce uint64) (*erdgo.Transaction, error) {
+	tx := tb.newTransaction(sender, req, nonce)
+	if tx.GasLimit == 0 {
+		var err error
+		tx.GasLimit, err = tb.EstimateGas(tx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return tx, nil
+}
+
+// newTransaction - creates an unsigned transaction with the given nonce and the request's gas limit
+func (tb *TxBuilder) newTransaction(sender string, req *TxRequest, nonce uint64) *erdgo.Transaction {
+	cfg := tb.networkConfig.Data.Config
+
+	return &erdgo.Transaction{
+		Nonce:    nonce,
+		Value:    req.Value.AttoString(),
+		RcvAddr:  req.Receiver,
+		SndAddr:  sender,
+		GasPrice: cfg.ErdMinGasPrice,
+		GasLimit: req.GasLimit,
+		Data:     []byte(req.Data()),
+		ChainID:  cfg.ErdChainID,
+		Version:  uint32(cfg.ErdMinTransactionVersion),
+	}
+}
+
+// Sign - signs a transaction with the provided private key
+func (tb *TxBuilder) Sign(tx *erdgo.Transaction, privateKey []byte) error {
+	return erdgo.SignTransaction(tx, privateKey)
+}
+
+// Send - builds, signs and broadcasts a transaction from the private key's address
+func (tb *TxBuilder) Send(privateKey []byte, req *TxRequest) (string, error) {
+	sender, err := erdgo.GetAddressFromPrivateKey(privateKey)
+	if err != nil {
+		return "", err
+	}
+
+	tx, err := tb.Build(sender, req)
+	if err != nil {
+		return "", err
+	}
+
+	err = tb.Sign(tx, privateKey)
+	if err != nil {
+		tb.releaseNonce(sender, tx.Nonce)
+		return "", err
+	}
+
+	hash, err := tb.broadcaster.SendTransaction(tx)
+	if err != nil {
+		tb.releaseNonce(sender, tx.Nonce)
+		return "", err
+	}
+
+	tb.noncesMut.Lock()
+	tb.sent[hash] = sender
+	tb.noncesMut.Unlock()
+
+	return hash, nil
+}
+
+// ResetNonce - forgets the locally tracked nonce of an address, so the next build reads it from the network
+func (tb *TxBuilder) ResetNonce(address string) {
+	tb.noncesMut.Lock()
+	delete(tb.nonces, address)
+	tb.noncesMut.Unlock()
+}
+
+// HandleTxOutcome - resets the sender's nonce when a transaction sent by the builder was dropped or is invalid,
+// since the nonces tracked after it would otherwise never be executed
+func (tb *TxBuilder) HandleTxOutcome(outcome *TxOutcome) {
+	tb.noncesMut.Lock()
+	sender, ok := tb.sent[outcome.Tx.Hash]
+	delete(tb.sent, outcome.Tx.Hash)
+	tb.noncesMut.Unlock()
+
+	if !ok || (outcome.Status != TxStatusTimeout && outcome.Status != TxStatusInvalid) {
+		return
+	}
+
+	log.Debug("resetting the nonce after an unexecuted transaction", "sender", sender, "hash", outcome.Tx.Hash,
+		"status", outcome.Status)
+	tb.ResetNonce(sender)
+}
+
+// nextNonce - returns the highest between the account's nonce and the locally tracked one
+func (tb *TxBuilder) nextNonce(address string) (uint64, error) {
+	account, err := tb.accounts.GetAccount(address)
+	if err != nil {
+		return 0, err
+	}
+
+	tb.noncesMut.Lock()
+	defer tb.noncesMut.Unlock()
+
+	return tb.maxNonce(address, account.Nonce), nil
+}
+
+// reserveNonce - returns the next nonce of an address and tracks the following one, so concurrent
+// builds from the same address get distinct nonces
+func (tb *TxBuilder) reserveNonce(address string) (uint64, error) {
+	account, err := tb.accounts.GetAccount(address)
+	if err != nil {
+		return 0, err
+	}
+
+	tb.noncesMut.Lock()
+	defer tb.noncesMut.Unlock()
+
+	nonce := tb.maxNonce(address, account.Nonce)
+	tb.nonces[address] = nonce + 1
+
+	return nonce, nil
+}
+
+// releaseNonce - gives back a reserved nonce whose transaction was not sent. If a later nonce was reserved
+// meanwhile, the tracked nonce is forgotten and the next build reads it from the network
+func (tb *TxBuilder) releaseNonce(address string, nonce uint64) {
+	tb.noncesMut.Lock()
+	defer tb.noncesMut.Unlock()
+
+	if tb.nonces[address] == nonce+1 {
+		tb.nonces[address] = nonce
+		return
+	}
+
+	delete(tb.nonces, address)
+}
+
+func (tb *TxBuilder) maxNonce(address string, accountNonce uint64) uint64 {
+	if local, ok := tb.nonces[address]; ok && local > accountNonce {
+		return local
+	}
+
+	return accountNonce
+}
+
+// EstimateGas - asks the proxy for the gas units needed by a transaction and adds a safety margin
+func (tb *TxBuilder) EstimateGas(tx *erdgo.Transaction) (uint64, error) {
+	cost, err := tb.getTransactionCost(tx)
+	if err != nil {
+		return 0, err
+	}
+	if cost.TxGasUnits == 0 {
+		return 0, errors.New("can not estimate gas: " + cost.ReturnMessage)
+	}
+
+	return tb.gasWithMargin(cost.TxGasUnits), nil
+}
+
+// gasWithMargin - adds the safety margin to the estimated gas units, keeping the network's minimum gas limit
+func (tb *TxBuilder) gasWithMargin(gasUnits uint64) uint64 {
+	gas := gasUnits + gasUnits*gasEstimationMargin/100
+	if gas < tb.networkConfig.Data.Config.ErdMinGasLimit {
+		gas = tb.networkConfig.Data.Config.ErdMinGasLimit
+	}
+
+	return gas
+}
+
+// Simulate - runs a transaction from sender through the proxy's cost estimation, without sending it or
+// reserving its nonce. The prediction covers the sender's balance, the gas limit and the contract's checks.
+// The proxy's rejections of the transaction are reported in the simulation's error, also when estimating
+// the gas limit
+func (tb *TxBuilder) Simulate(sender string, req *TxRequest) (*data.TxSimulation, error) {
+	nonce, err := tb.nextNonce(sender)
+	if err != nil {
+		return nil, err
+	}
+	tx := tb.newTransaction(sender, req, nonce)
+
+	simulation := &data.TxSimulation{
+		Sender:   sender,
+		GasLimit: tx.GasLimit,
+		Fee:      data.NewAmount(nil),
+	}
+
+	cost, err := tb.getTransactionCost(tx)
+	var proxyErr *httpclient.ProxyError
+	if errors.As(err, &proxyErr) && proxyErr.StatusCode < http.StatusInternalServerError {
+		simulation.Error = proxyErr.Message
+		return simulation, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if tx.GasLimit == 0 {
+		tx.GasLimit = tb.gasWithMargin(cost.TxGasUnits)
+		simulation.GasLimit = tx.GasLimit
+	}
+
+	account, err := tb.accounts.GetAccount(sender)
+	if err != nil {
+		return nil, err
+	}
+	balance, ok := big.NewInt(0).SetString(account.Balance, 10)
+	if !ok {
+		balance = big.NewInt(0)
+	}
+	maxFee := big.NewInt(0).Mul(big.NewInt(0).SetUint64(tx.GasLimit), big.NewInt(0).SetUint64(tx.GasPrice))
+	if balance.Cmp(big.NewInt(0).Add(req.Value.Int(), maxFee)) < 0 {
+		simulation.Error = "insufficient funds for the value and the fee"
+		return simulation, nil
+	}
+
+	simulation.GasUnits = cost.TxGasUnits
+	switch {
+	case cost.ReturnMessage != "":
+		simulation.Error = cost.ReturnMessage
+	case cost.TxGasUnits == 0:
+		simulation.Error = "the transaction can not be executed"
+	case cost.TxGasUnits > tx.GasLimit:
+		simulation.Error = "not enough gas"
+	default:
+		simulation.Fee = tb.ComputeFee(tx, cost.TxGasUnits)
+	}
+
+	return simulation, nil
+}
+
+// ComputeFee - returns the fee paid by a transaction using gasUnits. The gas used by smart contract
+// execution, above the move balance cost, is charged at the gas price times the network's modifier
+func (tb *TxBuilder) ComputeFee(tx *erdgo.Transaction, gasUnits uint64) *data.Amount {
+	cfg := tb.networkConfig.Data.Config
+	gasPrice := big.NewInt(0).SetUint64(tx.GasPrice)
+	moveBalanceGas := cfg.ErdMinGasLimit + cfg.ErdGasPerDataByte*uint64(len(tx.Data))
+	if gasUnits <= moveBalanceGas {
+		return data.NewAmount(big.NewInt(0).Mul(big.NewInt(0).SetUint64(gasUnits), gasPrice))
+	}
+
+	fee := big.NewInt(0).Mul(big.NewInt(0).SetUint64(moveBalanceGas), gasPrice)
+	modifier, ok := big.NewRat(0, 1).SetString(cfg.ErdGasPriceModifier)
+	if !ok {
+		modifier = big.NewRat(1, 1)
+	}
+	executionFee := big.NewRat(0, 1).SetInt(big.NewInt(0).Mul(big.NewInt(0).SetUint64(gasUnits-moveBalanceGas), gasPrice))
+	executionFee.Mul(executionFee, modifier)
+	fee.Add(fee, big.NewInt(0).Quo(executionFee.Num(), executionFee.Denom()))
+
+	return data.NewAmount(fee)
+}
+
+func (tb *TxBuilder) getTransactionCost(tx *erdgo.Transaction) (*data.TransactionCost, error) {
+	ctx, cancel := newRequestContext()
+	defer cancel()
+
+	res := &data.TransactionCostResponse{}
+	err := tb.client.PostJSON(ctx, "/transaction/cost", tx, res)
+	if err != nil {
+		return nil, err
+	}
+
+	return &res.Data, nil
+}
@@ -0,0 +1,478 @@
+package network_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/DrDelphi/ElrondDSSC/data"
+	"github.com/DrDelphi/ElrondDSSC/network"
+	"github.com/DrDelphi/ElrondDSSC/network/httpclient"
+	"github.com/DrDelphi/ElrondDSSC/network/networktest"
+	"github.com/ElrondNetwork/elrond-sdk/erdgo"
+)
+
+const testContract = "erd1qqqqqqqqqqqqqqqpqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqhllllsajxzat"
+
+// costProxy - answers the proxy's /transaction/cost endpoint with a configurable response
+type costProxy struct {
+	mut       sync.Mutex
+	status    int
+	response  *data.TransactionCostResponse
+	requested int
+}
+
+func (cp *costProxy) set(status int, response *data.TransactionCostResponse) {
+	cp.mut.Lock()
+	defer cp.mut.Unlock()
+
+	cp.status = status
+	cp.response = response
+}
+
+func (cp *costProxy) requests() int {
+	cp.mut.Lock()
+	defer cp.mut.Unlock()
+
+	return cp.requested
+}
+
+func (cp *costProxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	cp.mut.Lock()
+	defer cp.mut.Unlock()
+
+	if r.URL.Path != "/transaction/cost" || r.Method != http.MethodPost {
+		http.NotFound(w, r)
+		return
+	}
+	cp.requested++
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(cp.status)
+	_ = json.NewEncoder(w).Encode(cp.response)
+}
+
+func gasUnits(units uint64) *data.TransactionCostResponse {
+	res := &data.TransactionCostResponse{Code: "successful"}
+	res.Data.TxGasUnits = units
+
+	return res
+}
+
+func newTestNetworkConfig() *data.NetworkConfig {
+	cfg := &data.NetworkConfig{}
+	cfg.Data.Config.ErdChainID = "T"
+	cfg.Data.Config.ErdMinGasLimit = 50000
+	cfg.Data.Config.ErdMinGasPrice = 1000000000
+	cfg.Data.Config.ErdGasPerDataByte = 1500
+	cfg.Data.Config.ErdGasPriceModifier = "0.01"
+	cfg.Data.Config.ErdMinTransactionVersion = 1
+
+	return cfg
+}
+
+func newTestTxBuilder(t *testing.T) (*network.TxBuilder, *networktest.Fake, *costProxy) {
+	proxy := &costProxy{status: http.StatusOK, response: gasUnits(1000000)}
+	server := httptest.NewServer(proxy)
+	t.Cleanup(server.Close)
+
+	clientConfig := httpclient.DefaultConfig()
+	clientConfig.MaxRetries = 0
+	client, err := httpclient.NewClient([]string{server.URL}, clientConfig)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	fake := networktest.NewFake()
+
+	return network.NewTxBuilder(client, newTestNetworkConfig(), fake, fake), fake, proxy
+}
+
+func newTestKey(t *testing.T) ([]byte, string) {
+	privateKey := bytes.Repeat([]byte{7}, 32)
+	address, err := erdgo.GetAddressFromPrivateKey(privateKey)
+	if err != nil {
+		t.Fatalf("GetAddressFromPrivateKey: %v", err)
+	}
+
+	return privateKey, address
+}
+
+func delegateRequest() *network.TxRequest {
+	return &network.TxRequest{
+		Receiver: testContract,
+		Value:    data.MustParseAmount("10"),
+		Function: "delegate",
+		GasLimit: 12000000,
+	}
+}
+
+func TestArgEncoders(t *testing.T) {
+	tests := []struct {
+		name string
+		arg  []byte
+		want string
+	}{
+		{"nil big int", network.BigIntArg(nil), "00"},
+		{"zero big int", network.BigIntArg(big.NewInt(0)), "00"},
+		{"big int", network.BigIntArg(big.NewInt(256)), "0100"},
+		{"zero amount", network.AmountArg(data.NewAmount(nil)), "00"},
+		{"one eGLD", network.AmountArg(data.MustParseAmount("1")), "0de0b6b3a7640000"},
+		{"atto eGLD", network.AmountArg(data.MustParseAmount("0.000000000000000001")), "01"},
+		{"zero uint64", network.Uint64Arg(0), "00"},
+		{"service fee", network.Uint64Arg(1250), "04e2"},
+		{"max uint64", network.Uint64Arg(^uint64(0)), "ffffffffffffffff"},
+		{"true", network.BoolArg(true), "796573"},
+		{"false", network.BoolArg(false), "6e6f"},
+	}
+
+	for _, tt := range tests {
+		if got := hex.EncodeToString(tt.arg); got != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestTxRequestData(t *testing.T) {
+	req := &network.TxRequest{Function: "claimRewards"}
+	if got := req.Data(); got != "claimRewards" {
+		t.Errorf("no arguments: got %q", got)
+	}
+
+	req = &network.TxRequest{
+		Function: "modifyTotalDelegationCap",
+		Args:     [][]byte{network.AmountArg(data.NewAmount(nil)), network.Uint64Arg(1250), network.BoolArg(true)},
+	}
+	if got := req.Data(); got != "modifyTotalDelegationCap@00@04e2@796573" {
+		t.Errorf("with arguments: got %q", got)
+	}
+}
+
+func TestBuild(t *testing.T) {
+	builder, fake, proxy := newTestTxBuilder(t)
+	_, sender := newTestKey(t)
+	fake.SetAccount(sender, data.MustParseAmount("100"), 5)
+
+	tx, err := builder.Build(sender, delegateRequest())
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	if tx.Nonce != 5 || tx.SndAddr != sender || tx.RcvAddr != testContract || tx.Value != "10000000000000000000" ||
+		string(tx.Data) != "delegate" || tx.GasLimit != 12000000 || tx.GasPrice != 1000000000 ||
+		tx.ChainID != "T" || tx.Version != 1 {
+		t.Errorf("unexpected transaction %+v", tx)
+	}
+	if proxy.requests() != 0 {
+		t.Errorf("the gas was estimated although the request has a gas limit")
+	}
+
+	// the nonce is reserved by Build, even before the transaction is sent
+	tx, err = builder.Build(sender, delegateRequest())
+	if err != nil || tx.Nonce != 6 {
+		t.Fatalf("Build: expected nonce 6, got %+v, %v", tx, err)
+	}
+
+	// the network's nonce wins once it is ahead of the tracked one
+	fake.SetAccount(sender, data.MustParseAmount("100"), 10)
+	tx, err = builder.Build(sender, delegateRequest())
+	if err != nil || tx.Nonce != 10 {
+		t.Fatalf("Build: expected nonce 10, got %+v, %v", tx, err)
+	}
+
+	builder.ResetNonce(sender)
+	tx, err = builder.Build(sender, delegateRequest())
+	if err != nil || tx.Nonce != 10 {
+		t.Fatalf("Build after ResetNonce: expected nonce 10, got %+v, %v", tx, err)
+	}
+}
+
+func TestBuildConcurrent(t *testing.T) {
+	builder, fake, _ := newTestTxBuilder(t)
+	_, sender := newTestKey(t)
+	fake.SetAccount(sender, data.MustParseAmount("100"), 0)
+
+	const builds = 20
+	nonces := make([]int, builds)
+	var wg sync.WaitGroup
+	for i := 0; i < builds; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			tx, err := builder.Build(sender, delegateRequest())
+			if err != nil {
+				t.Errorf("Build: %v", err)
+				return
+			}
+			nonces[i] = int(tx.Nonce)
+		}(i)
+	}
+	wg.Wait()
+
+	sort.Ints(nonces)
+	for i, nonce := range nonces {
+		if nonce != i {
+			t.Fatalf("expected nonces 0 to %v, got %v", builds-1, nonces)
+		}
+	}
+}
+
+func TestBuildEstimatesGas(t *testing.T) {
+	builder, fake, proxy := newTestTxBuilder(t)
+	_, sender := newTestKey(t)
+	fake.SetAccount(sender, data.MustParseAmount("100"), 3)
+
+	req := delegateRequest()
+	req.GasLimit = 0
+	tx, err := builder.Build(sender, req)
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	if tx.GasLimit != 1100000 || proxy.requests() != 1 {
+		t.Errorf("expected 1100000 gas estimated by the proxy, got %v", tx.GasLimit)
+	}
+
+	// a failed estimation gives the nonce back
+	res := gasUnits(0)
+	res.Data.ReturnMessage = "insufficient funds"
+	proxy.set(http.StatusOK, res)
+	_, err = builder.Build(sender, req)
+	if err == nil {
+		t.Fatal("Build: expected the gas estimation error")
+	}
+
+	tx, err = builder.Build(sender, delegateRequest())
+	if err != nil || tx.Nonce != 4 {
+		t.Fatalf("Build: expected the released nonce 4, got %+v, %v", tx, err)
+	}
+}
+
+func TestBuildAccountError(t *testing.T) {
+	builder, fake, _ := newTestTxBuilder(t)
+	_, sender := newTestKey(t)
+	fake.SetError("GetAccount", errors.New("proxy down"))
+
+	_, err := builder.Build(sender, delegateRequest())
+	if err == nil {
+		t.Fatal("Build: expected the account error")
+	}
+}
+
+func TestEstimateGas(t *testing.T) {
+	builder, _, proxy := newTestTxBuilder(t)
+	tx := &erdgo.Transaction{Data: []byte("delegate")}
+
+	tests := []struct {
+		name    string
+		units   uint64
+		message string
+		want    uint64
+	}{
+		{"margin added", 1000000, "", 1100000},
+		{"minimum gas limit", 10000, "", 50000},
+		{"not executable", 0, "function not found", 0},
+	}
+
+	for _, tt := range tests {
+		res := gasUnits(tt.units)
+		res.Data.ReturnMessage = tt.message
+		proxy.set(http.StatusOK, res)
+
+		gas, err := builder.EstimateGas(tx)
+		if tt.want == 0 {
+			if err == nil || err.Error() != "can not estimate gas: "+tt.message {
+				t.Errorf("%s: expected the return message as error, got %v", tt.name, err)
+			}
+			continue
+		}
+		if err != nil || gas != tt.want {
+			t.Errorf("%s: got %v, %v, want %v", tt.name, gas, err, tt.want)
+		}
+	}
+}
+
+func TestSend(t *testing.T) {
+	builder, fake, _ := newTestTxBuilder(t)
+	privateKey, sender := newTestKey(t)
+	fake.SetAccount(sender, data.MustParseAmount("100"), 7)
+
+	for i := 0; i < 2; i++ {
+		_, err := builder.Send(privateKey, delegateRequest())
+		if err != nil {
+			t.Fatalf("Send: %v", err)
+		}
+	}
+
+	// a failed broadcast gives the nonce back
+	fake.SetError("SendTransaction", errors.New("rejected"))
+	_, err := builder.Send(privateKey, delegateRequest())
+	if err == nil {
+		t.Fatal("Send: expected the broadcast error")
+	}
+	fake.SetError("SendTransaction", nil)
+	_, err = builder.Send(privateKey, delegateRequest())
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	sent := fake.SentTransactions()
+	if len(sent) != 3 || sent[0].Nonce != 7 || sent[1].Nonce != 8 || sent[2].Nonce != 9 {
+		t.Fatalf("expected nonces 7, 8 and 9 sent, got %+v", sent)
+	}
+	for _, tx := range sent {
+		if len(tx.Signature) != 128 {
+			t.Errorf("transaction %v is not signed", tx.Nonce)
+		}
+	}
+}
+
+func TestSimulate(t *testing.T) {
+	builder, fake, proxy := newTestTxBuilder(t)
+	_, sender := newTestKey(t)
+	fake.SetAccount(sender, data.MustParseAmount("100"), 2)
+
+	proxy.set(http.StatusOK, gasUnits(12000000))
+	simulation, err := builder.Simulate(sender, delegateRequest())
+	if err != nil {
+		t.Fatalf("Simulate: %v", err)
+	}
+	// the move balance gas (50000 + 8 data bytes * 1500) at the full price, the rest at 1%
+	if !simulation.Succeeded() || simulation.Sender != sender || simulation.GasLimit != 12000000 ||
+		simulation.GasUnits != 12000000 || simulation.Fee.AttoString() != "181380000000000" {
+		t.Errorf("unexpected simulation %+v, fee %v", simulation, simulation.Fee)
+	}
+
+	// simulations do not reserve nonces
+	tx, err := builder.Build(sender, delegateRequest())
+	if err != nil || tx.Nonce != 2 {
+		t.Fatalf("Build after Simulate: expected nonce 2, got %+v, %v", tx, err)
+	}
+
+	tests := []struct {
+		name     string
+		balance  string
+		status   int
+		response *data.TransactionCostResponse
+		want     string
+	}{
+		{"insufficient funds", "10", http.StatusOK, gasUnits(12000000),
+			"insufficient funds for the value and the fee"},
+		{"contract error", "100", http.StatusOK, &data.TransactionCostResponse{
+			Data: data.TransactionCost{ReturnMessage: "delegation cap reached"}}, "delegation cap reached"},
+		{"not executable", "100", http.StatusOK, gasUnits(0), "the transaction can not be executed"},
+		{"not enough gas", "100", http.StatusOK, gasUnits(13000000), "not enough gas"},
+		{"proxy error", "100", http.StatusBadRequest, &data.TransactionCostResponse{
+			Error: "invalid receiver", Code: "bad_request"}, "invalid receiver"},
+	}
+
+	for _, tt := range tests {
+		fake.SetAccount(sender, data.MustParseAmount(tt.balance), 2)
+		proxy.set(tt.status, tt.response)
+
+		simulation, err := builder.Simulate(sender, delegateRequest())
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if simulation.Error != tt.want || simulation.Succeeded() {
+			t.Errorf("%s: got error %q, want %q", tt.name, simulation.Error, tt.want)
+		}
+	}
+}
+
+func TestSimulateEstimatesGas(t *testing.T) {
+	builder, fake, proxy := newTestTxBuilder(t)
+	_, sender := newTestKey(t)
+	fake.SetAccount(sender, data.MustParseAmount("100"), 2)
+	req := delegateRequest()
+	req.GasLimit = 0
+
+	simulation, err := builder.Simulate(sender, req)
+	if err != nil {
+		t.Fatalf("Simulate: %v", err)
+	}
+	if !simulation.Succeeded() || simulation.GasLimit != 1100000 || simulation.GasUnits != 1000000 ||
+		proxy.requests() != 1 {
+		t.Errorf("unexpected simulation %+v after %d cost requests", simulation, proxy.requests())
+	}
+
+	// the cost endpoint's rejections are the simulation's outcome, not errors
+	tests := []struct {
+		name     string
+		status   int
+		response *data.TransactionCostResponse
+		want     string
+	}{
+		{"contract error", http.StatusOK, &data.TransactionCostResponse{
+			Data: data.TransactionCost{ReturnMessage: "delegation cap reached"}}, "delegation cap reached"},
+		{"not executable", http.StatusOK, gasUnits(0), "the transaction can not be executed"},
+		{"proxy error", http.StatusBadRequest, &data.TransactionCostResponse{
+			Error: "invalid receiver", Code: "bad_request"}, "invalid receiver"},
+	}
+
+	for _, tt := range tests {
+		proxy.set(tt.status, tt.response)
+
+		simulation, err := builder.Simulate(sender, req)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if simulation.Error != tt.want || simulation.Succeeded() {
+			t.Errorf("%s: got error %q, want %q", tt.name, simulation.Error, tt.want)
+		}
+	}
+
+	proxy.set(http.StatusInternalServerError, &data.TransactionCostResponse{Error: "node down", Code: "internal_issue"})
+	_, err = builder.Simulate(sender, req)
+	if err == nil {
+		t.Error("Simulate: expected the proxy failure as error")
+	}
+}
+
+func TestHandleTxOutcome(t *testing.T) {
+	builder, fake, _ := newTestTxBuilder(t)
+	privateKey, sender := newTestKey(t)
+	fake.SetAccount(sender, data.MustParseAmount("100"), 7)
+
+	first, err := builder.Send(privateKey, delegateRequest())
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	second, err := builder.Send(privateKey, delegateRequest())
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	// executed transactions and the ones sent by others keep the tracked nonce
+	builder.HandleTxOutcome(&network.TxOutcome{Tx: &data.PendingTx{Hash: first}, Status: network.TxStatusFail})
+	builder.HandleTxOutcome(&network.TxOutcome{Tx: &data.PendingTx{Hash: "other"}, Status: network.TxStatusTimeout})
+	tx, err := builder.Build(sender, delegateRequest())
+	if err != nil || tx.Nonce != 9 {
+		t.Fatalf("Build: expected nonce 9, got %+v, %v", tx, err)
+	}
+
+	// a dropped transaction resets the nonce to the network's one
+	builder.HandleTxOutcome(&network.TxOutcome{Tx: &data.PendingTx{Hash: second}, Status: network.TxStatusTimeout})
+	tx, err = builder.Build(sender, delegateRequest())
+	if err != nil || tx.Nonce != 7 {
+		t.Fatalf("Build after a dropped transaction: expected nonce 7, got %+v, %v", tx, err)
+	}
+
+	fake.SetAccount(sender, data.MustParseAmount("100"), 8)
+	invalid, err := builder.Send(privateKey, delegateRequest())
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	builder.HandleTxOutcome(&network.TxOutcome{Tx: &data.PendingTx{Hash: invalid}, Status: network.TxStatusInvalid})
+	tx, err = builder.Build(sender, delegateRequest())
+	if err != nil || tx.Nonce != 8 {
+		t.Fatalf("Build after an invalid transaction: expected nonce 8, got %+v, %v", tx, err)
+	}
+}
@@ -0,0 +1,243 @@
+package network
+
+import (
+	"encoding/hex"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/DrDelphi/ElrondDSSC/data"
+)
+
+// transaction statuses, as reported by the proxy
+const (
+	TxStatusSuccess           = "success"
+	TxStatusExecuted          = "executed"
+	TxStatusFail              = "fail"
+	TxStatusNotExecuted       = "not-executed"
+	TxStatusInvalid           = "invalid"
+	TxStatusTimeout           = "timeout"
+	txStatusPending           = "pending"
+	txStatusReceived          = "received"
+	txStatusPartiallyExecuted = "partially-executed"
+)
+
+const (
+	txTrackerPollInterval = time.Second * 6
+	txTrackerMaxAge       = time.Hour
+	okReturnCode          = "ok"
+)
+
+// PendingTxStorer - defines the persistence of the tracked transactions
+type PendingTxStorer interface {
+	AddPendingTx(tx *data.PendingTx) error
+	GetPendingTxs() ([]*data.PendingTx, error)
+	RemovePendingTx(hash string) error
+}
+
+// TxOutcome - holds the final result of a tracked transaction
+type TxOutcome struct {
+	Tx     *data.PendingTx
+	Status string
+	Reason string
+}
+
+// Succeeded - returns true if the transaction and all its smart contract calls succeeded
+func (o *TxOutcome) Succeeded() bool {
+	return o.Status == TxStatusSuccess
+}
+
+// TxTracker - polls the proxy for the outcome of broadcasted transactions and reports it to its handlers
+type TxTracker struct {
+	reader TransactionReader
+	storer PendingTxStorer
+
+	pending     map[string]*data.PendingTx
+	handlers    []func(outcome *TxOutcome)
+	mut         sync.Mutex
+	startedOnce sync.Once
+}
+
+// NewTxTracker - creates a new TxTracker object
+func NewTxTracker(reader TransactionReader, storer PendingTxStorer) *TxTracker {
+	return &TxTracker{
+		reader:   reader,
+		storer:   storer,
+		pending:  make(map[string]*data.PendingTx),
+		handlers: make([]func(outcome *TxOutcome), 0),
+	}
+}
+
+// OnOutcome - registers a handler called once for every tracked transaction which reached a final state
+func (tt *TxTracker) OnOutcome(handler func(outcome *TxOutcome)) {
+	tt.mut.Lock()
+	tt.handlers = append(tt.handlers, handler)
+	tt.mut.Unlock()
+}
+
+// Track - starts tracking a broadcasted transaction on behalf of a Telegram user
+func (tt *TxTracker) Track(hash string, tgID int64, description string) error {
+	tx := &data.PendingTx{
+		Hash:        hash,
+		TgID:        tgID,
+		Description: description,
+		CreatedAt:   time.Now().Unix(),
+	}
+
+	err := tt.storer.AddPendingTx(tx)
+	if err != nil {
+		log.Error("can not save pending transaction", "hash", hash, "error", err)
+		return err
+	}
+
+	tt.mut.Lock()
+	tt.pending[hash] = tx
+	tt.mut.Unlock()
+
+	return nil
+}
+
+// Start - loads the transactions left pending by a previous run and starts polling
+func (tt *TxTracker) Start() {
+	tt.startedOnce.Do(func() {
+		txs, err := tt.storer.GetPendingTxs()
+		if err != nil {
+			log.Error("can not read pending transactions", "error", err)
+		}
+
+		tt.mut.Lock()
+		for _, tx := range txs {
+			tt.pending[tx.Hash] = tx
+		}
+		tt.mut.Unlock()
+
+		go func() {
+			for {
+				tt.checkPending()
+				time.Sleep(txTrackerPollInterval)
+			}
+		}()
+	})
+}
+
+func (tt *TxTracker) checkPending() {
+	tt.mut.Lock()
+	txs := make([]*data.PendingTx, 0, len(tt.pending))
+	for _, tx := range tt.pending {
+		txs = append(txs, tx)
+	}
+	tt.mut.Unlock()
+
+	for _, tx := range txs {
+		outcome := tt.checkTx(tx)
+		if outcome == nil {
+			continue
+		}
+
+		err := tt.storer.RemovePendingTx(tx.Hash)
+		if err != nil {
+			log.Warn("can not remove pending transaction", "hash", tx.Hash, "error", err)
+		}
+
+		tt.mut.Lock()
+		delete(tt.pending, tx.Hash)
+		handlers := make([]func(outcome *TxOutcome), len(tt.handlers))
+		copy(handlers, tt.handlers)
+		tt.mut.Unlock()
+
+		log.Debug("transaction outcome", "hash", tx.Hash, "status", outcome.Status, "reason", outcome.Reason)
+		for _, handler := range handlers {
+			handler(outcome)
+		}
+	}
+}
+
+// checkTx - returns the outcome of a transaction or nil if it is still pending
+func (tt *TxTracker) checkTx(tx *data.PendingTx) *TxOutcome {
+	expired := time.Since(time.Unix(tx.CreatedAt, 0)) > txTrackerMaxAge
+
+	status, err := tt.reader.GetTransactionStatus(tx.Hash)
+	if err != nil || status == txStatusPending || status == txStatusReceived || status == txStatusPartiallyExecuted {
+		if expired {
+			return &TxOutcome{Tx: tx, Status: TxStatusTimeout, Reason: "no final status after " + txTrackerMaxAge.String()}
+		}
+		return nil
+	}
+
+	outcome := &TxOutcome{Tx: tx, Status: status}
+	switch status {
+	case TxStatusSuccess, TxStatusExecuted:
+		outcome.Status = TxStatusSuccess
+	case TxStatusNotExecuted:
+		outcome.Status = TxStatusFail
+	case TxStatusFail, TxStatusInvalid:
+	default:
+		log.Warn("unknown transaction status", "hash", tx.Hash, "status", status)
+		return nil
+	}
+
+	details, err := tt.reader.GetTransaction(tx.Hash)
+	if err != nil {
+		log.Warn("can not get transaction details", "hash", tx.Hash, "error", err)
+		outcome.Reason = statusReason(status)
+		return outcome
+	}
+
+	ok, reason := ParseScResults(details.SmartContractResults)
+	if !ok {
+		outcome.Status = TxStatusFail
+		outcome.Reason = reason
+	}
+	if outcome.Status != TxStatusSuccess && outcome.Reason == "" {
+		outcome.Reason = statusReason(status)
+	}
+
+	return outcome
+}
+
+// statusReason - describes an unsuccessful status, for the outcomes whose failure reason is not known
+func statusReason(status string) string {
+	switch status {
+	case TxStatusSuccess, TxStatusExecuted:
+		return ""
+	case TxStatusInvalid:
+		return "the transaction is invalid"
+	case TxStatusNotExecuted:
+		return "the transaction was not executed"
+	default:
+		return "the transaction failed"
+	}
+}
+
+// ParseScResults - looks for the return code of a smart contract call (the @6f6b@... "ok" result or an
+// error code) and returns false together with the decoded reason if the call failed
+func ParseScResults(results []*data.SmartContractResult) (bool, string) {
+	for _, scr := range results {
+		if !strings.HasPrefix(scr.Data, "@") {
+			continue
+		}
+
+		parts := strings.Split(scr.Data, "@")
+		code, err := hex.DecodeString(parts[1])
+		if err != nil {
+			continue
+		}
+		if string(code) == okReturnCode {
+			return true, ""
+		}
+
+		reason := string(code)
+		if scr.ReturnMessage != "" {
+			reason += ": " + scr.ReturnMessage
+		} else if len(parts) > 2 {
+			message, err := hex.DecodeString(parts[2])
+			if err == nil {
+				reason += ": " + string(message)
+			}
+		}
+
+		return false, reason
+	}
+
+	return true, ""
+}
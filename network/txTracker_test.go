@@ -0,0 +1,165 @@
+package network_test
+
+import (
+	"encoding/hex"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DrDelphi/ElrondDSSC/data"
+	"github.com/DrDelphi/ElrondDSSC/network"
+)
+
+func hexData(parts ...string) string {
+	result := ""
+	for _, part := range parts {
+		result += "@" + hex.EncodeToString([]byte(part))
+	}
+
+	return result
+}
+
+func TestParseScResults(t *testing.T) {
+	tests := []struct {
+		name       string
+		results    []*data.SmartContractResult
+		wantOk     bool
+		wantReason string
+	}{
+		{"no results", nil, true, ""},
+		{"ok", []*data.SmartContractResult{{Data: hexData("ok")}}, true, ""},
+		{"ok after a transfer", []*data.SmartContractResult{{Data: "delegate"}, {Data: hexData("ok", "01")}}, true, ""},
+		{
+			"error with return message",
+			[]*data.SmartContractResult{{Data: hexData("user error"), ReturnMessage: "delegation cap reached"}},
+			false, "user error: delegation cap reached",
+		},
+		{
+			"error with hex message",
+			[]*data.SmartContractResult{{Data: hexData("user error", "not enough funds")}},
+			false, "user error: not enough funds",
+		},
+		{"error without message", []*data.SmartContractResult{{Data: hexData("user error")}}, false, "user error"},
+		{
+			"error with malformed hex message",
+			[]*data.SmartContractResult{{Data: hexData("user error") + "@zz"}},
+			false, "user error",
+		},
+		{"malformed hex code", []*data.SmartContractResult{{Data: "@6f6"}}, true, ""},
+		{
+			"malformed hex code before an error",
+			[]*data.SmartContractResult{{Data: "@zz"}, {Data: hexData("user error", "paused")}},
+			false, "user error: paused",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			ok, reason := network.ParseScResults(tt.results)
+			if ok != tt.wantOk || reason != tt.wantReason {
+				t.Errorf("ParseScResults = %v, %q, want %v, %q", ok, reason, tt.wantOk, tt.wantReason)
+			}
+		})
+	}
+}
+
+// statusReader - reports a fixed status for every transaction, with details only if set
+type statusReader struct {
+	status  string
+	details *data.TransactionOnNetwork
+}
+
+func (sr *statusReader) GetTransactionStatus(_ string) (string, error) {
+	return sr.status, nil
+}
+
+func (sr *statusReader) GetTransaction(_ string) (*data.TransactionOnNetwork, error) {
+	if sr.details == nil {
+		return nil, errors.New("transaction not found")
+	}
+
+	return sr.details, nil
+}
+
+// memoryPendingTxs - keeps the pending transactions in memory
+type memoryPendingTxs struct {
+	mut sync.Mutex
+	txs map[string]*data.PendingTx
+}
+
+func (mp *memoryPendingTxs) AddPendingTx(tx *data.PendingTx) error {
+	mp.mut.Lock()
+	defer mp.mut.Unlock()
+
+	mp.txs[tx.Hash] = tx
+
+	return nil
+}
+
+func (mp *memoryPendingTxs) GetPendingTxs() ([]*data.PendingTx, error) {
+	return nil, nil
+}
+
+func (mp *memoryPendingTxs) RemovePendingTx(hash string) error {
+	mp.mut.Lock()
+	defer mp.mut.Unlock()
+
+	delete(mp.txs, hash)
+
+	return nil
+}
+
+func TestTxTrackerOutcome(t *testing.T) {
+	tests := []struct {
+		name       string
+		reader     *statusReader
+		wantStatus string
+		wantReason string
+	}{
+		{"success", &statusReader{status: network.TxStatusSuccess}, network.TxStatusSuccess, ""},
+		{"fail without details", &statusReader{status: network.TxStatusFail}, network.TxStatusFail,
+			"the transaction failed"},
+		{"invalid without details", &statusReader{status: network.TxStatusInvalid}, network.TxStatusInvalid,
+			"the transaction is invalid"},
+		{"not executed without details", &statusReader{status: network.TxStatusNotExecuted}, network.TxStatusFail,
+			"the transaction was not executed"},
+		{
+			"invalid without results",
+			&statusReader{status: network.TxStatusInvalid, details: &data.TransactionOnNetwork{}},
+			network.TxStatusInvalid, "the transaction is invalid",
+		},
+		{
+			"executed with an error result",
+			&statusReader{status: network.TxStatusExecuted, details: &data.TransactionOnNetwork{
+				SmartContractResults: []*data.SmartContractResult{{Data: hexData("user error", "paused")}},
+			}},
+			network.TxStatusFail, "user error: paused",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tracker := network.NewTxTracker(tt.reader, &memoryPendingTxs{txs: make(map[string]*data.PendingTx)})
+			outcomes := make(chan *network.TxOutcome, 1)
+			tracker.OnOutcome(func(outcome *network.TxOutcome) {
+				outcomes <- outcome
+			})
+			err := tracker.Track(testTxHash, 5, "Delegate")
+			if err != nil {
+				t.Fatalf("Track: %v", err)
+			}
+			tracker.Start()
+
+			select {
+			case outcome := <-outcomes:
+				if outcome.Status != tt.wantStatus || outcome.Reason != tt.wantReason || outcome.Tx.Hash != testTxHash {
+					t.Errorf("outcome = %v, %q, want %v, %q", outcome.Status, outcome.Reason, tt.wantStatus,
+						tt.wantReason)
+				}
+			case <-time.After(time.Second * 5):
+				t.Fatal("no outcome reported")
+			}
+		})
+	}
+}
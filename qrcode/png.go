@@ -0,0 +1,52 @@
+package qrcode
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+)
+
+// quietZone - the light border, in modules, required around a QR code
+const quietZone = 4
+
+// Image - renders the QR code with each module as a scale x scale square, surrounded by the quiet zone
+func (q *QRCode) Image(scale int) *image.Paletted {
+	if scale < 1 {
+		scale = 1
+	}
+
+	side := (q.Size + quietZone*2) * scale
+	palette := color.Palette{color.White, color.Black}
+	img := image.NewPaletted(image.Rect(0, 0, side, side), palette)
+	for y := 0; y < side; y++ {
+		for x := 0; x < side; x++ {
+			if q.Dark(x/scale-quietZone, y/scale-quietZone) {
+				img.SetColorIndex(x, y, 1)
+			}
+		}
+	}
+
+	return img
+}
+
+// PNG - returns the QR code rendered by Image, encoded as PNG
+func (q *QRCode) PNG(scale int) ([]byte, error) {
+	var buf bytes.Buffer
+	err := png.Encode(&buf, q.Image(scale))
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// EncodePNG - encodes the content in a QR code and renders it as PNG
+func EncodePNG(content string, scale int) ([]byte, error) {
+	q, err := Encode(content)
+	if err != nil {
+		return nil, err
+	}
+
+	return q.PNG(scale)
+}
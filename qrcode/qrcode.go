@@ -0,0 +1,480 @@
+// Package qrcode encodes text in QR codes (ISO/IEC 18004), in byte mode with error correction level M,
+// choosing the smallest version which fits the content
+package qrcode
+
+import (
+	"errors"
+)
+
+const (
+	minVersion = 1
+	maxVersion = 40
+
+	modeByte          = 0x4
+	formatBitsLevelM  = 0 // the error correction level M, as encoded in the format information
+	formatBitsMask    = 0x5412
+	formatBitsPoly    = 0x537
+	versionBitsPoly   = 0x1F25
+	padCodewordFirst  = 0xEC
+	padCodewordSecond = 0x11
+)
+
+// eccCodewordsPerBlock - the error correction codewords in each block, by version, for level M
+var eccCodewordsPerBlock = [maxVersion + 1]int{
+	-1, 10, 16, 26, 18, 24, 16, 18, 22, 22, 26, 30, 22, 22, 24, 24, 28, 28, 26, 26, 26,
+	26, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28,
+}
+
+// numErrorCorrectionBlocks - the number of blocks the codewords are split in, by version, for level M
+var numErrorCorrectionBlocks = [maxVersion + 1]int{
+	-1, 1, 1, 1, 2, 2, 4, 4, 4, 5, 5, 5, 8, 9, 9, 10, 10, 11, 13, 14, 16,
+	17, 17, 18, 20, 21, 23, 25, 26, 28, 29, 31, 33, 35, 37, 38, 40, 43, 45, 47, 49,
+}
+
+// ErrContentTooLong - the content does not fit in the largest QR code
+var ErrContentTooLong = errors.New("content too long for a QR code")
+
+// QRCode - holds the modules of an encoded QR code
+type QRCode struct {
+	Version int
+	Size    int
+	Mask    int
+
+	modules    [][]bool
+	isFunction [][]bool
+}
+
+// Encode - encodes the content in the smallest QR code which fits it
+func Encode(content string) (*QRCode, error) {
+	data := []byte(content)
+	version := minVersion
+	for ; version <= maxVersion; version++ {
+		if dataBitsNeeded(len(data), version) <= numDataCodewords(version)*8 {
+			break
+		}
+	}
+	if version > maxVersion {
+		return nil, ErrContentTooLong
+	}
+
+	codewords := dataCodewords(data, version)
+	q := newQRCode(version)
+	q.drawFunctionPatterns()
+	q.drawCodewords(addEccAndInterleave(codewords, version))
+	q.applyBestMask()
+
+	return q, nil
+}
+
+// Dark - returns true if the module at column x and row y is dark. Coordinates outside the code are light
+func (q *QRCode) Dark(x int, y int) bool {
+	if x < 0 || y < 0 || x >= q.Size || y >= q.Size {
+		return false
+	}
+
+	return q.modules[y][x]
+}
+
+func newQRCode(version int) *QRCode {
+	size := version*4 + 17
+	q := &QRCode{
+		Version:    version,
+		Size:       size,
+		modules:    make([][]bool, size),
+		isFunction: make([][]bool, size),
+	}
+	for i := 0; i < size; i++ {
+		q.modules[i] = make([]bool, size)
+		q.isFunction[i] = make([]bool, size)
+	}
+
+	return q
+}
+
+// dataBitsNeeded - returns the size of a byte mode segment holding n bytes
+func dataBitsNeeded(n int, version int) int {
+	return 4 + charCountBits(version) + n*8
+}
+
+func charCountBits(version int) int {
+	if version <= 9 {
+		return 8
+	}
+
+	return 16
+}
+
+// numRawDataModules - returns the number of modules available for data and error correction codewords
+func numRawDataModules(version int) int {
+	result := (16*version+128)*version + 64
+	if version >= 2 {
+		numAlign := version/7 + 2
+		result -= (25*numAlign-10)*numAlign - 55
+		if version >= 7 {
+			result -= 36
+		}
+	}
+
+	return result
+}
+
+func numDataCodewords(version int) int {
+	return numRawDataModules(version)/8 - eccCodewordsPerBlock[version]*numErrorCorrectionBlocks[version]
+}
+
+// dataCodewords - returns the byte mode segment, terminated and padded to the version's data capacity
+func dataCodewords(data []byte, version int) []byte {
+	bb := &bitBuffer{}
+	bb.append(modeByte, 4)
+	bb.append(uint32(len(data)), charCountBits(version))
+	for _, b := range data {
+		bb.append(uint32(b), 8)
+	}
+
+	capacity := numDataCodewords(version) * 8
+	terminator := capacity - len(bb.bits)
+	if terminator > 4 {
+		terminator = 4
+	}
+	bb.append(0, terminator)
+	bb.append(0, (8-len(bb.bits)%8)%8)
+	for pad := padCodewordFirst; len(bb.bits) < capacity; pad ^= padCodewordFirst ^ padCodewordSecond {
+		bb.append(uint32(pad), 8)
+	}
+
+	return bb.bytes()
+}
+
+// addEccAndInterleave - splits the data in blocks, appends the error correction codewords to each
+// block and interleaves the blocks
+func addEccAndInterleave(data []byte, version int) []byte {
+	numBlocks := numErrorCorrectionBlocks[version]
+	blockEccLen := eccCodewordsPerBlock[version]
+	rawCodewords := numRawDataModules(version) / 8
+	numShortBlocks := numBlocks - rawCodewords%numBlocks
+	shortBlockLen := rawCodewords / numBlocks
+
+	divisor := reedSolomonDivisor(blockEccLen)
+	blocks := make([][]byte, 0, numBlocks)
+	k := 0
+	for i := 0; i < numBlocks; i++ {
+		dataLen := shortBlockLen - blockEccLen
+		if i >= numShortBlocks {
+			dataLen++
+		}
+		block := make([]byte, 0, shortBlockLen+1)
+		block = append(block, data[k:k+dataLen]...)
+		k += dataLen
+		ecc := reedSolomonRemainder(block, divisor)
+		if i < numShortBlocks {
+			block = append(block, 0)
+		}
+		blocks = append(blocks, append(block, ecc...))
+	}
+
+	result := make([]byte, 0, rawCodewords)
+	for i := 0; i < len(blocks[0]); i++ {
+		for j, block := range blocks {
+			// skip the padding byte of the short blocks
+			if i != shortBlockLen-blockEccLen || j >= numShortBlocks {
+				result = append(result, block[i])
+			}
+		}
+	}
+
+	return result
+}
+
+func (q *QRCode) setFunctionModule(x int, y int, dark bool) {
+	q.modules[y][x] = dark
+	q.isFunction[y][x] = true
+}
+
+func (q *QRCode) drawFunctionPatterns() {
+	for i := 0; i < q.Size; i++ {
+		q.setFunctionModule(6, i, i%2 == 0)
+		q.setFunctionModule(i, 6, i%2 == 0)
+	}
+
+	q.drawFinderPattern(3, 3)
+	q.drawFinderPattern(q.Size-4, 3)
+	q.drawFinderPattern(3, q.Size-4)
+
+	positions := alignmentPatternPositions(q.Version)
+	last := len(positions) - 1
+	for i, x := range positions {
+		for j, y := range positions {
+			// the corners with finder patterns
+			if (i == 0 && j == 0) || (i == 0 && j == last) || (i == last && j == 0) {
+				continue
+			}
+			q.drawAlignmentPattern(x, y)
+		}
+	}
+
+	// reserve the format areas, drawn for real once the mask is known
+	q.drawFormatBits(0)
+	q.drawVersion()
+}
+
+func (q *QRCode) drawFinderPattern(x int, y int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			dist := maxInt(absInt(dx), absInt(dy))
+			xx, yy := x+dx, y+dy
+			if xx >= 0 && xx < q.Size && yy >= 0 && yy < q.Size {
+				q.setFunctionModule(xx, yy, dist != 2 && dist != 4)
+			}
+		}
+	}
+}
+
+func (q *QRCode) drawAlignmentPattern(x int, y int) {
+	for dy := -2; dy <= 2; dy++ {
+		for dx := -2; dx <= 2; dx++ {
+			q.setFunctionModule(x+dx, y+dy, maxInt(absInt(dx), absInt(dy)) != 1)
+		}
+	}
+}
+
+// alignmentPatternPositions - returns the coordinates of the alignment patterns' centers, on each axis
+func alignmentPatternPositions(version int) []int {
+	if version == 1 {
+		return []int{}
+	}
+
+	numAlign := version/7 + 2
+	step := (version*8 + numAlign*3 + 5) / (numAlign*4 - 4) * 2
+	result := make([]int, numAlign)
+	result[0] = 6
+	for i, pos := numAlign-1, version*4+17-7; i >= 1; i, pos = i-1, pos-step {
+		result[i] = pos
+	}
+
+	return result
+}
+
+func (q *QRCode) drawFormatBits(mask int) {
+	data := formatBitsLevelM<<3 | mask
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = (rem << 1) ^ ((rem >> 9) * formatBitsPoly)
+	}
+	bits := (data<<10 | rem) ^ formatBitsMask
+
+	for i := 0; i <= 5; i++ {
+		q.setFunctionModule(8, i, bit(bits, i))
+	}
+	q.setFunctionModule(8, 7, bit(bits, 6))
+	q.setFunctionModule(8, 8, bit(bits, 7))
+	q.setFunctionModule(7, 8, bit(bits, 8))
+	for i := 9; i < 15; i++ {
+		q.setFunctionModule(14-i, 8, bit(bits, i))
+	}
+
+	for i := 0; i < 8; i++ {
+		q.setFunctionModule(q.Size-1-i, 8, bit(bits, i))
+	}
+	for i := 8; i < 15; i++ {
+		q.setFunctionModule(8, q.Size-15+i, bit(bits, i))
+	}
+	q.setFunctionModule(8, q.Size-8, true)
+}
+
+func (q *QRCode) drawVersion() {
+	if q.Version < 7 {
+		return
+	}
+
+	rem := q.Version
+	for i := 0; i < 12; i++ {
+		rem = (rem << 1) ^ ((rem >> 11) * versionBitsPoly)
+	}
+	bits := q.Version<<12 | rem
+
+	for i := 0; i < 18; i++ {
+		dark := bit(bits, i)
+		a := q.Size - 11 + i%3
+		b := i / 3
+		q.setFunctionModule(a, b, dark)
+		q.setFunctionModule(b, a, dark)
+	}
+}
+
+// drawCodewords - places the codewords in the zigzag order, in pairs of columns from the bottom right
+func (q *QRCode) drawCodewords(data []byte) {
+	i := 0
+	for right := q.Size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := 0; vert < q.Size; vert++ {
+			for j := 0; j < 2; j++ {
+				x := right - j
+				upward := (right+1)&2 == 0
+				y := vert
+				if upward {
+					y = q.Size - 1 - vert
+				}
+				if !q.isFunction[y][x] && i < len(data)*8 {
+					q.modules[y][x] = bit(int(data[i>>3]), 7-(i&7))
+					i++
+				}
+			}
+		}
+	}
+}
+
+// applyMask - inverts the data modules selected by the mask pattern. Applying it twice undoes it
+func (q *QRCode) applyMask(mask int) {
+	for y := 0; y < q.Size; y++ {
+		for x := 0; x < q.Size; x++ {
+			var invert bool
+			switch mask {
+			case 0:
+				invert = (x+y)%2 == 0
+			case 1:
+				invert = y%2 == 0
+			case 2:
+				invert = x%3 == 0
+			case 3:
+				invert = (x+y)%3 == 0
+			case 4:
+				invert = (x/3+y/2)%2 == 0
+			case 5:
+				invert = x*y%2+x*y%3 == 0
+			case 6:
+				invert = (x*y%2+x*y%3)%2 == 0
+			case 7:
+				invert = ((x+y)%2+x*y%3)%2 == 0
+			}
+			if invert && !q.isFunction[y][x] {
+				q.modules[y][x] = !q.modules[y][x]
+			}
+		}
+	}
+}
+
+// applyBestMask - applies the mask pattern with the lowest penalty score
+func (q *QRCode) applyBestMask() {
+	best, minPenalty := 0, -1
+	for mask := 0; mask < 8; mask++ {
+		q.applyMask(mask)
+		q.drawFormatBits(mask)
+		penalty := q.penaltyScore()
+		if minPenalty < 0 || penalty < minPenalty {
+			best, minPenalty = mask, penalty
+		}
+		q.applyMask(mask)
+	}
+
+	q.Mask = best
+	q.applyMask(best)
+	q.drawFormatBits(best)
+}
+
+// penaltyScore - scores the patterns which make a QR code harder to read: long runs of the same color,
+// 2x2 blocks, finder-like patterns and an unbalanced dark / light ratio
+func (q *QRCode) penaltyScore() int {
+	penalty := 0
+	for i := 0; i < q.Size; i++ {
+		row := make([]bool, q.Size)
+		column := make([]bool, q.Size)
+		for j := 0; j < q.Size; j++ {
+			row[j] = q.modules[i][j]
+			column[j] = q.modules[j][i]
+		}
+		penalty += linePenalty(row) + linePenalty(column)
+	}
+
+	dark := 0
+	for y := 0; y < q.Size; y++ {
+		for x := 0; x < q.Size; x++ {
+			if q.modules[y][x] {
+				dark++
+			}
+			if x < q.Size-1 && y < q.Size-1 {
+				color := q.modules[y][x]
+				if color == q.modules[y][x+1] && color == q.modules[y+1][x] && color == q.modules[y+1][x+1] {
+					penalty += 3
+				}
+			}
+		}
+	}
+
+	total := q.Size * q.Size
+	k := (absInt(dark*20-total*10)+total-1)/total - 1
+	penalty += k * 10
+
+	return penalty
+}
+
+// finderLikePattern - dark-light-dark-dark-dark-light-dark, preceded or followed by 4 light modules
+var finderLikePattern = []bool{true, false, true, true, true, false, true}
+
+func linePenalty(line []bool) int {
+	penalty := 0
+	run := 1
+	for i := 1; i <= len(line); i++ {
+		if i < len(line) && line[i] == line[i-1] {
+			run++
+			continue
+		}
+		if run >= 5 {
+			penalty += 3 + run - 5
+		}
+		run = 1
+	}
+
+	for i := 0; i+len(finderLikePattern) <= len(line); i++ {
+		if !matches(line[i:i+len(finderLikePattern)], finderLikePattern) {
+			continue
+		}
+		if isLight(line, i-4, i) || isLight(line, i+len(finderLikePattern), i+len(finderLikePattern)+4) {
+			penalty += 40
+		}
+	}
+
+	return penalty
+}
+
+func matches(line []bool, pattern []bool) bool {
+	for i := range pattern {
+		if line[i] != pattern[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isLight - returns true if the modules in [from, to) are light, the ones outside the line counting as light
+func isLight(line []bool, from int, to int) bool {
+	for i := from; i < to; i++ {
+		if i >= 0 && i < len(line) && line[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func bit(x int, i int) bool {
+	return (x>>uint(i))&1 != 0
+}
+
+func absInt(x int) int {
+	if x < 0 {
+		return -x
+	}
+
+	return x
+}
+
+func maxInt(a int, b int) int {
+	if a > b {
+		return a
+	}
+
+	return b
+}
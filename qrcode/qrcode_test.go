@@ -0,0 +1,465 @@
+package qrcode
+
+import (
+	"bytes"
+	"errors"
+	"image/png"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// formatBitsM - the format information of level M for each mask, from the QR code specification
+var formatBitsM = []string{
+	"101010000010010", "101000100100101", "101111001111100", "101101101001011",
+	"100010111111001", "100000011001110", "100111110010111", "100101010100000",
+}
+
+// versionBits - the version information of some versions, from the QR code specification
+var versionBits = map[int]int{7: 0x07C94, 8: 0x085BC, 9: 0x09A99, 10: 0x0A4D3}
+
+// finderPattern - the 7x7 finder pattern, followed by its light separator
+var finderPattern = []string{
+	"#######.",
+	"#.....#.",
+	"#.###.#.",
+	"#.###.#.",
+	"#.###.#.",
+	"#.....#.",
+	"#######.",
+	"........",
+}
+
+func TestReedSolomonRemainder(t *testing.T) {
+	// the example of the specification's annex I, version 1-M
+	data := []byte{0x10, 0x20, 0x0C, 0x56, 0x61, 0x80, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11}
+	want := []byte{0xA5, 0x24, 0xD4, 0xC1, 0xED, 0x36, 0xC7, 0x87, 0x2C, 0x55}
+
+	got := reedSolomonRemainder(data, reedSolomonDivisor(len(want)))
+	if !bytes.Equal(got, want) {
+		t.Errorf("error correction codewords = % X, want % X", got, want)
+	}
+}
+
+func TestDataCodewords(t *testing.T) {
+	got := dataCodewords([]byte("hello"), 1)
+	want := []byte{0x40, 0x56, 0x86, 0x56, 0xC6, 0xC6, 0xF0, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11, 0xEC}
+	if !bytes.Equal(got, want) {
+		t.Errorf("data codewords = % X, want % X", got, want)
+	}
+}
+
+func TestAlignmentPatternPositions(t *testing.T) {
+	tests := map[int][]int{
+		1:  {},
+		2:  {6, 18},
+		7:  {6, 22, 38},
+		14: {6, 26, 46, 66},
+		32: {6, 34, 60, 86, 112, 138},
+		40: {6, 30, 58, 86, 114, 142, 170},
+	}
+	for version, want := range tests {
+		got := alignmentPatternPositions(version)
+		if !equalInts(got, want) {
+			t.Errorf("version %v: alignment patterns at %v, want %v", version, got, want)
+		}
+	}
+}
+
+func TestEncodeVersion(t *testing.T) {
+	// the byte mode capacities of level M
+	tests := []struct {
+		length  int
+		version int
+	}{
+		{1, 1}, {14, 1}, {15, 2}, {26, 2}, {27, 3}, {213, 10}, {214, 11}, {2331, 40},
+	}
+	for _, tt := range tests {
+		q, err := Encode(strings.Repeat("a", tt.length))
+		if err != nil {
+			t.Fatalf("%v bytes: %v", tt.length, err)
+		}
+		if q.Version != tt.version || q.Size != tt.version*4+17 {
+			t.Errorf("%v bytes: version %v of size %v, want version %v", tt.length, q.Version, q.Size, tt.version)
+		}
+	}
+
+	_, err := Encode(strings.Repeat("a", 2332))
+	if !errors.Is(err, ErrContentTooLong) {
+		t.Errorf("expected ErrContentTooLong, got %v", err)
+	}
+}
+
+func TestEncodeDecode(t *testing.T) {
+	contents := []string{
+		"",
+		"hello",
+		"erd1qqqqqqqqqqqqqqqpqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqhllllsajxzat",
+		"https://wallet.elrond.com/hook/transaction?receiver=erd1qqqqqqqqqqqqqqqpqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqhllllsajxzat&value=10&gasLimit=12000000&data=delegate&callbackUrl=https%3A%2F%2Fexample.com%2Fhook",
+		"ünïcödé ✅",
+		strings.Repeat("0123456789", 30),
+	}
+	for _, content := range contents {
+		q, err := Encode(content)
+		if err != nil {
+			t.Fatalf("%q: %v", content, err)
+		}
+
+		got, err := decode(q)
+		if err != nil {
+			t.Errorf("%q (version %v): %v", content, q.Version, err)
+			continue
+		}
+		if got != content {
+			t.Errorf("decoded %q, want %q", got, content)
+		}
+	}
+}
+
+func TestDecodeCorrupted(t *testing.T) {
+	q, err := Encode("hello")
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+
+	// the bottom right module always holds data
+	q.modules[q.Size-1][q.Size-1] = !q.modules[q.Size-1][q.Size-1]
+	_, err = decode(q)
+	if err == nil {
+		t.Error("expected a corrupted module to be detected")
+	}
+}
+
+func TestFunctionPatterns(t *testing.T) {
+	q, err := Encode(strings.Repeat("a", 150))
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	if q.Version < 7 {
+		t.Fatalf("expected a version with version information, got %v", q.Version)
+	}
+
+	for y, row := range finderPattern {
+		for x, module := range row {
+			dark := module == '#'
+			last := q.Size - 1
+			if q.Dark(x, y) != dark || q.Dark(last-x, y) != dark || q.Dark(x, last-y) != dark {
+				t.Fatalf("finder pattern differs at %v,%v", x, y)
+			}
+		}
+	}
+	for i := 8; i < q.Size-8; i++ {
+		if q.Dark(i, 6) != (i%2 == 0) || q.Dark(6, i) != (i%2 == 0) {
+			t.Fatalf("timing pattern differs at %v", i)
+		}
+	}
+	if !q.Dark(8, q.Size-8) {
+		t.Error("the dark module is light")
+	}
+
+	bits := 0
+	bitsTransposed := 0
+	for i := 0; i < 18; i++ {
+		if q.Dark(q.Size-11+i%3, i/3) {
+			bits |= 1 << uint(i)
+		}
+		if q.Dark(i/3, q.Size-11+i%3) {
+			bitsTransposed |= 1 << uint(i)
+		}
+	}
+	if bits != versionBits[q.Version] || bitsTransposed != bits {
+		t.Errorf("version information = %05X / %05X, want %05X", bits, bitsTransposed, versionBits[q.Version])
+	}
+}
+
+func TestImage(t *testing.T) {
+	q, err := Encode("hello")
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+
+	const scale = 3
+	encoded, err := q.PNG(scale)
+	if err != nil {
+		t.Fatalf("PNG: %v", err)
+	}
+	img, err := png.Decode(bytes.NewReader(encoded))
+	if err != nil {
+		t.Fatalf("can not decode the PNG: %v", err)
+	}
+
+	side := (q.Size + quietZone*2) * scale
+	if img.Bounds().Dx() != side || img.Bounds().Dy() != side {
+		t.Fatalf("image size = %v, want %vx%v", img.Bounds(), side, side)
+	}
+	for y := 0; y < side; y++ {
+		for x := 0; x < side; x++ {
+			r, _, _, _ := img.At(x, y).RGBA()
+			dark := r == 0
+			if dark != q.Dark(x/scale-quietZone, y/scale-quietZone) {
+				t.Fatalf("pixel %v,%v does not match its module", x, y)
+			}
+		}
+	}
+}
+
+// decode - reads the content back from the QR code's modules, checking the format information and the
+// error correction codewords of each block
+func decode(q *QRCode) (string, error) {
+	mask, err := readFormat(q)
+	if err != nil {
+		return "", err
+	}
+	if mask != q.Mask {
+		return "", errors.New("the format information does not hold the mask")
+	}
+
+	codewords := readCodewords(q, mask)
+	blocks := deinterleave(codewords, q.Version)
+	data := make([]byte, 0, len(codewords))
+	for _, block := range blocks {
+		for i := 0; i < eccCodewordsPerBlock[q.Version]; i++ {
+			if syndrome(block, i) != 0 {
+				return "", errors.New("wrong error correction codewords")
+			}
+		}
+		data = append(data, block[:len(block)-eccCodewordsPerBlock[q.Version]]...)
+	}
+
+	return parseByteSegment(data, q.Version)
+}
+
+// readFormat - reads both copies of the format information and returns the mask they hold
+func readFormat(q *QRCode) (int, error) {
+	first, second := 0, 0
+	for i := 0; i < 15; i++ {
+		var x, y int
+		switch {
+		case i <= 5:
+			x, y = 8, i
+		case i == 6:
+			x, y = 8, 7
+		case i == 7:
+			x, y = 8, 8
+		case i == 8:
+			x, y = 7, 8
+		default:
+			x, y = 14-i, 8
+		}
+		if q.Dark(x, y) {
+			first |= 1 << uint(i)
+		}
+
+		x, y = q.Size-1-i, 8
+		if i >= 8 {
+			x, y = 8, q.Size-15+i
+		}
+		if q.Dark(x, y) {
+			second |= 1 << uint(i)
+		}
+	}
+	if first != second {
+		return 0, errors.New("the format information copies differ")
+	}
+
+	for mask, bits := range formatBitsM {
+		value, _ := strconv.ParseInt(bits, 2, 32)
+		if int(value) == first {
+			return mask, nil
+		}
+	}
+
+	return 0, errors.New("the format information is not of level M")
+}
+
+// readCodewords - reads the data modules in the zigzag order, removing the mask
+func readCodewords(q *QRCode, mask int) []byte {
+	functions := functionModules(q.Version)
+	bb := &bitBuffer{}
+	for right := q.Size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		upward := (right+1)&2 == 0
+		for vert := 0; vert < q.Size; vert++ {
+			y := vert
+			if upward {
+				y = q.Size - 1 - vert
+			}
+			for x := right; x >= right-1; x-- {
+				if functions[y][x] {
+					continue
+				}
+				dark := q.Dark(x, y) != masked(mask, x, y)
+				bb.bits = append(bb.bits, dark)
+			}
+		}
+	}
+	bb.bits = bb.bits[:len(bb.bits)/8*8]
+
+	return bb.bytes()
+}
+
+// functionModules - marks the finder patterns with their separators, the timing patterns, the alignment
+// patterns and the format and version information
+func functionModules(version int) [][]bool {
+	size := version*4 + 17
+	functions := make([][]bool, size)
+	for i := range functions {
+		functions[i] = make([]bool, size)
+	}
+	mark := func(x0 int, y0 int, width int, height int) {
+		for y := y0; y < y0+height; y++ {
+			for x := x0; x < x0+width; x++ {
+				functions[y][x] = true
+			}
+		}
+	}
+
+	mark(0, 0, 9, 9)
+	mark(size-8, 0, 8, 9)
+	mark(0, size-8, 9, 8)
+	mark(6, 0, 1, size)
+	mark(0, 6, size, 1)
+	if version >= 7 {
+		mark(size-11, 0, 3, 6)
+		mark(0, size-11, 6, 3)
+	}
+
+	// the alignment patterns, except the ones overlapping the finder patterns
+	positions := alignmentPatternPositions(version)
+	last := len(positions) - 1
+	for i, x := range positions {
+		for j, y := range positions {
+			if (i != 0 || j != 0) && (i != 0 || j != last) && (i != last || j != 0) {
+				mark(x-2, y-2, 5, 5)
+			}
+		}
+	}
+
+	return functions
+}
+
+func masked(mask int, x int, y int) bool {
+	switch mask {
+	case 0:
+		return (x+y)%2 == 0
+	case 1:
+		return y%2 == 0
+	case 2:
+		return x%3 == 0
+	case 3:
+		return (x+y)%3 == 0
+	case 4:
+		return (x/3+y/2)%2 == 0
+	case 5:
+		return x*y%2+x*y%3 == 0
+	case 6:
+		return (x*y%2+x*y%3)%2 == 0
+	default:
+		return ((x+y)%2+x*y%3)%2 == 0
+	}
+}
+
+// deinterleave - splits the codewords in blocks of data followed by error correction codewords
+func deinterleave(codewords []byte, version int) [][]byte {
+	numBlocks := numErrorCorrectionBlocks[version]
+	eccLen := eccCodewordsPerBlock[version]
+	numLongBlocks := len(codewords) % numBlocks
+	shortDataLen := len(codewords)/numBlocks - eccLen
+
+	blocks := make([][]byte, numBlocks)
+	k := 0
+	for i := 0; i < shortDataLen; i++ {
+		for j := range blocks {
+			blocks[j] = append(blocks[j], codewords[k])
+			k++
+		}
+	}
+	for j := numBlocks - numLongBlocks; j < numBlocks; j++ {
+		blocks[j] = append(blocks[j], codewords[k])
+		k++
+	}
+	for i := 0; i < eccLen; i++ {
+		for j := range blocks {
+			blocks[j] = append(blocks[j], codewords[k])
+			k++
+		}
+	}
+
+	return blocks
+}
+
+// syndrome - evaluates the block, as a polynomial, in the i-th power of the generator's root
+func syndrome(block []byte, i int) byte {
+	root := byte(1)
+	for j := 0; j < i; j++ {
+		root = gfMultiply(root, 0x02)
+	}
+
+	result := byte(0)
+	for _, b := range block {
+		result = gfMultiply(result, root) ^ b
+	}
+
+	return result
+}
+
+// parseByteSegment - reads the byte mode segment and checks the terminator and the padding
+func parseByteSegment(data []byte, version int) (string, error) {
+	bits := make([]bool, 0, len(data)*8)
+	for _, b := range data {
+		for i := 7; i >= 0; i-- {
+			bits = append(bits, (b>>uint(i))&1 != 0)
+		}
+	}
+	read := func(n int) int {
+		value := 0
+		for i := 0; i < n; i++ {
+			value <<= 1
+			if bits[0] {
+				value |= 1
+			}
+			bits = bits[1:]
+		}
+		return value
+	}
+
+	if read(4) != modeByte {
+		return "", errors.New("not a byte mode segment")
+	}
+	length := read(charCountBits(version))
+	if length*8 > len(bits) {
+		return "", errors.New("the segment is longer than the data")
+	}
+	content := make([]byte, length)
+	for i := range content {
+		content[i] = byte(read(8))
+	}
+
+	for len(bits)%8 != 0 {
+		if read(1) != 0 {
+			return "", errors.New("wrong terminator")
+		}
+	}
+	for pad := padCodewordFirst; len(bits) > 0; pad ^= padCodewordFirst ^ padCodewordSecond {
+		if read(8) != pad {
+			return "", errors.New("wrong padding")
+		}
+	}
+
+	return string(content), nil
+}
+
+func equalInts(a []int, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,71 @@
+package qrcode
+
+// bitBuffer - a sequence of bits, most significant first
+type bitBuffer struct {
+	bits []bool
+}
+
+// append - appends the n low bits of value
+func (bb *bitBuffer) append(value uint32, n int) {
+	for i := n - 1; i >= 0; i-- {
+		bb.bits = append(bb.bits, (value>>uint(i))&1 != 0)
+	}
+}
+
+// bytes - packs the bits, whose count must be a multiple of 8
+func (bb *bitBuffer) bytes() []byte {
+	result := make([]byte, len(bb.bits)/8)
+	for i, b := range bb.bits {
+		if b {
+			result[i>>3] |= 1 << uint(7-i&7)
+		}
+	}
+
+	return result
+}
+
+// reedSolomonDivisor - returns the coefficients of the generator polynomial of the given degree,
+// without the leading 1, from the highest to the lowest power
+func reedSolomonDivisor(degree int) []byte {
+	result := make([]byte, degree)
+	result[degree-1] = 1
+
+	root := byte(1)
+	for i := 0; i < degree; i++ {
+		for j := range result {
+			result[j] = gfMultiply(result[j], root)
+			if j+1 < len(result) {
+				result[j] ^= result[j+1]
+			}
+		}
+		root = gfMultiply(root, 0x02)
+	}
+
+	return result
+}
+
+// reedSolomonRemainder - returns the error correction codewords of data
+func reedSolomonRemainder(data []byte, divisor []byte) []byte {
+	result := make([]byte, len(divisor))
+	for _, b := range data {
+		factor := b ^ result[0]
+		copy(result, result[1:])
+		result[len(result)-1] = 0
+		for i := range result {
+			result[i] ^= gfMultiply(divisor[i], factor)
+		}
+	}
+
+	return result
+}
+
+// gfMultiply - multiplies two elements of GF(2^8) modulo x^8 + x^4 + x^3 + x^2 + 1
+func gfMultiply(x byte, y byte) byte {
+	z := 0
+	for i := 7; i >= 0; i-- {
+		z = (z << 1) ^ ((z >> 7) * 0x11D)
+		z ^= int((y>>uint(i))&1) * int(x)
+	}
+
+	return byte(z)
+}
@@ -9,10 +9,21 @@ const (
 	// MainHelp -
 	MainHelp = "`MyWallets` - menu for adding the wallets you wish to delegate from and the bot will monitor " +
 		"your delegations and rewards\n\r" +
-		"`Contract Info` - displays details about the Delegation SC (address, fee, etc.)"
+		"`Contract Info` - displays details about the Delegation SC (address, fee, APR, etc.)\n\r" +
+		"`Activity` - the latest delegations, undelegations, nodes and admin changes of the Delegation SC\n\r" +
+		"`QR` - the claim, compound and withdraw transactions as QR codes, to sign them on another device\n\r" +
+		"`/calc <amount> <days>` - estimates the rewards for delegating an amount of eGLD for a number of days\n\r" +
+		"`/cancel` - drops the question the bot is waiting an answer for, such as an amount or an address"
 	// MyWalletsHelp -
 	MyWalletsHelp = "`Add` - here you can add a wallet to be managed by the bot\n\r" +
-		"`Balances` - here you can see each of your wallet's delegations, balances and claimable rewards"
+		"`Balances` - here you can see each of your wallet's delegations, balances, claimable rewards and the " +
+		"rewards earned in the last 24h, 7d and 30d, and get the wallet's deposit address as a QR code\n\r" +
+		"`Stake History` - your wallet's latest delegation and rewards snapshots\n\r" +
+		"`Verify` - prove you own a wallet by signing a message with it, to see its delegations and rewards\n\r" +
+		"`Rewards History` - lifetime rewards claimed and compounded, and net deposits of each of your wallets"
+
+	// CalcUsageMessage -
+	CalcUsageMessage = "Usage: `/calc <amount> <days>`, e.g. `/calc 100 30`"
 
 	// SetOwnerAddressMessage -
 	SetOwnerAddressMessage = "Send owner's address or PEM/JSON file (for JSONs, first write the password, then attach the file)"
@@ -25,8 +36,16 @@ const (
 
 	// AddNodeMessage -
 	AddNodeMessage = "Send the node's validatorKey.pem"
+	// VerifyWalletMessage -
+	VerifyWalletMessage = "Send the signature of the challenge for wallet"
+	// NodeNameMessage -
+	NodeNameMessage = "Send the new name (or \"-\" to clear it) of node"
 	// ChangeServiceFeeMessage -
 	ChangeServiceFeeMessage = "Send new service fee"
 	// ModifyDelegationCapMessage -
 	ModifyDelegationCapMessage = "Send new delegation cap"
+	// SetMetadataMessage -
+	SetMetadataMessage = "Send the provider's name, website and keybase identity, one per line"
+	// SetContractAddressMessage -
+	SetContractAddressMessage = "Send the contract's address or \"auto\" to look it up through the delegation manager"
 )
@@ -1,3 +0,0 @@
-package utils
-
-var ContractAddress string
@@ -0,0 +1,41 @@
+package utils
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"strconv"
+
+	"github.com/ElrondNetwork/elrond-go/hashing/keccak"
+	"github.com/ElrondNetwork/elrond-sdk/erdgo"
+)
+
+// signedMessagePrefix - prepended by the Elrond wallets to the messages they sign, so a signed message can
+// never be a valid transaction
+const signedMessagePrefix = "\x17Elrond Signed Message:\n"
+
+// ErrInvalidSignature - the signature does not match the message and the address
+var ErrInvalidSignature = errors.New("invalid signature")
+
+// SignableMessageHash - returns the hash the Elrond wallets sign for a message
+func SignableMessageHash(message string) []byte {
+	return keccak.Keccak{}.Compute(signedMessagePrefix + strconv.Itoa(len(message)) + message)
+}
+
+// VerifyMessageSignature - checks the message was signed with the wallet's message signing by the
+// owner of the address
+func VerifyMessageSignature(address string, message string, signature []byte) error {
+	pubkey, err := erdgo.Bech32ToPubkey(address)
+	if err != nil {
+		return err
+	}
+
+	if len(pubkey) != ed25519.PublicKeySize || len(signature) != ed25519.SignatureSize {
+		return ErrInvalidSignature
+	}
+
+	if !ed25519.Verify(pubkey, SignableMessageHash(message), signature) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
@@ -0,0 +1,89 @@
+package utils_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"testing"
+
+	"github.com/DrDelphi/ElrondDSSC/utils"
+	"github.com/ElrondNetwork/elrond-go/hashing/keccak"
+	"github.com/ElrondNetwork/elrond-sdk/erdgo"
+)
+
+// the vector below was signed with the ed25519 key whose seed is the bytes 1..32
+const (
+	vectorAddress   = "erd10x64vt50ue20jsrckyfw32vt57gplpf6u62ma4lquwgshtgyjejq7dw54j"
+	vectorMessage   = "DSSC wallet verification 42"
+	vectorHash      = "ccca5b620ff49f18fe73a532e4f61057a841b4fb551665c3bf98f94e41bd4d1d"
+	vectorSignature = "3782cca62799775b853302975fb9fc57aaba2b3e36fcfd8809fe73924fce78e7a6e24e4a2997bb4dbfe599e41299550a6daf07ff71a93f259663b6aed1da820c"
+)
+
+func TestSignableMessageHash(t *testing.T) {
+	hash := utils.SignableMessageHash(vectorMessage)
+	if hex.EncodeToString(hash) != vectorHash {
+		t.Fatalf("hash = %x, want %s", hash, vectorHash)
+	}
+
+	wanted := keccak.Keccak{}.Compute("\x17Elrond Signed Message:\n27" + vectorMessage)
+	if hex.EncodeToString(wanted) != vectorHash {
+		t.Fatalf("the vector does not hash the prefix, the length and the message")
+	}
+}
+
+func TestVerifyMessageSignatureVector(t *testing.T) {
+	signature, _ := hex.DecodeString(vectorSignature)
+	err := utils.VerifyMessageSignature(vectorAddress, vectorMessage, signature)
+	if err != nil {
+		t.Fatalf("known good signature rejected: %v", err)
+	}
+}
+
+func TestVerifyMessageSignature(t *testing.T) {
+	pubkey, privkey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	address, err := erdgo.PubkeyToBech32(pubkey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherPubkey, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherAddress, err := erdgo.PubkeyToBech32(otherPubkey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	message := "verify me 1234"
+	signature := ed25519.Sign(privkey, utils.SignableMessageHash(message))
+	rawSignature := ed25519.Sign(privkey, []byte(message))
+
+	tests := []struct {
+		name      string
+		address   string
+		message   string
+		signature []byte
+		wantErr   bool
+	}{
+		{"signed by the address", address, message, signature, false},
+		{"tampered message", address, message + "5", signature, true},
+		{"another address", otherAddress, message, signature, true},
+		{"signature without the prefix", address, message, rawSignature, true},
+		{"short signature", address, message, signature[:ed25519.SignatureSize-1], true},
+		{"long signature", address, message, append(append([]byte{}, signature...), 0), true},
+		{"empty signature", address, message, nil, true},
+		{"invalid address", "erd1invalid", message, signature, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := utils.VerifyMessageSignature(tt.address, tt.message, tt.signature)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("VerifyMessageSignature() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
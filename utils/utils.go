@@ -2,8 +2,6 @@ package utils
 
 import (
 	"fmt"
-	"io/ioutil"
-	"net/http"
 	"regexp"
 	"strings"
 
@@ -14,39 +12,6 @@ import (
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
 )
 
-func GetHTTP(address string) ([]byte, error) {
-	req, err := http.NewRequest(http.MethodGet, address, nil)
-	if err != nil {
-		return nil, err
-	}
-	client := http.DefaultClient
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-
-	return body, nil
-}
-
-func PostHTTP(address, body string) ([]byte, error) {
-	resp, err := http.Post(address, "", strings.NewReader(body))
-	if err != nil {
-		return nil, err
-	}
-	defer func() {
-		if resp != nil {
-			resp.Body.Close()
-		}
-	}()
-
-	return ioutil.ReadAll(resp.Body)
-}
-
 func GetValidatorPubKeyFromPrivateKey(skBytes []byte) ([]byte, error) {
 	gen := signing.NewKeyGenerator(mcl.NewSuiteBLS12())
 
@@ -106,3 +71,10 @@ func IsValidNodeKey(v string) bool {
 	re := regexp.MustCompile("[0-9a-fA-F]{192}")
 	return re.MatchString(v)
 }
+
+// EscapeMarkdown - escapes the characters with a special meaning in Telegram's Markdown
+func EscapeMarkdown(s string) string {
+	return markdownEscaper.Replace(s)
+}
+
+var markdownEscaper = strings.NewReplacer("_", "\\_", "*", "\\*", "`", "\\`", "[", "\\[")
@@ -0,0 +1,142 @@
+// Package vault keeps the owner's private key encrypted at rest. The key is decrypted in memory only, when a
+// transaction has to be signed with it, using a passphrase given when the application starts
+package vault
+
+import (
+	"encoding/hex"
+	"errors"
+	"sync"
+
+	logger "github.com/ElrondNetwork/elrond-go-logger"
+)
+
+var log = logger.GetOrCreate("vault")
+
+// ErrLocked - the application was started without the vault passphrase
+var ErrLocked = errors.New("the key vault is locked: start the application with the vault passphrase")
+
+// ErrNoKey - no private key is stored
+var ErrNoKey = errors.New("no private key stored")
+
+// ErrPlaintextKey - the private key saved by an older version was not encrypted yet
+var ErrPlaintextKey = errors.New("the owner private key is stored in plaintext: start the application with " +
+	"the vault passphrase to encrypt it")
+
+// KeyStorer - saves and reads the owner's private key, as sealed by the vault. Replacing the key must not leave
+// the old value recoverable from the storage
+type KeyStorer interface {
+	GetOwnerPrivateKey() string
+	SetOwnerPrivateKey(privateKey string) error
+}
+
+// KeyVault - holds the passphrase which unlocks the owner's private key
+type KeyVault struct {
+	storer     KeyStorer
+	passphrase []byte
+	mut        sync.Mutex
+}
+
+// NewKeyVault - creates a new KeyVault object, checking the passphrase against the stored key. A plaintext
+// key saved by an older version is sealed right away, and refused without a passphrase. Without a passphrase
+// the vault is locked
+func NewKeyVault(storer KeyStorer, passphrase []byte) (*KeyVault, error) {
+	v := &KeyVault{
+		storer: storer,
+	}
+	if len(passphrase) > 0 {
+		v.passphrase = append([]byte{}, passphrase...)
+	}
+
+	stored := storer.GetOwnerPrivateKey()
+	if stored == "" {
+		return v, nil
+	}
+
+	if v.Locked() {
+		if !IsSealed(stored) {
+			log.Error("can not start with a plaintext owner private key", "error", ErrPlaintextKey)
+			return nil, ErrPlaintextKey
+		}
+		return v, nil
+	}
+
+	if IsSealed(stored) {
+		key, err := Open(stored, v.passphrase)
+		if err != nil {
+			log.Error("can not unlock the owner private key", "error", err)
+			return nil, err
+		}
+		Zero(key)
+		return v, nil
+	}
+
+	key, err := hex.DecodeString(stored)
+	if err != nil {
+		log.Error("invalid plaintext owner private key", "error", err)
+		return nil, err
+	}
+	defer Zero(key)
+
+	err = v.Store(key)
+	if err != nil {
+		return nil, err
+	}
+	log.Info("the plaintext owner private key was encrypted")
+
+	return v, nil
+}
+
+// Locked - returns true if the vault has no passphrase, so keys can not be stored or used
+func (v *KeyVault) Locked() bool {
+	return len(v.passphrase) == 0
+}
+
+// HasKey - returns true if a private key is stored
+func (v *KeyVault) HasKey() bool {
+	return v.storer.GetOwnerPrivateKey() != ""
+}
+
+// Store - seals the private key and saves it
+func (v *KeyVault) Store(privateKey []byte) error {
+	v.mut.Lock()
+	defer v.mut.Unlock()
+
+	if v.Locked() {
+		return ErrLocked
+	}
+
+	sealed, err := Seal(privateKey, v.passphrase)
+	if err != nil {
+		log.Error("can not encrypt the owner private key", "error", err)
+		return err
+	}
+
+	return v.storer.SetOwnerPrivateKey(sealed)
+}
+
+// Unlock - returns the decrypted private key. The caller should Zero it as soon as it is signed with
+func (v *KeyVault) Unlock() ([]byte, error) {
+	v.mut.Lock()
+	defer v.mut.Unlock()
+
+	stored := v.storer.GetOwnerPrivateKey()
+	if stored == "" {
+		return nil, ErrNoKey
+	}
+	if !IsSealed(stored) {
+		return nil, ErrPlaintextKey
+	}
+	if v.Locked() {
+		return nil, ErrLocked
+	}
+
+	return Open(stored, v.passphrase)
+}
+
+// Wipe - removes the stored private key. The storer purges the replaced value
+func (v *KeyVault) Wipe() error {
+	v.mut.Lock()
+	defer v.mut.Unlock()
+
+	return v.storer.SetOwnerPrivateKey("")
+}
@@ -0,0 +1,129 @@
+package vault_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/DrDelphi/ElrondDSSC/vault"
+)
+
+// memoryKeyStorer - keeps the stored key in memory
+type memoryKeyStorer struct {
+	key string
+}
+
+func (s *memoryKeyStorer) GetOwnerPrivateKey() string {
+	return s.key
+}
+
+func (s *memoryKeyStorer) SetOwnerPrivateKey(privateKey string) error {
+	s.key = privateKey
+	return nil
+}
+
+func TestNewKeyVaultSealsPlaintextKey(t *testing.T) {
+	storer := &memoryKeyStorer{key: hex.EncodeToString(testKey)}
+
+	v, err := vault.NewKeyVault(storer, testPassphrase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !vault.IsSealed(storer.key) {
+		t.Fatalf("the plaintext key was not sealed: %s", storer.key)
+	}
+
+	key, err := v.Unlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(key, testKey) {
+		t.Fatalf("Unlock() = %x, want %x", key, testKey)
+	}
+}
+
+func TestNewKeyVaultRefusesPlaintextKeyWhenLocked(t *testing.T) {
+	plaintext := hex.EncodeToString(testKey)
+	storer := &memoryKeyStorer{key: plaintext}
+
+	_, err := vault.NewKeyVault(storer, nil)
+	if err != vault.ErrPlaintextKey {
+		t.Fatalf("NewKeyVault() error = %v, want %v", err, vault.ErrPlaintextKey)
+	}
+	if storer.key != plaintext {
+		t.Fatalf("the stored key changed: %s", storer.key)
+	}
+}
+
+func TestNewKeyVaultWrongPassphrase(t *testing.T) {
+	sealed, err := vault.Seal(testKey, testPassphrase)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = vault.NewKeyVault(&memoryKeyStorer{key: sealed}, []byte("wrong"))
+	if err != vault.ErrWrongPassphrase {
+		t.Fatalf("NewKeyVault() error = %v, want %v", err, vault.ErrWrongPassphrase)
+	}
+}
+
+func TestKeyVaultLocked(t *testing.T) {
+	sealed, err := vault.Seal(testKey, testPassphrase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	storer := &memoryKeyStorer{key: sealed}
+
+	v, err := vault.NewKeyVault(storer, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !v.Locked() || !v.HasKey() {
+		t.Fatalf("Locked() = %v, HasKey() = %v, want both true", v.Locked(), v.HasKey())
+	}
+
+	_, err = v.Unlock()
+	if err != vault.ErrLocked {
+		t.Fatalf("Unlock() error = %v, want %v", err, vault.ErrLocked)
+	}
+	err = v.Store(testKey)
+	if err != vault.ErrLocked {
+		t.Fatalf("Store() error = %v, want %v", err, vault.ErrLocked)
+	}
+	if storer.key != sealed {
+		t.Fatalf("the stored key changed while locked")
+	}
+}
+
+func TestKeyVaultWipe(t *testing.T) {
+	storer := &memoryKeyStorer{}
+	v, err := vault.NewKeyVault(storer, testPassphrase)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = v.Unlock()
+	if err != vault.ErrNoKey {
+		t.Fatalf("Unlock() error = %v, want %v", err, vault.ErrNoKey)
+	}
+
+	err = v.Store(testKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !v.HasKey() {
+		t.Fatalf("HasKey() = false after Store()")
+	}
+
+	err = v.Wipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.HasKey() || storer.key != "" {
+		t.Fatalf("the key is still stored after Wipe(): %q", storer.key)
+	}
+	_, err = v.Unlock()
+	if err != vault.ErrNoKey {
+		t.Fatalf("Unlock() error = %v, want %v", err, vault.ErrNoKey)
+	}
+}
@@ -0,0 +1,57 @@
+package vault
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/DrDelphi/ElrondDSSC/data"
+	"golang.org/x/term"
+)
+
+// DefaultPassphraseEnv - the environment variable holding the vault passphrase, unless configured otherwise
+const DefaultPassphraseEnv = "DSSC_VAULT_PASSPHRASE"
+
+// ReadPassphrase - reads the vault passphrase from the environment variable, then from the passphrase file,
+// then from the terminal, if the application runs in one. Returns nil if no passphrase is given
+func ReadPassphrase(cfg *data.KeyVaultConfig) ([]byte, error) {
+	envName := DefaultPassphraseEnv
+	fileName := ""
+	if cfg != nil {
+		if cfg.PassphraseEnv != "" {
+			envName = cfg.PassphraseEnv
+		}
+		fileName = cfg.PassphraseFile
+	}
+
+	passphrase, ok := os.LookupEnv(envName)
+	if ok && passphrase != "" {
+		// keep the passphrase away from child processes
+		_ = os.Unsetenv(envName)
+		return []byte(passphrase), nil
+	}
+
+	if fileName != "" {
+		contents, err := ioutil.ReadFile(fileName)
+		if err != nil {
+			log.Error("can not read the vault passphrase file", "file", fileName, "error", err)
+			return nil, err
+		}
+		return bytes.TrimRight(contents, "\r\n"), nil
+	}
+
+	stdin := int(os.Stdin.Fd())
+	if !term.IsTerminal(stdin) {
+		return nil, nil
+	}
+
+	fmt.Print("Key vault passphrase (leave empty to start with the vault locked): ")
+	input, err := term.ReadPassword(stdin)
+	fmt.Println()
+	if err != nil {
+		return nil, err
+	}
+
+	return input, nil
+}
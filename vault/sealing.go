@@ -0,0 +1,171 @@
+package vault
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	sealVersion = 1
+	kdfScrypt   = "scrypt"
+
+	// scrypt parameters recommended for interactive logins: ~100ms and 32MB per derivation
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+
+	// bounds of the scrypt parameters accepted when opening a key, so a tampered record can neither weaken the
+	// derivation nor make it take minutes and gigabytes of memory
+	minScryptN      = 1 << 14
+	maxScryptN      = 1 << 20
+	maxScryptR      = 16
+	maxScryptP      = 16
+	maxScryptMemory = 1 << 30 // 128 * N * R bytes
+
+	saltLength = 32
+	keyLength  = 32 // AES-256
+)
+
+// additionalData - binds the ciphertext to its purpose, so it can not be passed off as another sealed value
+var additionalData = []byte("ElrondDSSC owner private key")
+
+// ErrWrongPassphrase - the sealed key can not be opened with the passphrase, or it was tampered with
+var ErrWrongPassphrase = errors.New("wrong passphrase or corrupted key")
+
+// ErrUnsupportedSeal - the sealed key was written by an unknown version or key derivation function
+var ErrUnsupportedSeal = errors.New("unsupported sealed key format")
+
+// sealedKey - the encrypted form of a key, as saved in the database
+type sealedKey struct {
+	Version    int    `json:"version"`
+	KDF        string `json:"kdf"`
+	N          int    `json:"n"`
+	R          int    `json:"r"`
+	P          int    `json:"p"`
+	Salt       string `json:"salt"`
+	Nonce      string `json:"nonce"`
+	Ciphertext string `json:"ciphertext"`
+}
+
+// Seal - encrypts a key with AES-GCM, using a key derived from the passphrase with scrypt
+func Seal(key []byte, passphrase []byte) (string, error) {
+	salt := make([]byte, saltLength)
+	_, err := rand.Read(salt)
+	if err != nil {
+		return "", err
+	}
+
+	sealed := &sealedKey{
+		Version: sealVersion,
+		KDF:     kdfScrypt,
+		N:       scryptN,
+		R:       scryptR,
+		P:       scryptP,
+		Salt:    hex.EncodeToString(salt),
+	}
+	aead, err := sealed.cipher(passphrase)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	_, err = rand.Read(nonce)
+	if err != nil {
+		return "", err
+	}
+
+	sealed.Nonce = hex.EncodeToString(nonce)
+	sealed.Ciphertext = hex.EncodeToString(aead.Seal(nil, nonce, key, additionalData))
+
+	result, err := json.Marshal(sealed)
+	if err != nil {
+		return "", err
+	}
+
+	return string(result), nil
+}
+
+// Open - decrypts a key encrypted by Seal. The caller should Zero the key once done with it
+func Open(value string, passphrase []byte) ([]byte, error) {
+	sealed := &sealedKey{}
+	err := json.Unmarshal([]byte(value), sealed)
+	if err != nil {
+		return nil, err
+	}
+	if sealed.Version != sealVersion || sealed.KDF != kdfScrypt || !sealed.validParameters() {
+		return nil, ErrUnsupportedSeal
+	}
+
+	aead, err := sealed.cipher(passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce, err := hex.DecodeString(sealed.Nonce)
+	if err != nil || len(nonce) != aead.NonceSize() {
+		return nil, ErrUnsupportedSeal
+	}
+	ciphertext, err := hex.DecodeString(sealed.Ciphertext)
+	if err != nil {
+		return nil, ErrUnsupportedSeal
+	}
+
+	key, err := aead.Open(nil, nonce, ciphertext, additionalData)
+	if err != nil {
+		return nil, ErrWrongPassphrase
+	}
+
+	return key, nil
+}
+
+// IsSealed - returns true if the value was produced by Seal, false for a plaintext key
+func IsSealed(value string) bool {
+	return strings.HasPrefix(strings.TrimSpace(value), "{")
+}
+
+// Zero - overwrites a key in memory
+func Zero(key []byte) {
+	for i := range key {
+		key[i] = 0
+	}
+}
+
+// validParameters - returns true if the scrypt parameters are within the accepted bounds
+func (s *sealedKey) validParameters() bool {
+	if s.N < minScryptN || s.N > maxScryptN || s.N&(s.N-1) != 0 {
+		return false
+	}
+	if s.R < 1 || s.R > maxScryptR || s.P < 1 || s.P > maxScryptP {
+		return false
+	}
+
+	return 128*s.N*s.R <= maxScryptMemory
+}
+
+// cipher - returns the AES-GCM cipher keyed with the passphrase and the sealed key's parameters
+func (s *sealedKey) cipher(passphrase []byte) (cipher.AEAD, error) {
+	salt, err := hex.DecodeString(s.Salt)
+	if err != nil {
+		return nil, ErrUnsupportedSeal
+	}
+
+	derived, err := scrypt.Key(passphrase, salt, s.N, s.R, s.P, keyLength)
+	if err != nil {
+		return nil, err
+	}
+	defer Zero(derived)
+
+	block, err := aes.NewCipher(derived)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,121 @@
+package vault_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/DrDelphi/ElrondDSSC/vault"
+)
+
+var (
+	testKey        = bytes.Repeat([]byte{0x42}, 32)
+	testPassphrase = []byte("correct horse battery staple")
+)
+
+// reseal - returns the sealed value with one of its fields replaced
+func reseal(t *testing.T, sealed string, field string, value interface{}) string {
+	t.Helper()
+
+	fields := make(map[string]interface{})
+	err := json.Unmarshal([]byte(sealed), &fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields[field] = value
+
+	result, err := json.Marshal(fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(result)
+}
+
+// flipHex - returns the hex string with the bits of its first byte flipped
+func flipHex(t *testing.T, value string) string {
+	t.Helper()
+
+	decoded, err := hex.DecodeString(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded[0] ^= 0xff
+
+	return hex.EncodeToString(decoded)
+}
+
+func TestSealOpen(t *testing.T) {
+	sealed, err := vault.Seal(testKey, testPassphrase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !vault.IsSealed(sealed) {
+		t.Fatalf("sealed value not recognized: %s", sealed)
+	}
+	if bytes.Contains([]byte(sealed), []byte(hex.EncodeToString(testKey))) {
+		t.Fatalf("the sealed value contains the plaintext key")
+	}
+
+	key, err := vault.Open(sealed, testPassphrase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(key, testKey) {
+		t.Fatalf("Open() = %x, want %x", key, testKey)
+	}
+
+	again, err := vault.Seal(testKey, testPassphrase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again == sealed {
+		t.Fatalf("sealing twice gave the same salt and nonce")
+	}
+}
+
+func TestOpenErrors(t *testing.T) {
+	sealed, err := vault.Seal(testKey, testPassphrase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields := make(map[string]interface{})
+	err = json.Unmarshal([]byte(sealed), &fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		sealed     string
+		passphrase []byte
+		want       error
+	}{
+		{"wrong passphrase", sealed, []byte("wrong"), vault.ErrWrongPassphrase},
+		{"empty passphrase", sealed, nil, vault.ErrWrongPassphrase},
+		{"tampered ciphertext", reseal(t, sealed, "ciphertext", flipHex(t, fields["ciphertext"].(string))), testPassphrase, vault.ErrWrongPassphrase},
+		{"tampered nonce", reseal(t, sealed, "nonce", flipHex(t, fields["nonce"].(string))), testPassphrase, vault.ErrWrongPassphrase},
+		{"tampered salt", reseal(t, sealed, "salt", flipHex(t, fields["salt"].(string))), testPassphrase, vault.ErrWrongPassphrase},
+		{"short nonce", reseal(t, sealed, "nonce", "00"), testPassphrase, vault.ErrUnsupportedSeal},
+		{"unknown version", reseal(t, sealed, "version", 2), testPassphrase, vault.ErrUnsupportedSeal},
+		{"unknown kdf", reseal(t, sealed, "kdf", "pbkdf2"), testPassphrase, vault.ErrUnsupportedSeal},
+		{"n too low", reseal(t, sealed, "n", 1<<4), testPassphrase, vault.ErrUnsupportedSeal},
+		{"n too high", reseal(t, sealed, "n", 1<<24), testPassphrase, vault.ErrUnsupportedSeal},
+		{"n not a power of two", reseal(t, sealed, "n", 1<<15+1), testPassphrase, vault.ErrUnsupportedSeal},
+		{"r zero", reseal(t, sealed, "r", 0), testPassphrase, vault.ErrUnsupportedSeal},
+		{"r too high", reseal(t, sealed, "r", 1024), testPassphrase, vault.ErrUnsupportedSeal},
+		{"p zero", reseal(t, sealed, "p", 0), testPassphrase, vault.ErrUnsupportedSeal},
+		{"p too high", reseal(t, sealed, "p", 1<<20), testPassphrase, vault.ErrUnsupportedSeal},
+		{"too much memory", reseal(t, reseal(t, sealed, "n", 1<<20), "r", 16), testPassphrase, vault.ErrUnsupportedSeal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := vault.Open(tt.sealed, tt.passphrase)
+			if err != tt.want {
+				t.Fatalf("Open() = %x, %v, want %v", key, err, tt.want)
+			}
+		})
+	}
+}